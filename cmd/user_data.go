@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/urfave/cli/v2"
 
@@ -364,6 +365,44 @@ var UserData = &cli.Command{
 				},
 			},
 		},
+		{
+			Name:   "user-data-backup",
+			Usage:  "Backup user all data (including transaction pictures) to archive file",
+			Action: bindAction(backupUserData),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Specific backup file path (e.g. backup.zip)",
+				},
+			},
+		},
+		{
+			Name:   "user-data-restore",
+			Usage:  "Restore user all data from archive file to specified user who does not have any data",
+			Action: bindAction(restoreUserData),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     "username",
+					Aliases:  []string{"n"},
+					Required: true,
+					Usage:    "Specific user name",
+				},
+				&cli.StringFlag{
+					Name:     "file",
+					Aliases:  []string{"f"},
+					Required: true,
+					Usage:    "Specific backup file path (e.g. backup.zip)",
+				},
+			},
+		},
 	},
 }
 
@@ -867,7 +906,7 @@ func importUserTransaction(c *core.CliContext) error {
 
 	log.CliInfof(c, "[user_data.importUserTransaction] start importing transactions to user \"%s\"", username)
 
-	err = clis.UserData.ImportTransaction(c, username, filetype, data)
+	err = clis.UserData.ImportTransaction(c, username, filetype, filepath.Base(filePath), data)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.importUserTransaction] error occurs when importing user data")
@@ -879,6 +918,92 @@ func importUserTransaction(c *core.CliContext) error {
 	return nil
 }
 
+func backupUserData(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	filePath := c.String("file")
+
+	if filePath == "" {
+		log.CliErrorf(c, "[user_data.backupUserData] backup file path is unspecified")
+		return os.ErrNotExist
+	}
+
+	fileExists, err := utils.IsExists(filePath)
+
+	if fileExists {
+		log.CliErrorf(c, "[user_data.backupUserData] specified file path already exists")
+		return os.ErrExist
+	}
+
+	log.CliInfof(c, "[user_data.backupUserData] starting backing up user \"%s\" data", username)
+
+	content, err := clis.UserData.BackupUserData(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.backupUserData] error occurs when backing up user data")
+		return err
+	}
+
+	err = utils.WriteFile(filePath, content)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.backupUserData] failed to write to %s", filePath)
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.backupUserData] user data has been backed up to %s", filePath)
+
+	return nil
+}
+
+func restoreUserData(c *core.CliContext) error {
+	_, err := initializeSystem(c)
+
+	if err != nil {
+		return err
+	}
+
+	username := c.String("username")
+	filePath := c.String("file")
+
+	if filePath == "" {
+		log.CliErrorf(c, "[user_data.restoreUserData] backup file path is not specified")
+		return os.ErrNotExist
+	}
+
+	fileExists, err := utils.IsExists(filePath)
+
+	if !fileExists {
+		log.CliErrorf(c, "[user_data.restoreUserData] backup file does not exist")
+		return os.ErrNotExist
+	}
+
+	data, err := os.ReadFile(filePath)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.restoreUserData] failed to load backup file")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.restoreUserData] start restoring data to user \"%s\"", username)
+
+	backup, err := clis.UserData.RestoreUserData(c, username, data)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.restoreUserData] error occurs when restoring user data")
+		return err
+	}
+
+	log.CliInfof(c, "[user_data.restoreUserData] %d accounts, %d transaction categories, %d transaction tags, %d transactions, %d transaction pictures and %d transaction templates have been restored to user \"%s\"", len(backup.Accounts), len(backup.Categories), len(backup.Tags), len(backup.Transactions), len(backup.Pictures), len(backup.Templates), username)
+
+	return nil
+}
+
 func printUserInfo(user *models.User) {
 	fmt.Printf("[Uid] %d\n", user.Uid)
 	fmt.Printf("[Username] %s\n", user.Username)
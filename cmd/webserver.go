@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"net/http"
 	"path/filepath"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/middlewares"
 	"github.com/mayswind/ezbookkeeping/pkg/requestid"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 	"github.com/mayswind/ezbookkeeping/pkg/validators"
@@ -70,6 +72,15 @@ func startWebServer(c *core.CliContext) error {
 		return err
 	}
 
+	interruptedImportJobCount, err := services.TransactionImportJobs.FailAllUnfinishedJobs(c)
+
+	if err != nil {
+		log.BootErrorf(c, "[webserver.startWebServer] failed to mark unfinished transaction import jobs as failed, because %s", err.Error())
+		return err
+	} else if interruptedImportJobCount > 0 {
+		log.BootWarnf(c, "[webserver.startWebServer] %d unfinished transaction import jobs have been marked as failed", interruptedImportJobCount)
+	}
+
 	serverInfo := fmt.Sprintf("current server id is %d, current instance id is %d", requestid.Container.Current.GetCurrentServerUniqId(), requestid.Container.Current.GetCurrentInstanceUniqId())
 	uuidServerInfo := ""
 	if config.UuidGeneratorType == settings.InternalUuidGeneratorType {
@@ -291,6 +302,16 @@ func startWebServer(c *core.CliContext) error {
 			if config.EnableDataExport {
 				apiV1Route.GET("/data/export.csv", bindCsv(api.DataManagements.ExportDataToEzbookkeepingCSVHandler))
 				apiV1Route.GET("/data/export.tsv", bindTsv(api.DataManagements.ExportDataToEzbookkeepingTSVHandler))
+				apiV1Route.GET("/data/export.xlsx", bindXlsx(api.DataManagements.ExportDataToXlsxHandler))
+				apiV1Route.GET("/data/export.ofx", bindOfx(api.DataManagements.ExportDataToOFXHandler))
+				apiV1Route.GET("/data/export.qif", bindQif(api.DataManagements.ExportDataToQIFHandler))
+				apiV1Route.GET("/data/export.beancount", bindPlainText(api.DataManagements.ExportDataToBeancountHandler))
+				apiV1Route.GET("/data/export.journal", bindPlainText(api.DataManagements.ExportDataToHledgerHandler))
+				apiV1Route.GET("/data/backup.zip", bindZip(api.DataManagements.ExportUserDataBackupHandler))
+			}
+
+			if config.EnableDataImport {
+				apiV1Route.POST("/data/restore.json", bindApi(api.DataManagements.RestoreUserDataBackupHandler))
 			}
 
 			// Accounts
@@ -317,6 +338,23 @@ func startWebServer(c *core.CliContext) error {
 			if config.EnableDataImport {
 				apiV1Route.POST("/transactions/parse_import.json", bindApi(api.Transactions.TransactionParseImportFileHandler))
 				apiV1Route.POST("/transactions/import.json", bindApi(api.Transactions.TransactionImportHandler))
+
+				// Transaction Import Profiles
+				apiV1Route.GET("/transaction/import_profiles/list.json", bindApi(api.TransactionImportProfiles.ImportProfileListHandler))
+				apiV1Route.GET("/transaction/import_profiles/get.json", bindApi(api.TransactionImportProfiles.ImportProfileGetHandler))
+				apiV1Route.POST("/transaction/import_profiles/add.json", bindApi(api.TransactionImportProfiles.ImportProfileCreateHandler))
+				apiV1Route.POST("/transaction/import_profiles/modify.json", bindApi(api.TransactionImportProfiles.ImportProfileModifyHandler))
+				apiV1Route.POST("/transaction/import_profiles/delete.json", bindApi(api.TransactionImportProfiles.ImportProfileDeleteHandler))
+				apiV1Route.POST("/transaction/import_profiles/parse_headers.json", bindApi(api.TransactionImportProfiles.ImportProfileParseHeadersHandler))
+
+				// Transaction Import Jobs
+				apiV1Route.GET("/transaction/import_jobs/list.json", bindApi(api.TransactionImportJobs.ImportJobListHandler))
+				apiV1Route.GET("/transaction/import_jobs/get.json", bindApi(api.TransactionImportJobs.ImportJobGetHandler))
+				apiV1Route.POST("/transaction/import_jobs/add.json", bindApi(api.TransactionImportJobs.ImportJobCreateHandler))
+
+				// Transaction Import Batches
+				apiV1Route.GET("/transaction/import_batches/list.json", bindApi(api.TransactionImportBatches.ImportBatchListHandler))
+				apiV1Route.POST("/transaction/import_batches/revert.json", bindApi(api.TransactionImportBatches.ImportBatchRevertHandler))
 			}
 
 			// Transaction Pictures
@@ -347,14 +385,40 @@ func startWebServer(c *core.CliContext) error {
 			// Transaction Templates
 			apiV1Route.GET("/transaction/templates/list.json", bindApi(api.TransactionTemplates.TemplateListHandler))
 			apiV1Route.GET("/transaction/templates/get.json", bindApi(api.TransactionTemplates.TemplateGetHandler))
+			apiV1Route.GET("/transaction/templates/forecast.json", bindApi(api.TransactionTemplates.TemplateForecastHandler))
 			apiV1Route.POST("/transaction/templates/add.json", bindApi(api.TransactionTemplates.TemplateCreateHandler))
 			apiV1Route.POST("/transaction/templates/modify.json", bindApi(api.TransactionTemplates.TemplateModifyHandler))
 			apiV1Route.POST("/transaction/templates/hide.json", bindApi(api.TransactionTemplates.TemplateHideHandler))
 			apiV1Route.POST("/transaction/templates/move.json", bindApi(api.TransactionTemplates.TemplateMoveHandler))
 			apiV1Route.POST("/transaction/templates/delete.json", bindApi(api.TransactionTemplates.TemplateDeleteHandler))
 
+			// Transaction Rules
+			apiV1Route.GET("/transaction/rules/list.json", bindApi(api.TransactionRules.RuleListHandler))
+			apiV1Route.GET("/transaction/rules/get.json", bindApi(api.TransactionRules.RuleGetHandler))
+			apiV1Route.POST("/transaction/rules/add.json", bindApi(api.TransactionRules.RuleCreateHandler))
+			apiV1Route.POST("/transaction/rules/modify.json", bindApi(api.TransactionRules.RuleModifyHandler))
+			apiV1Route.POST("/transaction/rules/hide.json", bindApi(api.TransactionRules.RuleHideHandler))
+			apiV1Route.POST("/transaction/rules/move.json", bindApi(api.TransactionRules.RuleMoveHandler))
+			apiV1Route.POST("/transaction/rules/delete.json", bindApi(api.TransactionRules.RuleDeleteHandler))
+			apiV1Route.POST("/transaction/rules/dry_run.json", bindApi(api.TransactionRules.RuleDryRunHandler))
+
+			// Budgets
+			apiV1Route.GET("/budgets/list.json", bindApi(api.Budgets.BudgetListHandler))
+			apiV1Route.GET("/budgets/get.json", bindApi(api.Budgets.BudgetGetHandler))
+			apiV1Route.GET("/budgets/progress.json", bindApi(api.Budgets.BudgetProgressHandler))
+			apiV1Route.POST("/budgets/add.json", bindApi(api.Budgets.BudgetCreateHandler))
+			apiV1Route.POST("/budgets/modify.json", bindApi(api.Budgets.BudgetModifyHandler))
+			apiV1Route.POST("/budgets/move.json", bindApi(api.Budgets.BudgetMoveHandler))
+			apiV1Route.POST("/budgets/delete.json", bindApi(api.Budgets.BudgetDeleteHandler))
+
 			// Exchange Rates
 			apiV1Route.GET("/exchange_rates/latest.json", bindApi(api.ExchangeRates.LatestExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/historical.json", bindApi(api.ExchangeRates.HistoricalExchangeRateHandler))
+			apiV1Route.GET("/exchange_rates/custom/list.json", bindApi(api.UserCustomExchangeRates.CustomExchangeRateListHandler))
+			apiV1Route.GET("/exchange_rates/custom/get.json", bindApi(api.UserCustomExchangeRates.CustomExchangeRateGetHandler))
+			apiV1Route.POST("/exchange_rates/custom/add.json", bindApi(api.UserCustomExchangeRates.CustomExchangeRateCreateHandler))
+			apiV1Route.POST("/exchange_rates/custom/modify.json", bindApi(api.UserCustomExchangeRates.CustomExchangeRateModifyHandler))
+			apiV1Route.POST("/exchange_rates/custom/delete.json", bindApi(api.UserCustomExchangeRates.CustomExchangeRateDeleteHandler))
 		}
 	}
 
@@ -430,7 +494,78 @@ func bindCachedJs(fn core.DataHandlerFunc, store persistence.CacheStore) gin.Han
 	})
 }
 
-func bindCsv(fn core.DataHandlerFunc) gin.HandlerFunc {
+func bindCsv(fn core.DataStreamHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		writer, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else if err := utils.PrintDataStreamSuccessResult(c, "text/csv", fileName, writer); err != nil {
+			log.Errorf(c, "[webserver.bindCsv] failed to write data stream, because %s", err.Error())
+
+			// the success status code and part of data have been sent, so the connection is aborted to make the client know the data is incomplete
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+func bindTsv(fn core.DataStreamHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		writer, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else if err := utils.PrintDataStreamSuccessResult(c, "text/tab-separated-values", fileName, writer); err != nil {
+			log.Errorf(c, "[webserver.bindTsv] failed to write data stream, because %s", err.Error())
+
+			// the success status code and part of data have been sent, so the connection is aborted to make the client know the data is incomplete
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+func bindXlsx(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", fileName, result)
+		}
+	}
+}
+
+func bindOfx(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/x-ofx", fileName, result)
+		}
+	}
+}
+
+func bindQif(fn core.DataHandlerFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		c := core.WrapWebContext(ginCtx)
+		result, fileName, err := fn(c)
+
+		if err != nil {
+			utils.PrintDataErrorResult(c, "text/text", err)
+		} else {
+			utils.PrintDataSuccessResult(c, "application/qif", fileName, result)
+		}
+	}
+}
+
+func bindPlainText(fn core.DataHandlerFunc) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx)
 		result, fileName, err := fn(c)
@@ -438,12 +573,12 @@ func bindCsv(fn core.DataHandlerFunc) gin.HandlerFunc {
 		if err != nil {
 			utils.PrintDataErrorResult(c, "text/text", err)
 		} else {
-			utils.PrintDataSuccessResult(c, "text/csv", fileName, result)
+			utils.PrintDataSuccessResult(c, "text/plain", fileName, result)
 		}
 	}
 }
 
-func bindTsv(fn core.DataHandlerFunc) gin.HandlerFunc {
+func bindZip(fn core.DataHandlerFunc) gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		c := core.WrapWebContext(ginCtx)
 		result, fileName, err := fn(c)
@@ -451,7 +586,7 @@ func bindTsv(fn core.DataHandlerFunc) gin.HandlerFunc {
 		if err != nil {
 			utils.PrintDataErrorResult(c, "text/text", err)
 		} else {
-			utils.PrintDataSuccessResult(c, "text/tab-separated-values", fileName, result)
+			utils.PrintDataSuccessResult(c, "application/zip", fileName, result)
 		}
 	}
 }
@@ -0,0 +1,565 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// BudgetsApi represents budget api
+type BudgetsApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	budgets                 *services.BudgetService
+	transactions            *services.TransactionService
+	transactionCategories   *services.TransactionCategoryService
+	accounts                *services.AccountService
+	users                   *services.UserService
+	exchangeRates           *services.ExchangeRateService
+	userCustomExchangeRates *services.UserCustomExchangeRateService
+}
+
+// Initialize a budget api singleton instance
+var (
+	Budgets = &BudgetsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			container: duplicatechecker.Container,
+		},
+		budgets:                 services.Budgets,
+		transactions:            services.Transactions,
+		transactionCategories:   services.TransactionCategories,
+		accounts:                services.Accounts,
+		users:                   services.Users,
+		exchangeRates:           services.ExchangeRates,
+		userCustomExchangeRates: services.UserCustomExchangeRates,
+	}
+)
+
+// BudgetListHandler returns budget list of current user
+func (a *BudgetsApi) BudgetListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	budgets, err := a.budgets.GetAllBudgetsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetListHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetResps := make(models.BudgetInfoResponseSlice, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		budgetResps[i] = budgets[i].ToBudgetInfoResponse()
+	}
+
+	sort.Sort(budgetResps)
+
+	return budgetResps, nil
+}
+
+// BudgetGetHandler returns one specific budget of current user
+func (a *BudgetsApi) BudgetGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetGetReq models.BudgetGetRequest
+	err := c.ShouldBindQuery(&budgetGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetGetHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budgetResp := budget.ToBudgetInfoResponse()
+
+	return budgetResp, nil
+}
+
+// BudgetCreateHandler saves a new budget by request parameters for current user
+func (a *BudgetsApi) BudgetCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetCreateReq models.BudgetCreateRequest
+	err := c.ShouldBindJSON(&budgetCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !budgetCreateReq.PeriodType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] budget period type invalid, type is %d", budgetCreateReq.PeriodType)
+		return nil, errs.ErrBudgetPeriodTypeInvalid
+	}
+
+	if !budgetCreateReq.RolloverType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] budget rollover type invalid, type is %d", budgetCreateReq.RolloverType)
+		return nil, errs.ErrBudgetRolloverTypeInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.checkBudgetCategoryAndAccount(c, uid, budgetCreateReq.CategoryId, budgetCreateReq.AccountId)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetCreateHandler] category \"id:%d\" or account \"id:%d\" is invalid for user \"uid:%d\", because %s", budgetCreateReq.CategoryId, budgetCreateReq.AccountId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	maxOrderId, err := a.budgets.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	budget := &models.Budget{
+		Uid:          uid,
+		CategoryId:   budgetCreateReq.CategoryId,
+		AccountId:    budgetCreateReq.AccountId,
+		PeriodType:   budgetCreateReq.PeriodType,
+		Amount:       budgetCreateReq.Amount,
+		RolloverType: budgetCreateReq.RolloverType,
+		Comment:      budgetCreateReq.Comment,
+		DisplayOrder: maxOrderId + 1,
+	}
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && budgetCreateReq.ClientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_BUDGET, uid, budgetCreateReq.ClientSessionId)
+
+		if found {
+			log.Infof(c, "[budgets.BudgetCreateHandler] another budget \"id:%s\" has been created for user \"uid:%d\"", remark, uid)
+			budgetId, err := utils.StringToInt64(remark)
+
+			if err == nil {
+				budget, err = a.budgets.GetBudgetByBudgetId(c, uid, budgetId)
+
+				if err != nil {
+					log.Errorf(c, "[budgets.BudgetCreateHandler] failed to get existed budget \"id:%d\" for user \"uid:%d\", because %s", budgetId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
+				budgetResp := budget.ToBudgetInfoResponse()
+
+				return budgetResp, nil
+			}
+		}
+	}
+
+	err = a.budgets.CreateBudget(c, budget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetCreateHandler] failed to create budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetCreateHandler] user \"uid:%d\" has created a new budget \"id:%d\" successfully", uid, budget.BudgetId)
+
+	a.SetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_BUDGET, uid, budgetCreateReq.ClientSessionId, utils.Int64ToString(budget.BudgetId))
+	budgetResp := budget.ToBudgetInfoResponse()
+
+	return budgetResp, nil
+}
+
+// BudgetModifyHandler saves an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetModifyReq models.BudgetModifyRequest
+	err := c.ShouldBindJSON(&budgetModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !budgetModifyReq.PeriodType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] budget period type invalid, type is %d", budgetModifyReq.PeriodType)
+		return nil, errs.ErrBudgetPeriodTypeInvalid
+	}
+
+	if !budgetModifyReq.RolloverType.IsValid() {
+		log.Warnf(c, "[budgets.BudgetModifyHandler] budget rollover type invalid, type is %d", budgetModifyReq.RolloverType)
+		return nil, errs.ErrBudgetRolloverTypeInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newBudget := &models.Budget{
+		BudgetId:     budget.BudgetId,
+		Uid:          uid,
+		CategoryId:   budgetModifyReq.CategoryId,
+		AccountId:    budgetModifyReq.AccountId,
+		PeriodType:   budgetModifyReq.PeriodType,
+		Amount:       budgetModifyReq.Amount,
+		RolloverType: budgetModifyReq.RolloverType,
+		Comment:      budgetModifyReq.Comment,
+	}
+
+	if newBudget.CategoryId == budget.CategoryId &&
+		newBudget.AccountId == budget.AccountId &&
+		newBudget.PeriodType == budget.PeriodType &&
+		newBudget.Amount == budget.Amount &&
+		newBudget.RolloverType == budget.RolloverType &&
+		newBudget.Comment == budget.Comment {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	if newBudget.CategoryId != budget.CategoryId || newBudget.AccountId != budget.AccountId {
+		err = a.checkBudgetCategoryAndAccount(c, uid, newBudget.CategoryId, newBudget.AccountId)
+
+		if err != nil {
+			log.Warnf(c, "[budgets.BudgetModifyHandler] category \"id:%d\" or account \"id:%d\" is invalid for user \"uid:%d\", because %s", newBudget.CategoryId, newBudget.AccountId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	err = a.budgets.ModifyBudget(c, newBudget)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetModifyHandler] failed to update budget \"id:%d\" for user \"uid:%d\", because %s", budgetModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetModifyHandler] user \"uid:%d\" has updated budget \"id:%d\" successfully", uid, budgetModifyReq.Id)
+
+	newBudget.DisplayOrder = budget.DisplayOrder
+	budgetResp := newBudget.ToBudgetInfoResponse()
+
+	return budgetResp, nil
+}
+
+// BudgetMoveHandler moves display order of existed budgets by request parameters for current user
+func (a *BudgetsApi) BudgetMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetMoveReq models.BudgetMoveRequest
+	err := c.ShouldBindJSON(&budgetMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	budgets := make([]*models.Budget, len(budgetMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(budgetMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := budgetMoveReq.NewDisplayOrders[i]
+		budget := &models.Budget{
+			Uid:          uid,
+			BudgetId:     newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		budgets[i] = budget
+	}
+
+	err = a.budgets.ModifyBudgetDisplayOrders(c, uid, budgets)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetMoveHandler] failed to move budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetMoveHandler] user \"uid:%d\" has moved budgets", uid)
+	return true, nil
+}
+
+// BudgetDeleteHandler deletes an existed budget by request parameters for current user
+func (a *BudgetsApi) BudgetDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetDeleteReq models.BudgetDeleteRequest
+	err := c.ShouldBindJSON(&budgetDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.budgets.DeleteBudget(c, uid, budgetDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetDeleteHandler] failed to delete budget \"id:%d\" for user \"uid:%d\", because %s", budgetDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[budgets.BudgetDeleteHandler] user \"uid:%d\" has deleted budget \"id:%d\"", uid, budgetDeleteReq.Id)
+	return true, nil
+}
+
+// BudgetProgressHandler returns the progress of all budgets or one specific budget of current user in the period which contains the specified time
+func (a *BudgetsApi) BudgetProgressHandler(c *core.WebContext) (any, *errs.Error) {
+	var budgetProgressReq models.BudgetProgressRequest
+	err := c.ShouldBindQuery(&budgetProgressReq)
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetProgressHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[budgets.BudgetProgressHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	var budgets []*models.Budget
+
+	if budgetProgressReq.Id > 0 {
+		budget, err := a.budgets.GetBudgetByBudgetId(c, uid, budgetProgressReq.Id)
+
+		if err != nil {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get budget \"id:%d\" for user \"uid:%d\", because %s", budgetProgressReq.Id, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		budgets = append(budgets, budget)
+	} else {
+		budgets, err = a.budgets.GetAllBudgetsByUid(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get budgets for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	}
+
+	progressResps := make([]*models.BudgetProgressResponse, 0, len(budgets))
+
+	if len(budgets) < 1 {
+		return progressResps, nil
+	}
+
+	subCategoryIdsMap, subAccountIdsMap, err := a.getBudgetSubCategoryAndSubAccountIds(c, uid, budgets)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get sub-categories or sub-accounts of budgets for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	customExchangeRates, err := a.userCustomExchangeRates.GetAllCustomExchangeRatesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get custom exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	// the budget amount is in the default currency of user, so the spent amounts in other currencies are converted to the default currency
+	exchangeRateConverter := a.exchangeRates.NewExchangeRateConverter(c, user.DefaultCurrency, accounts, customExchangeRates)
+	currentUnixTime := budgetProgressReq.Time
+
+	if currentUnixTime <= 0 {
+		currentUnixTime = time.Now().Unix()
+	}
+
+	timezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	totalAmountsByPeriod := make(map[string][]*models.TransactionTotalAmount)
+
+	for i := 0; i < len(budgets); i++ {
+		budget := budgets[i]
+		startTime, endTime := budget.GetPeriodTimeRange(currentUnixTime, timezone, user.FirstDayOfWeek)
+		spentAmount, err := a.getBudgetSpentAmount(c, uid, budget, startTime, endTime, utcOffset, subCategoryIdsMap, subAccountIdsMap, exchangeRateConverter, totalAmountsByPeriod)
+
+		if err != nil {
+			log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get spent amount of budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		rolloverAmount := int64(0)
+
+		if budget.RolloverType != models.BUDGET_ROLLOVER_TYPE_NONE {
+			previousSpentAmounts := make([]int64, 0)
+			previousStartTime, _ := budget.GetPeriodTimeRange(budget.CreatedUnixTime, timezone, user.FirstDayOfWeek)
+
+			for previousStartTime < startTime {
+				_, previousEndTime := budget.GetPeriodTimeRange(previousStartTime, timezone, user.FirstDayOfWeek)
+				previousSpentAmount, err := a.getBudgetSpentAmount(c, uid, budget, previousStartTime, previousEndTime, utcOffset, subCategoryIdsMap, subAccountIdsMap, exchangeRateConverter, totalAmountsByPeriod)
+
+				if err != nil {
+					log.Errorf(c, "[budgets.BudgetProgressHandler] failed to get previous period spent amount of budget \"id:%d\" for user \"uid:%d\", because %s", budget.BudgetId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
+				previousSpentAmounts = append(previousSpentAmounts, previousSpentAmount)
+				previousStartTime = previousEndTime + 1
+			}
+
+			rolloverAmount = budget.GetRolloverAmount(previousSpentAmounts)
+		}
+
+		plannedAmount := budget.Amount + rolloverAmount
+
+		progressResps = append(progressResps, &models.BudgetProgressResponse{
+			BudgetId:        budget.BudgetId,
+			StartTime:       startTime,
+			EndTime:         endTime,
+			BudgetAmount:    budget.Amount,
+			RolloverAmount:  rolloverAmount,
+			PlannedAmount:   plannedAmount,
+			SpentAmount:     spentAmount,
+			RemainingAmount: plannedAmount - spentAmount,
+		})
+	}
+
+	return progressResps, nil
+}
+
+func (a *BudgetsApi) checkBudgetCategoryAndAccount(c *core.WebContext, uid int64, categoryId int64, accountId int64) error {
+	category, err := a.transactionCategories.GetCategoryByCategoryId(c, uid, categoryId)
+
+	if err != nil {
+		return err
+	}
+
+	if category.Type != models.CATEGORY_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_EXPENSE {
+		return errs.ErrBudgetCategoryTypeInvalid
+	}
+
+	if accountId > 0 {
+		accounts, err := a.accounts.GetAccountsByAccountIds(c, uid, []int64{accountId})
+
+		if err != nil {
+			return err
+		}
+
+		if _, exists := accounts[accountId]; !exists {
+			return errs.ErrAccountNotFound
+		}
+	}
+
+	return nil
+}
+
+func (a *BudgetsApi) getBudgetSubCategoryAndSubAccountIds(c *core.WebContext, uid int64, budgets []*models.Budget) (map[int64][]int64, map[int64][]int64, error) {
+	categoryIds := make([]int64, 0, len(budgets))
+	accountIds := make([]int64, 0, len(budgets))
+
+	for i := 0; i < len(budgets); i++ {
+		categoryIds = append(categoryIds, budgets[i].CategoryId)
+
+		if budgets[i].AccountId > 0 {
+			accountIds = append(accountIds, budgets[i].AccountId)
+		}
+	}
+
+	subCategories, err := a.transactionCategories.GetSubCategoriesByCategoryIds(c, uid, categoryIds)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subCategoryIdsMap := make(map[int64][]int64)
+
+	for i := 0; i < len(subCategories); i++ {
+		subCategory := subCategories[i]
+		subCategoryIdsMap[subCategory.ParentCategoryId] = append(subCategoryIdsMap[subCategory.ParentCategoryId], subCategory.CategoryId)
+	}
+
+	subAccountIdsMap := make(map[int64][]int64)
+
+	if len(accountIds) > 0 {
+		subAccounts, err := a.accounts.GetSubAccountsByAccountIds(c, uid, accountIds)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i := 0; i < len(subAccounts); i++ {
+			subAccount := subAccounts[i]
+			subAccountIdsMap[subAccount.ParentAccountId] = append(subAccountIdsMap[subAccount.ParentAccountId], subAccount.AccountId)
+		}
+	}
+
+	return subCategoryIdsMap, subAccountIdsMap, nil
+}
+
+func (a *BudgetsApi) getBudgetSpentAmount(c *core.WebContext, uid int64, budget *models.Budget, startTime int64, endTime int64, utcOffset int16, subCategoryIdsMap map[int64][]int64, subAccountIdsMap map[int64][]int64, exchangeRateConverter *services.ExchangeRateConverter, totalAmountsByPeriod map[string][]*models.TransactionTotalAmount) (int64, error) {
+	periodKey := fmt.Sprintf("%d_%d", startTime, endTime)
+	totalAmounts, exists := totalAmountsByPeriod[periodKey]
+
+	if !exists {
+		var err error
+		totalAmounts, err = a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, startTime, endTime, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, utcOffset, false, exchangeRateConverter)
+
+		if err != nil {
+			return 0, err
+		}
+
+		totalAmountsByPeriod[periodKey] = totalAmounts
+	}
+
+	categoryIds := make(map[int64]bool)
+	categoryIds[budget.CategoryId] = true
+
+	for _, subCategoryId := range subCategoryIdsMap[budget.CategoryId] {
+		categoryIds[subCategoryId] = true
+	}
+
+	var accountIds map[int64]bool
+
+	if budget.AccountId > 0 {
+		accountIds = make(map[int64]bool)
+		accountIds[budget.AccountId] = true
+
+		for _, subAccountId := range subAccountIdsMap[budget.AccountId] {
+			accountIds[subAccountId] = true
+		}
+	}
+
+	spentAmount := int64(0)
+
+	for i := 0; i < len(totalAmounts); i++ {
+		totalAmount := totalAmounts[i]
+
+		if !categoryIds[totalAmount.CategoryId] {
+			continue
+		}
+
+		if accountIds != nil && !accountIds[totalAmount.AccountId] {
+			continue
+		}
+
+		if totalAmount.HasAmountInDefaultCurrency {
+			spentAmount += totalAmount.AmountInDefaultCurrency
+		} else {
+			log.Warnf(c, "[budgets.getBudgetSpentAmount] cannot convert amount of account \"id:%d\" to default currency for budget \"id:%d\" of user \"uid:%d\", because there is no available exchange rate", totalAmount.AccountId, budget.BudgetId, uid)
+			spentAmount += totalAmount.Amount
+		}
+	}
+
+	return spentAmount, nil
+}
@@ -2,10 +2,12 @@ package api
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/converters"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
@@ -20,14 +22,22 @@ const pageCountForDataExport = 1000
 // DataManagementsApi represents data management api
 type DataManagementsApi struct {
 	ApiUsingConfig
-	tokens       *services.TokenService
-	users        *services.UserService
-	accounts     *services.AccountService
-	transactions *services.TransactionService
-	categories   *services.TransactionCategoryService
-	tags         *services.TransactionTagService
-	pictures     *services.TransactionPictureService
-	templates    *services.TransactionTemplateService
+	tokens         *services.TokenService
+	users          *services.UserService
+	accounts       *services.AccountService
+	transactions   *services.TransactionService
+	categories     *services.TransactionCategoryService
+	tags           *services.TransactionTagService
+	splits         *services.TransactionSplitService
+	pictures       *services.TransactionPictureService
+	templates      *services.TransactionTemplateService
+	budgets        *services.BudgetService
+	rules          *services.TransactionRuleService
+	customRates    *services.UserCustomExchangeRateService
+	importProfiles *services.TransactionImportProfileService
+	importBatches  *services.TransactionImportBatchService
+	importJobs     *services.TransactionImportJobService
+	backups        *services.UserDataBackupService
 }
 
 // Initialize a data management api singleton instance
@@ -36,25 +46,187 @@ var (
 		ApiUsingConfig: ApiUsingConfig{
 			container: settings.Container,
 		},
-		tokens:       services.Tokens,
-		users:        services.Users,
-		accounts:     services.Accounts,
-		transactions: services.Transactions,
-		categories:   services.TransactionCategories,
-		tags:         services.TransactionTags,
-		pictures:     services.TransactionPictures,
-		templates:    services.TransactionTemplates,
+		tokens:         services.Tokens,
+		users:          services.Users,
+		accounts:       services.Accounts,
+		transactions:   services.Transactions,
+		categories:     services.TransactionCategories,
+		tags:           services.TransactionTags,
+		splits:         services.TransactionSplits,
+		pictures:       services.TransactionPictures,
+		templates:      services.TransactionTemplates,
+		budgets:        services.Budgets,
+		rules:          services.TransactionRules,
+		customRates:    services.UserCustomExchangeRates,
+		importProfiles: services.TransactionImportProfiles,
+		importBatches:  services.TransactionImportBatches,
+		importJobs:     services.TransactionImportJobs,
+		backups:        services.UserDataBackups,
 	}
 )
 
 // ExportDataToEzbookkeepingCSVHandler returns exported data in csv format
-func (a *DataManagementsApi) ExportDataToEzbookkeepingCSVHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
-	return a.getExportedFileContent(c, "csv")
+func (a *DataManagementsApi) ExportDataToEzbookkeepingCSVHandler(c *core.WebContext) (core.DataStreamWriterFunc, string, *errs.Error) {
+	return a.getExportedFileStreamWriter(c, "csv", "csv")
 }
 
-// ExportDataToEzbookkeepingTSVHandler returns exported data in csv format
-func (a *DataManagementsApi) ExportDataToEzbookkeepingTSVHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
-	return a.getExportedFileContent(c, "tsv")
+// ExportDataToEzbookkeepingTSVHandler returns exported data in tsv format
+func (a *DataManagementsApi) ExportDataToEzbookkeepingTSVHandler(c *core.WebContext) (core.DataStreamWriterFunc, string, *errs.Error) {
+	return a.getExportedFileStreamWriter(c, "tsv", "tsv")
+}
+
+// ExportDataToOFXHandler returns exported data in open financial exchange (ofx) 2.x format
+func (a *DataManagementsApi) ExportDataToOFXHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "ofx", "ofx")
+}
+
+// ExportDataToQIFHandler returns exported data in quicken interchange format (qif)
+func (a *DataManagementsApi) ExportDataToQIFHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	dateFormat := c.Query("dateFormat")
+
+	if dateFormat == "" {
+		dateFormat = "ymd"
+	} else if dateFormat != "ymd" && dateFormat != "mdy" && dateFormat != "dmy" {
+		log.Warnf(c, "[data_managements.ExportDataToQIFHandler] date format \"%s\" is invalid", dateFormat)
+		return nil, "", errs.ErrParameterInvalid
+	}
+
+	return a.getExportedFileContent(c, "qif_"+dateFormat, "qif")
+}
+
+// ExportDataToXlsxHandler returns exported data in excel xlsx format, the accounts, categories and monthly summary sheets are optional
+func (a *DataManagementsApi) ExportDataToXlsxHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	withAccountsSheet := c.Query("withAccounts") == "true"
+	withCategoriesSheet := c.Query("withCategories") == "true"
+	withMonthlySummarySheet := c.Query("withMonthlySummary") == "true"
+	dataExporter := converters.GetXlsxTransactionDataExporter(withAccountsSheet, withCategoriesSheet, withMonthlySummarySheet)
+
+	return a.getExportedFileContentByExporter(c, dataExporter, "xlsx", "xlsx")
+}
+
+// ExportDataToBeancountHandler returns exported data in beancount journal format
+func (a *DataManagementsApi) ExportDataToBeancountHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "beancount", "beancount")
+}
+
+// ExportDataToHledgerHandler returns exported data in hledger journal format
+func (a *DataManagementsApi) ExportDataToHledgerHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	return a.getExportedFileContent(c, "hledger", "journal")
+}
+
+// ExportUserDataBackupHandler returns the user data backup archive which contains all data of current user
+func (a *DataManagementsApi) ExportUserDataBackupHandler(c *core.WebContext) ([]byte, string, *errs.Error) {
+	if !a.CurrentConfig().EnableDataExport {
+		return nil, "", errs.ErrDataExportNotAllowed
+	}
+
+	timezone := time.Local
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[data_managements.ExportUserDataBackupHandler] cannot get client timezone offset, because %s", err.Error())
+	} else {
+		timezone = time.FixedZone("Client Timezone", int(utcOffset)*60)
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[data_managements.ExportUserDataBackupHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, "", errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_EXPORT_TRANSACTION) {
+		return nil, "", errs.ErrNotPermittedToPerformThisAction
+	}
+
+	result, err := a.backups.ExportUserDataBackupArchive(c, user)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ExportUserDataBackupHandler] failed to export user data backup for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	fileName := a.getFileName(user, timezone, "zip")
+
+	return result, fileName, nil
+}
+
+// RestoreUserDataBackupHandler restores all data in the uploaded user data backup archive to current user who does not have any data
+func (a *DataManagementsApi) RestoreUserDataBackupHandler(c *core.WebContext) (any, *errs.Error) {
+	if !a.CurrentConfig().EnableDataImport {
+		return nil, errs.ErrDataImportNotAllowed
+	}
+
+	uid := c.GetCurrentUid()
+	form, err := c.MultipartForm()
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.RestoreUserDataBackupHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrParameterInvalid
+	}
+
+	backupFiles := form.File["file"]
+
+	if len(backupFiles) < 1 {
+		log.Warnf(c, "[data_managements.RestoreUserDataBackupHandler] there is no backup file in request for user \"uid:%d\"", uid)
+		return nil, errs.ErrNoFilesUpload
+	}
+
+	if backupFiles[0].Size < 1 {
+		log.Warnf(c, "[data_managements.RestoreUserDataBackupHandler] the size of backup file in request is zero for user \"uid:%d\"", uid)
+		return nil, errs.ErrUploadedFileEmpty
+	}
+
+	if backupFiles[0].Size > int64(a.CurrentConfig().MaxImportFileSize) {
+		log.Warnf(c, "[data_managements.RestoreUserDataBackupHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of import file for user \"uid:%d\"", backupFiles[0].Size, a.CurrentConfig().MaxImportFileSize, uid)
+		return nil, errs.ErrExceedMaxUploadFileSize
+	}
+
+	backupFile, err := backupFiles[0].Open()
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.RestoreUserDataBackupHandler] failed to get backup file from request for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer backupFile.Close()
+
+	fileData, err := io.ReadAll(backupFile)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.RestoreUserDataBackupHandler] failed to read backup file data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Warnf(c, "[data_managements.RestoreUserDataBackupHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	backup, err := a.backups.RestoreUserDataBackupArchive(c, user, fileData)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.RestoreUserDataBackupHandler] failed to restore user data backup for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[data_managements.RestoreUserDataBackupHandler] user \"uid:%d\" has restored %d accounts and %d transactions from backup", uid, len(backup.Accounts), len(backup.Transactions))
+
+	return backup.ToUserDataBackupRestoreResponse(), nil
 }
 
 // DataStatisticsHandler returns user data statistics
@@ -151,6 +323,48 @@ func (a *DataManagementsApi) ClearDataHandler(c *core.WebContext) (any, *errs.Er
 		return nil, errs.ErrNotPermittedToPerformThisAction
 	}
 
+	err = a.customRates.DeleteAllCustomExchangeRates(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearDataHandler] failed to delete all custom exchange rates, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.importProfiles.DeleteAllProfiles(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearDataHandler] failed to delete all transaction import profiles, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.importBatches.DeleteAllBatches(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearDataHandler] failed to delete all transaction import batches, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.importJobs.DeleteAllJobs(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearDataHandler] failed to delete all transaction import jobs, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.budgets.DeleteAllBudgets(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearDataHandler] failed to delete all budgets, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.rules.DeleteAllRules(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ClearDataHandler] failed to delete all transaction rules, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	err = a.templates.DeleteAllTemplates(c, uid)
 
 	if err != nil {
@@ -183,9 +397,120 @@ func (a *DataManagementsApi) ClearDataHandler(c *core.WebContext) (any, *errs.Er
 	return true, nil
 }
 
-func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType string) ([]byte, string, *errs.Error) {
+func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType string, fileExtension string) ([]byte, string, *errs.Error) {
+	dataExporter := converters.GetTransactionDataExporter(fileType)
+
+	if dataExporter == nil {
+		return nil, "", errs.ErrNotImplemented
+	}
+
+	return a.getExportedFileContentByExporter(c, dataExporter, fileType, fileExtension)
+}
+
+func (a *DataManagementsApi) getExportedFileContentByExporter(c *core.WebContext, dataExporter base.TransactionDataExporter, fileType string, fileExtension string) ([]byte, string, *errs.Error) {
+	exportContext, exportErr := a.prepareTransactionDataExport(c)
+
+	if exportErr != nil {
+		return nil, "", exportErr
+	}
+
+	var allTransactions []*models.Transaction
+	allTagIndexes := make(map[int64][]int64)
+	allSplits := make(map[int64]models.TransactionSplitSlice)
+
+	err := a.iterateExportedTransactions(c, exportContext, func(transactions []*models.Transaction, tagIndexes map[int64][]int64, splits map[int64]models.TransactionSplitSlice) error {
+		allTransactions = append(allTransactions, transactions...)
+
+		for transactionId, tagIds := range tagIndexes {
+			allTagIndexes[transactionId] = tagIds
+		}
+
+		for transactionId, transactionSplits := range splits {
+			allSplits[transactionId] = transactionSplits
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get transactions for user \"uid:%d\", because %s", exportContext.uid, err.Error())
+		return nil, "", errs.ErrOperationFailed
+	}
+
+	result, err := dataExporter.ToExportedContent(c, exportContext.uid, allTransactions, exportContext.accountMap, exportContext.categoryMap, exportContext.tagMap, allTagIndexes, allSplits)
+
+	if err != nil {
+		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get %s format exported data for \"uid:%d\", because %s", fileType, exportContext.uid, err.Error())
+		return nil, "", errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	fileName := a.getFileName(exportContext.user, exportContext.timezone, fileExtension)
+
+	return result, fileName, nil
+}
+
+func (a *DataManagementsApi) getExportedFileStreamWriter(c *core.WebContext, fileType string, fileExtension string) (core.DataStreamWriterFunc, string, *errs.Error) {
+	exportContext, exportErr := a.prepareTransactionDataExport(c)
+
+	if exportErr != nil {
+		return nil, "", exportErr
+	}
+
+	dataExporter, ok := converters.GetTransactionDataExporter(fileType).(base.TransactionDataStreamExporter)
+
+	if !ok {
+		return nil, "", errs.ErrNotImplemented
+	}
+
+	writer := func(writer io.Writer) error {
+		err := dataExporter.WriteExportedHeader(c, writer)
+
+		if err != nil {
+			return err
+		}
+
+		return a.iterateExportedTransactions(c, exportContext, func(transactions []*models.Transaction, tagIndexes map[int64][]int64, splits map[int64]models.TransactionSplitSlice) error {
+			return dataExporter.WriteExportedContent(c, writer, exportContext.uid, transactions, exportContext.accountMap, exportContext.categoryMap, exportContext.tagMap, tagIndexes, splits)
+		})
+	}
+
+	fileName := a.getFileName(exportContext.user, exportContext.timezone, fileExtension)
+
+	return writer, fileName, nil
+}
+
+// transactionDataExportContext represents the user, the filter conditions and the metadata which are required for exporting transactions
+type transactionDataExportContext struct {
+	uid            int64
+	user           *models.User
+	timezone       *time.Location
+	exportDataReq  *models.ExportDataRequest
+	minTime        int64
+	maxTime        int64
+	allAccountIds  []int64
+	allCategoryIds []int64
+	allTagIds      []int64
+	noTags         bool
+	accountMap     map[int64]*models.Account
+	categoryMap    map[int64]*models.TransactionCategory
+	tagMap         map[int64]*models.TransactionTag
+}
+
+func (a *DataManagementsApi) prepareTransactionDataExport(c *core.WebContext) (*transactionDataExportContext, *errs.Error) {
 	if !a.CurrentConfig().EnableDataExport {
-		return nil, "", errs.ErrDataExportNotAllowed
+		return nil, errs.ErrDataExportNotAllowed
+	}
+
+	var exportDataReq models.ExportDataRequest
+	err := c.ShouldBindQuery(&exportDataReq)
+
+	if err != nil {
+		log.Warnf(c, "[data_managements.ExportDataHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if exportDataReq.EndTime > 0 && exportDataReq.StartTime > exportDataReq.EndTime {
+		return nil, errs.ErrQueryItemsInvalid
 	}
 
 	timezone := time.Local
@@ -205,68 +530,140 @@ func (a *DataManagementsApi) getExportedFileContent(c *core.WebContext, fileType
 			log.Warnf(c, "[data_managements.ExportDataHandler] failed to get user for user \"uid:%d\", because %s", uid, err.Error())
 		}
 
-		return nil, "", errs.ErrUserNotFound
+		return nil, errs.ErrUserNotFound
 	}
 
 	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_EXPORT_TRANSACTION) {
-		return nil, "", errs.ErrNotPermittedToPerformThisAction
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	allAccountIds, err := Transactions.getAccountOrSubAccountIds(c, exportDataReq.AccountIds, uid)
+
+	if err != nil {
+		log.Warnf(c, "[data_managements.ExportDataHandler] get account error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allCategoryIds, err := Transactions.getCategoryOrSubCategoryIds(c, exportDataReq.CategoryIds, uid)
+
+	if err != nil {
+		log.Warnf(c, "[data_managements.ExportDataHandler] get transaction category error, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	var allTagIds []int64
+	noTags := exportDataReq.TagIds == "none"
+
+	if !noTags {
+		allTagIds, err = Transactions.getTagIds(exportDataReq.TagIds)
+
+		if err != nil {
+			log.Warnf(c, "[data_managements.ExportDataHandler] get transaction tag ids error, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
 	}
 
 	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get all accounts for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
+		return nil, errs.ErrOperationFailed
 	}
 
 	categories, err := a.categories.GetAllCategoriesByUid(c, uid, 0, -1)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get categories for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
+		return nil, errs.ErrOperationFailed
 	}
 
 	tags, err := a.tags.GetAllTagsByUid(c, uid)
 
 	if err != nil {
 		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get tags for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
+		return nil, errs.ErrOperationFailed
 	}
 
-	tagIndexes, err := a.tags.GetAllTagIdsMapOfAllTransactions(c, uid)
+	maxTime := utils.GetMaxTransactionTimeFromUnixTime(time.Now().Unix())
+	minTime := int64(0)
 
-	if err != nil {
-		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get tag index for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
+	if exportDataReq.EndTime > 0 {
+		maxTime = utils.GetMaxTransactionTimeFromUnixTime(exportDataReq.EndTime)
 	}
 
-	accountMap := a.accounts.GetAccountMapByList(accounts)
-	categoryMap := a.categories.GetCategoryMapByList(categories)
-	tagMap := a.tags.GetTagMapByList(tags)
+	if exportDataReq.StartTime > 0 {
+		minTime = utils.GetMinTransactionTimeFromUnixTime(exportDataReq.StartTime)
+	}
 
-	allTransactions, err := a.transactions.GetAllTransactions(c, uid, pageCountForDataExport, true)
+	return &transactionDataExportContext{
+		uid:            uid,
+		user:           user,
+		timezone:       timezone,
+		exportDataReq:  &exportDataReq,
+		minTime:        minTime,
+		maxTime:        maxTime,
+		allAccountIds:  allAccountIds,
+		allCategoryIds: allCategoryIds,
+		allTagIds:      allTagIds,
+		noTags:         noTags,
+		accountMap:     a.accounts.GetAccountMapByList(accounts),
+		categoryMap:    a.categories.GetCategoryMapByList(categories),
+		tagMap:         a.tags.GetTagMapByList(tags),
+	}, nil
+}
 
-	if err != nil {
-		log.Errorf(c, "[data_managements.ExportDataHandler] failed to all transactions user \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.ErrOperationFailed
-	}
+func (a *DataManagementsApi) iterateExportedTransactions(c *core.WebContext, exportContext *transactionDataExportContext, fn func(transactions []*models.Transaction, tagIndexes map[int64][]int64, splits map[int64]models.TransactionSplitSlice) error) error {
+	exportDataReq := exportContext.exportDataReq
+	maxTime := exportContext.maxTime
 
-	dataExporter := converters.GetTransactionDataExporter(fileType)
+	for maxTime > 0 {
+		transactions, err := a.transactions.GetTransactionsByMaxTime(c, exportContext.uid, maxTime, exportContext.minTime, exportDataReq.Type, exportContext.allCategoryIds, exportContext.allAccountIds, exportContext.allTagIds, exportContext.noTags, exportDataReq.TagFilterType, exportDataReq.AmountFilter, exportDataReq.Keyword, 1, pageCountForDataExport, false, true)
 
-	if dataExporter == nil {
-		return nil, "", errs.ErrNotImplemented
-	}
+		if err != nil {
+			return err
+		}
 
-	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexes)
+		if len(transactions) < 1 {
+			break
+		}
 
-	if err != nil {
-		log.Errorf(c, "[data_managements.ExportDataHandler] failed to get csv format exported data for \"uid:%d\", because %s", uid, err.Error())
-		return nil, "", errs.Or(err, errs.ErrOperationFailed)
-	}
+		if len(transactions) < pageCountForDataExport {
+			maxTime = 0
+		} else {
+			maxTime = transactions[len(transactions)-1].TransactionTime - 1
+		}
 
-	fileName := a.getFileName(user, timezone, fileType)
+		transactionIds := make([]int64, 0, len(transactions))
 
-	return result, fileName, nil
+		for i := 0; i < len(transactions); i++ {
+			// the transfer in transaction would be skipped by exporters, so use the related transfer out transaction instead
+			if transactions[i].Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+				transactions[i] = a.transactions.GetRelatedTransferTransaction(transactions[i])
+			}
+
+			transactionIds = append(transactionIds, transactions[i].TransactionId)
+		}
+
+		tagIndexes, err := a.tags.GetAllTagIdsOfTransactions(c, exportContext.uid, transactionIds)
+
+		if err != nil {
+			return err
+		}
+
+		splits, err := a.splits.GetSplitsByTransactionIds(c, exportContext.uid, transactionIds)
+
+		if err != nil {
+			return err
+		}
+
+		err = fn(transactions, tagIndexes, splits)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (a *DataManagementsApi) getFileName(user *models.User, timezone *time.Location, fileExtension string) string {
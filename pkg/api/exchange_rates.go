@@ -1,11 +1,6 @@
 package api
 
 import (
-	"crypto/tls"
-	"fmt"
-	"io"
-	"net/http"
-	"sort"
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
@@ -13,13 +8,15 @@ import (
 	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
-	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // ExchangeRatesApi represents exchange rate api
 type ExchangeRatesApi struct {
 	ApiUsingConfig
+	exchangeRates           *services.ExchangeRateService
+	userCustomExchangeRates *services.UserCustomExchangeRateService
 }
 
 // Initialize a exchange rate api singleton instance
@@ -28,104 +25,60 @@ var (
 		ApiUsingConfig: ApiUsingConfig{
 			container: settings.Container,
 		},
+		exchangeRates:           services.ExchangeRates,
+		userCustomExchangeRates: services.UserCustomExchangeRates,
 	}
 )
 
 // LatestExchangeRateHandler returns latest exchange rate data
 func (a *ExchangeRatesApi) LatestExchangeRateHandler(c *core.WebContext) (any, *errs.Error) {
-	dataSource := exchangerates.Container.Current
-
-	if dataSource == nil {
-		return nil, errs.ErrInvalidExchangeRatesDataSource
-	}
-
 	uid := c.GetCurrentUid()
+	exchangeRateResponse, err := exchangerates.Container.GetLatestExchangeRates(c, uid, a.CurrentConfig())
 
-	transport := http.DefaultTransport.(*http.Transport).Clone()
-	utils.SetProxyUrl(transport, a.CurrentConfig().ExchangeRatesProxy)
-
-	if a.CurrentConfig().ExchangeRatesSkipTLSVerify {
-		transport.TLSClientConfig = &tls.Config{
-			InsecureSkipVerify: true,
-		}
-	}
-
-	client := &http.Client{
-		Transport: transport,
-		Timeout:   time.Duration(a.CurrentConfig().ExchangeRatesRequestTimeout) * time.Millisecond,
+	if err != nil {
+		return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
 	}
 
-	requests, err := dataSource.BuildRequests()
+	today := time.Now().In(time.UTC).Format(models.UserCustomExchangeRateDateFormat)
+	customExchangeRates, err := a.userCustomExchangeRates.GetEffectiveCustomExchangeRatesByUid(c, uid, today)
 
 	if err != nil {
-		log.Errorf(c, "[exchange_rates.LatestExchangeRateHandler] failed to build requests for user \"uid:%d\", because %s", uid, err.Error())
-		return nil, errs.ErrFailedToRequestRemoteApi
+		log.Errorf(c, "[exchange_rates.LatestExchangeRateHandler] failed to get custom exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(requests))
-
-	for i := 0; i < len(requests); i++ {
-		req := requests[i]
-		req.Header.Set("User-Agent", fmt.Sprintf("ezBookkeeping/%s ", settings.Version))
-
-		resp, err := client.Do(req)
-
-		if err != nil {
-			log.Errorf(c, "[exchange_rates.LatestExchangeRateHandler] failed to request latest exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.ErrFailedToRequestRemoteApi
-		}
-
-		if resp.StatusCode != 200 {
-			log.Errorf(c, "[exchange_rates.LatestExchangeRateHandler] failed to get latest exchange rate data response for user \"uid:%d\", because response code is not 200", uid)
-			return nil, errs.ErrFailedToRequestRemoteApi
-		}
-
-		defer resp.Body.Close()
-		body, err := io.ReadAll(resp.Body)
-
-		log.Debugf(c, "[exchange_rates.LatestExchangeRateHandler] response#%d is %s", i, body)
-
-		exchangeRateResp, err := dataSource.Parse(c, body)
-
-		if err != nil {
-			log.Errorf(c, "[exchange_rates.LatestExchangeRateHandler] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
-			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
-		}
-
-		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	if len(customExchangeRates) > 0 {
+		exchangeRateResponse = exchangeRateResponse.MergeUserCustomExchangeRates(customExchangeRates)
 	}
 
-	lastExchangeRateResponse := exchangeRateResps[len(exchangeRateResps)-1]
-	allExchangeRatesMap := make(map[string]string)
+	return exchangeRateResponse, nil
+}
 
-	for i := 0; i < len(exchangeRateResps); i++ {
-		exchangeRateResp := exchangeRateResps[i]
+// HistoricalExchangeRateHandler returns the exchange rate of specified currency pair at specified time
+func (a *ExchangeRatesApi) HistoricalExchangeRateHandler(c *core.WebContext) (any, *errs.Error) {
+	var exchangeRateReq models.HistoricalExchangeRateRequest
+	err := c.ShouldBindQuery(&exchangeRateReq)
 
-		for j := 0; j < len(exchangeRateResp.ExchangeRates); j++ {
-			exchangeRate := exchangeRateResp.ExchangeRates[j]
-			allExchangeRatesMap[exchangeRate.Currency] = exchangeRate.Rate
-		}
+	if err != nil {
+		log.Warnf(c, "[exchange_rates.HistoricalExchangeRateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
-	allExchangeRatesMap[lastExchangeRateResponse.BaseCurrency] = "1"
-	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(allExchangeRatesMap))
+	uid := c.GetCurrentUid()
+	date := time.Unix(exchangeRateReq.Time, 0).In(time.UTC).Format(models.UserCustomExchangeRateDateFormat)
+	customExchangeRates, err := a.userCustomExchangeRates.GetEffectiveCustomExchangeRatesByUid(c, uid, date)
 
-	for currency, rate := range allExchangeRatesMap {
-		allExchangeRates = append(allExchangeRates, &models.LatestExchangeRate{
-			Currency: currency,
-			Rate:     rate,
-		})
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.HistoricalExchangeRateHandler] failed to get custom exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	sort.Sort(allExchangeRates)
+	exchangeRateResponse, err := a.exchangeRates.GetExchangeRate(c, exchangeRateReq.FromCurrency, exchangeRateReq.ToCurrency, exchangeRateReq.Time, customExchangeRates)
 
-	finalExchangeRateResponse := &models.LatestExchangeRateResponse{
-		DataSource:    lastExchangeRateResponse.DataSource,
-		ReferenceUrl:  lastExchangeRateResponse.ReferenceUrl,
-		UpdateTime:    lastExchangeRateResponse.UpdateTime,
-		BaseCurrency:  lastExchangeRateResponse.BaseCurrency,
-		ExchangeRates: allExchangeRates,
+	if err != nil {
+		log.Errorf(c, "[exchange_rates.HistoricalExchangeRateHandler] failed to get exchange rate from \"%s\" to \"%s\" at %d for user \"uid:%d\", because %s", exchangeRateReq.FromCurrency, exchangeRateReq.ToCurrency, exchangeRateReq.Time, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	return finalExchangeRateResponse, nil
+	return exchangeRateResponse, nil
 }
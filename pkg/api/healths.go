@@ -3,15 +3,23 @@ package api
 import (
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // HealthsApi represents health api
-type HealthsApi struct{}
+type HealthsApi struct {
+	ApiUsingConfig
+}
 
 // Initialize a healths api singleton instance
 var (
-	Healths = &HealthsApi{}
+	Healths = &HealthsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+	}
 )
 
 // HealthStatusHandler returns the health status of current service
@@ -22,5 +30,12 @@ func (a *HealthsApi) HealthStatusHandler(c *core.WebContext) (any, *errs.Error)
 	result["commit"] = settings.CommitHash
 	result["status"] = "ok"
 
+	exchangeRatesCacheStatus, exchangeRatesCacheAge := exchangerates.Container.GetCacheStatus(a.CurrentConfig())
+	result["exchangeRatesCacheStatus"] = exchangeRatesCacheStatus
+
+	if exchangeRatesCacheStatus != "disabled" && exchangeRatesCacheStatus != "empty" {
+		result["exchangeRatesCacheAge"] = utils.Int64ToString(exchangeRatesCacheAge)
+	}
+
 	return result, nil
 }
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+// TransactionImportBatchesApi represents transaction import batch api
+type TransactionImportBatchesApi struct {
+	importBatches *services.TransactionImportBatchService
+	users         *services.UserService
+}
+
+// Initialize a transaction import batch api singleton instance
+var (
+	TransactionImportBatches = &TransactionImportBatchesApi{
+		importBatches: services.TransactionImportBatches,
+		users:         services.Users,
+	}
+)
+
+// ImportBatchListHandler returns transaction import batch list of current user
+func (a *TransactionImportBatchesApi) ImportBatchListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	batches, err := a.importBatches.GetAllBatchesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_batches.ImportBatchListHandler] failed to get transaction import batches for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	batchResps := make(models.TransactionImportBatchInfoResponseSlice, len(batches))
+
+	for i := 0; i < len(batches); i++ {
+		batchResps[i] = batches[i].ToTransactionImportBatchInfoResponse()
+	}
+
+	sort.Sort(batchResps)
+
+	return batchResps, nil
+}
+
+// ImportBatchRevertHandler deletes all transactions of one specific import batch and the unused accounts and categories created by it for current user
+func (a *TransactionImportBatchesApi) ImportBatchRevertHandler(c *core.WebContext) (any, *errs.Error) {
+	var batchRevertReq models.TransactionImportBatchRevertRequest
+	err := c.ShouldBindJSON(&batchRevertReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_batches.ImportBatchRevertHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_batches.ImportBatchRevertHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transaction_import_batches.ImportBatchRevertHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	result, err := a.importBatches.RevertBatch(c, user, batchRevertReq.Id, utcOffset)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_batches.ImportBatchRevertHandler] failed to revert transaction import batch \"id:%d\" for user \"uid:%d\", because %s", batchRevertReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_import_batches.ImportBatchRevertHandler] user \"uid:%d\" has reverted transaction import batch \"id:%d\" successfully", uid, batchRevertReq.Id)
+
+	return result, nil
+}
@@ -0,0 +1,711 @@
+package api
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const transactionImportJobChunkSize = 500
+const transactionImportJobErrorMessageMaxLength = 255
+const transactionImportJobNewItemIcon = 1
+const transactionImportJobNewItemColor = "000000"
+
+// TransactionImportJobsApi represents transaction import job api
+type TransactionImportJobsApi struct {
+	ApiUsingConfig
+	importJobs            *services.TransactionImportJobService
+	importProfiles        *services.TransactionImportProfileService
+	transactions          *services.TransactionService
+	transactionCategories *services.TransactionCategoryService
+	transactionTags       *services.TransactionTagService
+	transactionRules      *services.TransactionRuleService
+	accounts              *services.AccountService
+	users                 *services.UserService
+}
+
+// Initialize a transaction import job api singleton instance
+var (
+	TransactionImportJobs = &TransactionImportJobsApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		importJobs:            services.TransactionImportJobs,
+		importProfiles:        services.TransactionImportProfiles,
+		transactions:          services.Transactions,
+		transactionCategories: services.TransactionCategories,
+		transactionTags:       services.TransactionTags,
+		transactionRules:      services.TransactionRules,
+		accounts:              services.Accounts,
+		users:                 services.Users,
+	}
+)
+
+// ImportJobListHandler returns transaction import job list of current user
+func (a *TransactionImportJobsApi) ImportJobListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	jobs, err := a.importJobs.GetAllJobsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_jobs.ImportJobListHandler] failed to get transaction import jobs for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	now := time.Now().Unix()
+	jobResps := make(models.TransactionImportJobInfoResponseSlice, len(jobs))
+
+	for i := 0; i < len(jobs); i++ {
+		jobResps[i] = jobs[i].ToTransactionImportJobInfoResponse(now)
+	}
+
+	sort.Sort(jobResps)
+
+	return jobResps, nil
+}
+
+// ImportJobGetHandler returns the status and progress of one specific transaction import job of current user
+func (a *TransactionImportJobsApi) ImportJobGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var jobGetReq models.TransactionImportJobGetRequest
+	err := c.ShouldBindQuery(&jobGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_jobs.ImportJobGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	job, err := a.importJobs.GetJobByJobId(c, uid, jobGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_jobs.ImportJobGetHandler] failed to get transaction import job \"id:%d\" for user \"uid:%d\", because %s", jobGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return job.ToTransactionImportJobInfoResponse(time.Now().Unix()), nil
+}
+
+// ImportJobCreateHandler saves the uploaded import file as a new transaction import job and starts importing it in background for current user
+func (a *TransactionImportJobsApi) ImportJobCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	form, err := c.MultipartForm()
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_jobs.ImportJobCreateHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrParameterInvalid
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_jobs.ImportJobCreateHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	fileType, dataImporter, errResp := getTransactionDataImporterByForm(c, a.importProfiles, uid, form)
+
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	skipLikelyDuplicates := false
+
+	if values := form.Value["skipLikelyDuplicates"]; len(values) > 0 && values[0] == "true" {
+		skipLikelyDuplicates = true
+	}
+
+	importFiles := form.File["file"]
+
+	if len(importFiles) < 1 {
+		log.Warnf(c, "[transaction_import_jobs.ImportJobCreateHandler] there is no import file in request for user \"uid:%d\"", uid)
+		return nil, errs.ErrNoFilesUpload
+	}
+
+	if importFiles[0].Size < 1 {
+		log.Warnf(c, "[transaction_import_jobs.ImportJobCreateHandler] the size of import file in request is zero for user \"uid:%d\"", uid)
+		return nil, errs.ErrUploadedFileEmpty
+	}
+
+	if importFiles[0].Size > int64(a.CurrentConfig().MaxImportJobFileSize) {
+		log.Warnf(c, "[transaction_import_jobs.ImportJobCreateHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of import job file for user \"uid:%d\"", importFiles[0].Size, a.CurrentConfig().MaxImportJobFileSize, uid)
+		return nil, errs.ErrExceedMaxUploadFileSize
+	}
+
+	importFile, err := importFiles[0].Open()
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_jobs.ImportJobCreateHandler] failed to get import file from request for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer importFile.Close()
+
+	fileData, err := io.ReadAll(importFile)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_jobs.ImportJobCreateHandler] failed to read import file data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		if !errs.IsCustomError(err) {
+			log.Errorf(c, "[transaction_import_jobs.ImportJobCreateHandler] failed to get user, because %s", err.Error())
+		}
+
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.FeatureRestriction.Contains(core.USER_FEATURE_RESTRICTION_TYPE_IMPORT_TRANSACTION) {
+		return nil, errs.ErrNotPermittedToPerformThisAction
+	}
+
+	job := &models.TransactionImportJob{
+		Uid:                  uid,
+		FileType:             fileType,
+		FileName:             utils.SubString(importFiles[0].Filename, 0, 255),
+		SkipLikelyDuplicates: skipLikelyDuplicates,
+	}
+
+	err = a.importJobs.CreateJob(c, job)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_jobs.ImportJobCreateHandler] failed to create transaction import job for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_import_jobs.ImportJobCreateHandler] user \"uid:%d\" has created transaction import job \"id:%d\" for \"%s\" file (%d bytes)", uid, job.JobId, fileType, len(fileData))
+
+	jobResp := job.ToTransactionImportJobInfoResponse(job.CreatedUnixTime)
+	clientIp := c.ClientIP()
+
+	// the import job should not be cancelled when client disconnects, so it uses a new context instead of the request context
+	go a.runImportJob(core.NewNullContext(), job, user, dataImporter, fileData, utcOffset, clientIp)
+
+	return jobResp, nil
+}
+
+func (a *TransactionImportJobsApi) runImportJob(c core.Context, job *models.TransactionImportJob, user *models.User, dataImporter base.TransactionDataImporter, fileData []byte, utcOffset int16, clientIp string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf(c, "[transaction_import_jobs.runImportJob] transaction import job \"id:%d\" of user \"uid:%d\" crashed, because %v", job.JobId, user.Uid, r)
+			a.failImportJob(c, job, errs.ErrOperationFailed)
+		}
+	}()
+
+	job.Status = models.TRANSACTION_IMPORT_JOB_STATUS_PARSING
+	a.updateImportJobProgress(c, job)
+
+	importBatch := &models.TransactionImportBatch{
+		FileType: job.FileType,
+		FileName: job.FileName,
+	}
+
+	parsedTransactions, err := a.parseImportJobData(c, user, dataImporter, fileData, utcOffset, job.SkipLikelyDuplicates, importBatch)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_jobs.runImportJob] failed to parse data of transaction import job \"id:%d\" for user \"uid:%d\", because %s", job.JobId, user.Uid, err.Error())
+		a.failImportJob(c, job, err)
+		return
+	}
+
+	rules, err := a.transactionRules.GetAllRulesByUid(c, user.Uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_jobs.runImportJob] failed to get transaction rules for user \"uid:%d\", because %s", user.Uid, err.Error())
+		a.failImportJob(c, job, err)
+		return
+	}
+
+	validTransactions := make(models.ImportedTransactionSlice, 0, len(parsedTransactions))
+	validTransactionRowIndexes := make([]int, 0, len(parsedTransactions))
+	job.TotalCount = int32(len(parsedTransactions))
+
+	for i := 0; i < len(parsedTransactions); i++ {
+		transaction := parsedTransactions[i]
+
+		if job.SkipLikelyDuplicates && transaction.LikelyDuplicate {
+			job.SkippedDuplicateCount++
+			continue
+		}
+
+		if rowErr := a.prepareImportJobTransaction(transaction, user, rules, clientIp); rowErr != nil {
+			job.AddRowError(i, transaction, rowErr.Message)
+			continue
+		}
+
+		validTransactions = append(validTransactions, transaction)
+		validTransactionRowIndexes = append(validTransactionRowIndexes, i)
+	}
+
+	job.ProcessedCount = job.SkippedDuplicateCount + job.FailedCount
+	job.Status = models.TRANSACTION_IMPORT_JOB_STATUS_IMPORTING
+	a.updateImportJobProgress(c, job)
+
+	for i := 0; i < len(validTransactions); i += transactionImportJobChunkSize {
+		end := i + transactionImportJobChunkSize
+
+		if end > len(validTransactions) {
+			end = len(validTransactions)
+		}
+
+		chunkTransactions := validTransactions[i:end]
+		err := a.importImportJobTransactions(c, user.Uid, chunkTransactions, importBatch)
+
+		if err != nil && a.isImportJobRowError(err) {
+			// the chunk is rejected by the data of some rows, so import the transactions in this chunk one by one and record the failed rows
+			log.Warnf(c, "[transaction_import_jobs.runImportJob] failed to import transactions %d - %d of transaction import job \"id:%d\" for user \"uid:%d\", because %s, retry importing them one by one", i, end-1, job.JobId, user.Uid, err.Error())
+
+			for j := 0; j < len(chunkTransactions); j++ {
+				err = a.importImportJobTransactions(c, user.Uid, chunkTransactions[j:j+1], importBatch)
+
+				if err != nil && !a.isImportJobRowError(err) {
+					break
+				} else if err != nil {
+					job.AddRowError(validTransactionRowIndexes[i+j], chunkTransactions[j], errs.Or(err, errs.ErrOperationFailed).Message)
+				} else {
+					job.ImportedCount++
+				}
+
+				job.ProcessedCount++
+				err = nil
+			}
+		} else if err == nil {
+			job.ImportedCount += int32(len(chunkTransactions))
+			job.ProcessedCount += int32(len(chunkTransactions))
+		}
+
+		if err != nil {
+			log.Errorf(c, "[transaction_import_jobs.runImportJob] failed to import transactions %d - %d of transaction import job \"id:%d\" for user \"uid:%d\", because %s", i, end-1, job.JobId, user.Uid, err.Error())
+			job.ImportBatchId = importBatch.BatchId
+			a.failImportJob(c, job, err)
+			return
+		}
+
+		job.ImportBatchId = importBatch.BatchId
+		a.updateImportJobProgress(c, job)
+	}
+
+	job.Status = models.TRANSACTION_IMPORT_JOB_STATUS_SUCCEEDED
+	a.updateImportJobProgress(c, job)
+
+	log.Infof(c, "[transaction_import_jobs.runImportJob] transaction import job \"id:%d\" of user \"uid:%d\" has finished, %d transactions imported, %d duplicate transactions skipped, %d transactions failed", job.JobId, user.Uid, job.ImportedCount, job.SkippedDuplicateCount, job.FailedCount)
+}
+
+func (a *TransactionImportJobsApi) parseImportJobData(c core.Context, user *models.User, dataImporter base.TransactionDataImporter, fileData []byte, utcOffset int16, markLikelyDuplicates bool, importBatch *models.TransactionImportBatch) (models.ImportedTransactionSlice, error) {
+	accounts, err := a.accounts.GetAllAccountsByUid(c, user.Uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accountMap := a.accounts.GetAccountNameMapByList(accounts)
+
+	categories, err := a.transactionCategories.GetAllCategoriesByUid(c, user.Uid, 0, -1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expenseCategoryMap, incomeCategoryMap, transferCategoryMap := a.transactionCategories.GetCategoryNameMapByList(categories)
+
+	tags, err := a.transactionTags.GetAllTagsByUid(c, user.Uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tagMap := a.transactionTags.GetTagNameMapByList(tags)
+
+	parsedTransactions, newAccounts, _, _, _, newTags, err := dataImporter.ParseImportedData(c, user, fileData, utcOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(parsedTransactions) < 1 {
+		return nil, errs.ErrNoDataToImport
+	}
+
+	err = a.createImportJobNewItems(c, user, utcOffset, parsedTransactions, categories, newAccounts, newTags, importBatch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if markLikelyDuplicates {
+		_, err = a.transactions.MarkLikelyDuplicateImportTransactions(c, user.Uid, parsedTransactions)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return parsedTransactions, nil
+}
+
+func (a *TransactionImportJobsApi) createImportJobNewItems(c core.Context, user *models.User, utcOffset int16, parsedTransactions models.ImportedTransactionSlice, categories []*models.TransactionCategory, newAccounts []*models.Account, newTags []*models.TransactionTag, importBatch *models.TransactionImportBatch) error {
+	newSubCategories, newSubCategoryKeys, parentCategoryNames := a.getImportJobNewSubCategories(user, parsedTransactions)
+
+	existedPrimaryCategories := make(map[models.TransactionCategoryType]map[string]*models.TransactionCategory, len(newSubCategories))
+	newPrimaryCategoryNames := make(map[models.TransactionCategoryType]map[string]bool, len(newSubCategories))
+	newCategoryCount := 0
+
+	for i := 0; i < len(categories); i++ {
+		category := categories[i]
+
+		if category.ParentCategoryId != models.LevelOneTransactionParentId {
+			continue
+		}
+
+		if _, exists := existedPrimaryCategories[category.Type]; !exists {
+			existedPrimaryCategories[category.Type] = make(map[string]*models.TransactionCategory)
+		}
+
+		existedPrimaryCategories[category.Type][category.Name] = category
+	}
+
+	for categoryType, subCategories := range newSubCategories {
+		newPrimaryCategoryNames[categoryType] = make(map[string]bool)
+
+		for i := 0; i < len(subCategories); i++ {
+			parentCategoryName := a.getImportJobNewPrimaryCategoryName(parentCategoryNames[categoryType], newSubCategoryKeys[categoryType][i], subCategories[i].Name)
+
+			if _, exists := existedPrimaryCategories[categoryType][parentCategoryName]; exists {
+				newCategoryCount++
+			} else if !newPrimaryCategoryNames[categoryType][parentCategoryName] {
+				newPrimaryCategoryNames[categoryType][parentCategoryName] = true
+				newCategoryCount++
+			}
+		}
+	}
+
+	if len(newAccounts) > models.TRANSACTION_IMPORT_BATCH_MAX_CREATED_ITEMS || newCategoryCount > models.TRANSACTION_IMPORT_BATCH_MAX_CREATED_ITEMS {
+		return errs.ErrTransactionImportJobTooManyNewItems
+	}
+
+	newAccountMap := make(map[string]*models.Account, len(newAccounts))
+	createdAccountIds := make([]int64, 0, len(newAccounts))
+
+	for i := 0; i < len(newAccounts); i++ {
+		account := newAccounts[i]
+		newAccountMap[account.Name] = account
+
+		maxOrderId, err := a.accounts.GetMaxDisplayOrder(c, user.Uid, models.ACCOUNT_CATEGORY_CASH)
+
+		if err != nil {
+			return err
+		}
+
+		account.Uid = user.Uid
+		account.Category = models.ACCOUNT_CATEGORY_CASH
+		account.Type = models.ACCOUNT_TYPE_SINGLE_ACCOUNT
+		account.ParentAccountId = models.LevelOneAccountParentId
+		account.Name = utils.SubString(account.Name, 0, 32)
+		account.DisplayOrder = maxOrderId + 1
+		account.Icon = transactionImportJobNewItemIcon
+		account.Color = transactionImportJobNewItemColor
+		account.Extend = &models.AccountExtend{}
+
+		err = a.accounts.CreateAccounts(c, account, 0, nil, nil, utcOffset)
+
+		if err != nil {
+			return err
+		}
+
+		createdAccountIds = append(createdAccountIds, account.AccountId)
+	}
+
+	newCategoryMaps := make(map[models.TransactionCategoryType]map[string]*models.TransactionCategory, len(newSubCategories))
+	createdPrimaryCategories := make(map[models.TransactionCategoryType]map[string]*models.TransactionCategory, len(newSubCategories))
+	createdCategoryIds := make([]int64, 0, newCategoryCount)
+
+	for categoryType, subCategories := range newSubCategories {
+		newCategoryMaps[categoryType] = make(map[string]*models.TransactionCategory, len(subCategories))
+		createdPrimaryCategories[categoryType] = make(map[string]*models.TransactionCategory)
+
+		for i := 0; i < len(subCategories); i++ {
+			subCategory := subCategories[i]
+			subCategoryKey := newSubCategoryKeys[categoryType][i]
+			newCategoryMaps[categoryType][subCategoryKey] = subCategory
+
+			parentCategoryName := a.getImportJobNewPrimaryCategoryName(parentCategoryNames[categoryType], subCategoryKey, subCategory.Name)
+			parentCategory, isExistedParentCategory := existedPrimaryCategories[categoryType][parentCategoryName]
+
+			if !isExistedParentCategory {
+				parentCategory = createdPrimaryCategories[categoryType][parentCategoryName]
+			}
+
+			if parentCategory == nil {
+				maxOrderId, err := a.transactionCategories.GetMaxDisplayOrder(c, user.Uid, categoryType)
+
+				if err != nil {
+					return err
+				}
+
+				parentCategory = &models.TransactionCategory{
+					Uid:              user.Uid,
+					Name:             utils.SubString(parentCategoryName, 0, 32),
+					Type:             categoryType,
+					ParentCategoryId: models.LevelOneTransactionParentId,
+					DisplayOrder:     maxOrderId + 1,
+					Icon:             transactionImportJobNewItemIcon,
+					Color:            transactionImportJobNewItemColor,
+				}
+
+				err = a.transactionCategories.CreateCategory(c, parentCategory)
+
+				if err != nil {
+					return err
+				}
+
+				createdPrimaryCategories[categoryType][parentCategoryName] = parentCategory
+				createdCategoryIds = append(createdCategoryIds, parentCategory.CategoryId)
+			}
+
+			maxOrderId, err := a.transactionCategories.GetMaxSubCategoryDisplayOrder(c, user.Uid, categoryType, parentCategory.CategoryId)
+
+			if err != nil {
+				return err
+			}
+
+			subCategory.Uid = user.Uid
+			subCategory.Type = categoryType
+			subCategory.ParentCategoryId = parentCategory.CategoryId
+			subCategory.Name = utils.SubString(subCategory.Name, 0, 32)
+			subCategory.DisplayOrder = maxOrderId + 1
+			subCategory.Icon = transactionImportJobNewItemIcon
+			subCategory.Color = transactionImportJobNewItemColor
+
+			err = a.transactionCategories.CreateCategory(c, subCategory)
+
+			if err != nil {
+				return err
+			}
+
+			// the sub-categories under the created primary category are deleted together with the primary category when reverting the import batch
+			if isExistedParentCategory {
+				createdCategoryIds = append(createdCategoryIds, subCategory.CategoryId)
+			}
+		}
+	}
+
+	newTagMap := make(map[string]*models.TransactionTag, len(newTags))
+
+	for i := 0; i < len(newTags); i++ {
+		tag := newTags[i]
+		newTagMap[tag.Name] = tag
+
+		maxOrderId, err := a.transactionTags.GetMaxDisplayOrder(c, user.Uid)
+
+		if err != nil {
+			return err
+		}
+
+		tag.Uid = user.Uid
+		tag.Name = utils.SubString(tag.Name, 0, 32)
+		tag.DisplayOrder = maxOrderId + 1
+
+		err = a.transactionTags.CreateTag(c, tag)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(parsedTransactions); i++ {
+		a.fillImportJobNewItemIds(parsedTransactions[i], newAccountMap, newCategoryMaps, newTagMap)
+	}
+
+	importBatch.CreatedAccountIds = strings.Join(utils.Int64ArrayToStringArray(createdAccountIds), ",")
+	importBatch.CreatedCategoryIds = strings.Join(utils.Int64ArrayToStringArray(createdCategoryIds), ",")
+
+	return nil
+}
+
+func (a *TransactionImportJobsApi) fillImportJobNewItemIds(transaction *models.ImportTransaction, newAccountMap map[string]*models.Account, newCategoryMaps map[models.TransactionCategoryType]map[string]*models.TransactionCategory, newTagMap map[string]*models.TransactionTag) {
+	if account, exists := newAccountMap[transaction.OriginalSourceAccountName]; exists && transaction.AccountId <= 0 {
+		transaction.AccountId = account.AccountId
+	}
+
+	if account, exists := newAccountMap[transaction.OriginalDestinationAccountName]; exists && transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT && transaction.RelatedAccountId <= 0 {
+		transaction.RelatedAccountId = account.AccountId
+	}
+
+	if categoryType, ok := a.getImportJobTransactionCategoryType(transaction.Type); ok && transaction.CategoryId <= 0 {
+		if category, exists := newCategoryMaps[categoryType][a.getImportJobTransactionCategoryKey(transaction)]; exists {
+			transaction.CategoryId = category.CategoryId
+		}
+	}
+
+	for i := 0; i < len(transaction.TagIds) && i < len(transaction.OriginalTagNames); i++ {
+		if tagId, err := utils.StringToInt64(transaction.TagIds[i]); err == nil && tagId > 0 {
+			continue
+		}
+
+		if tag, exists := newTagMap[transaction.OriginalTagNames[i]]; exists {
+			transaction.TagIds[i] = utils.Int64ToString(tag.TagId)
+		}
+	}
+}
+
+func (a *TransactionImportJobsApi) getImportJobTransactionCategoryType(transactionType models.TransactionDbType) (models.TransactionCategoryType, bool) {
+	switch transactionType {
+	case models.TRANSACTION_DB_TYPE_EXPENSE:
+		return models.CATEGORY_TYPE_EXPENSE, true
+	case models.TRANSACTION_DB_TYPE_INCOME:
+		return models.CATEGORY_TYPE_INCOME, true
+	case models.TRANSACTION_DB_TYPE_TRANSFER_OUT:
+		return models.CATEGORY_TYPE_TRANSFER, true
+	default:
+		return 0, false
+	}
+}
+
+func (a *TransactionImportJobsApi) getImportJobNewSubCategories(user *models.User, parsedTransactions models.ImportedTransactionSlice) (map[models.TransactionCategoryType][]*models.TransactionCategory, map[models.TransactionCategoryType][]string, map[models.TransactionCategoryType]map[string]string) {
+	newSubCategories := make(map[models.TransactionCategoryType][]*models.TransactionCategory)
+	newSubCategoryKeys := make(map[models.TransactionCategoryType][]string)
+	parentCategoryNames := make(map[models.TransactionCategoryType]map[string]string)
+
+	for i := 0; i < len(parsedTransactions); i++ {
+		transaction := parsedTransactions[i]
+		categoryType, ok := a.getImportJobTransactionCategoryType(transaction.Type)
+
+		if !ok || transaction.CategoryId > 0 {
+			continue
+		}
+
+		if _, exists := parentCategoryNames[categoryType]; !exists {
+			parentCategoryNames[categoryType] = make(map[string]string)
+		}
+
+		// the key is the same as the name which is used by the data importer to match category, so the sub-categories with the same name may be different categories
+		categoryKey := a.getImportJobTransactionCategoryKey(transaction)
+
+		if _, exists := parentCategoryNames[categoryType][categoryKey]; exists {
+			continue
+		}
+
+		parentCategoryNames[categoryType][categoryKey] = transaction.OriginalParentCategoryName
+		newSubCategories[categoryType] = append(newSubCategories[categoryType], &models.TransactionCategory{
+			Uid:  user.Uid,
+			Name: transaction.OriginalCategoryName,
+			Type: categoryType,
+		})
+		newSubCategoryKeys[categoryType] = append(newSubCategoryKeys[categoryType], categoryKey)
+	}
+
+	return newSubCategories, newSubCategoryKeys, parentCategoryNames
+}
+
+func (a *TransactionImportJobsApi) getImportJobTransactionCategoryKey(transaction *models.ImportTransaction) string {
+	if transaction.OriginalCategoryKey != "" {
+		return transaction.OriginalCategoryKey
+	}
+
+	return transaction.OriginalCategoryName
+}
+
+func (a *TransactionImportJobsApi) getImportJobNewPrimaryCategoryName(parentCategoryNames map[string]string, subCategoryKey string, subCategoryName string) string {
+	if parentCategoryName := parentCategoryNames[subCategoryKey]; parentCategoryName != "" {
+		return parentCategoryName
+	}
+
+	return subCategoryName
+}
+
+func (a *TransactionImportJobsApi) importImportJobTransactions(c core.Context, uid int64, transactions models.ImportedTransactionSlice, importBatch *models.TransactionImportBatch) error {
+	isNewImportBatch := importBatch.BatchId <= 0
+	transactionTagIdsMap, err := transactions.ToTransactionTagIdsMap()
+
+	if err != nil {
+		return err
+	}
+
+	err = a.transactions.BatchCreateTransactions(c, uid, transactions.ToTransactionsList(), transactionTagIdsMap, importBatch)
+
+	if err != nil && isNewImportBatch {
+		// the import batch is not saved if the database transaction fails, so it should be created again in next import
+		importBatch.BatchId = 0
+	}
+
+	return err
+}
+
+func (a *TransactionImportJobsApi) isImportJobRowError(err error) bool {
+	return errs.IsCustomError(err) && err != errs.ErrSystemIsBusy && err != errs.ErrTransactionImportBatchNotFound
+}
+
+func (a *TransactionImportJobsApi) prepareImportJobTransaction(transaction *models.ImportTransaction, user *models.User, rules []*models.TransactionRule, clientIp string) *errs.Error {
+	if transaction.AccountId <= 0 {
+		return errs.ErrAccountNotFound
+	}
+
+	transaction.ApplyTransactionRules(rules)
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		if transaction.RelatedAccountId <= 0 {
+			return errs.ErrAccountNotFound
+		} else if transaction.AccountId == transaction.RelatedAccountId {
+			return errs.ErrTransactionSourceAndDestinationIdCannotBeEqual
+		}
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE && transaction.CategoryId <= 0 {
+		return errs.ErrTransactionCategoryNotFound
+	}
+
+	tagIds, err := utils.StringArrayToInt64Array(transaction.TagIds)
+
+	if err != nil {
+		return errs.ErrTransactionTagIdInvalid
+	}
+
+	for i := 0; i < len(tagIds); i++ {
+		if tagIds[i] <= 0 {
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	if len(utils.ToUniqueInt64Slice(tagIds)) > maximumTagsCountOfTransaction {
+		return errs.ErrTransactionHasTooManyTags
+	}
+
+	if !user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transaction.TimezoneUtcOffset) {
+		return errs.ErrCannotCreateTransactionWithThisTransactionTime
+	}
+
+	transaction.CreatedIp = clientIp
+
+	return nil
+}
+
+func (a *TransactionImportJobsApi) failImportJob(c core.Context, job *models.TransactionImportJob, err error) {
+	errorMessage := errs.Or(err, errs.ErrOperationFailed).Message
+
+	if len(errorMessage) > transactionImportJobErrorMessageMaxLength {
+		errorMessage = errorMessage[:transactionImportJobErrorMessageMaxLength]
+	}
+
+	job.Status = models.TRANSACTION_IMPORT_JOB_STATUS_FAILED
+	job.ErrorMessage = errorMessage
+	a.updateImportJobProgress(c, job)
+}
+
+func (a *TransactionImportJobsApi) updateImportJobProgress(c core.Context, job *models.TransactionImportJob) {
+	err := a.importJobs.UpdateJobProgress(c, job)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_jobs.updateImportJobProgress] failed to update transaction import job \"id:%d\" for user \"uid:%d\", because %s", job.JobId, job.Uid, err.Error())
+	}
+}
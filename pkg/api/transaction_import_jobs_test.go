@@ -0,0 +1,82 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestTransactionImportJobsApiGetImportJobNewSubCategories_MatchByName(t *testing.T) {
+	user := &models.User{
+		Uid: 1234567890,
+	}
+
+	parsedTransactions := models.ImportedTransactionSlice{
+		{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_MODIFY_BALANCE}},
+		{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE}, OriginalParentCategoryName: "Food", OriginalCategoryName: "Groceries"},
+		{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE}, OriginalParentCategoryName: "Shopping", OriginalCategoryName: "Groceries"},
+		{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE, CategoryId: 1234567891}, OriginalParentCategoryName: "Food", OriginalCategoryName: "Dining"},
+		{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_INCOME}, OriginalCategoryName: "Salary"},
+	}
+
+	newSubCategories, newSubCategoryKeys, parentCategoryNames := TransactionImportJobs.getImportJobNewSubCategories(user, parsedTransactions)
+
+	assert.Equal(t, 1, len(newSubCategories[models.CATEGORY_TYPE_EXPENSE]))
+	assert.Equal(t, "Groceries", newSubCategories[models.CATEGORY_TYPE_EXPENSE][0].Name)
+	assert.Equal(t, int64(1234567890), newSubCategories[models.CATEGORY_TYPE_EXPENSE][0].Uid)
+	assert.Equal(t, []string{"Groceries"}, newSubCategoryKeys[models.CATEGORY_TYPE_EXPENSE])
+	assert.Equal(t, "Food", parentCategoryNames[models.CATEGORY_TYPE_EXPENSE]["Groceries"])
+
+	assert.Equal(t, 1, len(newSubCategories[models.CATEGORY_TYPE_INCOME]))
+	assert.Equal(t, "Salary", newSubCategories[models.CATEGORY_TYPE_INCOME][0].Name)
+	assert.Equal(t, "Salary", TransactionImportJobs.getImportJobNewPrimaryCategoryName(parentCategoryNames[models.CATEGORY_TYPE_INCOME], "Salary", "Salary"))
+
+	assert.Equal(t, 0, len(newSubCategories[models.CATEGORY_TYPE_TRANSFER]))
+}
+
+func TestTransactionImportJobsApiGetImportJobNewSubCategories_MatchByCategoryKey(t *testing.T) {
+	user := &models.User{
+		Uid: 1234567890,
+	}
+
+	foodGroceriesKey := models.GetTransactionCategoryHierarchyName("Food", "Groceries")
+	shoppingGroceriesKey := models.GetTransactionCategoryHierarchyName("Shopping", "Groceries")
+
+	parsedTransactions := models.ImportedTransactionSlice{
+		{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE}, OriginalParentCategoryName: "Food", OriginalCategoryName: "Groceries", OriginalCategoryKey: foodGroceriesKey},
+		{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE}, OriginalParentCategoryName: "Shopping", OriginalCategoryName: "Groceries", OriginalCategoryKey: shoppingGroceriesKey},
+		{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE}, OriginalParentCategoryName: "Food", OriginalCategoryName: "Groceries", OriginalCategoryKey: foodGroceriesKey},
+	}
+
+	newSubCategories, newSubCategoryKeys, parentCategoryNames := TransactionImportJobs.getImportJobNewSubCategories(user, parsedTransactions)
+
+	assert.Equal(t, 2, len(newSubCategories[models.CATEGORY_TYPE_EXPENSE]))
+	assert.Equal(t, "Groceries", newSubCategories[models.CATEGORY_TYPE_EXPENSE][0].Name)
+	assert.Equal(t, "Groceries", newSubCategories[models.CATEGORY_TYPE_EXPENSE][1].Name)
+	assert.Equal(t, []string{foodGroceriesKey, shoppingGroceriesKey}, newSubCategoryKeys[models.CATEGORY_TYPE_EXPENSE])
+	assert.Equal(t, "Food", TransactionImportJobs.getImportJobNewPrimaryCategoryName(parentCategoryNames[models.CATEGORY_TYPE_EXPENSE], foodGroceriesKey, "Groceries"))
+	assert.Equal(t, "Shopping", TransactionImportJobs.getImportJobNewPrimaryCategoryName(parentCategoryNames[models.CATEGORY_TYPE_EXPENSE], shoppingGroceriesKey, "Groceries"))
+}
+
+func TestTransactionImportJobsApiFillImportJobNewItemIds_MatchByCategoryKey(t *testing.T) {
+	foodGroceriesKey := models.GetTransactionCategoryHierarchyName("Food", "Groceries")
+	shoppingGroceriesKey := models.GetTransactionCategoryHierarchyName("Shopping", "Groceries")
+
+	newCategoryMaps := map[models.TransactionCategoryType]map[string]*models.TransactionCategory{
+		models.CATEGORY_TYPE_EXPENSE: {
+			foodGroceriesKey:     {CategoryId: 1234567891, Name: "Groceries"},
+			shoppingGroceriesKey: {CategoryId: 1234567892, Name: "Groceries"},
+			"Dining":             {CategoryId: 1234567893, Name: "Dining"},
+		},
+	}
+
+	transaction := &models.ImportTransaction{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE}, OriginalCategoryName: "Groceries", OriginalCategoryKey: shoppingGroceriesKey}
+	TransactionImportJobs.fillImportJobNewItemIds(transaction, nil, newCategoryMaps, nil)
+	assert.Equal(t, int64(1234567892), transaction.CategoryId)
+
+	transaction = &models.ImportTransaction{Transaction: &models.Transaction{Type: models.TRANSACTION_DB_TYPE_EXPENSE}, OriginalCategoryName: "Dining"}
+	TransactionImportJobs.fillImportJobNewItemIds(transaction, nil, newCategoryMaps, nil)
+	assert.Equal(t, int64(1234567893), transaction.CategoryId)
+}
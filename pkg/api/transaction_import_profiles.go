@@ -0,0 +1,311 @@
+package api
+
+import (
+	"io"
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/custom"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionImportProfilesApi represents transaction import profile api
+type TransactionImportProfilesApi struct {
+	ApiUsingConfig
+	importProfiles *services.TransactionImportProfileService
+}
+
+// Initialize a transaction import profile api singleton instance
+var (
+	TransactionImportProfiles = &TransactionImportProfilesApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		importProfiles: services.TransactionImportProfiles,
+	}
+)
+
+// ImportProfileListHandler returns transaction import profile list of current user
+func (a *TransactionImportProfilesApi) ImportProfileListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	profiles, err := a.importProfiles.GetAllProfilesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileListHandler] failed to get transaction import profiles for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	profileResps := make(models.TransactionImportProfileInfoResponseSlice, len(profiles))
+
+	for i := 0; i < len(profiles); i++ {
+		profileResps[i] = profiles[i].ToTransactionImportProfileInfoResponse()
+	}
+
+	sort.Sort(profileResps)
+
+	return profileResps, nil
+}
+
+// ImportProfileGetHandler returns one specific transaction import profile of current user
+func (a *TransactionImportProfilesApi) ImportProfileGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var profileGetReq models.TransactionImportProfileGetRequest
+	err := c.ShouldBindQuery(&profileGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_profiles.ImportProfileGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	profile, err := a.importProfiles.GetProfileByProfileId(c, uid, profileGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileGetHandler] failed to get transaction import profile \"id:%d\" for user \"uid:%d\", because %s", profileGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return profile.ToTransactionImportProfileInfoResponse(), nil
+}
+
+// ImportProfileCreateHandler saves a new transaction import profile by request parameters for current user
+func (a *TransactionImportProfilesApi) ImportProfileCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var profileCreateReq models.TransactionImportProfileCreateRequest
+	err := c.ShouldBindJSON(&profileCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_profiles.ImportProfileCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	profile := &models.TransactionImportProfile{
+		Uid:                 uid,
+		Name:                profileCreateReq.Name,
+		FileType:            profileCreateReq.FileType,
+		Delimiter:           profileCreateReq.Delimiter,
+		SkipRows:            profileCreateReq.SkipRows,
+		ColumnMapping:       profileCreateReq.ColumnMapping,
+		DateTimeFormat:      profileCreateReq.DateTimeFormat,
+		DecimalSeparator:    profileCreateReq.DecimalSeparator,
+		DigitGroupingSymbol: profileCreateReq.DigitGroupingSymbol,
+		AmountSignType:      profileCreateReq.AmountSignType,
+		IncomeTypeValue:     profileCreateReq.IncomeTypeValue,
+		ExpenseTypeValue:    profileCreateReq.ExpenseTypeValue,
+		TransferTypeValue:   profileCreateReq.TransferTypeValue,
+		DefaultAccountName:  profileCreateReq.DefaultAccountName,
+		TagSeparator:        profileCreateReq.TagSeparator,
+	}
+
+	err = a.checkImportProfile(profile)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_profiles.ImportProfileCreateHandler] transaction import profile is invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.importProfiles.CreateProfile(c, profile)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileCreateHandler] failed to create transaction import profile \"id:%d\" for user \"uid:%d\", because %s", profile.ProfileId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_import_profiles.ImportProfileCreateHandler] user \"uid:%d\" has created a new transaction import profile \"id:%d\" successfully", uid, profile.ProfileId)
+
+	return profile.ToTransactionImportProfileInfoResponse(), nil
+}
+
+// ImportProfileModifyHandler saves an existed transaction import profile by request parameters for current user
+func (a *TransactionImportProfilesApi) ImportProfileModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var profileModifyReq models.TransactionImportProfileModifyRequest
+	err := c.ShouldBindJSON(&profileModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_profiles.ImportProfileModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	profile, err := a.importProfiles.GetProfileByProfileId(c, uid, profileModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileModifyHandler] failed to get transaction import profile \"id:%d\" for user \"uid:%d\", because %s", profileModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newProfile := &models.TransactionImportProfile{
+		ProfileId:           profile.ProfileId,
+		Uid:                 uid,
+		Name:                profileModifyReq.Name,
+		FileType:            profileModifyReq.FileType,
+		Delimiter:           profileModifyReq.Delimiter,
+		SkipRows:            profileModifyReq.SkipRows,
+		ColumnMapping:       profileModifyReq.ColumnMapping,
+		DateTimeFormat:      profileModifyReq.DateTimeFormat,
+		DecimalSeparator:    profileModifyReq.DecimalSeparator,
+		DigitGroupingSymbol: profileModifyReq.DigitGroupingSymbol,
+		AmountSignType:      profileModifyReq.AmountSignType,
+		IncomeTypeValue:     profileModifyReq.IncomeTypeValue,
+		ExpenseTypeValue:    profileModifyReq.ExpenseTypeValue,
+		TransferTypeValue:   profileModifyReq.TransferTypeValue,
+		DefaultAccountName:  profileModifyReq.DefaultAccountName,
+		TagSeparator:        profileModifyReq.TagSeparator,
+	}
+
+	err = a.checkImportProfile(newProfile)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_profiles.ImportProfileModifyHandler] transaction import profile \"id:%d\" is invalid for user \"uid:%d\", because %s", profileModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.importProfiles.ModifyProfile(c, newProfile)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileModifyHandler] failed to update transaction import profile \"id:%d\" for user \"uid:%d\", because %s", profileModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_import_profiles.ImportProfileModifyHandler] user \"uid:%d\" has updated transaction import profile \"id:%d\" successfully", uid, profileModifyReq.Id)
+
+	return newProfile.ToTransactionImportProfileInfoResponse(), nil
+}
+
+// ImportProfileDeleteHandler deletes an existed transaction import profile by request parameters for current user
+func (a *TransactionImportProfilesApi) ImportProfileDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var profileDeleteReq models.TransactionImportProfileDeleteRequest
+	err := c.ShouldBindJSON(&profileDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_import_profiles.ImportProfileDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.importProfiles.DeleteProfile(c, uid, profileDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileDeleteHandler] failed to delete transaction import profile \"id:%d\" for user \"uid:%d\", because %s", profileDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_import_profiles.ImportProfileDeleteHandler] user \"uid:%d\" has deleted transaction import profile \"id:%d\"", uid, profileDeleteReq.Id)
+	return true, nil
+}
+
+// ImportProfileParseHeadersHandler returns the header column names of the uploaded file for configuring the column mapping of transaction import profile
+func (a *TransactionImportProfilesApi) ImportProfileParseHeadersHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	form, err := c.MultipartForm()
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileParseHeadersHandler] failed to get multi-part form data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrParameterInvalid
+	}
+
+	profile := &models.TransactionImportProfile{}
+	fileTypes := form.Value["fileType"]
+
+	if len(fileTypes) < 1 || fileTypes[0] == "" {
+		return nil, errs.ErrImportFileTypeIsEmpty
+	}
+
+	fileType, err := utils.StringToInt(fileTypes[0])
+
+	if err != nil || (models.TransactionImportProfileFileType(fileType) != models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV && models.TransactionImportProfileFileType(fileType) != models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_EXCEL) {
+		return nil, errs.ErrImportFileTypeNotSupported
+	}
+
+	profile.FileType = models.TransactionImportProfileFileType(fileType)
+
+	if profile.FileType == models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV {
+		delimiters := form.Value["delimiter"]
+
+		if len(delimiters) < 1 || delimiters[0] == "" {
+			return nil, errs.ErrTransactionImportProfileDelimiterInvalid
+		}
+
+		profile.Delimiter = delimiters[0]
+
+		if skipRows := form.Value["skipRows"]; len(skipRows) > 0 && skipRows[0] != "" {
+			skipRowCount, err := utils.StringToInt(skipRows[0])
+
+			if err != nil || skipRowCount < 0 || skipRowCount > models.TRANSACTION_IMPORT_PROFILE_MAX_SKIP_ROWS {
+				return nil, errs.ErrParameterInvalid
+			}
+
+			profile.SkipRows = int32(skipRowCount)
+		}
+	}
+
+	importFiles := form.File["file"]
+
+	if len(importFiles) < 1 {
+		log.Warnf(c, "[transaction_import_profiles.ImportProfileParseHeadersHandler] there is no import file in request for user \"uid:%d\"", uid)
+		return nil, errs.ErrNoFilesUpload
+	}
+
+	if importFiles[0].Size < 1 {
+		log.Warnf(c, "[transaction_import_profiles.ImportProfileParseHeadersHandler] the size of import file in request is zero for user \"uid:%d\"", uid)
+		return nil, errs.ErrUploadedFileEmpty
+	}
+
+	if importFiles[0].Size > int64(a.CurrentConfig().MaxImportFileSize) {
+		log.Warnf(c, "[transaction_import_profiles.ImportProfileParseHeadersHandler] the upload file size \"%d\" exceeds the maximum size \"%d\" of import file for user \"uid:%d\"", importFiles[0].Size, a.CurrentConfig().MaxImportFileSize, uid)
+		return nil, errs.ErrExceedMaxUploadFileSize
+	}
+
+	importFile, err := importFiles[0].Open()
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileParseHeadersHandler] failed to get import file from request for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	defer importFile.Close()
+
+	fileData, err := io.ReadAll(importFile)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileParseHeadersHandler] failed to read import file data for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	headerColumnNames, err := custom.ParseHeaderColumnNames(c, profile, fileData)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_import_profiles.ImportProfileParseHeadersHandler] failed to parse header column names for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return headerColumnNames, nil
+}
+
+func (a *TransactionImportProfilesApi) checkImportProfile(profile *models.TransactionImportProfile) error {
+	if profile.FileType == models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV && profile.Delimiter == "" {
+		return errs.ErrTransactionImportProfileDelimiterInvalid
+	}
+
+	if !profile.IsValidColumnMapping() {
+		return errs.ErrTransactionImportProfileColumnMappingInvalid
+	}
+
+	if _, err := custom.ParseDateTimeFormat(profile.DateTimeFormat); err != nil {
+		return err
+	}
+
+	if !profile.IsValidNumberFormat() {
+		return errs.ErrTransactionImportProfileNumberFormatInvalid
+	}
+
+	if profile.AmountSignType == models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_TYPE_COLUMN && (profile.IncomeTypeValue == "" || profile.ExpenseTypeValue == "") {
+		return errs.ErrTransactionImportProfileTypeValueInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,482 @@
+package api
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionRulesApi represents transaction rule api
+type TransactionRulesApi struct {
+	ApiUsingConfig
+	ApiUsingDuplicateChecker
+	rules                 *services.TransactionRuleService
+	transactionCategories *services.TransactionCategoryService
+	transactionTags       *services.TransactionTagService
+	accounts              *services.AccountService
+}
+
+// Initialize a transaction rule api singleton instance
+var (
+	TransactionRules = &TransactionRulesApi{
+		ApiUsingConfig: ApiUsingConfig{
+			container: settings.Container,
+		},
+		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
+			container: duplicatechecker.Container,
+		},
+		rules:                 services.TransactionRules,
+		transactionCategories: services.TransactionCategories,
+		transactionTags:       services.TransactionTags,
+		accounts:              services.Accounts,
+	}
+)
+
+// RuleListHandler returns transaction rule list of current user
+func (a *TransactionRulesApi) RuleListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	rules, err := a.rules.GetAllRulesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleListHandler] failed to get transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleResps := make(models.TransactionRuleInfoResponseSlice, len(rules))
+
+	for i := 0; i < len(rules); i++ {
+		ruleResps[i] = rules[i].ToTransactionRuleInfoResponse()
+	}
+
+	sort.Sort(ruleResps)
+
+	return ruleResps, nil
+}
+
+// RuleGetHandler returns one specific transaction rule of current user
+func (a *TransactionRulesApi) RuleGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleGetReq models.TransactionRuleGetRequest
+	err := c.ShouldBindQuery(&ruleGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ruleGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleGetHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	ruleResp := rule.ToTransactionRuleInfoResponse()
+
+	return ruleResp, nil
+}
+
+// RuleCreateHandler saves a new transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleCreateReq models.TransactionRuleCreateRequest
+	err := c.ShouldBindJSON(&ruleCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	addTagIds, err := a.getRuleTagIds(ruleCreateReq.AddTagIds)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] tag ids are invalid, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	uid := c.GetCurrentUid()
+	maxOrderId, err := a.rules.GetMaxDisplayOrder(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to get max display order for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	rule := &models.TransactionRule{
+		Uid:                     uid,
+		Name:                    ruleCreateReq.Name,
+		MatchType:               ruleCreateReq.MatchType,
+		MatchAccountId:          ruleCreateReq.MatchAccountId,
+		MatchKeywordType:        ruleCreateReq.MatchKeywordType,
+		MatchKeyword:            ruleCreateReq.MatchKeyword,
+		MatchAmountRange:        ruleCreateReq.MatchAmountRange,
+		MatchMinAmount:          ruleCreateReq.MatchMinAmount,
+		MatchMaxAmount:          ruleCreateReq.MatchMaxAmount,
+		SetCategoryId:           ruleCreateReq.SetCategoryId,
+		AddTagIds:               strings.Join(utils.Int64ArrayToStringArray(addTagIds), ","),
+		SetComment:              ruleCreateReq.SetComment,
+		SetDestinationAccountId: ruleCreateReq.SetDestinationAccountId,
+		DisplayOrder:            maxOrderId + 1,
+	}
+
+	err = a.checkRule(c, uid, rule)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleCreateHandler] transaction rule is invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if a.CurrentConfig().EnableDuplicateSubmissionsCheck && ruleCreateReq.ClientSessionId != "" {
+		found, remark := a.GetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_RULE, uid, ruleCreateReq.ClientSessionId)
+
+		if found {
+			log.Infof(c, "[transaction_rules.RuleCreateHandler] another transaction rule \"id:%s\" has been created for user \"uid:%d\"", remark, uid)
+			ruleId, err := utils.StringToInt64(remark)
+
+			if err == nil {
+				rule, err = a.rules.GetRuleByRuleId(c, uid, ruleId)
+
+				if err != nil {
+					log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to get existed transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleId, uid, err.Error())
+					return nil, errs.Or(err, errs.ErrOperationFailed)
+				}
+
+				ruleResp := rule.ToTransactionRuleInfoResponse()
+
+				return ruleResp, nil
+			}
+		}
+	}
+
+	err = a.rules.CreateRule(c, rule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleCreateHandler] failed to create transaction rule \"id:%d\" for user \"uid:%d\", because %s", rule.RuleId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleCreateHandler] user \"uid:%d\" has created a new transaction rule \"id:%d\" successfully", uid, rule.RuleId)
+
+	a.SetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_RULE, uid, ruleCreateReq.ClientSessionId, utils.Int64ToString(rule.RuleId))
+	ruleResp := rule.ToTransactionRuleInfoResponse()
+
+	return ruleResp, nil
+}
+
+// RuleModifyHandler saves an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleModifyReq models.TransactionRuleModifyRequest
+	err := c.ShouldBindJSON(&ruleModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	addTagIds, err := a.getRuleTagIds(ruleModifyReq.AddTagIds)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] tag ids are invalid, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	uid := c.GetCurrentUid()
+	rule, err := a.rules.GetRuleByRuleId(c, uid, ruleModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to get transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newRule := &models.TransactionRule{
+		RuleId:                  rule.RuleId,
+		Uid:                     uid,
+		Name:                    ruleModifyReq.Name,
+		MatchType:               ruleModifyReq.MatchType,
+		MatchAccountId:          ruleModifyReq.MatchAccountId,
+		MatchKeywordType:        ruleModifyReq.MatchKeywordType,
+		MatchKeyword:            ruleModifyReq.MatchKeyword,
+		MatchAmountRange:        ruleModifyReq.MatchAmountRange,
+		MatchMinAmount:          ruleModifyReq.MatchMinAmount,
+		MatchMaxAmount:          ruleModifyReq.MatchMaxAmount,
+		SetCategoryId:           ruleModifyReq.SetCategoryId,
+		AddTagIds:               strings.Join(utils.Int64ArrayToStringArray(addTagIds), ","),
+		SetComment:              ruleModifyReq.SetComment,
+		SetDestinationAccountId: ruleModifyReq.SetDestinationAccountId,
+	}
+
+	if newRule.Name == rule.Name &&
+		newRule.MatchType == rule.MatchType &&
+		newRule.MatchAccountId == rule.MatchAccountId &&
+		newRule.MatchKeywordType == rule.MatchKeywordType &&
+		newRule.MatchKeyword == rule.MatchKeyword &&
+		newRule.MatchAmountRange == rule.MatchAmountRange &&
+		newRule.MatchMinAmount == rule.MatchMinAmount &&
+		newRule.MatchMaxAmount == rule.MatchMaxAmount &&
+		newRule.SetCategoryId == rule.SetCategoryId &&
+		newRule.AddTagIds == rule.AddTagIds &&
+		newRule.SetComment == rule.SetComment &&
+		newRule.SetDestinationAccountId == rule.SetDestinationAccountId {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.checkRule(c, uid, newRule)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleModifyHandler] transaction rule \"id:%d\" is invalid for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.rules.ModifyRule(c, newRule)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleModifyHandler] failed to update transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleModifyHandler] user \"uid:%d\" has updated transaction rule \"id:%d\" successfully", uid, ruleModifyReq.Id)
+
+	newRule.DisplayOrder = rule.DisplayOrder
+	newRule.Hidden = rule.Hidden
+	ruleResp := newRule.ToTransactionRuleInfoResponse()
+
+	return ruleResp, nil
+}
+
+// RuleHideHandler hides an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleHideHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleHideReq models.TransactionRuleHideRequest
+	err := c.ShouldBindJSON(&ruleHideReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleHideHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.rules.HideRule(c, uid, []int64{ruleHideReq.Id}, ruleHideReq.Hidden)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleHideHandler] failed to hide transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleHideReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleHideHandler] user \"uid:%d\" has hidden transaction rule \"id:%d\"", uid, ruleHideReq.Id)
+	return true, nil
+}
+
+// RuleMoveHandler moves display order of existed transaction rules by request parameters for current user
+func (a *TransactionRulesApi) RuleMoveHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleMoveReq models.TransactionRuleMoveRequest
+	err := c.ShouldBindJSON(&ruleMoveReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleMoveHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	rules := make([]*models.TransactionRule, len(ruleMoveReq.NewDisplayOrders))
+
+	for i := 0; i < len(ruleMoveReq.NewDisplayOrders); i++ {
+		newDisplayOrder := ruleMoveReq.NewDisplayOrders[i]
+		rule := &models.TransactionRule{
+			Uid:          uid,
+			RuleId:       newDisplayOrder.Id,
+			DisplayOrder: newDisplayOrder.DisplayOrder,
+		}
+
+		rules[i] = rule
+	}
+
+	err = a.rules.ModifyRuleDisplayOrders(c, uid, rules)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleMoveHandler] failed to move transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleMoveHandler] user \"uid:%d\" has moved transaction rules", uid)
+	return true, nil
+}
+
+// RuleDeleteHandler deletes an existed transaction rule by request parameters for current user
+func (a *TransactionRulesApi) RuleDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleDeleteReq models.TransactionRuleDeleteRequest
+	err := c.ShouldBindJSON(&ruleDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.rules.DeleteRule(c, uid, ruleDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleDeleteHandler] failed to delete transaction rule \"id:%d\" for user \"uid:%d\", because %s", ruleDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[transaction_rules.RuleDeleteHandler] user \"uid:%d\" has deleted transaction rule \"id:%d\"", uid, ruleDeleteReq.Id)
+	return true, nil
+}
+
+// RuleDryRunHandler returns which transaction rule matches each given transaction and the result after applying it, without saving anything
+func (a *TransactionRulesApi) RuleDryRunHandler(c *core.WebContext) (any, *errs.Error) {
+	var ruleDryRunReq models.TransactionRuleDryRunRequest
+	err := c.ShouldBindJSON(&ruleDryRunReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_rules.RuleDryRunHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	rules, err := a.rules.GetAllRulesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_rules.RuleDryRunHandler] failed to get transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	results := make([]*models.TransactionRuleDryRunResultResponse, len(ruleDryRunReq.Transactions))
+
+	for i := 0; i < len(ruleDryRunReq.Transactions); i++ {
+		transactionCreateReq := ruleDryRunReq.Transactions[i]
+		matchedRule := models.TransactionRuleSlice(rules).ApplyTo(transactionCreateReq)
+
+		result := &models.TransactionRuleDryRunResultResponse{
+			Index:                i,
+			Matched:              matchedRule != nil,
+			CategoryId:           transactionCreateReq.CategoryId,
+			DestinationAccountId: transactionCreateReq.DestinationAccountId,
+			TagIds:               transactionCreateReq.TagIds,
+			Comment:              transactionCreateReq.Comment,
+		}
+
+		if matchedRule != nil {
+			result.RuleId = matchedRule.RuleId
+			result.RuleName = matchedRule.Name
+		}
+
+		if result.TagIds == nil {
+			result.TagIds = make([]string, 0)
+		}
+
+		results[i] = result
+	}
+
+	return results, nil
+}
+
+func (a *TransactionRulesApi) getRuleTagIds(tagIds []string) ([]int64, error) {
+	ids, err := utils.StringArrayToInt64Array(tagIds)
+
+	if err != nil {
+		return nil, errs.ErrTransactionTagIdInvalid
+	}
+
+	ids = utils.ToUniqueInt64Slice(ids)
+
+	if len(ids) > maximumTagsCountOfTransaction {
+		return nil, errs.ErrTransactionRuleHasTooManyTags
+	}
+
+	utils.Int64Sort(ids)
+
+	return ids, nil
+}
+
+func (a *TransactionRulesApi) checkRule(c *core.WebContext, uid int64, rule *models.TransactionRule) error {
+	if rule.MatchType == models.TRANSACTION_TYPE_MODIFY_BALANCE {
+		return errs.ErrTransactionTypeInvalid
+	}
+
+	if !rule.MatchKeywordType.IsValid() || !rule.IsKeywordValid() {
+		return errs.ErrTransactionRuleKeywordInvalid
+	}
+
+	if rule.MatchAmountRange && rule.MatchMinAmount > rule.MatchMaxAmount {
+		return errs.ErrTransactionRuleAmountRangeInvalid
+	}
+
+	if !rule.HasAction() {
+		return errs.ErrTransactionRuleHasNoAction
+	}
+
+	if rule.SetCategoryId > 0 {
+		if rule.MatchType == 0 {
+			return errs.ErrTransactionRuleCategoryRequiresType
+		}
+
+		category, err := a.transactionCategories.GetCategoryByCategoryId(c, uid, rule.SetCategoryId)
+
+		if err != nil {
+			return err
+		}
+
+		if category.ParentCategoryId < 1 {
+			return errs.ErrCannotUsePrimaryCategoryForTransaction
+		}
+
+		if (rule.MatchType == models.TRANSACTION_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_INCOME) ||
+			(rule.MatchType == models.TRANSACTION_TYPE_EXPENSE && category.Type != models.CATEGORY_TYPE_EXPENSE) ||
+			(rule.MatchType == models.TRANSACTION_TYPE_TRANSFER && category.Type != models.CATEGORY_TYPE_TRANSFER) {
+			return errs.ErrTransactionCategoryTypeInvalid
+		}
+	}
+
+	if rule.SetDestinationAccountId > 0 && rule.MatchType != models.TRANSACTION_TYPE_TRANSFER {
+		return errs.ErrTransactionRuleDestinationRequiresTransfer
+	}
+
+	accountIds := make([]int64, 0, 2)
+
+	if rule.MatchAccountId > 0 {
+		accountIds = append(accountIds, rule.MatchAccountId)
+	}
+
+	if rule.SetDestinationAccountId > 0 {
+		accountIds = append(accountIds, rule.SetDestinationAccountId)
+	}
+
+	if len(accountIds) > 0 {
+		accounts, err := a.accounts.GetAccountsByAccountIds(c, uid, accountIds)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(accountIds); i++ {
+			if _, exists := accounts[accountIds[i]]; !exists {
+				return errs.ErrAccountNotFound
+			}
+		}
+	}
+
+	addTagIds := rule.GetAddTagIds()
+
+	if len(addTagIds) > 0 {
+		tags, err := a.transactionTags.GetTagsByTagIds(c, uid, addTagIds)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(addTagIds); i++ {
+			if _, exists := tags[addTagIds[i]]; !exists {
+				return errs.ErrTransactionTagNotFound
+			}
+		}
+	}
+
+	return nil
+}
@@ -22,6 +22,7 @@ type TransactionTemplatesApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
 	templates *services.TransactionTemplateService
+	accounts  *services.AccountService
 }
 
 // Initialize a transaction template api singleton instance
@@ -34,6 +35,7 @@ var (
 			container: duplicatechecker.Container,
 		},
 		templates: services.TransactionTemplates,
+		accounts:  services.Accounts,
 	}
 )
 
@@ -104,6 +106,146 @@ func (a *TransactionTemplatesApi) TemplateGetHandler(c *core.WebContext) (any, *
 	return templateResp, nil
 }
 
+// TemplateForecastHandler returns the upcoming scheduled transactions and projected daily account balances of current user
+func (a *TransactionTemplatesApi) TemplateForecastHandler(c *core.WebContext) (any, *errs.Error) {
+	var forecastReq models.TransactionForecastRequest
+	err := c.ShouldBindQuery(&forecastReq)
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateForecastHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	if !a.CurrentConfig().EnableScheduledTransaction {
+		return nil, errs.ErrScheduledTransactionNotEnabled
+	}
+
+	utcOffset, err := c.GetClientTimezoneOffset()
+
+	if err != nil {
+		log.Warnf(c, "[transaction_templates.TemplateForecastHandler] cannot get client timezone offset, because %s", err.Error())
+		return nil, errs.ErrClientTimezoneOffsetInvalid
+	}
+
+	// the balances can only be projected from the current balances, so the forecast always starts from now
+	now := time.Now().Unix()
+	startUnixTime := forecastReq.StartTime
+
+	if startUnixTime < now {
+		startUnixTime = now
+	}
+
+	endUnixTime := forecastReq.EndTime
+
+	if endUnixTime < startUnixTime || endUnixTime-startUnixTime > models.TRANSACTION_FORECAST_MAX_DAYS*24*60*60 {
+		return nil, errs.ErrTransactionForecastTimeRangeInvalid
+	}
+
+	uid := c.GetCurrentUid()
+	// hidden templates are also included, because the scheduled transactions of them are still created by the scheduler
+	templates, err := a.templates.GetAllTemplatesByUid(c, uid, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateForecastHandler] failed to get templates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transaction_templates.TemplateForecastHandler] failed to get accounts for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionResps := make(models.TransactionForecastTransactionResponseSlice, 0)
+	templatesMap := make(map[int64]*models.TransactionTemplate, len(templates))
+
+	for i := 0; i < len(templates); i++ {
+		template := templates[i]
+
+		if !template.ScheduledFrequencyType.IsValidFrequency(template.ScheduledFrequency) || template.IsScheduledCountReached() {
+			continue
+		}
+
+		// the scheduled transactions before the last created one have already been counted in the current balances,
+		// and the missed ones after it would be created later, so they are also upcoming transactions
+		lastCreatedUnixTime := template.ScheduledLastCreatedTime
+
+		if lastCreatedUnixTime <= 0 {
+			lastCreatedUnixTime = now
+		}
+
+		occurrenceUnixTimes := template.GetScheduledOccurrenceTimes(lastCreatedUnixTime, endUnixTime+1)
+
+		for j := 0; j < len(occurrenceUnixTimes); j++ {
+			if template.ScheduledMaxCount > 0 && int32(j) >= template.ScheduledMaxCount-template.ScheduledCreatedCount {
+				break
+			}
+
+			transactionResps = append(transactionResps, template.ToTransactionForecastTransactionResponse(occurrenceUnixTimes[j]))
+		}
+
+		templatesMap[template.TemplateId] = template
+	}
+
+	sort.Sort(transactionResps)
+
+	accountResps := make([]*models.AccountForecastResponse, 0, len(accounts))
+	accountRespsMap := make(map[int64]*models.AccountForecastResponse, len(accounts))
+	accountBalances := make(map[int64]int64, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+
+		// the parent account with sub-accounts has no balance of itself
+		if account.Type != models.ACCOUNT_TYPE_SINGLE_ACCOUNT {
+			continue
+		}
+
+		accountResp := account.ToAccountForecastResponse()
+		accountResps = append(accountResps, accountResp)
+		accountRespsMap[account.AccountId] = accountResp
+		accountBalances[account.AccountId] = account.Balance
+	}
+
+	timezone := time.FixedZone("Client Timezone", int(utcOffset)*60)
+	startTime := time.Unix(startUnixTime, 0).In(timezone)
+	endTime := time.Unix(endUnixTime, 0).In(timezone)
+	dates := make([]string, 0)
+	transactionIndex := 0
+
+	for dayStartTime := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, timezone); !dayStartTime.After(endTime); dayStartTime = dayStartTime.AddDate(0, 0, 1) {
+		nextDayStartUnixTime := dayStartTime.AddDate(0, 0, 1).Unix()
+
+		for ; transactionIndex < len(transactionResps) && transactionResps[transactionIndex].Time < nextDayStartUnixTime; transactionIndex++ {
+			template := templatesMap[transactionResps[transactionIndex].TemplateId]
+
+			for accountId, amount := range template.GetAccountBalanceChanges() {
+				if _, exists := accountBalances[accountId]; exists {
+					accountBalances[accountId] += amount
+				}
+			}
+		}
+
+		dates = append(dates, dayStartTime.Format("2006-01-02"))
+
+		for accountId, accountResp := range accountRespsMap {
+			accountResp.Balances = append(accountResp.Balances, accountBalances[accountId])
+		}
+	}
+
+	forecastResp := &models.TransactionForecastResponse{
+		StartTime:    startUnixTime,
+		EndTime:      endUnixTime,
+		Dates:        dates,
+		Transactions: transactionResps,
+		Accounts:     accountResps,
+		Totals:       models.GetAccountForecastTotals(accountResps, len(dates)),
+	}
+
+	return forecastResp, nil
+}
+
 // TemplateCreateHandler saves a new transaction template by request parameters for current user
 func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any, *errs.Error) {
 	var templateCreateReq models.TransactionTemplateCreateRequest
@@ -135,11 +277,13 @@ func (a *TransactionTemplatesApi) TemplateCreateHandler(c *core.WebContext) (any
 			return nil, errs.ErrScheduledTransactionFrequencyInvalid
 		}
 
-		if *templateCreateReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateCreateReq.ScheduledFrequency != "" {
-			return nil, errs.ErrScheduledTransactionFrequencyInvalid
-		} else if *templateCreateReq.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateCreateReq.ScheduledFrequency == "" {
+		if !templateCreateReq.ScheduledFrequencyType.IsValidFrequency(a.getOrderedFrequencyValues(*templateCreateReq.ScheduledFrequency)) {
 			return nil, errs.ErrScheduledTransactionFrequencyInvalid
 		}
+
+		if templateCreateReq.ScheduledEndTime > 0 && templateCreateReq.ScheduledEndTime < templateCreateReq.ScheduledStartTime {
+			return nil, errs.ErrScheduledTransactionTimeRangeInvalid
+		}
 	}
 
 	if len(templateCreateReq.TagIds) > maximumTagsCountOfTemplate {
@@ -229,11 +373,13 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 			return nil, errs.ErrScheduledTransactionFrequencyInvalid
 		}
 
-		if *templateModifyReq.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateModifyReq.ScheduledFrequency != "" {
-			return nil, errs.ErrScheduledTransactionFrequencyInvalid
-		} else if *templateModifyReq.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED && *templateModifyReq.ScheduledFrequency == "" {
+		if !templateModifyReq.ScheduledFrequencyType.IsValidFrequency(a.getOrderedFrequencyValues(*templateModifyReq.ScheduledFrequency)) {
 			return nil, errs.ErrScheduledTransactionFrequencyInvalid
 		}
+
+		if templateModifyReq.ScheduledEndTime > 0 && templateModifyReq.ScheduledEndTime < templateModifyReq.ScheduledStartTime {
+			return nil, errs.ErrScheduledTransactionTimeRangeInvalid
+		}
 	}
 
 	if len(templateModifyReq.TagIds) > maximumTagsCountOfTemplate {
@@ -260,6 +406,10 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 		newTemplate.ScheduledFrequency = a.getOrderedFrequencyValues(*templateModifyReq.ScheduledFrequency)
 		newTemplate.ScheduledAt = a.getUTCScheduledAt(*templateModifyReq.ScheduledTimezoneUtcOffset)
 		newTemplate.ScheduledTimezoneUtcOffset = *templateModifyReq.ScheduledTimezoneUtcOffset
+		newTemplate.ScheduledInterval = templateModifyReq.ScheduledInterval
+		newTemplate.ScheduledStartTime = templateModifyReq.ScheduledStartTime
+		newTemplate.ScheduledEndTime = templateModifyReq.ScheduledEndTime
+		newTemplate.ScheduledMaxCount = templateModifyReq.ScheduledMaxCount
 	}
 
 	if newTemplate.Name == template.Name &&
@@ -278,12 +428,26 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 			if newTemplate.ScheduledFrequencyType == template.ScheduledFrequencyType &&
 				newTemplate.ScheduledFrequency == template.ScheduledFrequency &&
 				newTemplate.ScheduledAt == template.ScheduledAt &&
-				newTemplate.ScheduledTimezoneUtcOffset == template.ScheduledTimezoneUtcOffset {
+				newTemplate.ScheduledTimezoneUtcOffset == template.ScheduledTimezoneUtcOffset &&
+				newTemplate.ScheduledInterval == template.ScheduledInterval &&
+				newTemplate.ScheduledStartTime == template.ScheduledStartTime &&
+				newTemplate.ScheduledEndTime == template.ScheduledEndTime &&
+				newTemplate.ScheduledMaxCount == template.ScheduledMaxCount {
 				return nil, errs.ErrNothingWillBeUpdated
 			}
 		}
 	}
 
+	// scheduled transactions before the schedule is changed do not need to be created
+	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE &&
+		(newTemplate.ScheduledFrequencyType != template.ScheduledFrequencyType ||
+			newTemplate.ScheduledFrequency != template.ScheduledFrequency ||
+			newTemplate.ScheduledAt != template.ScheduledAt ||
+			newTemplate.ScheduledInterval != template.ScheduledInterval ||
+			newTemplate.ScheduledStartTime != template.ScheduledStartTime) {
+		newTemplate.ScheduledLastCreatedTime = time.Now().Unix()
+	}
+
 	err = a.templates.ModifyTemplate(c, newTemplate)
 
 	if err != nil {
@@ -297,6 +461,7 @@ func (a *TransactionTemplatesApi) TemplateModifyHandler(c *core.WebContext) (any
 	newTemplate.TemplateType = template.TemplateType
 	newTemplate.DisplayOrder = template.DisplayOrder
 	newTemplate.Hidden = template.Hidden
+	newTemplate.ScheduledCreatedCount = template.ScheduledCreatedCount
 	templateResp := newTemplate.ToTransactionTemplateInfoResponse(serverUtcOffset)
 
 	return templateResp, nil
@@ -441,6 +606,10 @@ func (a *TransactionTemplatesApi) createNewTemplateModel(uid int64, templateCrea
 		template.ScheduledFrequency = a.getOrderedFrequencyValues(*templateCreateReq.ScheduledFrequency)
 		template.ScheduledAt = a.getUTCScheduledAt(*templateCreateReq.ScheduledTimezoneUtcOffset)
 		template.ScheduledTimezoneUtcOffset = *templateCreateReq.ScheduledTimezoneUtcOffset
+		template.ScheduledInterval = templateCreateReq.ScheduledInterval
+		template.ScheduledStartTime = templateCreateReq.ScheduledStartTime
+		template.ScheduledEndTime = templateCreateReq.ScheduledEndTime
+		template.ScheduledMaxCount = templateCreateReq.ScheduledMaxCount
 	}
 
 	return template
@@ -2,12 +2,14 @@ package api
 
 import (
 	"io"
+	"mime/multipart"
 	"sort"
 	"strings"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 
 	"github.com/mayswind/ezbookkeeping/pkg/converters"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/duplicatechecker"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -20,17 +22,25 @@ import (
 
 const maximumTagsCountOfTransaction = 10
 const maximumPicturesCountOfTransaction = 10
+const maximumSplitsCountOfTransaction = 20
+const customImportFileType = "custom"
 
 // TransactionsApi represents transaction api
 type TransactionsApi struct {
 	ApiUsingConfig
 	ApiUsingDuplicateChecker
-	transactions          *services.TransactionService
-	transactionCategories *services.TransactionCategoryService
-	transactionTags       *services.TransactionTagService
-	transactionPictures   *services.TransactionPictureService
-	accounts              *services.AccountService
-	users                 *services.UserService
+	transactions            *services.TransactionService
+	transactionCategories   *services.TransactionCategoryService
+	transactionTags         *services.TransactionTagService
+	transactionPictures     *services.TransactionPictureService
+	transactionSplits       *services.TransactionSplitService
+	transactionRules        *services.TransactionRuleService
+	importProfiles          *services.TransactionImportProfileService
+	importBatches           *services.TransactionImportBatchService
+	accounts                *services.AccountService
+	users                   *services.UserService
+	exchangeRates           *services.ExchangeRateService
+	userCustomExchangeRates *services.UserCustomExchangeRateService
 }
 
 // Initialize a transaction api singleton instance
@@ -42,12 +52,18 @@ var (
 		ApiUsingDuplicateChecker: ApiUsingDuplicateChecker{
 			container: duplicatechecker.Container,
 		},
-		transactions:          services.Transactions,
-		transactionCategories: services.TransactionCategories,
-		transactionTags:       services.TransactionTags,
-		transactionPictures:   services.TransactionPictures,
-		accounts:              services.Accounts,
-		users:                 services.Users,
+		transactions:            services.Transactions,
+		transactionCategories:   services.TransactionCategories,
+		transactionTags:         services.TransactionTags,
+		transactionPictures:     services.TransactionPictures,
+		transactionSplits:       services.TransactionSplits,
+		transactionRules:        services.TransactionRules,
+		importProfiles:          services.TransactionImportProfiles,
+		importBatches:           services.TransactionImportBatches,
+		accounts:                services.Accounts,
+		users:                   services.Users,
+		exchangeRates:           services.ExchangeRates,
+		userCustomExchangeRates: services.UserCustomExchangeRates,
 	}
 )
 
@@ -312,7 +328,14 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 	}
 
 	uid := c.GetCurrentUid()
-	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, statisticReq.StartTime, statisticReq.EndTime, allTagIds, noTags, statisticReq.TagFilterType, utcOffset, statisticReq.UseTransactionTimezone)
+	exchangeRateConverter, err := a.getExchangeRateConverter(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get exchange rate converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	totalAmounts, err := a.transactions.GetAccountsAndCategoriesTotalIncomeAndExpense(c, uid, statisticReq.StartTime, statisticReq.EndTime, allTagIds, noTags, statisticReq.TagFilterType, utcOffset, statisticReq.UseTransactionTimezone, exchangeRateConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -327,12 +350,7 @@ func (a *TransactionsApi) TransactionStatisticsHandler(c *core.WebContext) (any,
 	statisticResp.Items = make([]*models.TransactionStatisticResponseItem, len(totalAmounts))
 
 	for i := 0; i < len(totalAmounts); i++ {
-		totalAmountItem := totalAmounts[i]
-		statisticResp.Items[i] = &models.TransactionStatisticResponseItem{
-			CategoryId:  totalAmountItem.CategoryId,
-			AccountId:   totalAmountItem.AccountId,
-			TotalAmount: totalAmountItem.Amount,
-		}
+		statisticResp.Items[i] = a.getTransactionStatisticResponseItem(totalAmounts[i])
 	}
 
 	return statisticResp, nil
@@ -375,7 +393,14 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 	}
 
 	uid := c.GetCurrentUid()
-	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyIncomeAndExpense(c, uid, startYear, startMonth, endYear, endMonth, allTagIds, noTags, statisticTrendsReq.TagFilterType, utcOffset, statisticTrendsReq.UseTransactionTimezone)
+	exchangeRateConverter, err := a.getExchangeRateConverter(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get exchange rate converter for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	allMonthlyTotalAmounts, err := a.transactions.GetAccountsAndCategoriesMonthlyIncomeAndExpense(c, uid, startYear, startMonth, endYear, endMonth, allTagIds, noTags, statisticTrendsReq.TagFilterType, utcOffset, statisticTrendsReq.UseTransactionTimezone, exchangeRateConverter)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionStatisticsTrendsHandler] failed to get accounts and categories total income and expense for user \"uid:%d\", because %s", uid, err.Error())
@@ -392,12 +417,7 @@ func (a *TransactionsApi) TransactionStatisticsTrendsHandler(c *core.WebContext)
 		}
 
 		for i := 0; i < len(monthlyTotalAmounts); i++ {
-			totalAmountItem := monthlyTotalAmounts[i]
-			monthlyStatisticResp.Items[i] = &models.TransactionStatisticResponseItem{
-				CategoryId:  totalAmountItem.CategoryId,
-				AccountId:   totalAmountItem.AccountId,
-				TotalAmount: totalAmountItem.Amount,
-			}
+			monthlyStatisticResp.Items[i] = a.getTransactionStatisticResponseItem(monthlyTotalAmounts[i])
 		}
 
 		statisticTrendsResp = append(statisticTrendsResp, monthlyStatisticResp)
@@ -596,6 +616,13 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	splits, err := a.transactionSplits.GetSplitsByTransactionId(c, uid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionGetHandler] failed to get transaction splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	var category *models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 	var pictureInfos []*models.TransactionPictureInfo
@@ -630,6 +657,7 @@ func (a *TransactionsApi) TransactionGetHandler(c *core.WebContext) (any, *errs.
 	transactionEditable := transaction.IsEditable(user, utcOffset, accountMap[transaction.AccountId], accountMap[transaction.RelatedAccountId])
 	transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 	transactionResp := transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
+	transactionResp.Splits = splits.ToTransactionSplitInfoResponses()
 
 	if !transactionGetReq.TrimAccount {
 		if sourceAccount := accountMap[transaction.AccountId]; sourceAccount != nil {
@@ -668,6 +696,22 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
 	}
 
+	if transactionCreateReq.ApplyRules {
+		uid := c.GetCurrentUid()
+		rules, err := a.transactionRules.GetAllRulesByUid(c, uid)
+
+		if err != nil {
+			log.Errorf(c, "[transactions.TransactionCreateHandler] failed to get transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+
+		matchedRule := models.TransactionRuleSlice(rules).ApplyTo(&transactionCreateReq)
+
+		if matchedRule != nil {
+			log.Infof(c, "[transactions.TransactionCreateHandler] transaction rule \"id:%d\" has been applied to new transaction for user \"uid:%d\"", matchedRule.RuleId, uid)
+		}
+	}
+
 	tagIds, err := utils.StringArrayToInt64Array(transactionCreateReq.TagIds)
 
 	if err != nil {
@@ -713,6 +757,13 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		return nil, errs.ErrTransactionDestinationAmountCannotBeSet
 	}
 
+	splits, err := a.getTransactionSplitModels(transactionCreateReq.Type, transactionCreateReq.SourceAmount, transactionCreateReq.Splits)
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionCreateHandler] transaction splits are invalid, because %s", err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	uid := c.GetCurrentUid()
 	user, err := a.users.GetUserById(c, uid)
 
@@ -727,6 +778,10 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 	transaction := a.createNewTransactionModel(uid, &transactionCreateReq, c.ClientIP())
 	transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transactionCreateReq.UtcOffset)
 
+	if len(splits) > 0 {
+		transaction.CategoryId = splits[0].CategoryId
+	}
+
 	if !transactionEditable {
 		return nil, errs.ErrCannotCreateTransactionWithThisTransactionTime
 	}
@@ -765,6 +820,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 				}
 
 				transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
+				transactionResp.Splits = splits.ToTransactionSplitInfoResponses()
 				transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
 
 				return transactionResp, nil
@@ -772,7 +828,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds)
+	err = a.transactions.CreateTransaction(c, transaction, tagIds, pictureIds, splits)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionCreateHandler] failed to create transaction \"id:%d\" for user \"uid:%d\", because %s", transaction.TransactionId, uid, err.Error())
@@ -783,6 +839,7 @@ func (a *TransactionsApi) TransactionCreateHandler(c *core.WebContext) (any, *er
 
 	a.SetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_NEW_TRANSACTION, uid, transactionCreateReq.ClientSessionId, utils.Int64ToString(transaction.TransactionId))
 	transactionResp := transaction.ToTransactionInfoResponse(tagIds, transactionEditable)
+	transactionResp.Splits = splits.ToTransactionSplitInfoResponses()
 	transactionResp.Pictures = a.GetTransactionPictureInfoResponseList(pictureInfos)
 
 	return transactionResp, nil
@@ -865,6 +922,36 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 
 	transactionPictureIds := a.transactionPictures.GetTransactionPictureIds(transactionPictureInfos)
 
+	transactionSplits, err := a.transactionSplits.GetSplitsByTransactionId(c, uid, transaction.TransactionId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to get transaction splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	transactionType, err := transaction.Type.ToTransactionType()
+
+	if err != nil {
+		log.Warnf(c, "[transactions.TransactionModifyHandler] transaction type of transaction \"id:%d\" is invalid for user \"uid:%d\"", transactionModifyReq.Id, uid)
+		return nil, errs.ErrTransactionTypeInvalid
+	}
+
+	// the existed splits would be kept if the splits field is not present in request
+	updateSplits := transactionModifyReq.Splits != nil
+	splits := transactionSplits
+
+	if updateSplits {
+		splits, err = a.getTransactionSplitModels(transactionType, transactionModifyReq.SourceAmount, transactionModifyReq.Splits)
+
+		if err != nil {
+			log.Warnf(c, "[transactions.TransactionModifyHandler] transaction splits are invalid, because %s", err.Error())
+			return nil, errs.Or(err, errs.ErrOperationFailed)
+		}
+	} else if len(splits) > 0 && splits.GetTotalAmount() != transactionModifyReq.SourceAmount {
+		log.Warnf(c, "[transactions.TransactionModifyHandler] total amount of existed transaction splits is not equal to new amount of transaction \"id:%d\" for user \"uid:%d\"", transactionModifyReq.Id, uid)
+		return nil, errs.ErrTransactionSplitsAmountNotEqual
+	}
+
 	newTransaction := &models.Transaction{
 		TransactionId:     transaction.TransactionId,
 		Uid:               uid,
@@ -887,6 +974,12 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		newTransaction.GeoLatitude = transactionModifyReq.GeoLocation.Latitude
 	}
 
+	if len(splits) > 0 {
+		newTransaction.CategoryId = splits[0].CategoryId
+	}
+
+	splitsChanged := updateSplits && !splits.IsEquals(transactionSplits)
+
 	if newTransaction.CategoryId == transaction.CategoryId &&
 		utils.GetUnixTimeFromTransactionTime(newTransaction.TransactionTime) == utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime) &&
 		newTransaction.TimezoneUtcOffset == transaction.TimezoneUtcOffset &&
@@ -899,7 +992,8 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		newTransaction.GeoLongitude == transaction.GeoLongitude &&
 		newTransaction.GeoLatitude == transaction.GeoLatitude &&
 		utils.Int64SliceEquals(tagIds, transactionTagIds) &&
-		utils.Int64SliceEquals(pictureIds, transactionPictureIds) {
+		utils.Int64SliceEquals(pictureIds, transactionPictureIds) &&
+		!splitsChanged {
 		return nil, errs.ErrNothingWillBeUpdated
 	}
 
@@ -957,7 +1051,7 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 		}
 	}
 
-	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds)
+	err = a.transactions.ModifyTransaction(c, newTransaction, len(transactionTagIds), addTransactionTagIds, removeTransactionTagIds, addTransactionPictureIds, removeTransactionPictureIds, splits, splitsChanged)
 
 	if err != nil {
 		log.Errorf(c, "[transactions.TransactionModifyHandler] failed to update transaction \"id:%d\" for user \"uid:%d\", because %s", transactionModifyReq.Id, uid, err.Error())
@@ -968,6 +1062,7 @@ func (a *TransactionsApi) TransactionModifyHandler(c *core.WebContext) (any, *er
 
 	newTransaction.Type = transaction.Type
 	newTransactionResp := newTransaction.ToTransactionInfoResponse(tagIds, transactionEditable)
+	newTransactionResp.Splits = splits.ToTransactionSplitInfoResponses()
 	newTransactionResp.Pictures = a.GetTransactionPictureInfoResponseList(newPictureInfos)
 
 	return newTransactionResp, nil
@@ -1047,17 +1142,10 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 		return nil, errs.ErrClientTimezoneOffsetInvalid
 	}
 
-	fileTypes := form.Value["fileType"]
+	_, dataImporter, errResp := getTransactionDataImporterByForm(c, a.importProfiles, uid, form)
 
-	if len(fileTypes) < 1 || fileTypes[0] == "" {
-		return nil, errs.ErrImportFileTypeIsEmpty
-	}
-
-	fileType := fileTypes[0]
-	dataImporter, err := converters.GetTransactionDataImporter(fileType)
-
-	if err != nil {
-		return nil, errs.Or(err, errs.ErrImportFileTypeNotSupported)
+	if errResp != nil {
+		return nil, errResp
 	}
 
 	importFiles := form.File["file"]
@@ -1139,6 +1227,17 @@ func (a *TransactionsApi) TransactionParseImportFileHandler(c *core.WebContext)
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
+	duplicateCount, err := a.transactions.MarkLikelyDuplicateImportTransactions(c, user.Uid, parsedTransactions)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionParseImportFileHandler] failed to check duplicate transactions for user \"uid:%d\", because %s", user.Uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	if duplicateCount > 0 {
+		log.Infof(c, "[transactions.TransactionParseImportFileHandler] %d of %d parsed transactions are likely duplicate for user \"uid:%d\"", duplicateCount, len(parsedTransactions), user.Uid)
+	}
+
 	parsedTransactionRespsList := parsedTransactions.ToImportTransactionResponseList()
 
 	if len(parsedTransactionRespsList) < 1 {
@@ -1178,10 +1277,24 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		}
 	}
 
+	rules, err := a.transactionRules.GetAllRulesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionImportHandler] failed to get transaction rules for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
 	newTransactionTagIdsMap := make(map[int][]int64, len(transactionImportReq.Transactions))
+	matchedRuleCount := 0
 
 	for i := 0; i < len(transactionImportReq.Transactions); i++ {
 		transactionCreateReq := transactionImportReq.Transactions[i]
+
+		// rules are applied after the accounts and categories of transaction are resolved, the same as the import job
+		if models.TransactionRuleSlice(rules).ApplyTo(transactionCreateReq) != nil {
+			matchedRuleCount++
+		}
+
 		tagIds, err := utils.StringArrayToInt64Array(transactionCreateReq.TagIds)
 
 		if err != nil {
@@ -1238,6 +1351,7 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 	for i := 0; i < len(transactionImportReq.Transactions); i++ {
 		transactionCreateReq := transactionImportReq.Transactions[i]
 		transaction := a.createNewTransactionModel(uid, transactionCreateReq, c.ClientIP())
+		transaction.ExternalId = transactionCreateReq.ExternalId
 		transactionEditable := user.CanEditTransactionByTransactionTime(transaction.TransactionTime, transactionCreateReq.UtcOffset)
 
 		if !transactionEditable {
@@ -1247,7 +1361,14 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		newTransactions[i] = transaction
 	}
 
-	err = a.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap)
+	importBatch, err := a.createNewImportBatchModel(c, uid, &transactionImportReq, newTransactions)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.TransactionImportHandler] failed to get created accounts and categories of imported transactions for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, importBatch)
 	count := len(newTransactions)
 
 	if err != nil {
@@ -1255,13 +1376,122 @@ func (a *TransactionsApi) TransactionImportHandler(c *core.WebContext) (any, *er
 		return nil, errs.Or(err, errs.ErrOperationFailed)
 	}
 
-	log.Infof(c, "[transactions.TransactionImportHandler] user \"uid:%d\" has imported %d transactions successfully", uid, count)
+	log.Infof(c, "[transactions.TransactionImportHandler] user \"uid:%d\" has imported %d transactions successfully, %d of them matched transaction rules", uid, count, matchedRuleCount)
 
 	a.SetSubmissionRemark(duplicatechecker.DUPLICATE_CHECKER_TYPE_IMPORT_TRANSACTIONS, uid, transactionImportReq.ClientSessionId, utils.IntToString(count))
 
 	return count, nil
 }
 
+func (a *TransactionsApi) createNewImportBatchModel(c *core.WebContext, uid int64, transactionImportReq *models.TransactionImportRequest, transactions []*models.Transaction) (*models.TransactionImportBatch, error) {
+	accountIds := make([]int64, 0, len(transactions))
+	categoryIds := make([]int64, 0, len(transactions))
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+		accountIds = append(accountIds, transaction.AccountId)
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			accountIds = append(accountIds, transaction.RelatedAccountId)
+		}
+
+		if transaction.CategoryId > 0 {
+			categoryIds = append(categoryIds, transaction.CategoryId)
+		}
+	}
+
+	// the accounts and categories which are not used by any transaction before importing are created for this import batch
+	createdAccountIds, createdCategoryIds, err := a.importBatches.GetUnusedAccountAndCategoryIds(c, uid, utils.ToUniqueInt64Slice(accountIds), utils.ToUniqueInt64Slice(categoryIds))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TransactionImportBatch{
+		FileType:           transactionImportReq.FileType,
+		FileName:           transactionImportReq.FileName,
+		CreatedAccountIds:  strings.Join(utils.Int64ArrayToStringArray(createdAccountIds), ","),
+		CreatedCategoryIds: strings.Join(utils.Int64ArrayToStringArray(createdCategoryIds), ","),
+	}, nil
+}
+
+// getTransactionDataImporterByForm returns the file type and the data importer according to the file type (and import profile id for custom file) and the optional text encoding in multi-part form
+func getTransactionDataImporterByForm(c *core.WebContext, importProfiles *services.TransactionImportProfileService, uid int64, form *multipart.Form) (string, base.TransactionDataImporter, *errs.Error) {
+	fileType, dataImporter, errResp := getTransactionDataImporterByFileTypeInForm(c, importProfiles, uid, form)
+
+	if errResp != nil {
+		return "", nil, errResp
+	}
+
+	encodings := form.Value["encoding"]
+
+	if len(encodings) < 1 || encodings[0] == "" {
+		return fileType, dataImporter, nil
+	}
+
+	textEncoding := utils.GetTextEncodingByName(encodings[0])
+
+	if textEncoding == nil {
+		log.Warnf(c, "[transactions.getTransactionDataImporterByForm] text encoding \"%s\" is not supported", encodings[0])
+		return "", nil, errs.ErrImportFileEncodingNotSupported
+	}
+
+	// the text encoding is ignored if the file is not a text file
+	if textDataImporter, ok := dataImporter.(base.TextTransactionDataImporter); ok {
+		dataImporter = textDataImporter.WithTextEncoding(textEncoding)
+	}
+
+	return fileType, dataImporter, nil
+}
+
+func getTransactionDataImporterByFileTypeInForm(c *core.WebContext, importProfiles *services.TransactionImportProfileService, uid int64, form *multipart.Form) (string, base.TransactionDataImporter, *errs.Error) {
+	fileTypes := form.Value["fileType"]
+
+	if len(fileTypes) < 1 || fileTypes[0] == "" {
+		return "", nil, errs.ErrImportFileTypeIsEmpty
+	}
+
+	fileType := fileTypes[0]
+
+	if fileType != customImportFileType {
+		dataImporter, err := converters.GetTransactionDataImporter(fileType)
+
+		if err != nil {
+			return "", nil, errs.Or(err, errs.ErrImportFileTypeNotSupported)
+		}
+
+		return fileType, dataImporter, nil
+	}
+
+	profileIds := form.Value["profileId"]
+
+	if len(profileIds) < 1 || profileIds[0] == "" {
+		return "", nil, errs.ErrTransactionImportProfileIdInvalid
+	}
+
+	profileId, err := utils.StringToInt64(profileIds[0])
+
+	if err != nil {
+		log.Warnf(c, "[transactions.getTransactionDataImporterByFileTypeInForm] parse profile id \"%s\" failed, because %s", profileIds[0], err.Error())
+		return "", nil, errs.ErrTransactionImportProfileIdInvalid
+	}
+
+	profile, err := importProfiles.GetProfileByProfileId(c, uid, profileId)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getTransactionDataImporterByFileTypeInForm] failed to get transaction import profile \"id:%d\" for user \"uid:%d\", because %s", profileId, uid, err.Error())
+		return "", nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	dataImporter, err := converters.GetCustomTransactionDataImporter(profile)
+
+	if err != nil {
+		return "", nil, errs.Or(err, errs.ErrImportFileTypeNotSupported)
+	}
+
+	return fileType, dataImporter, nil
+}
+
 func (a *TransactionsApi) filterTransactions(c *core.WebContext, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account) []*models.Transaction {
 	finalTransactions := make([]*models.Transaction, 0, len(transactions))
 
@@ -1390,6 +1620,43 @@ func (a *TransactionsApi) getCategoryOrSubCategoryIds(c *core.WebContext, catego
 	return allCategoryIds, nil
 }
 
+func (a *TransactionsApi) getExchangeRateConverter(c *core.WebContext, uid int64) (*services.ExchangeRateConverter, error) {
+	user, err := a.users.GetUserById(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := a.accounts.GetAllAccountsByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	customExchangeRates, err := a.userCustomExchangeRates.GetAllCustomExchangeRatesByUid(c, uid)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.exchangeRates.NewExchangeRateConverter(c, user.DefaultCurrency, accounts, customExchangeRates), nil
+}
+
+func (a *TransactionsApi) getTransactionStatisticResponseItem(totalAmount *models.TransactionTotalAmount) *models.TransactionStatisticResponseItem {
+	statisticResponseItem := &models.TransactionStatisticResponseItem{
+		CategoryId:  totalAmount.CategoryId,
+		AccountId:   totalAmount.AccountId,
+		TotalAmount: totalAmount.Amount,
+	}
+
+	if totalAmount.HasAmountInDefaultCurrency {
+		amountInDefaultCurrency := totalAmount.AmountInDefaultCurrency
+		statisticResponseItem.AmountInDefaultCurrency = &amountInDefaultCurrency
+	}
+
+	return statisticResponseItem
+}
+
 func (a *TransactionsApi) getTagIds(tagIds string) ([]int64, error) {
 	if tagIds == "" || tagIds == "0" {
 		return nil, nil
@@ -1469,6 +1736,13 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 		return nil, err
 	}
 
+	allTransactionSplits, err := a.transactionSplits.GetSplitsByTransactionIds(c, uid, transactionIds)
+
+	if err != nil {
+		log.Errorf(c, "[transactions.getTransactionResponseListResult] failed to get transactions splits for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
 	var categoryMap map[int64]*models.TransactionCategory
 	var tagMap map[int64]*models.TransactionTag
 	var pictureInfoMap map[int64][]*models.TransactionPictureInfo
@@ -1512,6 +1786,7 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 		transactionEditable := transaction.IsEditable(user, utcOffset, allAccounts[transaction.AccountId], allAccounts[transaction.RelatedAccountId])
 		transactionTagIds := allTransactionTagIds[transaction.TransactionId]
 		result[i] = transaction.ToTransactionInfoResponse(transactionTagIds, transactionEditable)
+		result[i].Splits = allTransactionSplits[transaction.TransactionId].ToTransactionSplitInfoResponses()
 
 		if !trimAccount {
 			if sourceAccount := allAccounts[transaction.AccountId]; sourceAccount != nil {
@@ -1547,6 +1822,56 @@ func (a *TransactionsApi) getTransactionResponseListResult(c *core.WebContext, u
 	return result, nil
 }
 
+func (a *TransactionsApi) getTransactionSplitModels(transactionType models.TransactionType, transactionAmount int64, splitReqs []*models.TransactionSplitRequest) (models.TransactionSplitSlice, error) {
+	if len(splitReqs) < 1 {
+		return nil, nil
+	}
+
+	if transactionType != models.TRANSACTION_TYPE_INCOME && transactionType != models.TRANSACTION_TYPE_EXPENSE {
+		return nil, errs.ErrTransactionSplitsNotAllowed
+	}
+
+	if len(splitReqs) < 2 {
+		return nil, errs.ErrTransactionSplitsTooFew
+	}
+
+	if len(splitReqs) > maximumSplitsCountOfTransaction {
+		return nil, errs.ErrTransactionHasTooManySplits
+	}
+
+	splits := make(models.TransactionSplitSlice, len(splitReqs))
+
+	for i := 0; i < len(splitReqs); i++ {
+		splitReq := splitReqs[i]
+		tagIds, err := utils.StringArrayToInt64Array(splitReq.TagIds)
+
+		if err != nil {
+			return nil, errs.ErrTransactionTagIdInvalid
+		}
+
+		tagIds = utils.ToUniqueInt64Slice(tagIds)
+
+		if len(tagIds) > maximumTagsCountOfTransaction {
+			return nil, errs.ErrTransactionSplitHasTooManyTags
+		}
+
+		utils.Int64Sort(tagIds)
+
+		splits[i] = &models.TransactionSplit{
+			CategoryId: splitReq.CategoryId,
+			Amount:     splitReq.Amount,
+			TagIds:     strings.Join(utils.Int64ArrayToStringArray(tagIds), ","),
+			Comment:    splitReq.Comment,
+		}
+	}
+
+	if splits.GetTotalAmount() != transactionAmount {
+		return nil, errs.ErrTransactionSplitsAmountNotEqual
+	}
+
+	return splits, nil
+}
+
 func (a *TransactionsApi) createNewTransactionModel(uid int64, transactionCreateReq *models.TransactionCreateRequest, clientIp string) *models.Transaction {
 	var transactionDbType models.TransactionDbType
 
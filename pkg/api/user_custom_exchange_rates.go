@@ -0,0 +1,194 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/services"
+)
+
+// UserCustomExchangeRatesApi represents user custom exchange rate api
+type UserCustomExchangeRatesApi struct {
+	userCustomExchangeRates *services.UserCustomExchangeRateService
+}
+
+// Initialize a user custom exchange rate api singleton instance
+var (
+	UserCustomExchangeRates = &UserCustomExchangeRatesApi{
+		userCustomExchangeRates: services.UserCustomExchangeRates,
+	}
+)
+
+// CustomExchangeRateListHandler returns custom exchange rate list of current user
+func (a *UserCustomExchangeRatesApi) CustomExchangeRateListHandler(c *core.WebContext) (any, *errs.Error) {
+	uid := c.GetCurrentUid()
+	customExchangeRates, err := a.userCustomExchangeRates.GetAllCustomExchangeRatesByUid(c, uid)
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_exchange_rates.CustomExchangeRateListHandler] failed to get custom exchange rates for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	customExchangeRateResps := make(models.UserCustomExchangeRateInfoResponseSlice, len(customExchangeRates))
+
+	for i := 0; i < len(customExchangeRates); i++ {
+		customExchangeRateResps[i] = customExchangeRates[i].ToUserCustomExchangeRateInfoResponse()
+	}
+
+	sort.Sort(customExchangeRateResps)
+
+	return customExchangeRateResps, nil
+}
+
+// CustomExchangeRateGetHandler returns one specific custom exchange rate of current user
+func (a *UserCustomExchangeRatesApi) CustomExchangeRateGetHandler(c *core.WebContext) (any, *errs.Error) {
+	var customExchangeRateGetReq models.UserCustomExchangeRateGetRequest
+	err := c.ShouldBindQuery(&customExchangeRateGetReq)
+
+	if err != nil {
+		log.Warnf(c, "[user_custom_exchange_rates.CustomExchangeRateGetHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	customExchangeRate, err := a.userCustomExchangeRates.GetCustomExchangeRateByRateId(c, uid, customExchangeRateGetReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_exchange_rates.CustomExchangeRateGetHandler] failed to get custom exchange rate \"id:%d\" for user \"uid:%d\", because %s", customExchangeRateGetReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	return customExchangeRate.ToUserCustomExchangeRateInfoResponse(), nil
+}
+
+// CustomExchangeRateCreateHandler saves a new custom exchange rate by request parameters for current user
+func (a *UserCustomExchangeRatesApi) CustomExchangeRateCreateHandler(c *core.WebContext) (any, *errs.Error) {
+	var customExchangeRateCreateReq models.UserCustomExchangeRateCreateRequest
+	err := c.ShouldBindJSON(&customExchangeRateCreateReq)
+
+	if err != nil {
+		log.Warnf(c, "[user_custom_exchange_rates.CustomExchangeRateCreateHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	customExchangeRate := &models.UserCustomExchangeRate{
+		Uid:           uid,
+		Currency:      customExchangeRateCreateReq.Currency,
+		BaseCurrency:  customExchangeRateCreateReq.BaseCurrency,
+		Rate:          customExchangeRateCreateReq.Rate,
+		ValidFromDate: customExchangeRateCreateReq.ValidFromDate,
+	}
+
+	err = a.checkCustomExchangeRate(customExchangeRate)
+
+	if err != nil {
+		log.Warnf(c, "[user_custom_exchange_rates.CustomExchangeRateCreateHandler] custom exchange rate is invalid for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.userCustomExchangeRates.CreateCustomExchangeRate(c, customExchangeRate)
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_exchange_rates.CustomExchangeRateCreateHandler] failed to create custom exchange rate \"id:%d\" for user \"uid:%d\", because %s", customExchangeRate.RateId, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[user_custom_exchange_rates.CustomExchangeRateCreateHandler] user \"uid:%d\" has created a new custom exchange rate \"id:%d\" successfully", uid, customExchangeRate.RateId)
+
+	return customExchangeRate.ToUserCustomExchangeRateInfoResponse(), nil
+}
+
+// CustomExchangeRateModifyHandler saves an existed custom exchange rate by request parameters for current user
+func (a *UserCustomExchangeRatesApi) CustomExchangeRateModifyHandler(c *core.WebContext) (any, *errs.Error) {
+	var customExchangeRateModifyReq models.UserCustomExchangeRateModifyRequest
+	err := c.ShouldBindJSON(&customExchangeRateModifyReq)
+
+	if err != nil {
+		log.Warnf(c, "[user_custom_exchange_rates.CustomExchangeRateModifyHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	customExchangeRate, err := a.userCustomExchangeRates.GetCustomExchangeRateByRateId(c, uid, customExchangeRateModifyReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_exchange_rates.CustomExchangeRateModifyHandler] failed to get custom exchange rate \"id:%d\" for user \"uid:%d\", because %s", customExchangeRateModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	newCustomExchangeRate := &models.UserCustomExchangeRate{
+		RateId:        customExchangeRate.RateId,
+		Uid:           uid,
+		Currency:      customExchangeRateModifyReq.Currency,
+		BaseCurrency:  customExchangeRateModifyReq.BaseCurrency,
+		Rate:          customExchangeRateModifyReq.Rate,
+		ValidFromDate: customExchangeRateModifyReq.ValidFromDate,
+	}
+
+	if newCustomExchangeRate.Currency == customExchangeRate.Currency &&
+		newCustomExchangeRate.BaseCurrency == customExchangeRate.BaseCurrency &&
+		newCustomExchangeRate.Rate == customExchangeRate.Rate &&
+		newCustomExchangeRate.ValidFromDate == customExchangeRate.ValidFromDate {
+		return nil, errs.ErrNothingWillBeUpdated
+	}
+
+	err = a.checkCustomExchangeRate(newCustomExchangeRate)
+
+	if err != nil {
+		log.Warnf(c, "[user_custom_exchange_rates.CustomExchangeRateModifyHandler] custom exchange rate \"id:%d\" is invalid for user \"uid:%d\", because %s", customExchangeRateModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	err = a.userCustomExchangeRates.ModifyCustomExchangeRate(c, newCustomExchangeRate)
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_exchange_rates.CustomExchangeRateModifyHandler] failed to update custom exchange rate \"id:%d\" for user \"uid:%d\", because %s", customExchangeRateModifyReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[user_custom_exchange_rates.CustomExchangeRateModifyHandler] user \"uid:%d\" has updated custom exchange rate \"id:%d\" successfully", uid, customExchangeRateModifyReq.Id)
+
+	return newCustomExchangeRate.ToUserCustomExchangeRateInfoResponse(), nil
+}
+
+// CustomExchangeRateDeleteHandler deletes an existed custom exchange rate by request parameters for current user
+func (a *UserCustomExchangeRatesApi) CustomExchangeRateDeleteHandler(c *core.WebContext) (any, *errs.Error) {
+	var customExchangeRateDeleteReq models.UserCustomExchangeRateDeleteRequest
+	err := c.ShouldBindJSON(&customExchangeRateDeleteReq)
+
+	if err != nil {
+		log.Warnf(c, "[user_custom_exchange_rates.CustomExchangeRateDeleteHandler] parse request failed, because %s", err.Error())
+		return nil, errs.NewIncompleteOrIncorrectSubmissionError(err)
+	}
+
+	uid := c.GetCurrentUid()
+	err = a.userCustomExchangeRates.DeleteCustomExchangeRate(c, uid, customExchangeRateDeleteReq.Id)
+
+	if err != nil {
+		log.Errorf(c, "[user_custom_exchange_rates.CustomExchangeRateDeleteHandler] failed to delete custom exchange rate \"id:%d\" for user \"uid:%d\", because %s", customExchangeRateDeleteReq.Id, uid, err.Error())
+		return nil, errs.Or(err, errs.ErrOperationFailed)
+	}
+
+	log.Infof(c, "[user_custom_exchange_rates.CustomExchangeRateDeleteHandler] user \"uid:%d\" has deleted custom exchange rate \"id:%d\"", uid, customExchangeRateDeleteReq.Id)
+	return true, nil
+}
+
+func (a *UserCustomExchangeRatesApi) checkCustomExchangeRate(customExchangeRate *models.UserCustomExchangeRate) error {
+	if customExchangeRate.Currency == customExchangeRate.BaseCurrency {
+		return errs.ErrUserCustomExchangeRateCurrencySameAsBase
+	}
+
+	if !customExchangeRate.IsValidRate() {
+		return errs.ErrExchangeRateInvalid
+	}
+
+	if !customExchangeRate.IsValidValidFromDate() {
+		return errs.ErrUserCustomExchangeRateValidFromDateInvalid
+	}
+
+	return nil
+}
@@ -25,10 +25,12 @@ type UserDataCli struct {
 	transactions            *services.TransactionService
 	categories              *services.TransactionCategoryService
 	tags                    *services.TransactionTagService
+	splits                  *services.TransactionSplitService
 	users                   *services.UserService
 	twoFactorAuthorizations *services.TwoFactorAuthorizationService
 	tokens                  *services.TokenService
 	forgetPasswords         *services.ForgetPasswordService
+	backups                 *services.UserDataBackupService
 }
 
 // Initialize a user data cli singleton instance
@@ -41,10 +43,12 @@ var (
 		transactions:            services.Transactions,
 		categories:              services.TransactionCategories,
 		tags:                    services.TransactionTags,
+		splits:                  services.TransactionSplits,
 		users:                   services.Users,
 		twoFactorAuthorizations: services.TwoFactorAuthorizations,
 		tokens:                  services.Tokens,
 		forgetPasswords:         services.ForgetPasswords,
+		backups:                 services.UserDataBackups,
 	}
 )
 
@@ -718,13 +722,20 @@ func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fil
 		return nil, err
 	}
 
+	allSplits, err := l.splits.GetAllSplitsMapOfAllTransactions(c, uid)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get transaction splits for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
 	dataExporter := converters.GetTransactionDataExporter(fileType)
 
 	if dataExporter == nil {
 		return nil, errs.ErrNotImplemented
 	}
 
-	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap)
+	result, err := dataExporter.ToExportedContent(c, uid, allTransactions, accountMap, categoryMap, tagMap, tagIndexesMap, allSplits)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ExportTransaction] failed to get csv format exported data for \"%s\", because %s", username, err.Error())
@@ -734,7 +745,7 @@ func (l *UserDataCli) ExportTransaction(c *core.CliContext, username string, fil
 	return result, nil
 }
 
-func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fileType string, data []byte) error {
+func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fileType string, fileName string, data []byte) error {
 	if username == "" {
 		log.CliErrorf(c, "[user_data.ImportTransaction] user name is empty")
 		return errs.ErrUsernameIsEmpty
@@ -810,7 +821,12 @@ func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fil
 		return errs.ErrOperationFailed
 	}
 
-	err = l.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap)
+	importBatch := &models.TransactionImportBatch{
+		FileType: fileType,
+		FileName: fileName,
+	}
+
+	err = l.transactions.BatchCreateTransactions(c, user.Uid, newTransactions, newTransactionTagIdsMap, importBatch)
 
 	if err != nil {
 		log.CliErrorf(c, "[user_data.ImportTransaction] failed to create transaction, because %s", err.Error())
@@ -820,6 +836,54 @@ func (l *UserDataCli) ImportTransaction(c *core.CliContext, username string, fil
 	return nil
 }
 
+// BackupUserData returns the user data backup archive which contains all data of specified user
+func (l *UserDataCli) BackupUserData(c *core.CliContext, username string) ([]byte, error) {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.BackupUserData] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
+	}
+
+	user, err := l.GetUserByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.BackupUserData] failed to get user by user name \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	result, err := l.backups.ExportUserDataBackupArchive(c, user)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.BackupUserData] failed to export user data backup for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RestoreUserData restores all data in the user data backup archive to specified user who does not have any data
+func (l *UserDataCli) RestoreUserData(c *core.CliContext, username string, data []byte) (*models.UserDataBackup, error) {
+	if username == "" {
+		log.CliErrorf(c, "[user_data.RestoreUserData] user name is empty")
+		return nil, errs.ErrUsernameIsEmpty
+	}
+
+	user, err := l.GetUserByUsername(c, username)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RestoreUserData] failed to get user by user name \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	backup, err := l.backups.RestoreUserDataBackupArchive(c, user, data)
+
+	if err != nil {
+		log.CliErrorf(c, "[user_data.RestoreUserData] failed to restore user data backup for user \"%s\", because %s", username, err.Error())
+		return nil, err
+	}
+
+	return backup, nil
+}
+
 func (l *UserDataCli) getUserIdByUsername(c *core.CliContext, username string) (int64, error) {
 	user, err := l.GetUserByUsername(c, username)
 
@@ -0,0 +1,75 @@
+package actualbudget
+
+import (
+	"golang.org/x/text/encoding"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/csv"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+var actualBudgetTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:     true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:     true,
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:         true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+}
+
+var actualBudgetTransactionTypeNameMapping = map[models.TransactionType]string{
+	models.TRANSACTION_TYPE_MODIFY_BALANCE: utils.IntToString(int(models.TRANSACTION_TYPE_MODIFY_BALANCE)),
+	models.TRANSACTION_TYPE_INCOME:         utils.IntToString(int(models.TRANSACTION_TYPE_INCOME)),
+	models.TRANSACTION_TYPE_EXPENSE:        utils.IntToString(int(models.TRANSACTION_TYPE_EXPENSE)),
+	models.TRANSACTION_TYPE_TRANSFER:       utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER)),
+}
+
+// actualBudgetTransactionDataCsvFileImporter defines the structure of actual budget csv importer for transaction data
+type actualBudgetTransactionDataCsvFileImporter struct {
+	textEncoding encoding.Encoding
+}
+
+// Initialize an actual budget transaction data csv file importer singleton instance
+var (
+	ActualBudgetTransactionDataCsvFileImporter = &actualBudgetTransactionDataCsvFileImporter{}
+)
+
+// ParseImportedData returns the imported data by parsing the actual budget transaction csv data
+func (c *actualBudgetTransactionDataCsvFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	dataTable, err := csv.CreateNewCsvImportedDataTableFromData(ctx, data, c.textEncoding)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	commonDataTable := datatable.CreateNewImportedCommonDataTable(dataTable)
+
+	if !commonDataTable.HasColumn(actualBudgetTransactionAccountColumnName) ||
+		!commonDataTable.HasColumn(actualBudgetTransactionDateColumnName) ||
+		!commonDataTable.HasColumn(actualBudgetTransactionPayeeColumnName) ||
+		!commonDataTable.HasColumn(actualBudgetTransactionAmountColumnName) {
+		log.Errorf(ctx, "[actualbudget_transaction_data_csv_file_importer.ParseImportedData] cannot parse actual budget csv data, because missing essential columns in header row")
+		return nil, nil, nil, nil, nil, nil, errs.ErrMissingRequiredFieldInHeaderRow
+	}
+
+	transactionRowParser := createActualBudgetTransactionDataRowParser(commonDataTable)
+	transactionDataTable := datatable.CreateNewCommonTransactionDataTable(commonDataTable, actualBudgetTransactionSupportedColumns, transactionRowParser)
+	dataTableImporter := datatable.CreateNewSimpleImporter(actualBudgetTransactionTypeNameMapping).WithCategoryHierarchyNameMatching()
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
+
+// WithTextEncoding returns a copy of the importer which decodes the imported data by the specified text encoding
+func (c *actualBudgetTransactionDataCsvFileImporter) WithTextEncoding(enc encoding.Encoding) base.TransactionDataImporter {
+	importer := *c
+	importer.textEncoding = enc
+
+	return &importer
+}
@@ -0,0 +1,266 @@
+package actualbudget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestActualBudgetCsvFileImporterParseImportedData_MinimumValidData(t *testing.T) {
+	converter := ActualBudgetTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	data := "Account,Date,Payee,Notes,Category,Amount,Split_Amount,Cleared,Reconciled\n" +
+		"Checking,2024-09-01,Starting Balance,,Starting Balances,123.45,0,true,false\n" +
+		"Checking,2024-09-02,Employer,,Income,0.12,0,true,false\n" +
+		"Checking,2024-09-03,Grocery Store,,Food,-1.00,0,true,false\n" +
+		"Checking,2024-09-04,Transfer : Savings,,,-0.05,0,true,false\n" +
+		"Savings,2024-09-04,Transfer : Checking,,,0.05,0,true,false\n"
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(data), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 0, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[1].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-02 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(12), allNewTransactions[1].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Income", allNewTransactions[1].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[2].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, "2024-09-03 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime), time.UTC))
+	assert.Equal(t, int64(100), allNewTransactions[2].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Food", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[3].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, "2024-09-04 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime), time.UTC))
+	assert.Equal(t, int64(5), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(5), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "", allNewTransactions[3].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[0].Uid)
+	assert.Equal(t, "Checking", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[1].Uid)
+	assert.Equal(t, "Savings", allNewAccounts[1].Name)
+	assert.Equal(t, "USD", allNewAccounts[1].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewSubExpenseCategories[0].Uid)
+	assert.Equal(t, "Food", allNewSubExpenseCategories[0].Name)
+
+	assert.Equal(t, int64(1234567890), allNewSubIncomeCategories[0].Uid)
+	assert.Equal(t, "Income", allNewSubIncomeCategories[0].Name)
+}
+
+func TestActualBudgetCsvFileImporterParseImportedData_ParseInvalidTime(t *testing.T) {
+	converter := ActualBudgetTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Amount\n"+
+		"Checking,09/01/2024,Store,-1.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
+
+func TestActualBudgetCsvFileImporterParseImportedData_ParseInvalidAmount(t *testing.T) {
+	converter := ActualBudgetTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Amount\n"+
+		"Checking,2024-09-01,Store,1.2.3\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
+
+func TestActualBudgetCsvFileImporterParseImportedData_ParseCategoryGroup(t *testing.T) {
+	converter := ActualBudgetTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Category Group,Category,Amount\n"+
+		"Checking,2024-09-01,Store,Usual Expenses,Food,-1.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "Usual Expenses", allNewTransactions[0].OriginalParentCategoryName)
+	assert.Equal(t, "Food", allNewTransactions[0].OriginalCategoryName)
+}
+
+func TestActualBudgetCsvFileImporterParseImportedData_ParseSameCategoryNameInDifferentCategoryGroups(t *testing.T) {
+	converter := ActualBudgetTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	expenseCategoryMap := map[string]*models.TransactionCategory{
+		models.GetTransactionCategoryHierarchyName("Usual Expenses", "Food"): {
+			CategoryId: 1234567891,
+			Name:       "Food",
+			Type:       models.CATEGORY_TYPE_EXPENSE,
+		},
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Category Group,Category,Amount\n"+
+		"Checking,2024-09-01,Store,Usual Expenses,Food,-1.00\n"+
+		"Checking,2024-09-02,Store,Travel,Food,-2.00\n"+
+		"Checking,2024-09-03,Store,Travel,Food,-3.00\n"), 0, nil, expenseCategoryMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, "Food", allNewSubExpenseCategories[0].Name)
+
+	assert.Equal(t, int64(1234567891), allNewTransactions[0].CategoryId)
+	assert.Equal(t, int64(0), allNewTransactions[1].CategoryId)
+	assert.Equal(t, "Travel", allNewTransactions[1].OriginalParentCategoryName)
+	assert.Equal(t, int64(0), allNewTransactions[2].CategoryId)
+	assert.Equal(t, "Travel", allNewTransactions[2].OriginalParentCategoryName)
+}
+
+func TestActualBudgetCsvFileImporterParseImportedData_ParseTransfer(t *testing.T) {
+	converter := ActualBudgetTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Amount\n"+
+		"Checking,2024-09-01,Transfer: Savings,-1.00\n"+
+		"Savings,2024-09-01,Transfer: Checking,1.00\n"+
+		"Checking,2024-09-02,Transfer Station,-2.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, int64(100), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[0].OriginalDestinationAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(200), allNewTransactions[1].Amount)
+	assert.Equal(t, "Transfer Station", allNewTransactions[1].Comment)
+}
+
+func TestActualBudgetCsvFileImporterParseImportedData_ParseUnmatchedInflowSideOfTransfer(t *testing.T) {
+	converter := ActualBudgetTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Amount\n"+
+		"Savings,2024-09-01,Transfer: Checking,1.00\n"+
+		"Checking,2024-09-01,Transfer: Savings,-2.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, int64(100), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[0].OriginalDestinationAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[1].Type)
+	assert.Equal(t, int64(200), allNewTransactions[1].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[1].OriginalDestinationAccountName)
+}
+
+func TestActualBudgetCsvFileImporterParseImportedData_ParseDescription(t *testing.T) {
+	converter := ActualBudgetTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Notes,Amount\n"+
+		"Checking,2024-09-01,Grocery Store,weekly shopping,-1.00\n"+
+		"Checking,2024-09-02,Grocery Store,,-1.00\n"+
+		"Checking,2024-09-03,,weekly shopping,-1.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, "Grocery Store - weekly shopping", allNewTransactions[0].Comment)
+	assert.Equal(t, "Grocery Store", allNewTransactions[1].Comment)
+	assert.Equal(t, "weekly shopping", allNewTransactions[2].Comment)
+}
+
+func TestActualBudgetCsvFileImporterParseImportedData_MissingRequiredColumn(t *testing.T) {
+	converter := ActualBudgetTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	// Missing Account Column
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Date,Payee,Amount\n"+
+		"2024-09-01,Store,-1.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	// Missing Date Column
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Account,Payee,Amount\n"+
+		"Checking,Store,-1.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	// Missing Payee Column
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Account,Date,Amount\n"+
+		"Checking,2024-09-01,-1.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	// Missing Amount Column
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Account,Date,Payee\n"+
+		"Checking,2024-09-01,Store\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+}
@@ -0,0 +1,201 @@
+package actualbudget
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const actualBudgetTransactionAccountColumnName = "Account"
+const actualBudgetTransactionDateColumnName = "Date"
+const actualBudgetTransactionPayeeColumnName = "Payee"
+const actualBudgetTransactionNotesColumnName = "Notes"
+const actualBudgetTransactionCategoryGroupColumnName = "Category Group"
+const actualBudgetTransactionCategoryColumnName = "Category"
+const actualBudgetTransactionAmountColumnName = "Amount"
+
+const actualBudgetTransactionTransferPayeePrefix = "Transfer"
+const actualBudgetTransactionTransferPayeeSeparator = ":"
+const actualBudgetTransactionStartingBalancePayee = "Starting Balance"
+
+// actualBudgetTransactionDataRowParser defines the structure of actual budget transaction data row parser
+type actualBudgetTransactionDataRowParser struct {
+	matchedTransferInflowRowIds map[string]bool
+}
+
+// Parse returns the converted transaction data row
+func (p *actualBudgetTransactionDataRowParser) Parse(ctx core.Context, user *models.User, dataTable *datatable.CommonTransactionDataTable, dataRow datatable.CommonDataRow, rowId string) (rowData map[datatable.TransactionDataTableColumn]string, rowDataValid bool, err error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(actualBudgetTransactionSupportedColumns))
+
+	date := strings.TrimSpace(dataRow.GetData(actualBudgetTransactionDateColumnName))
+
+	if !utils.IsValidLongDateFormat(date) {
+		log.Errorf(ctx, "[actualbudget_transaction_data_row_parser.Parse] cannot parse date \"%s\" of transaction in row \"%s\"", date, rowId)
+		return nil, false, errs.ErrTransactionTimeInvalid
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = date + " 00:00:00"
+
+	amount, err := p.parseAmount(dataRow)
+
+	if err != nil {
+		log.Errorf(ctx, "[actualbudget_transaction_data_row_parser.Parse] cannot parse amount \"%s\" of transaction in row \"%s\"", dataRow.GetData(actualBudgetTransactionAmountColumnName), rowId)
+		return nil, false, errs.ErrAmountInvalid
+	}
+
+	payee := strings.TrimSpace(dataRow.GetData(actualBudgetTransactionPayeeColumnName))
+	notes := ""
+
+	if dataTable.HasOriginalColumn(actualBudgetTransactionNotesColumnName) {
+		notes = strings.TrimSpace(dataRow.GetData(actualBudgetTransactionNotesColumnName))
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = dataRow.GetData(actualBudgetTransactionAccountColumnName)
+	data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = ""
+	data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = ""
+	data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = ""
+
+	if dataTable.HasOriginalColumn(actualBudgetTransactionCategoryGroupColumnName) {
+		data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = strings.TrimSpace(dataRow.GetData(actualBudgetTransactionCategoryGroupColumnName))
+	}
+
+	if dataTable.HasOriginalColumn(actualBudgetTransactionCategoryColumnName) {
+		data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = strings.TrimSpace(dataRow.GetData(actualBudgetTransactionCategoryColumnName))
+	}
+
+	if relatedAccountName, isTransfer := p.getTransferAccountName(payee); isTransfer {
+		// both sides of transfer are exported in actual budget, so the inflow side which matches an outflow side is skipped
+		if p.matchedTransferInflowRowIds[rowId] {
+			log.Warnf(ctx, "[actualbudget_transaction_data_row_parser.Parse] skip parsing transaction in row \"%s\", because it is the inflow side of transfer", rowId)
+			return nil, false, nil
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualBudgetTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = notes
+
+		if amount > 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = relatedAccountName
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = dataRow.GetData(actualBudgetTransactionAccountColumnName)
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = relatedAccountName
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+		}
+
+		return data, true, nil
+	}
+
+	if payee == actualBudgetTransactionStartingBalancePayee {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualBudgetTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = notes
+
+		return data, true, nil
+	}
+
+	if amount < 0 {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualBudgetTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = actualBudgetTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+	}
+
+	if payee != "" && notes != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = payee + " - " + notes
+	} else if payee != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = payee
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = notes
+	}
+
+	return data, true, nil
+}
+
+// getTransferAccountName returns the related account name if the payee is transfer payee (e.g. "Transfer : Savings" or "Transfer: Savings")
+func (p *actualBudgetTransactionDataRowParser) getTransferAccountName(payee string) (string, bool) {
+	if !strings.HasPrefix(payee, actualBudgetTransactionTransferPayeePrefix) {
+		return "", false
+	}
+
+	remain := strings.TrimLeft(payee[len(actualBudgetTransactionTransferPayeePrefix):], " ")
+
+	if !strings.HasPrefix(remain, actualBudgetTransactionTransferPayeeSeparator) {
+		return "", false
+	}
+
+	relatedAccountName := strings.TrimSpace(remain[len(actualBudgetTransactionTransferPayeeSeparator):])
+
+	if relatedAccountName == "" {
+		return "", false
+	}
+
+	return relatedAccountName, true
+}
+
+func (p *actualBudgetTransactionDataRowParser) parseAmount(dataRow datatable.CommonDataRow) (int64, error) {
+	return utils.ParseAmount(utils.TrimTrailingZerosInDecimal(strings.ReplaceAll(strings.TrimSpace(dataRow.GetData(actualBudgetTransactionAmountColumnName)), ",", "")))
+}
+
+// getMatchedTransferInflowRowIds returns the row ids of inflow side of transfers which have the corresponding outflow side in the data table
+func (p *actualBudgetTransactionDataRowParser) getMatchedTransferInflowRowIds(commonDataTable datatable.CommonDataTable) map[string]bool {
+	outflowTransferCounts := make(map[string]int)
+	inflowTransferRowIds := make([]string, 0)
+	inflowTransferKeys := make([]string, 0)
+	iterator := commonDataTable.DataRowIterator()
+
+	for iterator.HasNext() {
+		dataRow := iterator.Next()
+		rowId := iterator.CurrentRowId()
+		relatedAccountName, isTransfer := p.getTransferAccountName(strings.TrimSpace(dataRow.GetData(actualBudgetTransactionPayeeColumnName)))
+
+		if !isTransfer {
+			continue
+		}
+
+		amount, err := p.parseAmount(dataRow)
+
+		if err != nil {
+			continue
+		}
+
+		accountName := strings.TrimSpace(dataRow.GetData(actualBudgetTransactionAccountColumnName))
+		date := strings.TrimSpace(dataRow.GetData(actualBudgetTransactionDateColumnName))
+
+		if amount > 0 {
+			inflowTransferRowIds = append(inflowTransferRowIds, rowId)
+			inflowTransferKeys = append(inflowTransferKeys, p.getTransferKey(relatedAccountName, accountName, date, amount))
+		} else {
+			outflowTransferCounts[p.getTransferKey(accountName, relatedAccountName, date, -amount)]++
+		}
+	}
+
+	matchedInflowRowIds := make(map[string]bool, len(inflowTransferRowIds))
+
+	for i := 0; i < len(inflowTransferRowIds); i++ {
+		if outflowTransferCounts[inflowTransferKeys[i]] > 0 {
+			outflowTransferCounts[inflowTransferKeys[i]]--
+			matchedInflowRowIds[inflowTransferRowIds[i]] = true
+		}
+	}
+
+	return matchedInflowRowIds
+}
+
+func (p *actualBudgetTransactionDataRowParser) getTransferKey(sourceAccountName string, destinationAccountName string, date string, amount int64) string {
+	return fmt.Sprintf("%s\n%s\n%s\n%d", sourceAccountName, destinationAccountName, date, amount)
+}
+
+// createActualBudgetTransactionDataRowParser returns actual budget transaction data row parser
+func createActualBudgetTransactionDataRowParser(commonDataTable datatable.CommonDataTable) datatable.CommonTransactionDataRowParser {
+	parser := &actualBudgetTransactionDataRowParser{}
+	parser.matchedTransferInflowRowIds = parser.getMatchedTransferInflowRowIds(commonDataTable)
+
+	return parser
+}
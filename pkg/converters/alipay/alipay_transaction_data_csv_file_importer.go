@@ -6,9 +6,10 @@ import (
 	"io"
 	"strings"
 
+	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/simplifiedchinese"
-	"golang.org/x/text/transform"
 
+	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
 	csvdatatable "github.com/mayswind/ezbookkeeping/pkg/converters/csv"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
@@ -53,12 +54,26 @@ type alipayTransactionDataCsvFileImporter struct {
 	dataHeaderStartContent string
 	dataBottomEndLineRune  rune
 	originalColumnNames    alipayTransactionColumnNames
+	textEncoding           encoding.Encoding
 }
 
 // ParseImportedData returns the imported data by parsing the alipay transaction csv data
 func (c *alipayTransactionDataCsvFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
-	enc := simplifiedchinese.GB18030
-	reader := transform.NewReader(bytes.NewReader(data), enc.NewDecoder())
+	enc := c.textEncoding
+
+	// the alipay transaction files are always encoded by gb18030 unless the text encoding is specified
+	if enc == nil {
+		enc = simplifiedchinese.GB18030
+	}
+
+	content, err := utils.DecodeTextData(data, enc)
+
+	if err != nil {
+		log.Errorf(ctx, "[alipay_transaction_data_csv_file_importer.ParseImportedData] cannot decode imported data, because %s", err.Error())
+		return nil, nil, nil, nil, nil, nil, errs.ErrInvalidCSVFile
+	}
+
+	reader := bytes.NewReader(content)
 
 	dataTable, err := c.createNewAlipayImportedDataTable(ctx, reader, c.fileHeaderLine, c.dataHeaderStartContent, c.dataBottomEndLineRune)
 
@@ -83,6 +98,14 @@ func (c *alipayTransactionDataCsvFileImporter) ParseImportedData(ctx core.Contex
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
 
+// WithTextEncoding returns a copy of the importer which decodes the imported data by the specified text encoding
+func (c *alipayTransactionDataCsvFileImporter) WithTextEncoding(enc encoding.Encoding) base.TransactionDataImporter {
+	importer := *c
+	importer.textEncoding = enc
+
+	return &importer
+}
+
 func (c *alipayTransactionDataCsvFileImporter) createNewAlipayImportedDataTable(ctx core.Context, reader io.Reader, fileHeaderLine string, dataHeaderStartContent string, dataBottomEndLineRune rune) (datatable.ImportedDataTable, error) {
 	csvReader := csv.NewReader(reader)
 	csvReader.FieldsPerRecord = -1
@@ -1,6 +1,10 @@
 package base
 
 import (
+	"io"
+
+	"golang.org/x/text/encoding"
+
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
 )
@@ -8,7 +12,16 @@ import (
 // TransactionDataExporter defines the structure of transaction data exporter
 type TransactionDataExporter interface {
 	// ToExportedContent returns the exported data
-	ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error)
+	ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64]models.TransactionSplitSlice) ([]byte, error)
+}
+
+// TransactionDataStreamExporter defines the structure of transaction data exporter which can write the exported data to a writer batch by batch
+type TransactionDataStreamExporter interface {
+	// WriteExportedHeader writes the header of exported data to the writer
+	WriteExportedHeader(ctx core.Context, writer io.Writer) error
+
+	// WriteExportedContent writes the exported data of the specified transactions to the writer
+	WriteExportedContent(ctx core.Context, writer io.Writer, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64]models.TransactionSplitSlice) error
 }
 
 // TransactionDataImporter defines the structure of transaction data importer
@@ -17,6 +30,14 @@ type TransactionDataImporter interface {
 	ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error)
 }
 
+// TextTransactionDataImporter defines the structure of transaction data importer which parses text data in the specified or detected text encoding
+type TextTransactionDataImporter interface {
+	TransactionDataImporter
+
+	// WithTextEncoding returns a copy of the importer which decodes the imported data by the specified text encoding
+	WithTextEncoding(enc encoding.Encoding) TransactionDataImporter
+}
+
 // TransactionDataConverter defines the structure of transaction data converter
 type TransactionDataConverter interface {
 	TransactionDataExporter
@@ -0,0 +1,214 @@
+package camt
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// camtCreditDebitIndicator represents the credit or debit indicator of entry in camt file
+type camtCreditDebitIndicator string
+
+// CAMT credit or debit indicators
+const (
+	camtCreditIndicator camtCreditDebitIndicator = "CRDT"
+	camtDebitIndicator  camtCreditDebitIndicator = "DBIT"
+)
+
+// camtEntryStatusCode represents the status of entry in camt file
+type camtEntryStatusCode string
+
+// CAMT entry status codes
+const (
+	camtBookedEntryStatus      camtEntryStatusCode = "BOOK"
+	camtPendingEntryStatus     camtEntryStatusCode = "PDNG"
+	camtInformationEntryStatus camtEntryStatusCode = "INFO"
+)
+
+const camtDefaultTimezoneOffset = "+00:00"
+
+// camtDocument represents the struct of iso 20022 cash management (camt) document
+type camtDocument struct {
+	XMLName                        xml.Name            `xml:"Document"`
+	BankToCustomerStatement        *camtStatementGroup `xml:"BkToCstmrStmt,omitempty"`
+	BankToCustomerAccountReport    *camtStatementGroup `xml:"BkToCstmrAcctRpt,omitempty"`
+	BankToCustomerDebitCreditNotes *camtStatementGroup `xml:"BkToCstmrDbtCdtNtfctn,omitempty"`
+}
+
+// camtStatementGroup represents the struct of bank to customer statement (camt.053), account report (camt.052) or debit credit notification (camt.054)
+type camtStatementGroup struct {
+	Statements    []*camtStatement `xml:"Stmt"`
+	Reports       []*camtStatement `xml:"Rpt"`
+	Notifications []*camtStatement `xml:"Ntfctn"`
+}
+
+// camtStatement represents the struct of statement, report or notification in camt file
+type camtStatement struct {
+	Id       string         `xml:"Id"`
+	Account  *camtAccount   `xml:"Acct"`
+	Entries  []*camtEntry   `xml:"Ntry"`
+	Balances []*camtBalance `xml:"Bal"`
+}
+
+// camtAccount represents the struct of cash account in camt file
+type camtAccount struct {
+	Id       *camtAccountId `xml:"Id"`
+	Currency string         `xml:"Ccy"`
+	Name     string         `xml:"Nm"`
+}
+
+// camtAccountId represents the struct of cash account identification in camt file
+type camtAccountId struct {
+	IBAN  string                     `xml:"IBAN"`
+	Other *camtGenericIdentification `xml:"Othr"`
+}
+
+// camtGenericIdentification represents the struct of generic identification in camt file
+type camtGenericIdentification struct {
+	Id string `xml:"Id"`
+}
+
+// camtBalance represents the struct of balance in camt file
+type camtBalance struct {
+	Amount               *camtAmount              `xml:"Amt"`
+	CreditDebitIndicator camtCreditDebitIndicator `xml:"CdtDbtInd"`
+}
+
+// camtAmount represents the struct of amount with currency in camt file
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+// camtDate represents the struct of date or date time in camt file
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+// camtEntryStatus represents the struct of entry status in camt file, it is a code in camt.05x.001.02 and a structure since camt.05x.001.08
+type camtEntryStatus struct {
+	Value string `xml:",chardata"`
+	Code  string `xml:"Cd"`
+}
+
+// camtEntry represents the struct of entry in camt file
+type camtEntry struct {
+	EntryReference             string                   `xml:"NtryRef"`
+	Amount                     *camtAmount              `xml:"Amt"`
+	CreditDebitIndicator       camtCreditDebitIndicator `xml:"CdtDbtInd"`
+	ReversalIndicator          string                   `xml:"RvslInd"`
+	Status                     *camtEntryStatus         `xml:"Sts"`
+	BookingDate                *camtDate                `xml:"BookgDt"`
+	ValueDate                  *camtDate                `xml:"ValDt"`
+	AccountServicerReference   string                   `xml:"AcctSvcrRef"`
+	EntryDetails               []*camtEntryDetails      `xml:"NtryDtls"`
+	AdditionalEntryInformation string                   `xml:"AddtlNtryInf"`
+}
+
+// camtEntryDetails represents the struct of entry details in camt file
+type camtEntryDetails struct {
+	TransactionDetails []*camtTransactionDetails `xml:"TxDtls"`
+}
+
+// camtTransactionDetails represents the struct of transaction details in camt file
+type camtTransactionDetails struct {
+	References                       *camtTransactionReferences `xml:"Refs"`
+	Amount                           *camtAmount                `xml:"Amt"`
+	AmountDetails                    *camtAmountDetails         `xml:"AmtDtls"`
+	CreditDebitIndicator             camtCreditDebitIndicator   `xml:"CdtDbtInd"`
+	RelatedParties                   *camtRelatedParties        `xml:"RltdPties"`
+	RemittanceInformation            *camtRemittanceInformation `xml:"RmtInf"`
+	AdditionalTransactionInformation string                     `xml:"AddtlTxInf"`
+}
+
+// camtTransactionReferences represents the struct of transaction references in camt file
+type camtTransactionReferences struct {
+	EndToEndId               string `xml:"EndToEndId"`
+	AccountServicerReference string `xml:"AcctSvcrRef"`
+}
+
+// camtAmountDetails represents the struct of amount details in camt file
+type camtAmountDetails struct {
+	TransactionAmount *camtAmountAndCurrencyExchange `xml:"TxAmt"`
+}
+
+// camtAmountAndCurrencyExchange represents the struct of amount and currency exchange in camt file
+type camtAmountAndCurrencyExchange struct {
+	Amount *camtAmount `xml:"Amt"`
+}
+
+// camtRelatedParties represents the struct of related parties in camt file
+type camtRelatedParties struct {
+	Debtor           *camtParty   `xml:"Dbtr"`
+	DebtorAccount    *camtAccount `xml:"DbtrAcct"`
+	UltimateDebtor   *camtParty   `xml:"UltmtDbtr"`
+	Creditor         *camtParty   `xml:"Cdtr"`
+	CreditorAccount  *camtAccount `xml:"CdtrAcct"`
+	UltimateCreditor *camtParty   `xml:"UltmtCdtr"`
+}
+
+// camtParty represents the struct of party in camt file, the name is directly in party in camt.05x.001.02 and in sub party since camt.05x.001.08
+type camtParty struct {
+	Name  string     `xml:"Nm"`
+	Party *camtParty `xml:"Pty"`
+}
+
+// camtRemittanceInformation represents the struct of remittance information in camt file
+type camtRemittanceInformation struct {
+	Unstructured []string                               `xml:"Ustrd"`
+	Structured   []*camtStructuredRemittanceInformation `xml:"Strd"`
+}
+
+// camtStructuredRemittanceInformation represents the struct of structured remittance information in camt file
+type camtStructuredRemittanceInformation struct {
+	CreditorReferenceInformation    *camtCreditorReferenceInformation `xml:"CdtrRefInf"`
+	AdditionalRemittanceInformation []string                          `xml:"AddtlRmtInf"`
+}
+
+// camtCreditorReferenceInformation represents the struct of creditor reference information in camt file
+type camtCreditorReferenceInformation struct {
+	Reference string `xml:"Ref"`
+}
+
+// GetAccountId returns the iban or other identification of the account
+func (a *camtAccount) GetAccountId() string {
+	if a == nil || a.Id == nil {
+		return ""
+	}
+
+	if a.Id.IBAN != "" {
+		return a.Id.IBAN
+	}
+
+	if a.Id.Other != nil {
+		return a.Id.Other.Id
+	}
+
+	return ""
+}
+
+// GetName returns the name of the party
+func (p *camtParty) GetName() string {
+	if p == nil {
+		return ""
+	}
+
+	if p.Name != "" {
+		return p.Name
+	}
+
+	return p.Party.GetName()
+}
+
+// GetStatusCode returns the status code of the entry
+func (s *camtEntryStatus) GetStatusCode() camtEntryStatusCode {
+	if s == nil {
+		return ""
+	}
+
+	if strings.TrimSpace(s.Code) != "" {
+		return camtEntryStatusCode(strings.TrimSpace(s.Code))
+	}
+
+	return camtEntryStatusCode(strings.TrimSpace(s.Value))
+}
@@ -0,0 +1,45 @@
+package camt
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+// camtDocumentReader defines the structure of iso 20022 cash management (camt) document reader
+type camtDocumentReader struct {
+	xmlDecoder *xml.Decoder
+}
+
+// read returns the imported iso 20022 cash management (camt) document
+func (r *camtDocumentReader) read(ctx core.Context) (*camtDocument, error) {
+	document := &camtDocument{}
+
+	err := r.xmlDecoder.Decode(&document)
+
+	if err != nil {
+		log.Errorf(ctx, "[camt_data_reader.read] cannot read camt file, because %s", err.Error())
+		return nil, errs.ErrInvalidCAMTFile
+	}
+
+	if document.BankToCustomerStatement == nil && document.BankToCustomerAccountReport == nil && document.BankToCustomerDebitCreditNotes == nil {
+		log.Errorf(ctx, "[camt_data_reader.read] cannot read camt file, because it is not a camt.052, camt.053 or camt.054 document")
+		return nil, errs.ErrInvalidCAMTFile
+	}
+
+	return document, nil
+}
+
+func createNewCAMTDocumentReader(data []byte) *camtDocumentReader {
+	xmlDecoder := xml.NewDecoder(bytes.NewReader(data))
+	xmlDecoder.CharsetReader = charset.NewReaderLabel
+
+	return &camtDocumentReader{
+		xmlDecoder: xmlDecoder,
+	}
+}
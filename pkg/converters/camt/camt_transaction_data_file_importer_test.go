@@ -0,0 +1,318 @@
+package camt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestCAMTTransactionDataFileParseImportedData_MinimumValidData(t *testing.T) {
+	converter := CAMTTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(
+		"<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"+
+			"<Document xmlns=\"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02\">\n"+
+			"  <BkToCstmrStmt>\n"+
+			"    <Stmt>\n"+
+			"      <Acct><Id><IBAN>DE001</IBAN></Id><Ccy>EUR</Ccy></Acct>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">123.45</Amt>\n"+
+			"        <CdtDbtInd>CRDT</CdtDbtInd>\n"+
+			"        <Sts>BOOK</Sts>\n"+
+			"        <BookgDt><Dt>2024-09-01</Dt></BookgDt>\n"+
+			"        <ValDt><Dt>2024-09-02</Dt></ValDt>\n"+
+			"      </Ntry>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">0.12</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <Sts>BOOK</Sts>\n"+
+			"        <BookgDt><DtTm>2024-09-02T10:11:12+02:00</DtTm></BookgDt>\n"+
+			"      </Ntry>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">1.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <Sts>BOOK</Sts>\n"+
+			"        <ValDt><Dt>2024-09-03</Dt></ValDt>\n"+
+			"      </Ntry>\n"+
+			"    </Stmt>\n"+
+			"  </BkToCstmrStmt>\n"+
+			"</Document>"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 0, len(allNewSubTransferCategories))
+	assert.Equal(t, 0, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "DE001", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "EUR", allNewTransactions[0].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725264672), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int16(120), allNewTransactions[1].TimezoneUtcOffset)
+	assert.Equal(t, int64(12), allNewTransactions[1].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725321600), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int64(100), allNewTransactions[2].Amount)
+
+	assert.Equal(t, "DE001", allNewAccounts[0].Name)
+	assert.Equal(t, "EUR", allNewAccounts[0].Currency)
+}
+
+func TestCAMTTransactionDataFileParseImportedData_ParseDateWithDefaultTimezone(t *testing.T) {
+	converter := CAMTTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"<Document>\n"+
+			"  <BkToCstmrStmt>\n"+
+			"    <Stmt>\n"+
+			"      <Acct><Id><IBAN>DE001</IBAN></Id></Acct>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">1.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <BookgDt><Dt>2024-09-01</Dt></BookgDt>\n"+
+			"      </Ntry>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">1.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <BookgDt><DtTm>2024-09-01T12:00:00</DtTm></BookgDt>\n"+
+			"      </Ntry>\n"+
+			"    </Stmt>\n"+
+			"  </BkToCstmrStmt>\n"+
+			"</Document>"), 480, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1725120000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int16(480), allNewTransactions[0].TimezoneUtcOffset)
+	assert.Equal(t, int64(1725163200), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int16(480), allNewTransactions[1].TimezoneUtcOffset)
+}
+
+func TestCAMTTransactionDataFileParseImportedData_ParseEntryDetails(t *testing.T) {
+	converter := CAMTTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"<Document xmlns=\"urn:iso:std:iso:20022:tech:xsd:camt.052.001.08\">\n"+
+			"  <BkToCstmrAcctRpt>\n"+
+			"    <Rpt>\n"+
+			"      <Acct><Id><Othr><Id>12345</Id></Othr></Id><Ccy>EUR</Ccy></Acct>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">10.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <Sts><Cd>BOOK</Cd></Sts>\n"+
+			"        <BookgDt><Dt>2024-09-01</Dt></BookgDt>\n"+
+			"        <NtryDtls>\n"+
+			"          <TxDtls>\n"+
+			"            <RltdPties><Cdtr><Pty><Nm>Grocery Store</Nm></Pty></Cdtr></RltdPties>\n"+
+			"            <RmtInf><Ustrd>Invoice 1</Ustrd><Ustrd>Thanks</Ustrd></RmtInf>\n"+
+			"          </TxDtls>\n"+
+			"        </NtryDtls>\n"+
+			"      </Ntry>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">30.00</Amt>\n"+
+			"        <CdtDbtInd>CRDT</CdtDbtInd>\n"+
+			"        <Sts><Cd>BOOK</Cd></Sts>\n"+
+			"        <BookgDt><Dt>2024-09-02</Dt></BookgDt>\n"+
+			"        <NtryDtls>\n"+
+			"          <TxDtls>\n"+
+			"            <AmtDtls><TxAmt><Amt Ccy=\"EUR\">10.00</Amt></TxAmt></AmtDtls>\n"+
+			"            <RltdPties><Dbtr><Nm>Alice</Nm></Dbtr></RltdPties>\n"+
+			"            <RmtInf><Strd><CdtrRefInf><Ref>RF18</Ref></CdtrRefInf></Strd></RmtInf>\n"+
+			"          </TxDtls>\n"+
+			"          <TxDtls>\n"+
+			"            <Amt Ccy=\"EUR\">20.00</Amt>\n"+
+			"            <RltdPties><Dbtr><Nm>Bob</Nm></Dbtr></RltdPties>\n"+
+			"          </TxDtls>\n"+
+			"        </NtryDtls>\n"+
+			"        <AddtlNtryInf>Batch</AddtlNtryInf>\n"+
+			"      </Ntry>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">5.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <Sts><Cd>INFO</Cd></Sts>\n"+
+			"        <BookgDt><Dt>2024-09-03</Dt></BookgDt>\n"+
+			"      </Ntry>\n"+
+			"    </Rpt>\n"+
+			"  </BkToCstmrAcctRpt>\n"+
+			"</Document>"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1000), allNewTransactions[0].Amount)
+	assert.Equal(t, "12345", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Grocery Store - Invoice 1 Thanks", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Alice - RF18", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[2].Type)
+	assert.Equal(t, int64(2000), allNewTransactions[2].Amount)
+	assert.Equal(t, "Bob - Batch", allNewTransactions[2].Comment)
+}
+
+func TestCAMTTransactionDataFileParseImportedData_SkipNotBookedEntries(t *testing.T) {
+	converter := CAMTTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"<Document>\n"+
+			"  <BkToCstmrStmt>\n"+
+			"    <Stmt>\n"+
+			"      <Acct><Id><IBAN>DE001</IBAN></Id></Acct>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">1.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <Sts>BOOK</Sts>\n"+
+			"        <BookgDt><Dt>2024-09-01</Dt></BookgDt>\n"+
+			"      </Ntry>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">2.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <Sts>PDNG</Sts>\n"+
+			"        <BookgDt><Dt>2024-09-02</Dt></BookgDt>\n"+
+			"      </Ntry>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">3.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <Sts><Cd>PDNG</Cd></Sts>\n"+
+			"        <BookgDt><Dt>2024-09-03</Dt></BookgDt>\n"+
+			"      </Ntry>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">4.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <Sts><Cd>INFO</Cd></Sts>\n"+
+			"        <BookgDt><Dt>2024-09-04</Dt></BookgDt>\n"+
+			"      </Ntry>\n"+
+			"    </Stmt>\n"+
+			"  </BkToCstmrStmt>\n"+
+			"</Document>"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, int64(100), allNewTransactions[0].Amount)
+}
+
+func TestCAMTTransactionDataFileParseImportedData_ParseTransferBetweenStatements(t *testing.T) {
+	converter := CAMTTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, allNewSubTransferCategories, _, err := converter.ParseImportedData(context, user, []byte(
+		"<Document>\n"+
+			"  <BkToCstmrStmt>\n"+
+			"    <Stmt>\n"+
+			"      <Acct><Id><IBAN>DE001</IBAN></Id></Acct>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">50.00</Amt>\n"+
+			"        <CdtDbtInd>DBIT</CdtDbtInd>\n"+
+			"        <BookgDt><Dt>2024-09-01</Dt></BookgDt>\n"+
+			"        <NtryDtls><TxDtls><RltdPties><CdtrAcct><Id><IBAN>DE002</IBAN></Id></CdtrAcct></RltdPties></TxDtls></NtryDtls>\n"+
+			"      </Ntry>\n"+
+			"    </Stmt>\n"+
+			"    <Stmt>\n"+
+			"      <Acct><Id><IBAN>DE002</IBAN></Id></Acct>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">50.00</Amt>\n"+
+			"        <CdtDbtInd>CRDT</CdtDbtInd>\n"+
+			"        <BookgDt><Dt>2024-09-02</Dt></BookgDt>\n"+
+			"        <NtryDtls><TxDtls><RltdPties><DbtrAcct><Id><IBAN>DE001</IBAN></Id></DbtrAcct></RltdPties></TxDtls></NtryDtls>\n"+
+			"      </Ntry>\n"+
+			"      <Ntry>\n"+
+			"        <Amt Ccy=\"EUR\">20.00</Amt>\n"+
+			"        <CdtDbtInd>CRDT</CdtDbtInd>\n"+
+			"        <BookgDt><Dt>2024-09-03</Dt></BookgDt>\n"+
+			"        <NtryDtls><TxDtls><RltdPties><DbtrAcct><Id><IBAN>DE001</IBAN></Id></DbtrAcct></RltdPties></TxDtls></NtryDtls>\n"+
+			"      </Ntry>\n"+
+			"    </Stmt>\n"+
+			"  </BkToCstmrStmt>\n"+
+			"</Document>"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, int64(5000), allNewTransactions[0].Amount)
+	assert.Equal(t, "DE001", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "DE002", allNewTransactions[0].OriginalDestinationAccountName)
+	assert.Equal(t, int64(5000), allNewTransactions[0].RelatedAccountAmount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[1].Type)
+	assert.Equal(t, int64(2000), allNewTransactions[1].Amount)
+	assert.Equal(t, "DE001", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "DE002", allNewTransactions[1].OriginalDestinationAccountName)
+}
+
+func TestCAMTTransactionDataFileParseImportedData_InvalidData(t *testing.T) {
+	converter := CAMTTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("<Document><Other></Other></Document>"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidCAMTFile.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("not xml"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidCAMTFile.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"<Document><BkToCstmrStmt><Stmt>"+
+			"<Acct><Id><IBAN>DE001</IBAN></Id></Acct>"+
+			"<Ntry><Amt Ccy=\"EUR\">1.00</Amt><CdtDbtInd>DBIT</CdtDbtInd></Ntry>"+
+			"</Stmt></BkToCstmrStmt></Document>"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingTransactionTime.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"<Document><BkToCstmrStmt><Stmt>"+
+			"<Acct><Id><IBAN>DE001</IBAN></Id></Acct>"+
+			"<Ntry><Amt Ccy=\"EUR\">1.00</Amt><CdtDbtInd>XXX</CdtDbtInd><BookgDt><Dt>2024-09-01</Dt></BookgDt></Ntry>"+
+			"</Stmt></BkToCstmrStmt></Document>"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTypeInvalid.Message)
+}
@@ -0,0 +1,481 @@
+package camt
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const camtDateFormat = "2006-01-02"
+const camtDateTimeWithTimezoneFormat = "2006-01-02T15:04:05Z07:00"
+const camtDateTimeWithoutTimezoneFormat = "2006-01-02T15:04:05"
+const camtTransactionTimeFormat = "2006-01-02 15:04:05"
+
+var camtTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE:     true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+}
+
+// camtTransactionData defines the structure of iso 20022 cash management (camt) transaction data
+type camtTransactionData struct {
+	Entry                *camtEntry
+	Details              *camtTransactionDetails
+	AccountId            string
+	DefaultCurrency      string
+	Amount               *camtAmount
+	CreditDebitIndicator camtCreditDebitIndicator
+	CounterpartyName     string
+	CounterpartyAccount  string
+	IsTransfer           bool
+	RelatedAmount        *camtAmount
+}
+
+// camtTransactionDataTable defines the structure of iso 20022 cash management (camt) transaction data table
+type camtTransactionDataTable struct {
+	defaultTimezone string
+	allData         []*camtTransactionData
+}
+
+// camtTransactionDataRow defines the structure of iso 20022 cash management (camt) transaction data row
+type camtTransactionDataRow struct {
+	dataTable  *camtTransactionDataTable
+	data       *camtTransactionData
+	finalItems map[datatable.TransactionDataTableColumn]string
+}
+
+// camtTransactionDataRowIterator defines the structure of iso 20022 cash management (camt) transaction data row iterator
+type camtTransactionDataRowIterator struct {
+	dataTable    *camtTransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *camtTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := camtTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *camtTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *camtTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &camtTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *camtTransactionDataRow) IsValid() bool {
+	return true
+}
+
+// GetData returns the data in the specified column type
+func (r *camtTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := camtTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *camtTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next imported data row
+func (t *camtTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &camtTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+	}, nil
+}
+
+func (t *camtTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, camtTransaction *camtTransactionData) (map[datatable.TransactionDataTableColumn]string, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(camtTransactionSupportedColumns))
+
+	date := camtTransaction.Entry.BookingDate
+
+	if date == nil || (date.Date == "" && date.DateTime == "") { // use value date if booking date is missing
+		date = camtTransaction.Entry.ValueDate
+	}
+
+	if date == nil || (date.Date == "" && date.DateTime == "") {
+		return nil, errs.ErrMissingTransactionTime
+	}
+
+	datetime, timezone, err := t.parseTransactionTimeAndTimeZone(ctx, date)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = datetime
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIMEZONE] = timezone
+
+	if camtTransaction.AccountId == "" {
+		return nil, errs.ErrMissingAccountData
+	}
+
+	if camtTransaction.Amount == nil || strings.TrimSpace(camtTransaction.Amount.Value) == "" {
+		return nil, errs.ErrAmountInvalid
+	}
+
+	amount, err := utils.ParseAmount(strings.TrimSpace(camtTransaction.Amount.Value))
+
+	if err != nil {
+		log.Errorf(ctx, "[camt_transaction_table.parseTransaction] cannot parse amount \"%s\", because %s", camtTransaction.Amount.Value, err.Error())
+		return nil, errs.ErrAmountInvalid
+	}
+
+	currency := camtTransaction.Amount.Currency
+
+	if currency == "" {
+		currency = camtTransaction.DefaultCurrency
+	}
+
+	if camtTransaction.CreditDebitIndicator != camtCreditIndicator && camtTransaction.CreditDebitIndicator != camtDebitIndicator {
+		log.Errorf(ctx, "[camt_transaction_table.parseTransaction] cannot parse credit or debit indicator \"%s\"", camtTransaction.CreditDebitIndicator)
+		return nil, errs.ErrTransactionTypeInvalid
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = camtTransaction.AccountId
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = currency
+	data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+
+	if camtTransaction.IsTransfer {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = camtTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = currency
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = data[datatable.TRANSACTION_DATA_TABLE_AMOUNT]
+
+		if camtTransaction.CreditDebitIndicator == camtDebitIndicator { // transfer to counterparty account
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = camtTransaction.CounterpartyAccount
+
+			if camtTransaction.RelatedAmount != nil { // the credit entry of the same transfer is in another statement
+				relatedAmount, err := utils.ParseAmount(strings.TrimSpace(camtTransaction.RelatedAmount.Value))
+
+				if err != nil {
+					log.Errorf(ctx, "[camt_transaction_table.parseTransaction] cannot parse related amount \"%s\", because %s", camtTransaction.RelatedAmount.Value, err.Error())
+					return nil, errs.ErrAmountInvalid
+				}
+
+				if camtTransaction.RelatedAmount.Currency != "" {
+					data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = camtTransaction.RelatedAmount.Currency
+				}
+
+				data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(relatedAmount)
+			}
+		} else { // transfer from counterparty account
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = camtTransaction.CounterpartyAccount
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = camtTransaction.AccountId
+		}
+	} else if camtTransaction.CreditDebitIndicator == camtCreditIndicator {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = camtTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = camtTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = t.getDescription(camtTransaction)
+
+	return data, nil
+}
+
+func (t *camtTransactionDataRowIterator) parseTransactionTimeAndTimeZone(ctx core.Context, date *camtDate) (string, string, error) {
+	if date.DateTime != "" {
+		dateTime := strings.TrimSpace(date.DateTime)
+
+		if transactionTime, err := time.Parse(camtDateTimeWithTimezoneFormat, dateTime); err == nil {
+			return transactionTime.Format(camtTransactionTimeFormat), utils.FormatTimezoneOffset(transactionTime.Location()), nil
+		}
+
+		if transactionTime, err := time.Parse(camtDateTimeWithoutTimezoneFormat, dateTime); err == nil {
+			return transactionTime.Format(camtTransactionTimeFormat), t.dataTable.defaultTimezone, nil
+		}
+
+		log.Errorf(ctx, "[camt_transaction_table.parseTransactionTimeAndTimeZone] cannot parse date time \"%s\"", dateTime)
+		return "", "", errs.ErrTransactionTimeInvalid
+	}
+
+	transactionDate, err := time.Parse(camtDateFormat, strings.TrimSpace(date.Date))
+
+	if err != nil {
+		log.Errorf(ctx, "[camt_transaction_table.parseTransactionTimeAndTimeZone] cannot parse date \"%s\", because %s", date.Date, err.Error())
+		return "", "", errs.ErrTransactionTimeInvalid
+	}
+
+	return transactionDate.Format(camtTransactionTimeFormat), t.dataTable.defaultTimezone, nil
+}
+
+// getDescription returns the counterparty name and the remittance information (or additional information if remittance information is missing)
+func (t *camtTransactionDataRowIterator) getDescription(camtTransaction *camtTransactionData) string {
+	information := ""
+
+	if camtTransaction.Details != nil && camtTransaction.Details.RemittanceInformation != nil {
+		remittanceInformation := camtTransaction.Details.RemittanceInformation
+		items := make([]string, 0, len(remittanceInformation.Unstructured))
+
+		for i := 0; i < len(remittanceInformation.Unstructured); i++ {
+			if item := strings.TrimSpace(remittanceInformation.Unstructured[i]); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		if len(items) < 1 {
+			for i := 0; i < len(remittanceInformation.Structured); i++ {
+				structured := remittanceInformation.Structured[i]
+
+				for j := 0; j < len(structured.AdditionalRemittanceInformation); j++ {
+					if item := strings.TrimSpace(structured.AdditionalRemittanceInformation[j]); item != "" {
+						items = append(items, item)
+					}
+				}
+
+				if structured.CreditorReferenceInformation != nil && strings.TrimSpace(structured.CreditorReferenceInformation.Reference) != "" {
+					items = append(items, strings.TrimSpace(structured.CreditorReferenceInformation.Reference))
+				}
+			}
+		}
+
+		information = strings.Join(items, " ")
+	}
+
+	if information == "" && camtTransaction.Details != nil {
+		information = strings.TrimSpace(camtTransaction.Details.AdditionalTransactionInformation)
+	}
+
+	if information == "" {
+		information = strings.TrimSpace(camtTransaction.Entry.AdditionalEntryInformation)
+	}
+
+	if camtTransaction.CounterpartyName == "" {
+		return information
+	} else if information == "" {
+		return camtTransaction.CounterpartyName
+	}
+
+	return camtTransaction.CounterpartyName + " - " + information
+}
+
+func createNewCAMTTransactionDataTable(document *camtDocument, defaultTimezoneOffset int16) (*camtTransactionDataTable, error) {
+	if document == nil {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	allStatements := make([]*camtStatement, 0)
+
+	for _, statementGroup := range []*camtStatementGroup{document.BankToCustomerStatement, document.BankToCustomerAccountReport, document.BankToCustomerDebitCreditNotes} {
+		if statementGroup == nil {
+			continue
+		}
+
+		allStatements = append(allStatements, statementGroup.Statements...)
+		allStatements = append(allStatements, statementGroup.Reports...)
+		allStatements = append(allStatements, statementGroup.Notifications...)
+	}
+
+	allAccountIds := make(map[string]bool, len(allStatements))
+
+	for i := 0; i < len(allStatements); i++ {
+		if accountId := allStatements[i].Account.GetAccountId(); accountId != "" {
+			allAccountIds[accountId] = true
+		}
+	}
+
+	allData := make([]*camtTransactionData, 0)
+
+	for i := 0; i < len(allStatements); i++ {
+		statement := allStatements[i]
+		accountId := statement.Account.GetAccountId()
+		defaultCurrency := ""
+
+		if statement.Account != nil {
+			defaultCurrency = statement.Account.Currency
+		}
+
+		for j := 0; j < len(statement.Entries); j++ {
+			entry := statement.Entries[j]
+
+			// only booked entries are imported, the pending (or information only) entries are not booked and may be changed or cancelled later
+			if statusCode := entry.Status.GetStatusCode(); statusCode != "" && statusCode != camtBookedEntryStatus {
+				continue
+			}
+
+			for _, data := range createCAMTTransactionDataFromEntry(entry, accountId, defaultCurrency) {
+				data.IsTransfer = data.CounterpartyAccount != "" && data.CounterpartyAccount != accountId && allAccountIds[data.CounterpartyAccount]
+				allData = append(allData, data)
+			}
+		}
+	}
+
+	return &camtTransactionDataTable{
+		defaultTimezone: utils.FormatTimezoneOffset(time.FixedZone("Default Timezone", int(defaultTimezoneOffset)*60)),
+		allData:         mergeCAMTTransferTransactions(allData),
+	}, nil
+}
+
+// createCAMTTransactionDataFromEntry returns one transaction data for the entry, or one transaction data per transaction details if the entry is a batch booking with amount of each transaction
+func createCAMTTransactionDataFromEntry(entry *camtEntry, accountId string, defaultCurrency string) []*camtTransactionData {
+	allDetails := make([]*camtTransactionDetails, 0)
+
+	for i := 0; i < len(entry.EntryDetails); i++ {
+		if entry.EntryDetails[i] != nil {
+			allDetails = append(allDetails, entry.EntryDetails[i].TransactionDetails...)
+		}
+	}
+
+	splitByDetails := len(allDetails) > 1
+
+	for i := 0; i < len(allDetails) && splitByDetails; i++ {
+		if getCAMTTransactionDetailsAmount(allDetails[i]) == nil {
+			splitByDetails = false
+		}
+	}
+
+	if !splitByDetails {
+		var details *camtTransactionDetails
+
+		if len(allDetails) > 0 {
+			details = allDetails[0]
+		}
+
+		return []*camtTransactionData{createCAMTTransactionData(entry, details, accountId, defaultCurrency, entry.Amount, entry.CreditDebitIndicator)}
+	}
+
+	allData := make([]*camtTransactionData, 0, len(allDetails))
+
+	for i := 0; i < len(allDetails); i++ {
+		details := allDetails[i]
+		creditDebitIndicator := details.CreditDebitIndicator
+
+		if creditDebitIndicator == "" {
+			creditDebitIndicator = entry.CreditDebitIndicator
+		}
+
+		allData = append(allData, createCAMTTransactionData(entry, details, accountId, defaultCurrency, getCAMTTransactionDetailsAmount(details), creditDebitIndicator))
+	}
+
+	return allData
+}
+
+func createCAMTTransactionData(entry *camtEntry, details *camtTransactionDetails, accountId string, defaultCurrency string, amount *camtAmount, creditDebitIndicator camtCreditDebitIndicator) *camtTransactionData {
+	data := &camtTransactionData{
+		Entry:                entry,
+		Details:              details,
+		AccountId:            accountId,
+		DefaultCurrency:      defaultCurrency,
+		Amount:               amount,
+		CreditDebitIndicator: camtCreditDebitIndicator(strings.TrimSpace(string(creditDebitIndicator))),
+	}
+
+	if details != nil && details.RelatedParties != nil {
+		relatedParties := details.RelatedParties
+
+		if data.CreditDebitIndicator == camtDebitIndicator {
+			data.CounterpartyName = relatedParties.Creditor.GetName()
+			data.CounterpartyAccount = relatedParties.CreditorAccount.GetAccountId()
+
+			if data.CounterpartyName == "" {
+				data.CounterpartyName = relatedParties.UltimateCreditor.GetName()
+			}
+		} else {
+			data.CounterpartyName = relatedParties.Debtor.GetName()
+			data.CounterpartyAccount = relatedParties.DebtorAccount.GetAccountId()
+
+			if data.CounterpartyName == "" {
+				data.CounterpartyName = relatedParties.UltimateDebtor.GetName()
+			}
+		}
+	}
+
+	return data
+}
+
+func getCAMTTransactionDetailsAmount(details *camtTransactionDetails) *camtAmount {
+	if details.Amount != nil && details.Amount.Value != "" {
+		return details.Amount
+	}
+
+	if details.AmountDetails != nil && details.AmountDetails.TransactionAmount != nil &&
+		details.AmountDetails.TransactionAmount.Amount != nil && details.AmountDetails.TransactionAmount.Amount.Value != "" {
+		return details.AmountDetails.TransactionAmount.Amount
+	}
+
+	return nil
+}
+
+// mergeCAMTTransferTransactions merges the credit entry into the debit entry of the same transfer between two accounts in the same file
+func mergeCAMTTransferTransactions(allData []*camtTransactionData) []*camtTransactionData {
+	mergedData := make(map[*camtTransactionData]bool)
+
+	for i := 0; i < len(allData); i++ {
+		debitData := allData[i]
+
+		if !debitData.IsTransfer || debitData.CreditDebitIndicator != camtDebitIndicator || debitData.Amount == nil {
+			continue
+		}
+
+		for j := 0; j < len(allData); j++ {
+			creditData := allData[j]
+
+			if !creditData.IsTransfer || creditData.CreditDebitIndicator != camtCreditIndicator || creditData.Amount == nil || mergedData[creditData] {
+				continue
+			}
+
+			if creditData.AccountId == debitData.CounterpartyAccount && creditData.CounterpartyAccount == debitData.AccountId &&
+				strings.TrimSpace(creditData.Amount.Value) == strings.TrimSpace(debitData.Amount.Value) {
+				debitData.RelatedAmount = creditData.Amount
+				mergedData[creditData] = true
+				break
+			}
+		}
+	}
+
+	if len(mergedData) < 1 {
+		return allData
+	}
+
+	finalData := make([]*camtTransactionData, 0, len(allData)-len(mergedData))
+
+	for i := 0; i < len(allData); i++ {
+		if !mergedData[allData[i]] {
+			finalData = append(finalData, allData[i])
+		}
+	}
+
+	return finalData
+}
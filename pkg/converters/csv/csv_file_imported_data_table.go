@@ -1,14 +1,18 @@
 package csv
 
 import (
+	"bytes"
 	"encoding/csv"
 	"fmt"
 	"io"
 
+	"golang.org/x/text/encoding"
+
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // CsvFileImportedDataTable defines the structure of csv data table
@@ -99,6 +103,18 @@ func CreateNewCsvImportedDataTable(ctx core.Context, reader io.Reader) (*CsvFile
 	return createNewCsvFileDataTable(ctx, reader, ',')
 }
 
+// CreateNewCsvImportedDataTableFromData returns comma separated values data table by file binary data, the data is decoded by the specified text encoding, or by the detected text encoding if it is nil
+func CreateNewCsvImportedDataTableFromData(ctx core.Context, data []byte, enc encoding.Encoding) (*CsvFileImportedDataTable, error) {
+	content, err := utils.DecodeTextData(data, enc)
+
+	if err != nil {
+		log.Errorf(ctx, "[csv_file_imported_data_table.CreateNewCsvImportedDataTableFromData] cannot decode csv data, because %s", err.Error())
+		return nil, errs.ErrInvalidCSVFile
+	}
+
+	return createNewCsvFileDataTable(ctx, bytes.NewReader(content), ',')
+}
+
 // CreateNewCustomCsvImportedDataTable returns character separated values data table by io readers
 func CreateNewCustomCsvImportedDataTable(allLines [][]string) *CsvFileImportedDataTable {
 	return &CsvFileImportedDataTable{
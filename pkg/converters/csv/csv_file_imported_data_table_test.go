@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/charmap"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 )
@@ -182,3 +183,28 @@ func TestCreateNewCsvImportedDataTable_SkipBlankLine(t *testing.T) {
 	assert.Equal(t, "C3", row2.GetData(2))
 	assert.False(t, iterator.HasNext())
 }
+
+func TestCreateNewCsvImportedDataTableFromData_DetectTextEncoding(t *testing.T) {
+	context := core.NewNullContext()
+	datatable, err := CreateNewCsvImportedDataTableFromData(context, []byte{0xFF, 0xFE,
+		'A', 0x00, '1', 0x00, ',', 0x00, 'B', 0x00, '1', 0x00, '\n', 0x00,
+		'A', 0x00, '2', 0x00, ',', 0x00, 0xE9, 0x00, '\n', 0x00}, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, datatable.DataRowCount())
+	assert.EqualValues(t, []string{"A1", "B1"}, datatable.HeaderColumnNames())
+
+	row1 := datatable.DataRowIterator().Next()
+	assert.Equal(t, "A2", row1.GetData(0))
+	assert.Equal(t, "é", row1.GetData(1))
+}
+
+func TestCreateNewCsvImportedDataTableFromData_SpecifiedTextEncoding(t *testing.T) {
+	context := core.NewNullContext()
+	datatable, err := CreateNewCsvImportedDataTableFromData(context, []byte("A1,B1\nA2,10 \xA4\n"), charmap.ISO8859_15)
+	assert.Nil(t, err)
+
+	row1 := datatable.DataRowIterator().Next()
+	assert.Equal(t, "A2", row1.GetData(0))
+	assert.Equal(t, "10 €", row1.GetData(1))
+}
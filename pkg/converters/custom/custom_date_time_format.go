@@ -0,0 +1,83 @@
+package custom
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+// customDateTimeFormatTokens represents the supported tokens in date time format and the corresponding golang layout, longer tokens must be in front of the shorter tokens with the same prefix
+var customDateTimeFormatTokens = []struct {
+	token  string
+	layout string
+}{
+	{"YYYY", "2006"},
+	{"MMMM", "January"},
+	{"MMM", "Jan"},
+	{"YY", "06"},
+	{"MM", "01"},
+	{"M", "1"},
+	{"DD", "02"},
+	{"D", "2"},
+	{"HH", "15"},
+	{"H", "15"},
+	{"hh", "03"},
+	{"h", "3"},
+	{"mm", "04"},
+	{"m", "4"},
+	{"ss", "05"},
+	{"s", "5"},
+	{"A", "PM"},
+	{"a", "pm"},
+}
+
+// ParseDateTimeFormat returns the golang time layout of the specified date time format (e.g. "YYYY-MM-DD HH:mm:ss" or "DD.MM.YYYY")
+func ParseDateTimeFormat(format string) (string, error) {
+	var layout strings.Builder
+	hasYear := false
+	hasMonth := false
+	hasDay := false
+
+	for i := 0; i < len(format); {
+		matched := false
+
+		for j := 0; j < len(customDateTimeFormatTokens); j++ {
+			token := customDateTimeFormatTokens[j].token
+
+			if !strings.HasPrefix(format[i:], token) {
+				continue
+			}
+
+			switch token[0] {
+			case 'Y':
+				hasYear = true
+			case 'M':
+				hasMonth = true
+			case 'D':
+				hasDay = true
+			}
+
+			layout.WriteString(customDateTimeFormatTokens[j].layout)
+			i += len(token)
+			matched = true
+			break
+		}
+
+		if matched {
+			continue
+		}
+
+		if '0' <= format[i] && format[i] <= '9' { // digits in golang layout would be treated as layout elements
+			return "", errs.ErrTransactionImportProfileDateTimeFormatInvalid
+		}
+
+		layout.WriteByte(format[i])
+		i++
+	}
+
+	if !hasYear || !hasMonth || !hasDay {
+		return "", errs.ErrTransactionImportProfileDateTimeFormatInvalid
+	}
+
+	return layout.String(), nil
+}
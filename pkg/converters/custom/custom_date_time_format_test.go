@@ -0,0 +1,42 @@
+package custom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestParseDateTimeFormat(t *testing.T) {
+	layout, err := ParseDateTimeFormat("YYYY-MM-DD HH:mm:ss")
+	assert.Nil(t, err)
+	assert.Equal(t, "2006-01-02 15:04:05", layout)
+
+	layout, err = ParseDateTimeFormat("DD.MM.YY")
+	assert.Nil(t, err)
+	assert.Equal(t, "02.01.06", layout)
+
+	layout, err = ParseDateTimeFormat("M/D/YYYY h:mm A")
+	assert.Nil(t, err)
+	assert.Equal(t, "1/2/2006 3:04 PM", layout)
+
+	layout, err = ParseDateTimeFormat("D MMMM YYYY")
+	assert.Nil(t, err)
+	assert.Equal(t, "2 January 2006", layout)
+
+	layout, err = ParseDateTimeFormat("DD-MMM-YYYY")
+	assert.Nil(t, err)
+	assert.Equal(t, "02-Jan-2006", layout)
+}
+
+func TestParseDateTimeFormat_InvalidFormat(t *testing.T) {
+	_, err := ParseDateTimeFormat("HH:mm:ss")
+	assert.EqualError(t, err, errs.ErrTransactionImportProfileDateTimeFormatInvalid.Message)
+
+	_, err = ParseDateTimeFormat("YYYY-MM")
+	assert.EqualError(t, err, errs.ErrTransactionImportProfileDateTimeFormatInvalid.Message)
+
+	_, err = ParseDateTimeFormat("20YY-MM-DD")
+	assert.EqualError(t, err, errs.ErrTransactionImportProfileDateTimeFormatInvalid.Message)
+}
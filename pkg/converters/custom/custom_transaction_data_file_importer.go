@@ -0,0 +1,170 @@
+package custom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
+	csvdatatable "github.com/mayswind/ezbookkeeping/pkg/converters/csv"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/excel"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const customDefaultTagSeparator = ","
+
+var customTransactionTypeNameMapping = map[models.TransactionType]string{
+	models.TRANSACTION_TYPE_INCOME:   utils.IntToString(int(models.TRANSACTION_TYPE_INCOME)),
+	models.TRANSACTION_TYPE_EXPENSE:  utils.IntToString(int(models.TRANSACTION_TYPE_EXPENSE)),
+	models.TRANSACTION_TYPE_TRANSFER: utils.IntToString(int(models.TRANSACTION_TYPE_TRANSFER)),
+}
+
+// customTransactionDataFileImporter defines the structure of delimiter-separated values or excel file importer for transaction data with custom column mapping
+type customTransactionDataFileImporter struct {
+	profile      *models.TransactionImportProfile
+	textEncoding encoding.Encoding
+}
+
+// ParseImportedData returns the imported data by parsing the transaction data with the column mapping of transaction import profile
+func (c *customTransactionDataFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	dataTable, err := c.createImportedDataTable(ctx, data)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	transactionDataTable, err := createNewCustomTransactionDataTable(ctx, dataTable, c.profile)
+
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, err
+	}
+
+	tagSeparator := c.profile.TagSeparator
+
+	if tagSeparator == "" {
+		tagSeparator = customDefaultTagSeparator
+	}
+
+	dataTableImporter := datatable.CreateNewImporter(customTransactionTypeNameMapping, "", tagSeparator)
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
+
+func (c *customTransactionDataFileImporter) createImportedDataTable(ctx core.Context, data []byte) (datatable.ImportedDataTable, error) {
+	if c.profile.FileType == models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_EXCEL {
+		dataTable, err := excel.CreateNewSpreadsheetFileImportedDataTable(data)
+
+		if err != nil {
+			log.Errorf(ctx, "[custom_transaction_data_file_importer.createImportedDataTable] cannot parse excel file, because %s", err.Error())
+			return nil, errs.ErrInvalidExcelFile
+		}
+
+		return dataTable, nil
+	}
+
+	return createNewCustomDelimiterSeparatedValuesDataTable(ctx, data, c.textEncoding, c.profile.Delimiter, int(c.profile.SkipRows))
+}
+
+// WithTextEncoding returns a copy of the importer which decodes the delimiter-separated values data by the specified text encoding
+func (c *customTransactionDataFileImporter) WithTextEncoding(enc encoding.Encoding) base.TransactionDataImporter {
+	importer := *c
+	importer.textEncoding = enc
+
+	return &importer
+}
+
+// CreateNewCustomTransactionDataFileImporter returns a new transaction data importer with the column mapping of the specified transaction import profile
+func CreateNewCustomTransactionDataFileImporter(profile *models.TransactionImportProfile) (*customTransactionDataFileImporter, error) {
+	if profile == nil || !profile.IsValidColumnMapping() {
+		return nil, errs.ErrTransactionImportProfileColumnMappingInvalid
+	}
+
+	if profile.FileType != models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV && profile.FileType != models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_EXCEL {
+		return nil, errs.ErrImportFileTypeNotSupported
+	}
+
+	return &customTransactionDataFileImporter{
+		profile: profile,
+	}, nil
+}
+
+// ParseHeaderColumnNames returns the header column names of the file with the file type, delimiter and skipped rows of the specified transaction import profile
+func ParseHeaderColumnNames(ctx core.Context, profile *models.TransactionImportProfile, data []byte) ([]string, error) {
+	importer := &customTransactionDataFileImporter{
+		profile: profile,
+	}
+
+	dataTable, err := importer.createImportedDataTable(ctx, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	headerColumnNames := dataTable.HeaderColumnNames()
+
+	if len(headerColumnNames) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	return headerColumnNames, nil
+}
+
+func createNewCustomDelimiterSeparatedValuesDataTable(ctx core.Context, data []byte, enc encoding.Encoding, delimiter string, skipRows int) (datatable.ImportedDataTable, error) {
+	separator, size := utf8.DecodeRuneInString(delimiter)
+
+	if size != len(delimiter) || separator == utf8.RuneError || separator == '"' || separator == '\r' || separator == '\n' {
+		return nil, errs.ErrTransactionImportProfileDelimiterInvalid
+	}
+
+	content, err := utils.DecodeTextData(data, enc)
+
+	if err != nil {
+		log.Errorf(ctx, "[custom_transaction_data_file_importer.createNewCustomDelimiterSeparatedValuesDataTable] cannot decode data, because %s", err.Error())
+		return nil, errs.ErrInvalidCSVFile
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(content))
+
+	for i := 0; i < skipRows; i++ {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, errs.ErrNotFoundTransactionDataInFile
+		}
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = separator
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+
+	allLines := make([][]string, 0)
+
+	for {
+		items, err := csvReader.Read()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			log.Errorf(ctx, "[custom_transaction_data_file_importer.createNewCustomDelimiterSeparatedValuesDataTable] cannot parse data, because %s", err.Error())
+			return nil, errs.ErrInvalidCSVFile
+		}
+
+		if len(items) == 1 && items[0] == "" {
+			continue
+		}
+
+		allLines = append(allLines, items)
+	}
+
+	return csvdatatable.CreateNewCustomCsvImportedDataTable(allLines), nil
+}
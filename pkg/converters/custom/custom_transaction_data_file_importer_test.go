@@ -0,0 +1,301 @@
+package custom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestCustomTransactionDataFileParseImportedData_PositiveIncome(t *testing.T) {
+	importer, err := CreateNewCustomTransactionDataFileImporter(&models.TransactionImportProfile{
+		FileType:  models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV,
+		Delimiter: ",",
+		ColumnMapping: &models.TransactionImportProfileColumnMapping{
+			Time:     "Date",
+			Amount:   "Amount",
+			Account:  "Account",
+			Category: "Category",
+			Comment:  "Memo",
+			Tags:     "Tags",
+		},
+		DateTimeFormat:   "YYYY-MM-DD HH:mm:ss",
+		DecimalSeparator: ".",
+		AmountSignType:   models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_POSITIVE_INCOME,
+	})
+	assert.Nil(t, err)
+
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := importer.ParseImportedData(context, user, []byte(
+		"Date,Amount,Account,Category,Memo,Tags\n"+
+			"2024-09-01 01:23:45,123.45,Bank,Salary,\"Pay, September\",\"foo,bar\"\n"+
+			"2024-09-01 12:34:56,-0.12,Bank,Food,,\n"+
+			",,,Total,,\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 0, len(allNewSubTransferCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725153825), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Bank", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Salary", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, "Pay, September", allNewTransactions[0].Comment)
+	assert.Equal(t, 2, len(allNewTransactions[0].OriginalTagNames))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725194096), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(12), allNewTransactions[1].Amount)
+	assert.Equal(t, "Food", allNewTransactions[1].OriginalCategoryName)
+
+	assert.Equal(t, "Bank", allNewAccounts[0].Name)
+	assert.Equal(t, "CNY", allNewAccounts[0].Currency)
+}
+
+func TestCustomTransactionDataFileParseImportedData_PositiveExpenseWithDecimalComma(t *testing.T) {
+	importer, err := CreateNewCustomTransactionDataFileImporter(&models.TransactionImportProfile{
+		FileType:  models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV,
+		Delimiter: ";",
+		SkipRows:  2,
+		ColumnMapping: &models.TransactionImportProfileColumnMapping{
+			Time:            "Datum",
+			Amount:          "Betrag",
+			AccountCurrency: "Währung",
+		},
+		DateTimeFormat:      "DD.MM.YYYY",
+		DecimalSeparator:    ",",
+		DigitGroupingSymbol: ".",
+		AmountSignType:      models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_POSITIVE_EXPENSE,
+		DefaultAccountName:  "Girokonto",
+	})
+	assert.Nil(t, err)
+
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"\xEF\xBB\xBFKontoauszug\n"+
+			"Zeitraum: 09/2024\n"+
+			"Datum;Betrag;Währung\n"+
+			"01.09.2024;1.234,56 €;eur\n"+
+			"02.09.2024;-10,5;eur\n"+
+			"03.09.2024;(2,00);eur\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(123456), allNewTransactions[0].Amount)
+	assert.Equal(t, "Girokonto", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "EUR", allNewTransactions[0].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1050), allNewTransactions[1].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[2].Type)
+	assert.Equal(t, int64(200), allNewTransactions[2].Amount)
+
+	assert.Equal(t, "Girokonto", allNewAccounts[0].Name)
+	assert.Equal(t, "EUR", allNewAccounts[0].Currency)
+}
+
+func TestCustomTransactionDataFileParseImportedData_TypeColumnAndTransfer(t *testing.T) {
+	importer, err := CreateNewCustomTransactionDataFileImporter(&models.TransactionImportProfile{
+		FileType:  models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV,
+		Delimiter: "\t",
+		ColumnMapping: &models.TransactionImportProfileColumnMapping{
+			Time:           "Time",
+			Type:           "Type",
+			Amount:         "Amount",
+			Account:        "From",
+			RelatedAccount: "To",
+		},
+		DateTimeFormat:    "MM/DD/YYYY h:mm A",
+		DecimalSeparator:  ".",
+		AmountSignType:    models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_TYPE_COLUMN,
+		IncomeTypeValue:   "Credit",
+		ExpenseTypeValue:  "Debit",
+		TransferTypeValue: "Transfer",
+	})
+	assert.Nil(t, err)
+
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"Time\tType\tAmount\tFrom\tTo\n"+
+			"09/01/2024 1:23 AM\tcredit\t-1.00\tBank\t\n"+
+			"09/01/2024 1:23 PM\tDEBIT\t2.00\tBank\t\n"+
+			"09/02/2024 12:00 PM\tTransfer\t-3.00\tBank\tCash\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725153780), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(100), allNewTransactions[0].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725196980), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(200), allNewTransactions[1].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(300), allNewTransactions[2].Amount)
+	assert.Equal(t, int64(300), allNewTransactions[2].RelatedAccountAmount)
+	assert.Equal(t, "Bank", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Cash", allNewTransactions[2].OriginalDestinationAccountName)
+}
+
+func TestCustomTransactionDataFileParseImportedData_SeparateColumns(t *testing.T) {
+	importer, err := CreateNewCustomTransactionDataFileImporter(&models.TransactionImportProfile{
+		FileType:  models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV,
+		Delimiter: ",",
+		ColumnMapping: &models.TransactionImportProfileColumnMapping{
+			Time:         "Date",
+			Amount:       "Withdrawal",
+			IncomeAmount: "Deposit",
+		},
+		DateTimeFormat:      "YYYY/M/D",
+		DecimalSeparator:    ".",
+		DigitGroupingSymbol: ",",
+		AmountSignType:      models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_SEPARATE_COLUMNS,
+		DefaultAccountName:  "Checking",
+	})
+	assert.Nil(t, err)
+
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := importer.ParseImportedData(context, user, []byte(
+		"Date,Withdrawal,Deposit\n"+
+			"2024/9/1,,\"$1,000.00\"\n"+
+			"2024/9/2,12.34,\n"+
+			"2024/9/3,5.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1234), allNewTransactions[1].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(500), allNewTransactions[2].Amount)
+}
+
+func TestCustomTransactionDataFileParseImportedData_InvalidData(t *testing.T) {
+	profile := &models.TransactionImportProfile{
+		FileType:  models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV,
+		Delimiter: ",",
+		ColumnMapping: &models.TransactionImportProfileColumnMapping{
+			Time:    "Date",
+			Amount:  "Amount",
+			Account: "Account",
+		},
+		DateTimeFormat:   "YYYY-MM-DD",
+		DecimalSeparator: ".",
+	}
+
+	importer, err := CreateNewCustomTransactionDataFileImporter(profile)
+	assert.Nil(t, err)
+
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	// Missing mapped column in header row
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte(
+		"Date,Amount\n"+
+			"2024-09-01,1.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	// Invalid time
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte(
+		"Date,Amount,Account\n"+
+			"09/01/2024,1.00,Bank\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	// Invalid amount
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte(
+		"Date,Amount,Account\n"+
+			"2024-09-01,1.0.0,Bank\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+
+	// Invalid delimiter
+	profile.Delimiter = "\""
+	_, _, _, _, _, _, err = importer.ParseImportedData(context, user, []byte("Date,Amount,Account\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionImportProfileDelimiterInvalid.Message)
+
+	// Invalid column mapping
+	_, err = CreateNewCustomTransactionDataFileImporter(&models.TransactionImportProfile{
+		FileType: models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV,
+		ColumnMapping: &models.TransactionImportProfileColumnMapping{
+			Time:   "Date",
+			Amount: "Amount",
+		},
+	})
+	assert.EqualError(t, err, errs.ErrTransactionImportProfileColumnMappingInvalid.Message)
+}
+
+func TestParseHeaderColumnNames(t *testing.T) {
+	context := core.NewNullContext()
+
+	headerColumnNames, err := ParseHeaderColumnNames(context, &models.TransactionImportProfile{
+		FileType:  models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV,
+		Delimiter: ";",
+		SkipRows:  1,
+	}, []byte(
+		"Statement\n"+
+			"Date;Amount;\"Memo; Note\"\n"+
+			"2024-09-01;1.00;Test\n"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Date", "Amount", "Memo; Note"}, headerColumnNames)
+
+	_, err = ParseHeaderColumnNames(context, &models.TransactionImportProfile{
+		FileType:  models.TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV,
+		Delimiter: ",",
+		SkipRows:  3,
+	}, []byte("Date,Amount\n"))
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+}
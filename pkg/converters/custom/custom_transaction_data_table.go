@@ -0,0 +1,370 @@
+package custom
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const customTransactionTimeFormat = "2006-01-02 15:04:05"
+
+// customTransactionDataTable defines the structure of custom mapping transaction data table
+type customTransactionDataTable struct {
+	innerDataTable  datatable.ImportedDataTable
+	profile         *models.TransactionImportProfile
+	dateTimeLayout  string
+	columnIndexes   map[string]int
+	supportedColumn map[datatable.TransactionDataTableColumn]bool
+}
+
+// customTransactionDataRow defines the structure of custom mapping transaction data row
+type customTransactionDataRow struct {
+	dataTable    *customTransactionDataTable
+	rowData      map[datatable.TransactionDataTableColumn]string
+	rowDataValid bool
+}
+
+// customTransactionDataRowIterator defines the structure of custom mapping transaction data row iterator
+type customTransactionDataRowIterator struct {
+	dataTable     *customTransactionDataTable
+	innerIterator datatable.ImportedDataRowIterator
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *customTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := t.supportedColumn[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *customTransactionDataTable) TransactionRowCount() int {
+	return t.innerDataTable.DataRowCount()
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *customTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &customTransactionDataRowIterator{
+		dataTable:     t,
+		innerIterator: t.innerDataTable.DataRowIterator(),
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *customTransactionDataRow) IsValid() bool {
+	return r.rowDataValid
+}
+
+// GetData returns the data in the specified column type
+func (r *customTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	if !r.rowDataValid {
+		return ""
+	}
+
+	_, exists := r.dataTable.supportedColumn[column]
+
+	if exists {
+		return r.rowData[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *customTransactionDataRowIterator) HasNext() bool {
+	return t.innerIterator.HasNext()
+}
+
+// Next returns the next imported data row
+func (t *customTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	importedRow := t.innerIterator.Next()
+
+	if importedRow == nil {
+		return nil, nil
+	}
+
+	rowData, rowDataValid, err := t.parseTransaction(ctx, importedRow)
+
+	if err != nil {
+		log.Errorf(ctx, "[custom_transaction_data_table.Next] cannot parse data row \"%s\", because %s", t.innerIterator.CurrentRowId(), err.Error())
+		return nil, err
+	}
+
+	return &customTransactionDataRow{
+		dataTable:    t.dataTable,
+		rowData:      rowData,
+		rowDataValid: rowDataValid,
+	}, nil
+}
+
+func (t *customTransactionDataRowIterator) parseTransaction(ctx core.Context, importedRow datatable.ImportedDataRow) (map[datatable.TransactionDataTableColumn]string, bool, error) {
+	profile := t.dataTable.profile
+	mapping := profile.ColumnMapping
+	data := make(map[datatable.TransactionDataTableColumn]string, len(t.dataTable.supportedColumn))
+
+	timeValue := t.getColumnValue(importedRow, mapping.Time)
+
+	if timeValue == "" { // skip the rows without time, e.g. empty rows or summary rows
+		return nil, false, nil
+	}
+
+	transactionTime, err := time.Parse(t.dataTable.dateTimeLayout, timeValue)
+
+	if err != nil {
+		log.Errorf(ctx, "[custom_transaction_data_table.parseTransaction] cannot parse time \"%s\" with format \"%s\", because %s", timeValue, profile.DateTimeFormat, err.Error())
+		return nil, false, errs.ErrTransactionTimeInvalid
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime.Format(customTransactionTimeFormat)
+
+	transactionType, amount, err := t.parseTransactionTypeAndAmount(ctx, importedRow)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = customTransactionTypeNameMapping[transactionType]
+	data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+	data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = t.getColumnValue(importedRow, mapping.Category)
+
+	accountName := t.getColumnValue(importedRow, mapping.Account)
+
+	if accountName == "" {
+		accountName = profile.DefaultAccountName
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = accountName
+
+	if mapping.AccountCurrency != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = strings.ToUpper(t.getColumnValue(importedRow, mapping.AccountCurrency))
+	}
+
+	if transactionType == models.TRANSACTION_TYPE_TRANSFER {
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = t.getColumnValue(importedRow, mapping.RelatedAccount)
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = ""
+	}
+
+	if mapping.Tags != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_TAGS] = t.getColumnValue(importedRow, mapping.Tags)
+	}
+
+	if mapping.Comment != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = t.getColumnValue(importedRow, mapping.Comment)
+	}
+
+	return data, true, nil
+}
+
+func (t *customTransactionDataRowIterator) parseTransactionTypeAndAmount(ctx core.Context, importedRow datatable.ImportedDataRow) (models.TransactionType, int64, error) {
+	profile := t.dataTable.profile
+	mapping := profile.ColumnMapping
+	typeValue := t.getColumnValue(importedRow, mapping.Type)
+
+	amountValue := t.getColumnValue(importedRow, mapping.Amount)
+	amount := int64(0)
+	var err error
+
+	if amountValue != "" || profile.AmountSignType != models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_SEPARATE_COLUMNS {
+		amount, err = parseCustomAmount(amountValue, profile.DecimalSeparator, profile.DigitGroupingSymbol)
+
+		if err != nil {
+			log.Errorf(ctx, "[custom_transaction_data_table.parseTransactionTypeAndAmount] cannot parse amount \"%s\", because %s", amountValue, err.Error())
+			return 0, 0, errs.ErrAmountInvalid
+		}
+	}
+
+	if profile.TransferTypeValue != "" && strings.EqualFold(typeValue, profile.TransferTypeValue) {
+		return models.TRANSACTION_TYPE_TRANSFER, getAbsAmount(amount), nil
+	}
+
+	switch profile.AmountSignType {
+	case models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_POSITIVE_INCOME:
+		if amount >= 0 {
+			return models.TRANSACTION_TYPE_INCOME, amount, nil
+		}
+
+		return models.TRANSACTION_TYPE_EXPENSE, -amount, nil
+	case models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_POSITIVE_EXPENSE:
+		if amount > 0 {
+			return models.TRANSACTION_TYPE_EXPENSE, amount, nil
+		}
+
+		return models.TRANSACTION_TYPE_INCOME, -amount, nil
+	case models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_TYPE_COLUMN:
+		if profile.IncomeTypeValue != "" && strings.EqualFold(typeValue, profile.IncomeTypeValue) {
+			return models.TRANSACTION_TYPE_INCOME, getAbsAmount(amount), nil
+		} else if profile.ExpenseTypeValue != "" && strings.EqualFold(typeValue, profile.ExpenseTypeValue) {
+			return models.TRANSACTION_TYPE_EXPENSE, getAbsAmount(amount), nil
+		}
+
+		log.Errorf(ctx, "[custom_transaction_data_table.parseTransactionTypeAndAmount] cannot parse transaction type \"%s\"", typeValue)
+		return 0, 0, errs.ErrTransactionTypeInvalid
+	case models.TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_SEPARATE_COLUMNS:
+		incomeAmountValue := t.getColumnValue(importedRow, mapping.IncomeAmount)
+
+		if incomeAmountValue != "" {
+			incomeAmount, err := parseCustomAmount(incomeAmountValue, profile.DecimalSeparator, profile.DigitGroupingSymbol)
+
+			if err != nil {
+				log.Errorf(ctx, "[custom_transaction_data_table.parseTransactionTypeAndAmount] cannot parse income amount \"%s\", because %s", incomeAmountValue, err.Error())
+				return 0, 0, errs.ErrAmountInvalid
+			}
+
+			if incomeAmount != 0 || amountValue == "" {
+				return models.TRANSACTION_TYPE_INCOME, getAbsAmount(incomeAmount), nil
+			}
+		}
+
+		if amountValue == "" {
+			return 0, 0, errs.ErrAmountInvalid
+		}
+
+		return models.TRANSACTION_TYPE_EXPENSE, getAbsAmount(amount), nil
+	}
+
+	return 0, 0, errs.ErrTransactionTypeInvalid
+}
+
+func (t *customTransactionDataRowIterator) getColumnValue(importedRow datatable.ImportedDataRow, columnName string) string {
+	if columnName == "" {
+		return ""
+	}
+
+	columnIndex, exists := t.dataTable.columnIndexes[columnName]
+
+	if !exists || columnIndex >= importedRow.ColumnCount() {
+		return ""
+	}
+
+	return strings.TrimSpace(importedRow.GetData(columnIndex))
+}
+
+// parseCustomAmount returns the amount in cents, the amount can contain digit grouping symbols, currency symbols, trailing minus sign (e.g. "1.234,56-") or parentheses (e.g. "(1,234.56)")
+func parseCustomAmount(value string, decimalSeparator string, digitGroupingSymbol string) (int64, error) {
+	negative := false
+	var builder strings.Builder
+
+	value = strings.TrimSpace(value)
+
+	if strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")") {
+		negative = true
+		value = value[1 : len(value)-1]
+	}
+
+	if strings.HasSuffix(value, "-") {
+		negative = true
+		value = value[0 : len(value)-1]
+	}
+
+	for _, c := range value {
+		if '0' <= c && c <= '9' {
+			builder.WriteRune(c)
+		} else if c == '-' {
+			negative = !negative
+		} else if string(c) == decimalSeparator {
+			builder.WriteRune('.')
+		} else if string(c) == digitGroupingSymbol || c == '+' || c == ' ' || c == '\u00a0' || c == '\'' {
+			continue
+		} else if c > 127 || ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || c == '$' { // currency symbols or codes
+			continue
+		} else {
+			return 0, errs.ErrNumberInvalid
+		}
+	}
+
+	if builder.Len() < 1 {
+		return 0, errs.ErrNumberInvalid
+	}
+
+	amount, err := utils.ParseAmount(utils.TrimTrailingZerosInDecimal(builder.String()))
+
+	if err != nil {
+		return 0, err
+	}
+
+	if negative {
+		amount = -amount
+	}
+
+	return amount, nil
+}
+
+func createNewCustomTransactionDataTable(ctx core.Context, dataTable datatable.ImportedDataTable, profile *models.TransactionImportProfile) (*customTransactionDataTable, error) {
+	if profile == nil || !profile.IsValidColumnMapping() {
+		return nil, errs.ErrTransactionImportProfileColumnMappingInvalid
+	}
+
+	dateTimeLayout, err := ParseDateTimeFormat(profile.DateTimeFormat)
+
+	if err != nil {
+		return nil, err
+	}
+
+	headerColumnNames := dataTable.HeaderColumnNames()
+	columnIndexes := make(map[string]int, len(headerColumnNames))
+
+	for i := 0; i < len(headerColumnNames); i++ {
+		columnName := strings.TrimSpace(headerColumnNames[i])
+
+		if _, exists := columnIndexes[columnName]; !exists {
+			columnIndexes[columnName] = i
+		}
+	}
+
+	mapping := profile.ColumnMapping
+	mappedColumnNames := []string{mapping.Time, mapping.Type, mapping.Amount, mapping.IncomeAmount, mapping.Account, mapping.AccountCurrency, mapping.RelatedAccount, mapping.Category, mapping.Comment, mapping.Tags}
+
+	for i := 0; i < len(mappedColumnNames); i++ {
+		if mappedColumnNames[i] == "" {
+			continue
+		}
+
+		if _, exists := columnIndexes[mappedColumnNames[i]]; !exists {
+			log.Errorf(ctx, "[custom_transaction_data_table.createNewCustomTransactionDataTable] cannot find column \"%s\" in header row", mappedColumnNames[i])
+			return nil, errs.ErrMissingRequiredFieldInHeaderRow
+		}
+	}
+
+	supportedColumn := map[datatable.TransactionDataTableColumn]bool{
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:     true,
+		datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:     true,
+		datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:         true,
+		datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:         true,
+		datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
+		datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
+	}
+
+	if mapping.AccountCurrency != "" {
+		supportedColumn[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = true
+	}
+
+	if mapping.Tags != "" {
+		supportedColumn[datatable.TRANSACTION_DATA_TABLE_TAGS] = true
+	}
+
+	if mapping.Comment != "" {
+		supportedColumn[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = true
+	}
+
+	return &customTransactionDataTable{
+		innerDataTable:  dataTable,
+		profile:         profile,
+		dateTimeLayout:  dateTimeLayout,
+		columnIndexes:   columnIndexes,
+		supportedColumn: supportedColumn,
+	}, nil
+}
+
+func getAbsAmount(amount int64) int64 {
+	if amount < 0 {
+		return -amount
+	}
+
+	return amount
+}
@@ -23,9 +23,10 @@ type DataTableTransactionDataExporter struct {
 
 // DataTableTransactionDataImporter defines the structure of plain text data table importer for transaction data
 type DataTableTransactionDataImporter struct {
-	transactionTypeMapping  map[models.TransactionType]string
-	geoLocationSeparator    string
-	transactionTagSeparator string
+	transactionTypeMapping       map[models.TransactionType]string
+	geoLocationSeparator         string
+	transactionTagSeparator      string
+	matchCategoryByHierarchyName bool
 }
 
 // CreateNewExporter returns a new data table transaction data exporter according to the specified arguments
@@ -53,8 +54,16 @@ func CreateNewSimpleImporter(transactionTypeMapping map[models.TransactionType]s
 	}
 }
 
+// WithCategoryHierarchyNameMatching returns a copy of the importer which matches the sub-categories by both primary category name and sub-category name, so the sub-categories with the same name under different primary categories are different categories
+func (c *DataTableTransactionDataImporter) WithCategoryHierarchyNameMatching() *DataTableTransactionDataImporter {
+	importer := *c
+	importer.matchCategoryByHierarchyName = true
+
+	return &importer
+}
+
 // BuildExportedContent writes the exported transaction data to the data table builder
-func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context, dataTableBuilder TransactionDataTableBuilder, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) error {
+func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context, dataTableBuilder TransactionDataTableBuilder, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64]models.TransactionSplitSlice) error {
 	for i := 0; i < len(transactions); i++ {
 		transaction := transactions[i]
 
@@ -81,10 +90,37 @@ func (c *DataTableTransactionDataExporter) BuildExportedContent(ctx core.Context
 		}
 
 		dataRowMap[TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION] = c.getExportedGeographicLocation(transaction)
-		dataRowMap[TRANSACTION_DATA_TABLE_TAGS] = c.getExportedTags(dataTableBuilder, transaction.TransactionId, allTagIndexes, tagMap)
+		dataRowMap[TRANSACTION_DATA_TABLE_TAGS] = c.getExportedTags(dataTableBuilder, allTagIndexes[transaction.TransactionId], tagMap)
 		dataRowMap[TRANSACTION_DATA_TABLE_DESCRIPTION] = dataTableBuilder.ReplaceDelimiters(transaction.Comment)
 
-		dataTableBuilder.AppendTransaction(dataRowMap)
+		transactionTagIndexes := allTagIndexes[transaction.TransactionId]
+		splits := allSplits[transaction.TransactionId]
+
+		if len(splits) < 1 {
+			dataTableBuilder.AppendTransaction(dataRowMap)
+			continue
+		}
+
+		for j := 0; j < len(splits); j++ {
+			split := splits[j]
+			splitDataRowMap := make(map[TransactionDataTableColumn]string, len(dataRowMap))
+
+			for column, value := range dataRowMap {
+				splitDataRowMap[column] = value
+			}
+
+			splitDataRowMap[TRANSACTION_DATA_TABLE_CATEGORY] = c.getExportedTransactionCategoryName(dataTableBuilder, split.CategoryId, categoryMap)
+			splitDataRowMap[TRANSACTION_DATA_TABLE_SUB_CATEGORY] = c.getExportedTransactionSubCategoryName(dataTableBuilder, split.CategoryId, categoryMap)
+			splitDataRowMap[TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(split.Amount)
+			splitTagIndexes := utils.ToUniqueInt64Slice(append(append(make([]int64, 0, len(transactionTagIndexes)), transactionTagIndexes...), split.GetTagIds()...))
+			splitDataRowMap[TRANSACTION_DATA_TABLE_TAGS] = c.getExportedTags(dataTableBuilder, splitTagIndexes, tagMap)
+
+			if split.Comment != "" {
+				splitDataRowMap[TRANSACTION_DATA_TABLE_DESCRIPTION] = dataTableBuilder.ReplaceDelimiters(split.Comment)
+			}
+
+			dataTableBuilder.AppendTransaction(splitDataRowMap)
+		}
 	}
 
 	return nil
@@ -164,10 +200,8 @@ func (c *DataTableTransactionDataExporter) getExportedGeographicLocation(transac
 	return ""
 }
 
-func (c *DataTableTransactionDataExporter) getExportedTags(dataTableBuilder TransactionDataTableBuilder, transactionId int64, allTagIndexes map[int64][]int64, tagMap map[int64]*models.TransactionTag) string {
-	tagIndexes, exists := allTagIndexes[transactionId]
-
-	if !exists {
+func (c *DataTableTransactionDataExporter) getExportedTags(dataTableBuilder TransactionDataTableBuilder, tagIndexes []int64, tagMap map[int64]*models.TransactionTag) string {
+	if len(tagIndexes) < 1 {
 		return ""
 	}
 
@@ -285,7 +319,9 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 		}
 
 		categoryId := int64(0)
+		parentCategoryName := ""
 		subCategoryName := ""
+		categoryName := ""
 
 		if transactionDbType != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			transactionCategoryType, err := c.getTransactionCategoryType(transactionDbType)
@@ -297,33 +333,44 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 
 			subCategoryName = dataRow.GetData(TRANSACTION_DATA_TABLE_SUB_CATEGORY)
 
+			if dataTable.HasColumn(TRANSACTION_DATA_TABLE_CATEGORY) {
+				parentCategoryName = dataRow.GetData(TRANSACTION_DATA_TABLE_CATEGORY)
+			}
+
+			categoryName = subCategoryName
+
+			// the sub-categories with the same name under different primary categories are different categories
+			if c.matchCategoryByHierarchyName {
+				categoryName = models.GetTransactionCategoryHierarchyName(parentCategoryName, subCategoryName)
+			}
+
 			if transactionDbType == models.TRANSACTION_DB_TYPE_EXPENSE {
-				subCategory, exists := expenseCategoryMap[subCategoryName]
+				subCategory, exists := expenseCategoryMap[categoryName]
 
 				if !exists {
 					subCategory = c.createNewTransactionCategoryModel(user.Uid, subCategoryName, transactionCategoryType)
 					allNewSubExpenseCategories = append(allNewSubExpenseCategories, subCategory)
-					expenseCategoryMap[subCategoryName] = subCategory
+					expenseCategoryMap[categoryName] = subCategory
 				}
 
 				categoryId = subCategory.CategoryId
 			} else if transactionDbType == models.TRANSACTION_DB_TYPE_INCOME {
-				subCategory, exists := incomeCategoryMap[subCategoryName]
+				subCategory, exists := incomeCategoryMap[categoryName]
 
 				if !exists {
 					subCategory = c.createNewTransactionCategoryModel(user.Uid, subCategoryName, transactionCategoryType)
 					allNewSubIncomeCategories = append(allNewSubIncomeCategories, subCategory)
-					incomeCategoryMap[subCategoryName] = subCategory
+					incomeCategoryMap[categoryName] = subCategory
 				}
 
 				categoryId = subCategory.CategoryId
 			} else if transactionDbType == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
-				subCategory, exists := transferCategoryMap[subCategoryName]
+				subCategory, exists := transferCategoryMap[categoryName]
 
 				if !exists {
 					subCategory = c.createNewTransactionCategoryModel(user.Uid, subCategoryName, transactionCategoryType)
 					allNewSubTransferCategories = append(allNewSubTransferCategories, subCategory)
-					transferCategoryMap[subCategoryName] = subCategory
+					transferCategoryMap[categoryName] = subCategory
 				}
 
 				categoryId = subCategory.CategoryId
@@ -473,6 +520,16 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 			description = dataRow.GetData(TRANSACTION_DATA_TABLE_DESCRIPTION)
 		}
 
+		externalId := ""
+
+		if dataTable.HasColumn(TRANSACTION_DATA_TABLE_EXTERNAL_ID) {
+			externalId = dataRow.GetData(TRANSACTION_DATA_TABLE_EXTERNAL_ID)
+
+			if len(externalId) > models.TRANSACTION_EXTERNAL_ID_MAX_LENGTH { // the external id which is too long cannot be used for duplicate detection
+				externalId = ""
+			}
+		}
+
 		transaction := &models.ImportTransaction{
 			Transaction: &models.Transaction{
 				Uid:                  user.Uid,
@@ -486,12 +543,15 @@ func (c *DataTableTransactionDataImporter) ParseImportedData(ctx core.Context, u
 				RelatedAccountId:     relatedAccountId,
 				RelatedAccountAmount: relatedAccountAmount,
 				Comment:              description,
+				ExternalId:           externalId,
 				GeoLongitude:         geoLongitude,
 				GeoLatitude:          geoLatitude,
 				CreatedIp:            "127.0.0.1",
 			},
 			TagIds:                             tagIds,
+			OriginalParentCategoryName:         parentCategoryName,
 			OriginalCategoryName:               subCategoryName,
+			OriginalCategoryKey:                categoryName,
 			OriginalSourceAccountName:          accountName,
 			OriginalSourceAccountCurrency:      accountCurrency,
 			OriginalDestinationAccountName:     account2Name,
@@ -72,4 +72,5 @@ const (
 	TRANSACTION_DATA_TABLE_GEOGRAPHIC_LOCATION      TransactionDataTableColumn = 12
 	TRANSACTION_DATA_TABLE_TAGS                     TransactionDataTableColumn = 13
 	TRANSACTION_DATA_TABLE_DESCRIPTION              TransactionDataTableColumn = 14
+	TRANSACTION_DATA_TABLE_EXTERNAL_ID              TransactionDataTableColumn = 15
 )
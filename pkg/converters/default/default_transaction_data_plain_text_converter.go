@@ -1,14 +1,23 @@
 package _default
 
 import (
+	"io"
+
+	"golang.org/x/text/encoding"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 // defaultTransactionDataPlainTextConverter defines the structure of ezbookkeeping default plain text converter for transaction data
 type defaultTransactionDataPlainTextConverter struct {
 	columnSeparator string
+	textEncoding    encoding.Encoding
 }
 
 const ezbookkeepingLineSeparator = "\n"
@@ -57,7 +66,7 @@ var ezbookkeepingDataColumns = []datatable.TransactionDataTableColumn{
 }
 
 // ToExportedContent returns the exported transaction plain text data
-func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) ([]byte, error) {
+func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64]models.TransactionSplitSlice) ([]byte, error) {
 	dataTableBuilder := createNewDefaultTransactionPlainTextDataTableBuilder(
 		len(transactions),
 		ezbookkeepingDataColumns,
@@ -72,7 +81,7 @@ func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Co
 		ezbookkeepingTagSeparator,
 	)
 
-	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits)
 
 	if err != nil {
 		return nil, err
@@ -81,10 +90,44 @@ func (c *defaultTransactionDataPlainTextConverter) ToExportedContent(ctx core.Co
 	return []byte(dataTableBuilder.String()), nil
 }
 
+// WriteExportedHeader writes the header line of exported transaction plain text data to the writer
+func (c *defaultTransactionDataPlainTextConverter) WriteExportedHeader(ctx core.Context, writer io.Writer) error {
+	dataTableBuilder := c.createNewStreamBuilder(writer)
+	dataTableBuilder.WriteHeader()
+
+	return dataTableBuilder.Error()
+}
+
+// WriteExportedContent writes the exported transaction plain text data rows of the specified transactions to the writer
+func (c *defaultTransactionDataPlainTextConverter) WriteExportedContent(ctx core.Context, writer io.Writer, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64]models.TransactionSplitSlice) error {
+	dataTableBuilder := c.createNewStreamBuilder(writer)
+
+	dataTableExporter := datatable.CreateNewExporter(
+		ezbookkeepingTransactionTypeNameMapping,
+		ezbookkeepingGeoLocationSeparator,
+		ezbookkeepingTagSeparator,
+	)
+
+	err := dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits)
+
+	if err != nil {
+		return err
+	}
+
+	return dataTableBuilder.Error()
+}
+
 // ParseImportedData returns the imported data by parsing the transaction plain text data
 func (c *defaultTransactionDataPlainTextConverter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	content, err := utils.DecodeTextData(data, c.textEncoding)
+
+	if err != nil {
+		log.Errorf(ctx, "[default_transaction_data_plain_text_converter.ParseImportedData] cannot decode imported data, because %s", err.Error())
+		return nil, nil, nil, nil, nil, nil, errs.ErrInvalidCSVFile
+	}
+
 	dataTable, err := createNewDefaultPlainTextDataTable(
-		string(data),
+		string(content),
 		c.columnSeparator,
 		ezbookkeepingLineSeparator,
 	)
@@ -103,3 +146,21 @@ func (c *defaultTransactionDataPlainTextConverter) ParseImportedData(ctx core.Co
 
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
+
+// WithTextEncoding returns a copy of the converter which decodes the imported data by the specified text encoding
+func (c *defaultTransactionDataPlainTextConverter) WithTextEncoding(enc encoding.Encoding) base.TransactionDataImporter {
+	converter := *c
+	converter.textEncoding = enc
+
+	return &converter
+}
+
+func (c *defaultTransactionDataPlainTextConverter) createNewStreamBuilder(writer io.Writer) *defaultTransactionPlainTextDataTableBuilder {
+	return createNewDefaultTransactionPlainTextDataTableStreamBuilder(
+		writer,
+		ezbookkeepingDataColumns,
+		ezbookkeepingDataColumnNameMapping,
+		c.columnSeparator,
+		ezbookkeepingLineSeparator,
+	)
+}
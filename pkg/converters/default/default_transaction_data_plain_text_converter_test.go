@@ -1,9 +1,12 @@
 package _default
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/charmap"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -119,12 +122,168 @@ func TestDefaultTransactionDataCSVFileConverterToExportedContent(t *testing.T) {
 		"2024-09-01 12:34:56,+08:00,Income,Test Category,Test Sub Category,Test Account,CNY,123.45,,,,123.450000 45.670000,Test Tag;Test Tag2,Hello World\n" +
 		"2024-09-01 12:34:56,+00:00,Expense,Test Category2,Test Sub Category2,Test Account,CNY,-0.10,,,,,Test Tag,Foo#Bar\n" +
 		"2024-09-01 12:34:56,-05:00,Transfer,Test Category3,Test Sub Category3,Test Account,CNY,123.45,Test Account2,USD,17.35,,Test Tag2,T\te s t test\n"
-	actualContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes)
+	actualContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
 
 	assert.Nil(t, err)
 	assert.Equal(t, expectedContent, string(actualContent))
 }
 
+func TestDefaultTransactionDataCSVFileConverterToExportedContent_WithSplits(t *testing.T) {
+	converter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 1)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            3000,
+		Comment:           "Shopping",
+	}
+
+	accountMap := make(map[int64]*models.Account, 1)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Currency:  "CNY",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 3)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Test Category",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId:       2,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 1,
+		Name:             "Test Sub Category",
+	}
+	categoryMap[3] = &models.TransactionCategory{
+		CategoryId:       3,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 1,
+		Name:             "Test Sub Category2",
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 2)
+	tagMap[1] = &models.TransactionTag{
+		TagId: 1,
+		Name:  "Tag",
+	}
+	tagMap[2] = &models.TransactionTag{
+		TagId: 2,
+		Name:  "Tag2",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 1)
+	allTagIndexes[1] = []int64{1}
+
+	allSplits := make(map[int64]models.TransactionSplitSlice, 1)
+	allSplits[1] = models.TransactionSplitSlice{
+		&models.TransactionSplit{TransactionId: 1, CategoryId: 2, Amount: 1000, TagIds: "1,2", Comment: "Food"},
+		&models.TransactionSplit{TransactionId: 1, CategoryId: 3, Amount: 2000},
+	}
+
+	expectedContent := "Time,Timezone,Type,Category,Sub Category,Account,Account Currency,Amount,Account2,Account2 Currency,Account2 Amount,Geographic Location,Tags,Description\n" +
+		"2024-09-01 12:34:56,+00:00,Expense,Test Category,Test Sub Category,Test Account,CNY,10.00,,,,,Tag;Tag2,Food\n" +
+		"2024-09-01 12:34:56,+00:00,Expense,Test Category,Test Sub Category2,Test Account,CNY,20.00,,,,,Tag,Shopping\n"
+	actualContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestDefaultTransactionDataCSVFileConverterWriteExportedContent(t *testing.T) {
+	converter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	transactions := make([]*models.Transaction, 2)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		CategoryId:        1,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Hello,World",
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            -10,
+		Comment:           "Foo#Bar",
+	}
+
+	accountMap := make(map[int64]*models.Account, 1)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Currency:  "CNY",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 2)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_INCOME,
+		Name:       "Test Category",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId: 2,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Test Category2",
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 1)
+	tagMap[1] = &models.TransactionTag{
+		TagId: 1,
+		Name:  "Test Tag",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 1)
+	allTagIndexes[2] = []int64{1}
+
+	var builder strings.Builder
+	err := converter.WriteExportedHeader(context, &builder)
+	assert.Nil(t, err)
+
+	err = converter.WriteExportedContent(context, &builder, 123, transactions[:1], accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+
+	err = converter.WriteExportedContent(context, &builder, 123, transactions[1:], accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+
+	expectedContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, string(expectedContent), builder.String())
+	assert.Equal(t, "Time,Timezone,Type,Category,Sub Category,Account,Account Currency,Amount,Account2,Account2 Currency,Account2 Amount,Geographic Location,Tags,Description\n"+
+		"2024-09-01 12:34:56,+08:00,Income,Test Category,Test Category,Test Account,CNY,123.45,,,,,,Hello World\n"+
+		"2024-09-01 12:34:56,+00:00,Expense,Test Category2,Test Category2,Test Account,CNY,-0.10,,,,,Test Tag,Foo#Bar\n", builder.String())
+}
+
+func TestDefaultTransactionDataTSVFileConverterWriteExportedContent_WriteError(t *testing.T) {
+	converter := DefaultTransactionDataTSVFileConverter
+	context := core.NewNullContext()
+
+	err := converter.WriteExportedHeader(context, &failedWriter{})
+	assert.EqualError(t, err, "write failed")
+}
+
+type failedWriter struct{}
+
+func (w *failedWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
 func TestDefaultTransactionDataCSVFileConverterParseImportedData_MinimumValidData(t *testing.T) {
 	converter := DefaultTransactionDataCSVFileConverter
 	context := core.NewNullContext()
@@ -196,6 +355,43 @@ func TestDefaultTransactionDataCSVFileConverterParseImportedData_MinimumValidDat
 	assert.Equal(t, "Test Category3", allNewSubTransferCategories[0].Name)
 }
 
+func TestDefaultTransactionDataCSVFileConverterParseImportedData_MatchSubCategoryByName(t *testing.T) {
+	converter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	data := []byte("Time,Type,Category,Sub Category,Account,Amount,Account2,Account2 Amount\n" +
+		"2024-09-01 01:23:45,Expense,Food,Test Category,Test Account,1.00,,\n" +
+		"2024-09-01 12:34:56,Expense,Shopping,Test Category,Test Account,2.00,,")
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err := converter.ParseImportedData(context, user, data, 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, "Test Category", allNewSubExpenseCategories[0].Name)
+
+	expenseCategoryMap := map[string]*models.TransactionCategory{
+		"Test Category": {
+			CategoryId: 1234567891,
+			Name:       "Test Category",
+			Type:       models.CATEGORY_TYPE_EXPENSE,
+		},
+	}
+
+	allNewTransactions, _, allNewSubExpenseCategories, _, _, _, err = converter.ParseImportedData(context, user, data, 0, nil, expenseCategoryMap, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 0, len(allNewSubExpenseCategories))
+	assert.Equal(t, int64(1234567891), allNewTransactions[0].CategoryId)
+	assert.Equal(t, int64(1234567891), allNewTransactions[1].CategoryId)
+}
+
 func TestDefaultTransactionDataCSVFileConverterParseImportedData_ParseInvalidTime(t *testing.T) {
 	converter := DefaultTransactionDataCSVFileConverter
 	context := core.NewNullContext()
@@ -466,6 +662,48 @@ func TestDefaultTransactionDataCSVFileConverterParseImportedData_ParseDescriptio
 	assert.Equal(t, "foo    bar\t#test", allNewTransactions[0].Comment)
 }
 
+func TestDefaultTransactionDataCSVFileConverterParseImportedData_DetectTextEncoding(t *testing.T) {
+	converter := DefaultTransactionDataCSVFileConverter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("\xEF\xBB\xBFTime,Type,Sub Category,Account,Amount,Account2,Account2 Amount,Description\n"+
+		"2024-09-01 12:34:56,Expense,Test Category,Test Account,123.45,,,Caf\xC3\xA9"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "Café", allNewTransactions[0].Comment)
+
+	allNewTransactions, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Time,Type,Sub Category,Account,Amount,Account2,Account2 Amount,Description\n"+
+		"2024-09-01 12:34:56,Expense,Test Category,Test Account,123.45,,,Caf\xE9 Cr\xE8me"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "Café Crème", allNewTransactions[0].Comment)
+}
+
+func TestDefaultTransactionDataCSVFileConverterParseImportedData_SpecifiedTextEncoding(t *testing.T) {
+	converter := DefaultTransactionDataCSVFileConverter.WithTextEncoding(charmap.ISO8859_15)
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Time,Type,Sub Category,Account,Amount,Account2,Account2 Amount,Description\n"+
+		"2024-09-01 12:34:56,Expense,Test Category,Test Account,123.45,,,10 \xA4"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "10 €", allNewTransactions[0].Comment)
+	assert.Nil(t, DefaultTransactionDataCSVFileConverter.textEncoding)
+}
+
 func TestDefaultTransactionDataCSVFileConverterParseImportedData_MissingFileHeader(t *testing.T) {
 	converter := DefaultTransactionDataCSVFileConverter
 	context := core.NewNullContext()
@@ -0,0 +1,405 @@
+package _default
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tealeg/xlsx"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/excel"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const ezbookkeepingXlsxTransactionsSheetName = "Transactions"
+const ezbookkeepingXlsxAccountsSheetName = "Accounts"
+const ezbookkeepingXlsxCategoriesSheetName = "Categories"
+const ezbookkeepingXlsxMonthlySummarySheetName = "Monthly Summary"
+const ezbookkeepingXlsxAmountFormat = "0.00"
+
+var ezbookkeepingXlsxAccountColumnNames = []string{"Account", "Parent Account", "Currency", "Balance", "Hidden", "Description"}
+var ezbookkeepingXlsxCategoryColumnNames = []string{"Type", "Category", "Sub Category", "Hidden"}
+var ezbookkeepingXlsxMonthlySummaryColumnNames = []string{"Month", "Currency", "Income", "Expense", "Net"}
+
+var ezbookkeepingCategoryTypeNameMapping = map[models.TransactionCategoryType]string{
+	models.CATEGORY_TYPE_INCOME:   "Income",
+	models.CATEGORY_TYPE_EXPENSE:  "Expense",
+	models.CATEGORY_TYPE_TRANSFER: "Transfer",
+}
+
+// defaultTransactionDataXlsxFileConverter defines the structure of ezbookkeeping default xlsx file converter
+type defaultTransactionDataXlsxFileConverter struct {
+	withAccountsSheet       bool
+	withCategoriesSheet     bool
+	withMonthlySummarySheet bool
+}
+
+// defaultTransactionXlsxDataTableBuilder defines the structure of ezbookkeeping default transaction xlsx data table builder
+type defaultTransactionXlsxDataTableBuilder struct {
+	sheet   *xlsx.Sheet
+	columns []datatable.TransactionDataTableColumn
+}
+
+// defaultTransactionXlsxMonthlySummaryItem defines the structure of the total income and expense amount of one currency in one month
+type defaultTransactionXlsxMonthlySummaryItem struct {
+	month         string
+	currency      string
+	incomeAmount  int64
+	expenseAmount int64
+}
+
+// Initialize an ezbookkeeping default transaction data xlsx file converter singleton instance
+var (
+	DefaultTransactionDataXlsxFileConverter = &defaultTransactionDataXlsxFileConverter{}
+)
+
+// CreateNewDefaultTransactionDataXlsxFileConverter returns a new ezbookkeeping default xlsx file converter which also exports the specified optional sheets
+func CreateNewDefaultTransactionDataXlsxFileConverter(withAccountsSheet bool, withCategoriesSheet bool, withMonthlySummarySheet bool) *defaultTransactionDataXlsxFileConverter {
+	return &defaultTransactionDataXlsxFileConverter{
+		withAccountsSheet:       withAccountsSheet,
+		withCategoriesSheet:     withCategoriesSheet,
+		withMonthlySummarySheet: withMonthlySummarySheet,
+	}
+}
+
+// ToExportedContent returns the exported transaction xlsx data, the transactions are in the first sheet and the accounts, categories and monthly summary are in the optional sheets
+func (c *defaultTransactionDataXlsxFileConverter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64]models.TransactionSplitSlice) ([]byte, error) {
+	file := xlsx.NewFile()
+	transactionsSheet, err := file.AddSheet(ezbookkeepingXlsxTransactionsSheetName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dataTableBuilder := createNewDefaultTransactionXlsxDataTableBuilder(transactionsSheet, ezbookkeepingDataColumns, ezbookkeepingDataColumnNameMapping)
+
+	dataTableExporter := datatable.CreateNewExporter(
+		ezbookkeepingTransactionTypeNameMapping,
+		ezbookkeepingGeoLocationSeparator,
+		ezbookkeepingTagSeparator,
+	)
+
+	err = dataTableExporter.BuildExportedContent(ctx, dataTableBuilder, uid, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.withAccountsSheet {
+		err = c.appendAccountsSheet(file, accountMap)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.withCategoriesSheet {
+		err = c.appendCategoriesSheet(file, categoryMap)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.withMonthlySummarySheet {
+		err = c.appendMonthlySummarySheet(file, transactions, accountMap)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var buffer bytes.Buffer
+	err = file.Write(&buffer)
+
+	if err != nil {
+		log.Errorf(ctx, "[default_transaction_data_xlsx_file_converter.ToExportedContent] failed to write xlsx file for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// ParseImportedData returns the imported data by parsing the transaction sheet of xlsx data
+func (c *defaultTransactionDataXlsxFileConverter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
+	dataTable, err := excel.CreateNewExcelXlsxFileImportedDataTableFromSheet(data, ezbookkeepingXlsxTransactionsSheetName)
+
+	if err != nil {
+		log.Errorf(ctx, "[default_transaction_data_xlsx_file_converter.ParseImportedData] cannot parse xlsx file, because %s", err.Error())
+		return nil, nil, nil, nil, nil, nil, errs.ErrInvalidExcelFile
+	}
+
+	transactionDataTable := datatable.CreateNewImportedTransactionDataTable(dataTable, ezbookkeepingDataColumnNameMapping)
+
+	dataTableImporter := datatable.CreateNewImporter(
+		ezbookkeepingTransactionTypeNameMapping,
+		ezbookkeepingGeoLocationSeparator,
+		ezbookkeepingTagSeparator,
+	)
+
+	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
+}
+
+func (c *defaultTransactionDataXlsxFileConverter) appendAccountsSheet(file *xlsx.File, accountMap map[int64]*models.Account) error {
+	sheet, err := file.AddSheet(ezbookkeepingXlsxAccountsSheetName)
+
+	if err != nil {
+		return err
+	}
+
+	appendXlsxTextRow(sheet, ezbookkeepingXlsxAccountColumnNames)
+
+	topLevelAccounts := make([]*models.Account, 0, len(accountMap))
+	subAccounts := make(map[int64][]*models.Account)
+
+	for _, account := range accountMap {
+		if account.ParentAccountId == models.LevelOneAccountParentId {
+			topLevelAccounts = append(topLevelAccounts, account)
+		} else {
+			subAccounts[account.ParentAccountId] = append(subAccounts[account.ParentAccountId], account)
+		}
+	}
+
+	sortAccountsByDisplayOrder(topLevelAccounts)
+
+	for i := 0; i < len(topLevelAccounts); i++ {
+		account := topLevelAccounts[i]
+		appendXlsxAccountRow(sheet, account, "")
+
+		children := subAccounts[account.AccountId]
+		sortAccountsByDisplayOrder(children)
+
+		for j := 0; j < len(children); j++ {
+			appendXlsxAccountRow(sheet, children[j], account.Name)
+		}
+	}
+
+	return nil
+}
+
+func (c *defaultTransactionDataXlsxFileConverter) appendCategoriesSheet(file *xlsx.File, categoryMap map[int64]*models.TransactionCategory) error {
+	sheet, err := file.AddSheet(ezbookkeepingXlsxCategoriesSheetName)
+
+	if err != nil {
+		return err
+	}
+
+	appendXlsxTextRow(sheet, ezbookkeepingXlsxCategoryColumnNames)
+
+	topLevelCategories := make([]*models.TransactionCategory, 0, len(categoryMap))
+	subCategories := make(map[int64][]*models.TransactionCategory)
+
+	for _, category := range categoryMap {
+		if category.ParentCategoryId == models.LevelOneTransactionParentId {
+			topLevelCategories = append(topLevelCategories, category)
+		} else {
+			subCategories[category.ParentCategoryId] = append(subCategories[category.ParentCategoryId], category)
+		}
+	}
+
+	sortCategoriesByDisplayOrder(topLevelCategories)
+
+	for i := 0; i < len(topLevelCategories); i++ {
+		category := topLevelCategories[i]
+		children := subCategories[category.CategoryId]
+		sortCategoriesByDisplayOrder(children)
+
+		if len(children) < 1 {
+			appendXlsxTextRow(sheet, []string{ezbookkeepingCategoryTypeNameMapping[category.Type], category.Name, "", getXlsxBooleanText(category.Hidden)})
+			continue
+		}
+
+		for j := 0; j < len(children); j++ {
+			appendXlsxTextRow(sheet, []string{ezbookkeepingCategoryTypeNameMapping[category.Type], category.Name, children[j].Name, getXlsxBooleanText(category.Hidden || children[j].Hidden)})
+		}
+	}
+
+	return nil
+}
+
+func (c *defaultTransactionDataXlsxFileConverter) appendMonthlySummarySheet(file *xlsx.File, transactions []*models.Transaction, accountMap map[int64]*models.Account) error {
+	sheet, err := file.AddSheet(ezbookkeepingXlsxMonthlySummarySheetName)
+
+	if err != nil {
+		return err
+	}
+
+	appendXlsxTextRow(sheet, ezbookkeepingXlsxMonthlySummaryColumnNames)
+
+	summaryItems := getMonthlySummaryItems(transactions, accountMap)
+
+	for i := 0; i < len(summaryItems); i++ {
+		item := summaryItems[i]
+		row := sheet.AddRow()
+		row.AddCell().SetString(item.month)
+		row.AddCell().SetString(item.currency)
+		setXlsxAmountCell(row.AddCell(), item.incomeAmount)
+		setXlsxAmountCell(row.AddCell(), item.expenseAmount)
+		setXlsxAmountCell(row.AddCell(), item.incomeAmount-item.expenseAmount)
+	}
+
+	return nil
+}
+
+// AppendTransaction appends the specified transaction to the transaction sheet
+func (b *defaultTransactionXlsxDataTableBuilder) AppendTransaction(data map[datatable.TransactionDataTableColumn]string) {
+	row := b.sheet.AddRow()
+
+	for i := 0; i < len(b.columns); i++ {
+		column := b.columns[i]
+		value := data[column]
+		cell := row.AddCell()
+
+		if (column == datatable.TRANSACTION_DATA_TABLE_AMOUNT || column == datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT) && value != "" {
+			amount, err := strconv.ParseFloat(value, 64)
+
+			if err == nil {
+				cell.SetFloatWithFormat(amount, ezbookkeepingXlsxAmountFormat)
+				continue
+			}
+		}
+
+		cell.SetString(value)
+	}
+}
+
+// ReplaceDelimiters returns the text after removing the line breaks, because xlsx cell does not have delimiters
+func (b *defaultTransactionXlsxDataTableBuilder) ReplaceDelimiters(text string) string {
+	text = strings.Replace(text, "\r\n", " ", -1)
+	text = strings.Replace(text, "\r", " ", -1)
+	text = strings.Replace(text, "\n", " ", -1)
+
+	return text
+}
+
+func createNewDefaultTransactionXlsxDataTableBuilder(sheet *xlsx.Sheet, columns []datatable.TransactionDataTableColumn, dataColumnNameMapping map[datatable.TransactionDataTableColumn]string) *defaultTransactionXlsxDataTableBuilder {
+	headerRowItems := make([]string, len(columns))
+
+	for i := 0; i < len(columns); i++ {
+		headerRowItems[i] = dataColumnNameMapping[columns[i]]
+	}
+
+	appendXlsxTextRow(sheet, headerRowItems)
+
+	return &defaultTransactionXlsxDataTableBuilder{
+		sheet:   sheet,
+		columns: columns,
+	}
+}
+
+func getMonthlySummaryItems(transactions []*models.Transaction, accountMap map[int64]*models.Account) []*defaultTransactionXlsxMonthlySummaryItem {
+	summaryItemsMap := make(map[string]*defaultTransactionXlsxMonthlySummaryItem)
+
+	for i := 0; i < len(transactions); i++ {
+		transaction := transactions[i]
+
+		if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			continue
+		}
+
+		account, exists := accountMap[transaction.AccountId]
+
+		if !exists {
+			continue
+		}
+
+		transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+		month := time.UnixMilli(transaction.TransactionTime).In(transactionTimeZone).Format("2006-01")
+		key := month + "|" + account.Currency
+		item, exists := summaryItemsMap[key]
+
+		if !exists {
+			item = &defaultTransactionXlsxMonthlySummaryItem{
+				month:    month,
+				currency: account.Currency,
+			}
+			summaryItemsMap[key] = item
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			item.incomeAmount += transaction.Amount
+		} else {
+			item.expenseAmount += transaction.Amount
+		}
+	}
+
+	summaryItems := make([]*defaultTransactionXlsxMonthlySummaryItem, 0, len(summaryItemsMap))
+
+	for _, item := range summaryItemsMap {
+		summaryItems = append(summaryItems, item)
+	}
+
+	sort.Slice(summaryItems, func(i, j int) bool {
+		if summaryItems[i].month != summaryItems[j].month {
+			return summaryItems[i].month < summaryItems[j].month
+		}
+
+		return summaryItems[i].currency < summaryItems[j].currency
+	})
+
+	return summaryItems
+}
+
+func appendXlsxTextRow(sheet *xlsx.Sheet, items []string) {
+	row := sheet.AddRow()
+
+	for i := 0; i < len(items); i++ {
+		row.AddCell().SetString(items[i])
+	}
+}
+
+func appendXlsxAccountRow(sheet *xlsx.Sheet, account *models.Account, parentAccountName string) {
+	row := sheet.AddRow()
+	row.AddCell().SetString(account.Name)
+	row.AddCell().SetString(parentAccountName)
+	row.AddCell().SetString(account.Currency)
+	setXlsxAmountCell(row.AddCell(), account.Balance)
+	row.AddCell().SetString(getXlsxBooleanText(account.Hidden))
+	row.AddCell().SetString(account.Comment)
+}
+
+func setXlsxAmountCell(cell *xlsx.Cell, amount int64) {
+	cell.SetFloatWithFormat(float64(amount)/100, ezbookkeepingXlsxAmountFormat)
+}
+
+func getXlsxBooleanText(value bool) string {
+	if value {
+		return "Yes"
+	}
+
+	return "No"
+}
+
+func sortAccountsByDisplayOrder(accounts []*models.Account) {
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Category != accounts[j].Category {
+			return accounts[i].Category < accounts[j].Category
+		}
+
+		if accounts[i].DisplayOrder != accounts[j].DisplayOrder {
+			return accounts[i].DisplayOrder < accounts[j].DisplayOrder
+		}
+
+		return accounts[i].AccountId < accounts[j].AccountId
+	})
+}
+
+func sortCategoriesByDisplayOrder(categories []*models.TransactionCategory) {
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Type != categories[j].Type {
+			return categories[i].Type < categories[j].Type
+		}
+
+		if categories[i].DisplayOrder != categories[j].DisplayOrder {
+			return categories[i].DisplayOrder < categories[j].DisplayOrder
+		}
+
+		return categories[i].CategoryId < categories[j].CategoryId
+	})
+}
@@ -0,0 +1,232 @@
+package _default
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tealeg/xlsx"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestDefaultTransactionDataXlsxFileConverterToExportedContent(t *testing.T) {
+	converter := CreateNewDefaultTransactionDataXlsxFileConverter(true, true, true)
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := getXlsxFileConverterTestData()
+	actualContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+
+	file, err := xlsx.OpenBinary(actualContent)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(file.Sheets))
+
+	transactionsSheet := file.Sheets[0]
+	assert.Equal(t, "Transactions", transactionsSheet.Name)
+	assert.Equal(t, 4, len(transactionsSheet.Rows))
+	assert.Equal(t, "Time", transactionsSheet.Rows[0].Cells[0].Value)
+	assert.Equal(t, "2024-09-01 04:34:56", transactionsSheet.Rows[1].Cells[0].Value)
+	assert.Equal(t, "Income", transactionsSheet.Rows[1].Cells[2].Value)
+	assert.Equal(t, "Test Sub Category", transactionsSheet.Rows[1].Cells[4].Value)
+	assert.Equal(t, xlsx.CellTypeNumeric, transactionsSheet.Rows[1].Cells[7].Type())
+	assert.Equal(t, "123.45", transactionsSheet.Rows[1].Cells[7].Value)
+	assert.Equal(t, "Test Tag;Test Tag2", transactionsSheet.Rows[1].Cells[12].Value)
+	assert.Equal(t, "Hello World", transactionsSheet.Rows[1].Cells[13].Value)
+	assert.Equal(t, "Test Account2", transactionsSheet.Rows[3].Cells[8].Value)
+	assert.Equal(t, "17.35", transactionsSheet.Rows[3].Cells[10].Value)
+
+	accountsSheet := file.Sheets[1]
+	assert.Equal(t, "Accounts", accountsSheet.Name)
+	assert.Equal(t, 3, len(accountsSheet.Rows))
+	assert.Equal(t, "Test Account", accountsSheet.Rows[1].Cells[0].Value)
+	assert.Equal(t, "CNY", accountsSheet.Rows[1].Cells[2].Value)
+	assert.Equal(t, "1000", accountsSheet.Rows[1].Cells[3].Value)
+	assert.Equal(t, "Test Account2", accountsSheet.Rows[2].Cells[0].Value)
+	assert.Equal(t, "Yes", accountsSheet.Rows[2].Cells[4].Value)
+
+	categoriesSheet := file.Sheets[2]
+	assert.Equal(t, "Categories", categoriesSheet.Name)
+	assert.Equal(t, 4, len(categoriesSheet.Rows))
+	assert.Equal(t, "Income", categoriesSheet.Rows[1].Cells[0].Value)
+	assert.Equal(t, "Test Category", categoriesSheet.Rows[1].Cells[1].Value)
+	assert.Equal(t, "Test Sub Category", categoriesSheet.Rows[1].Cells[2].Value)
+	assert.Equal(t, "Expense", categoriesSheet.Rows[2].Cells[0].Value)
+	assert.Equal(t, "Transfer", categoriesSheet.Rows[3].Cells[0].Value)
+
+	monthlySummarySheet := file.Sheets[3]
+	assert.Equal(t, "Monthly Summary", monthlySummarySheet.Name)
+	assert.Equal(t, 2, len(monthlySummarySheet.Rows))
+	assert.Equal(t, "2024-09", monthlySummarySheet.Rows[1].Cells[0].Value)
+	assert.Equal(t, "CNY", monthlySummarySheet.Rows[1].Cells[1].Value)
+	assert.Equal(t, "123.45", monthlySummarySheet.Rows[1].Cells[2].Value)
+	assert.Equal(t, "0.1", monthlySummarySheet.Rows[1].Cells[3].Value)
+	assert.Equal(t, "123.35", monthlySummarySheet.Rows[1].Cells[4].Value)
+}
+
+func TestDefaultTransactionDataXlsxFileConverterToExportedContent_WithoutOptionalSheets(t *testing.T) {
+	converter := DefaultTransactionDataXlsxFileConverter
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := getXlsxFileConverterTestData()
+	actualContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+
+	file, err := xlsx.OpenBinary(actualContent)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(file.Sheets))
+	assert.Equal(t, "Transactions", file.Sheets[0].Name)
+}
+
+func TestDefaultTransactionDataXlsxFileConverterParseImportedData_ExportedContent(t *testing.T) {
+	converter := CreateNewDefaultTransactionDataXlsxFileConverter(true, true, true)
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes := getXlsxFileConverterTestData()
+	exportedContent, err := converter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, tagMap, allTagIndexes, nil)
+	assert.Nil(t, err)
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, exportedContent, 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725165296), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Test Sub Category", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(10), allNewTransactions[1].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(12345), allNewTransactions[2].Amount)
+	assert.Equal(t, "Test Account2", allNewTransactions[2].OriginalDestinationAccountName)
+	assert.Equal(t, int64(1735), allNewTransactions[2].RelatedAccountAmount)
+}
+
+func TestDefaultTransactionDataXlsxFileConverterParseImportedData_InvalidFile(t *testing.T) {
+	converter := DefaultTransactionDataXlsxFileConverter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Time,Type,Sub Category,Account,Amount,Account2,Account2 Amount\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidExcelFile.Message)
+}
+
+func getXlsxFileConverterTestData() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory, map[int64]*models.TransactionTag, map[int64][]int64) {
+	transactions := make([]*models.Transaction, 3)
+	transactions[0] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 0,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Hello\nWorld",
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        4,
+		AccountId:         1,
+		Amount:            10,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:        3,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    0,
+		CategoryId:           6,
+		AccountId:            1,
+		Amount:               12345,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 1735,
+	}
+
+	accountMap := make(map[int64]*models.Account, 2)
+	accountMap[1] = &models.Account{
+		AccountId:    1,
+		Name:         "Test Account",
+		Currency:     "CNY",
+		Balance:      100000,
+		DisplayOrder: 1,
+	}
+	accountMap[2] = &models.Account{
+		AccountId:    2,
+		Name:         "Test Account2",
+		Currency:     "USD",
+		Hidden:       true,
+		DisplayOrder: 2,
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 6)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_INCOME,
+		Name:       "Test Category",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId:       2,
+		Type:             models.CATEGORY_TYPE_INCOME,
+		ParentCategoryId: 1,
+		Name:             "Test Sub Category",
+	}
+	categoryMap[3] = &models.TransactionCategory{
+		CategoryId: 3,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Test Category2",
+	}
+	categoryMap[4] = &models.TransactionCategory{
+		CategoryId:       4,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 3,
+		Name:             "Test Sub Category2",
+	}
+	categoryMap[5] = &models.TransactionCategory{
+		CategoryId: 5,
+		Type:       models.CATEGORY_TYPE_TRANSFER,
+		Name:       "Test Category3",
+	}
+	categoryMap[6] = &models.TransactionCategory{
+		CategoryId:       6,
+		Type:             models.CATEGORY_TYPE_TRANSFER,
+		ParentCategoryId: 5,
+		Name:             "Test Sub Category3",
+	}
+
+	tagMap := make(map[int64]*models.TransactionTag, 2)
+	tagMap[1] = &models.TransactionTag{
+		TagId: 1,
+		Name:  "Test Tag",
+	}
+	tagMap[2] = &models.TransactionTag{
+		TagId: 2,
+		Name:  "Test Tag2",
+	}
+
+	allTagIndexes := make(map[int64][]int64, 1)
+	allTagIndexes[1] = []int64{1, 2}
+
+	return transactions, accountMap, categoryMap, tagMap, allTagIndexes
+}
@@ -2,6 +2,7 @@ package _default
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
@@ -35,6 +36,8 @@ type defaultTransactionPlainTextDataTableBuilder struct {
 	dataColumnNameMapping map[datatable.TransactionDataTableColumn]string
 	dataLineFormat        string
 	builder               *strings.Builder
+	writer                io.Writer
+	err                   error
 }
 
 // DataRowCount returns the total count of data row
@@ -107,7 +110,7 @@ func (b *defaultTransactionPlainTextDataTableBuilder) AppendTransaction(data map
 		dataRowParams[i] = data[b.columns[i]]
 	}
 
-	b.builder.WriteString(fmt.Sprintf(b.dataLineFormat, dataRowParams...))
+	b.write(fmt.Sprintf(b.dataLineFormat, dataRowParams...))
 }
 
 // ReplaceDelimiters returns the text after removing the delimiters
@@ -123,9 +126,31 @@ func (b *defaultTransactionPlainTextDataTableBuilder) ReplaceDelimiters(text str
 
 // String returns the textual representation of this data
 func (b *defaultTransactionPlainTextDataTableBuilder) String() string {
+	if b.builder == nil {
+		return ""
+	}
+
 	return b.builder.String()
 }
 
+// Error returns the first error occurred when writing data to the writer
+func (b *defaultTransactionPlainTextDataTableBuilder) Error() error {
+	return b.err
+}
+
+// WriteHeader writes the header line to the writer
+func (b *defaultTransactionPlainTextDataTableBuilder) WriteHeader() {
+	b.write(b.generateHeaderLine())
+}
+
+func (b *defaultTransactionPlainTextDataTableBuilder) write(content string) {
+	if b.err != nil {
+		return
+	}
+
+	_, b.err = io.WriteString(b.writer, content)
+}
+
 func (b *defaultTransactionPlainTextDataTableBuilder) generateHeaderLine() string {
 	var ret strings.Builder
 
@@ -184,19 +209,23 @@ func createNewDefaultTransactionPlainTextDataTableBuilder(transactionCount int,
 	var builder strings.Builder
 	builder.Grow(transactionCount * 100)
 
+	dataTableBuilder := createNewDefaultTransactionPlainTextDataTableStreamBuilder(&builder, columns, dataColumnNameMapping, columnSeparator, lineSeparator)
+	dataTableBuilder.builder = &builder
+	dataTableBuilder.WriteHeader()
+
+	return dataTableBuilder
+}
+
+func createNewDefaultTransactionPlainTextDataTableStreamBuilder(writer io.Writer, columns []datatable.TransactionDataTableColumn, dataColumnNameMapping map[datatable.TransactionDataTableColumn]string, columnSeparator string, lineSeparator string) *defaultTransactionPlainTextDataTableBuilder {
 	dataTableBuilder := &defaultTransactionPlainTextDataTableBuilder{
 		columnSeparator:       columnSeparator,
 		lineSeparator:         lineSeparator,
 		columns:               columns,
 		dataColumnNameMapping: dataColumnNameMapping,
-		builder:               &builder,
+		writer:                writer,
 	}
 
-	headerLine := dataTableBuilder.generateHeaderLine()
-	dataLineFormat := dataTableBuilder.generateDataLineFormat()
-
-	dataTableBuilder.builder.WriteString(headerLine)
-	dataTableBuilder.dataLineFormat = dataLineFormat
+	dataTableBuilder.dataLineFormat = dataTableBuilder.generateDataLineFormat()
 
 	return dataTableBuilder
 }
@@ -0,0 +1,110 @@
+package excel
+
+import (
+	"math"
+	"time"
+
+	"github.com/tealeg/xlsx"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+)
+
+const xlsxDateTimeFormat = "2006-01-02 15:04:05"
+const xlsxDateFormat = "2006-01-02"
+
+// CreateNewExcelXlsxFileImportedDataTable returns excel xlsx data table by file binary data
+func CreateNewExcelXlsxFileImportedDataTable(data []byte) (datatable.ImportedDataTable, error) {
+	file, err := xlsx.OpenBinary(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allSheets := make([][][]string, 0, len(file.Sheets))
+
+	for i := 0; i < len(file.Sheets); i++ {
+		allSheets = append(allSheets, getXlsxSheetRows(file.Sheets[i], file.Date1904))
+	}
+
+	return createNewSpreadsheetFileImportedDataTable(allSheets)
+}
+
+// CreateNewExcelXlsxFileImportedDataTableFromSheet returns excel xlsx data table which only contains the specified sheet by file binary data, the first sheet is used if the specified sheet does not exist
+func CreateNewExcelXlsxFileImportedDataTableFromSheet(data []byte, sheetName string) (datatable.ImportedDataTable, error) {
+	file, err := xlsx.OpenBinary(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(file.Sheets) < 1 {
+		return createNewSpreadsheetFileImportedDataTable(nil)
+	}
+
+	sheet, exists := file.Sheet[sheetName]
+
+	if !exists {
+		sheet = file.Sheets[0]
+	}
+
+	return createNewSpreadsheetFileImportedDataTable([][][]string{getXlsxSheetRows(sheet, file.Date1904)})
+}
+
+func getXlsxSheetRows(sheet *xlsx.Sheet, date1904 bool) [][]string {
+	allRows := make([][]string, 0, len(sheet.Rows))
+
+	for i := 0; i < len(sheet.Rows); i++ {
+		row := sheet.Rows[i]
+
+		if row == nil {
+			continue
+		}
+
+		rowItems := make([]string, len(row.Cells))
+
+		for j := 0; j < len(row.Cells); j++ {
+			rowItems[j] = getXlsxCellTextualValue(row.Cells[j], date1904)
+		}
+
+		if isBlankSpreadsheetRow(rowItems) {
+			continue
+		}
+
+		allRows = append(allRows, rowItems)
+	}
+
+	return allRows
+}
+
+func getXlsxCellTextualValue(cell *xlsx.Cell, date1904 bool) string {
+	if cell == nil {
+		return ""
+	}
+
+	if cell.Type() != xlsx.CellTypeNumeric {
+		return cell.Value
+	}
+
+	if cell.IsTime() {
+		value, err := cell.Float()
+
+		if err == nil {
+			// the excel time is stored as float, so it should be rounded to avoid precision loss
+			dateTime := xlsx.TimeFromExcelTime(value, date1904).Round(time.Second)
+
+			if value == math.Trunc(value) {
+				return dateTime.Format(xlsxDateFormat)
+			}
+
+			return dateTime.Format(xlsxDateTimeFormat)
+		}
+	}
+
+	value, err := cell.GeneralNumericWithoutScientific()
+
+	if err != nil {
+		return cell.Value
+	}
+
+	return value
+}
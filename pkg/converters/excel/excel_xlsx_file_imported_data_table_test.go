@@ -0,0 +1,182 @@
+package excel
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tealeg/xlsx"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestExcelXlsxFileImportedDataTableDataRowCount(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/simple_excel_file.xlsx")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewExcelXlsxFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, datatable.DataRowCount())
+}
+
+func TestExcelXlsxFileImportedDataTableDataRowCount_MultipleSheets(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/multiple_sheets_excel_file.xlsx")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewExcelXlsxFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, datatable.DataRowCount())
+}
+
+func TestExcelXlsxFileImportedDataTableHeaderColumnNames(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/simple_excel_file.xlsx")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewExcelXlsxFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"A1", "B1", "C1"}, datatable.HeaderColumnNames())
+}
+
+func TestExcelXlsxFileDataRowIterator(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/simple_excel_file.xlsx")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewExcelXlsxFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+
+	iterator := datatable.DataRowIterator()
+	assert.True(t, iterator.HasNext())
+
+	// data row 1
+	assert.NotNil(t, iterator.Next())
+	assert.True(t, iterator.HasNext())
+
+	// data row 2
+	assert.NotNil(t, iterator.Next())
+	assert.False(t, iterator.HasNext())
+
+	// not existed data row 3
+	assert.Nil(t, iterator.Next())
+	assert.False(t, iterator.HasNext())
+}
+
+func TestExcelXlsxFileDataRowGetData(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/simple_excel_file.xlsx")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewExcelXlsxFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+
+	iterator := datatable.DataRowIterator()
+
+	row1 := iterator.Next()
+	assert.Equal(t, "A2", row1.GetData(0))
+	assert.Equal(t, "B2", row1.GetData(1))
+	assert.Equal(t, "C2", row1.GetData(2))
+	assert.Equal(t, "", row1.GetData(3))
+
+	row2 := iterator.Next()
+	assert.Equal(t, "A3", row2.GetData(0))
+	assert.Equal(t, "B3", row2.GetData(1))
+	assert.Equal(t, "C3", row2.GetData(2))
+}
+
+func TestExcelXlsxFileDataRowGetData_MultipleSheets(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/multiple_sheets_excel_file.xlsx")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewExcelXlsxFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+
+	iterator := datatable.DataRowIterator()
+
+	sheet1Row1 := iterator.Next()
+	assert.Equal(t, "1-A2", sheet1Row1.GetData(0))
+	assert.Equal(t, "1-B2", sheet1Row1.GetData(1))
+	assert.Equal(t, "1-C2", sheet1Row1.GetData(2))
+
+	sheet1Row2 := iterator.Next()
+	assert.Equal(t, "1-A3", sheet1Row2.GetData(0))
+	assert.Equal(t, "1-B3", sheet1Row2.GetData(1))
+	assert.Equal(t, "1-C3", sheet1Row2.GetData(2))
+
+	// skip empty sheet2
+
+	sheet3Row1 := iterator.Next()
+	assert.Equal(t, "3-A2", sheet3Row1.GetData(0))
+	assert.Equal(t, "3-B2", sheet3Row1.GetData(1))
+	assert.Equal(t, "", sheet3Row1.GetData(2))
+
+	// skip no data row sheet4
+
+	sheet5Row1 := iterator.Next()
+	assert.Equal(t, "5-A2", sheet5Row1.GetData(0))
+	assert.Equal(t, "5-B2", sheet5Row1.GetData(1))
+	assert.Equal(t, "5-C2", sheet5Row1.GetData(2))
+
+	sheet5Row2 := iterator.Next()
+	assert.Equal(t, "5-A3", sheet5Row2.GetData(0))
+	assert.Equal(t, "5-B3", sheet5Row2.GetData(1))
+	assert.Equal(t, "5-C3", sheet5Row2.GetData(2))
+
+	assert.False(t, iterator.HasNext())
+}
+
+func TestExcelXlsxFileDataRowGetData_NumericAndDateTimeCell(t *testing.T) {
+	file := xlsx.NewFile()
+	sheet, err := file.AddSheet("Sheet1")
+	assert.Nil(t, err)
+
+	headerRow := sheet.AddRow()
+	headerRow.AddCell().SetString("Time")
+	headerRow.AddCell().SetString("Date")
+	headerRow.AddCell().SetString("Amount")
+
+	dataRow := sheet.AddRow()
+	dataRow.AddCell().SetDateTime(time.Date(2024, 9, 1, 12, 34, 56, 0, time.UTC))
+	dataRow.AddCell().SetDate(time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC))
+	dataRow.AddCell().SetFloatWithFormat(-123.45, "0.00")
+
+	var buffer bytes.Buffer
+	err = file.Write(&buffer)
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewExcelXlsxFileImportedDataTable(buffer.Bytes())
+	assert.Nil(t, err)
+
+	iterator := datatable.DataRowIterator()
+
+	row1 := iterator.Next()
+	assert.Equal(t, "2024-09-01 12:34:56", row1.GetData(0))
+	assert.Equal(t, "2024-09-02", row1.GetData(1))
+	assert.Equal(t, "-123.45", row1.GetData(2))
+}
+
+func TestCreateNewExcelXlsxFileImportedDataTableFromSheet(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/multiple_sheets_excel_file.xlsx")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewExcelXlsxFileImportedDataTableFromSheet(testdata, "Sheet5")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, datatable.DataRowCount())
+
+	row1 := datatable.DataRowIterator().Next()
+	assert.Equal(t, "5-A2", row1.GetData(0))
+
+	datatable, err = CreateNewExcelXlsxFileImportedDataTableFromSheet(testdata, "NotExistedSheet")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, datatable.DataRowCount())
+
+	row1 = datatable.DataRowIterator().Next()
+	assert.Equal(t, "1-A2", row1.GetData(0))
+}
+
+func TestCreateNewExcelXlsxFileImportedDataTable_MultipleSheetsWithDifferentHeaders(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/multiple_sheets_with_different_header_row_excel_file.xlsx")
+	assert.Nil(t, err)
+
+	_, err = CreateNewExcelXlsxFileImportedDataTable(testdata)
+	assert.EqualError(t, err, errs.ErrFieldsInMultiTableAreDifferent.Message)
+}
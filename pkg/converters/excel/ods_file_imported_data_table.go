@@ -0,0 +1,312 @@
+package excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+const odsMimeType = "application/vnd.oasis.opendocument.spreadsheet"
+const odsTableNamespace = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+const odsOfficeNamespace = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+const odsTextNamespace = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+
+const odsMaxRowCount = 1048576
+const odsMaxColumnCount = 16384
+const odsMaxCellCount = 16777216
+const odsMaxCellTextLength = 32767
+
+// odsFileContentReader defines the structure of opendocument spreadsheet content.xml reader
+type odsFileContentReader struct {
+	decoder *xml.Decoder
+}
+
+// CreateNewOdsFileImportedDataTable returns opendocument spreadsheet (ods) data table by file binary data
+func CreateNewOdsFileImportedDataTable(data []byte) (datatable.ImportedDataTable, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	var contentFile *zip.File
+
+	for i := 0; i < len(zipReader.File); i++ {
+		if zipReader.File[i].Name == "content.xml" {
+			contentFile = zipReader.File[i]
+			break
+		}
+	}
+
+	if contentFile == nil {
+		return nil, errs.ErrInvalidExcelFile
+	}
+
+	content, err := contentFile.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer content.Close()
+
+	reader := &odsFileContentReader{
+		decoder: xml.NewDecoder(content),
+	}
+
+	allSheets, err := reader.readAllTables()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return createNewSpreadsheetFileImportedDataTable(allSheets)
+}
+
+func (r *odsFileContentReader) readAllTables() ([][][]string, error) {
+	allSheets := make([][][]string, 0)
+
+	for {
+		token, err := r.decoder.Token()
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if element, ok := token.(xml.StartElement); ok && element.Name.Space == odsTableNamespace && element.Name.Local == "table" {
+			allRows, err := r.readTable()
+
+			if err != nil {
+				return nil, err
+			}
+
+			allSheets = append(allSheets, allRows)
+		}
+	}
+
+	return allSheets, nil
+}
+
+func (r *odsFileContentReader) readTable() ([][]string, error) {
+	allRows := make([][]string, 0)
+	totalCellCount := 0
+
+	for {
+		token, err := r.decoder.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if element.Name.Space == odsTableNamespace && element.Name.Local == "table-row" {
+				rowItems, err := r.readTableRow()
+
+				if err != nil {
+					return nil, err
+				}
+
+				if isBlankSpreadsheetRow(rowItems) {
+					continue
+				}
+
+				// the repeated rows which are not blank are rare, and each of them is the same
+				repeatedCount := getOdsRepeatedCount(element, "number-rows-repeated")
+
+				// the total count of rows and cells are limited, so that the repeated rows cannot exhaust memory
+				if repeatedCount > odsMaxRowCount-len(allRows) || len(rowItems)*repeatedCount > odsMaxCellCount-totalCellCount {
+					return nil, errs.ErrSheetTooLarge
+				}
+
+				totalCellCount += len(rowItems) * repeatedCount
+
+				for i := 0; i < repeatedCount; i++ {
+					allRows = append(allRows, rowItems)
+				}
+			}
+		case xml.EndElement:
+			if element.Name.Space == odsTableNamespace && element.Name.Local == "table" {
+				return allRows, nil
+			}
+		}
+	}
+}
+
+func (r *odsFileContentReader) readTableRow() ([]string, error) {
+	rowItems := make([]string, 0)
+	pendingBlankCellCount := 0
+
+	for {
+		token, err := r.decoder.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if element.Name.Space == odsTableNamespace && (element.Name.Local == "table-cell" || element.Name.Local == "covered-table-cell") {
+				value, err := r.readTableCell(element)
+
+				if err != nil {
+					return nil, err
+				}
+
+				repeatedCount := getOdsRepeatedCount(element, "number-columns-repeated")
+
+				// blank cells are only appended when there are non-blank cells after them, so that the trailing repeated blank cells are ignored
+				if value == "" {
+					pendingBlankCellCount = min(pendingBlankCellCount+repeatedCount, odsMaxColumnCount+1)
+					continue
+				}
+
+				if pendingBlankCellCount > odsMaxColumnCount-len(rowItems) || repeatedCount > odsMaxColumnCount-len(rowItems)-pendingBlankCellCount {
+					return nil, errs.ErrSheetTooLarge
+				}
+
+				for i := 0; i < pendingBlankCellCount; i++ {
+					rowItems = append(rowItems, "")
+				}
+
+				pendingBlankCellCount = 0
+
+				for i := 0; i < repeatedCount; i++ {
+					rowItems = append(rowItems, value)
+				}
+			}
+		case xml.EndElement:
+			if element.Name.Space == odsTableNamespace && element.Name.Local == "table-row" {
+				return rowItems, nil
+			}
+		}
+	}
+}
+
+func (r *odsFileContentReader) readTableCell(cellElement xml.StartElement) (string, error) {
+	valueType := getOdsAttributeValue(cellElement, odsOfficeNamespace, "value-type")
+	value := ""
+
+	switch valueType {
+	case "float", "percentage", "currency":
+		value = getOdsAttributeValue(cellElement, odsOfficeNamespace, "value")
+	case "date":
+		value = strings.Replace(getOdsAttributeValue(cellElement, odsOfficeNamespace, "date-value"), "T", " ", 1)
+	}
+
+	var textBuilder strings.Builder
+	paragraphCount := 0
+	depth := 0
+
+	for {
+		token, err := r.decoder.Token()
+
+		if err != nil {
+			return "", err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			depth++
+
+			if element.Name.Space == odsTextNamespace && element.Name.Local == "p" {
+				if paragraphCount > 0 {
+					textBuilder.WriteString("\n")
+				}
+
+				paragraphCount++
+			} else if element.Name.Space == odsTextNamespace && element.Name.Local == "s" {
+				spaceCount := getOdsRepeatedCount(element, "c")
+
+				if spaceCount > odsMaxCellTextLength-textBuilder.Len() {
+					return "", errs.ErrSheetTooLarge
+				}
+
+				textBuilder.WriteString(strings.Repeat(" ", spaceCount))
+			} else if element.Name.Space == odsTextNamespace && element.Name.Local == "tab" {
+				textBuilder.WriteString("\t")
+			} else if element.Name.Space == odsTextNamespace && element.Name.Local == "line-break" {
+				textBuilder.WriteString("\n")
+			}
+		case xml.CharData:
+			if depth > 0 {
+				textBuilder.Write(element)
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				if value != "" {
+					return value, nil
+				}
+
+				return textBuilder.String(), nil
+			}
+
+			depth--
+		}
+	}
+}
+
+func isOdsFile(data []byte) bool {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+
+	if err != nil {
+		return false
+	}
+
+	for i := 0; i < len(zipReader.File); i++ {
+		file := zipReader.File[i]
+
+		if file.Name != "mimetype" {
+			continue
+		}
+
+		mimeTypeFile, err := file.Open()
+
+		if err != nil {
+			return false
+		}
+
+		mimeType, err := io.ReadAll(io.LimitReader(mimeTypeFile, int64(len(odsMimeType))+1))
+		mimeTypeFile.Close()
+
+		return err == nil && string(mimeType) == odsMimeType
+	}
+
+	return false
+}
+
+func getOdsAttributeValue(element xml.StartElement, namespace string, name string) string {
+	for i := 0; i < len(element.Attr); i++ {
+		attr := element.Attr[i]
+
+		if attr.Name.Space == namespace && attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+func getOdsRepeatedCount(element xml.StartElement, name string) int {
+	namespace := odsTableNamespace
+
+	if name == "c" {
+		namespace = odsTextNamespace
+	}
+
+	repeatedCount, err := strconv.Atoi(getOdsAttributeValue(element, namespace, name))
+
+	if err != nil || repeatedCount < 1 {
+		return 1
+	}
+
+	return repeatedCount
+}
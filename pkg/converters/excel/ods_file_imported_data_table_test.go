@@ -0,0 +1,180 @@
+package excel
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestOdsFileImportedDataTableDataRowCount(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/simple_ods_file.ods")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewOdsFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, datatable.DataRowCount())
+}
+
+func TestOdsFileImportedDataTableDataRowCount_MultipleSheets(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/multiple_sheets_ods_file.ods")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewOdsFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, datatable.DataRowCount())
+}
+
+func TestOdsFileImportedDataTableHeaderColumnNames(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/simple_ods_file.ods")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewOdsFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"A1", "B1", "C1"}, datatable.HeaderColumnNames())
+}
+
+func TestOdsFileDataRowIterator(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/simple_ods_file.ods")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewOdsFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+
+	iterator := datatable.DataRowIterator()
+	assert.True(t, iterator.HasNext())
+
+	// data row 1
+	assert.NotNil(t, iterator.Next())
+	assert.True(t, iterator.HasNext())
+
+	// data row 2
+	assert.NotNil(t, iterator.Next())
+	assert.False(t, iterator.HasNext())
+
+	// not existed data row 3
+	assert.Nil(t, iterator.Next())
+	assert.False(t, iterator.HasNext())
+}
+
+func TestOdsFileDataRowColumnCount(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/simple_ods_file.ods")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewOdsFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+
+	iterator := datatable.DataRowIterator()
+
+	// the trailing repeated blank cells are ignored
+	row1 := iterator.Next()
+	assert.EqualValues(t, 3, row1.ColumnCount())
+}
+
+func TestOdsFileDataRowGetData_MultipleSheets(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/multiple_sheets_ods_file.ods")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewOdsFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+
+	iterator := datatable.DataRowIterator()
+
+	sheet1Row1 := iterator.Next()
+	assert.Equal(t, "1-A2", sheet1Row1.GetData(0))
+	assert.Equal(t, "1-B2", sheet1Row1.GetData(1))
+	assert.Equal(t, "1-C2", sheet1Row1.GetData(2))
+
+	sheet1Row2 := iterator.Next()
+	assert.Equal(t, "1-A3", sheet1Row2.GetData(0))
+	assert.Equal(t, "1-B3", sheet1Row2.GetData(1))
+	assert.Equal(t, "1-C3", sheet1Row2.GetData(2))
+
+	// skip empty sheet2
+
+	sheet3Row1 := iterator.Next()
+	assert.Equal(t, "3-A2", sheet3Row1.GetData(0))
+	assert.Equal(t, "3-B2", sheet3Row1.GetData(1))
+	assert.Equal(t, "", sheet3Row1.GetData(2))
+
+	// skip no data row sheet4
+
+	sheet5Row1 := iterator.Next()
+	assert.Equal(t, "5-A2", sheet5Row1.GetData(0))
+	assert.Equal(t, "5-B2", sheet5Row1.GetData(1))
+	assert.Equal(t, "5-C2", sheet5Row1.GetData(2))
+
+	sheet5Row2 := iterator.Next()
+	assert.Equal(t, "5-A3", sheet5Row2.GetData(0))
+	assert.Equal(t, "5-B3", sheet5Row2.GetData(1))
+	assert.Equal(t, "5-C3", sheet5Row2.GetData(2))
+
+	assert.False(t, iterator.HasNext())
+}
+
+func TestOdsFileDataRowGetData_NumericAndDateTimeCell(t *testing.T) {
+	testdata, err := os.ReadFile("../../../testdata/values_ods_file.ods")
+	assert.Nil(t, err)
+
+	datatable, err := CreateNewOdsFileImportedDataTable(testdata)
+	assert.Nil(t, err)
+
+	iterator := datatable.DataRowIterator()
+
+	row1 := iterator.Next()
+	assert.Equal(t, "2024-09-01 12:34:56", row1.GetData(0))
+	assert.Equal(t, "-123.45", row1.GetData(1))
+	assert.Equal(t, "", row1.GetData(2))
+
+	row2 := iterator.Next()
+	assert.Equal(t, "2024-09-02", row2.GetData(0))
+	assert.Equal(t, "1000", row2.GetData(1))
+	assert.Equal(t, "foo", row2.GetData(2))
+}
+
+func TestCreateNewOdsFileImportedDataTable_TooManyRepeatedRows(t *testing.T) {
+	testdata := createOdsTestFile(t, "<table:table-row table:number-rows-repeated=\"1000000\"><table:table-cell office:value-type=\"string\"><text:p>A</text:p></table:table-cell></table:table-row>"+
+		"<table:table-row table:number-rows-repeated=\"1000000\"><table:table-cell office:value-type=\"string\"><text:p>B</text:p></table:table-cell></table:table-row>")
+
+	_, err := CreateNewOdsFileImportedDataTable(testdata)
+	assert.EqualError(t, err, errs.ErrSheetTooLarge.Message)
+}
+
+func TestCreateNewOdsFileImportedDataTable_TooManyRepeatedCells(t *testing.T) {
+	testdata := createOdsTestFile(t, "<table:table-row><table:table-cell table:number-columns-repeated=\"10000\" office:value-type=\"string\"><text:p>A</text:p></table:table-cell>"+
+		"<table:table-cell table:number-columns-repeated=\"10000\" office:value-type=\"string\"><text:p>B</text:p></table:table-cell></table:table-row>")
+
+	_, err := CreateNewOdsFileImportedDataTable(testdata)
+	assert.EqualError(t, err, errs.ErrSheetTooLarge.Message)
+
+	testdata = createOdsTestFile(t, "<table:table-row table:number-rows-repeated=\"1100\"><table:table-cell table:number-columns-repeated=\"16384\" office:value-type=\"string\"><text:p>A</text:p></table:table-cell></table:table-row>")
+
+	_, err = CreateNewOdsFileImportedDataTable(testdata)
+	assert.EqualError(t, err, errs.ErrSheetTooLarge.Message)
+}
+
+func createOdsTestFile(t *testing.T, tableContent string) []byte {
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+
+	mimeTypeFile, err := zipWriter.Create("mimetype")
+	assert.Nil(t, err)
+	_, err = mimeTypeFile.Write([]byte(odsMimeType))
+	assert.Nil(t, err)
+
+	contentFile, err := zipWriter.Create("content.xml")
+	assert.Nil(t, err)
+	_, err = contentFile.Write([]byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>" +
+		"<office:document-content xmlns:office=\"" + odsOfficeNamespace + "\" xmlns:table=\"" + odsTableNamespace + "\" xmlns:text=\"" + odsTextNamespace + "\">" +
+		"<office:body><office:spreadsheet><table:table table:name=\"Sheet1\">" + tableContent + "</table:table></office:spreadsheet></office:body>" +
+		"</office:document-content>"))
+	assert.Nil(t, err)
+
+	assert.Nil(t, zipWriter.Close())
+
+	return buffer.Bytes()
+}
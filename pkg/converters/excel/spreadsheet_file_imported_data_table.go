@@ -0,0 +1,191 @@
+package excel
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+var xlsFileHeader = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+var zipFileHeader = []byte{0x50, 0x4B, 0x03, 0x04}
+
+// SpreadsheetFileImportedDataTable defines the structure of spreadsheet file data table which has been loaded into memory
+type SpreadsheetFileImportedDataTable struct {
+	allSheets             [][][]string
+	headerLineColumnNames []string
+}
+
+// SpreadsheetFileDataRow defines the structure of spreadsheet file data table row
+type SpreadsheetFileDataRow struct {
+	allItems []string
+}
+
+// SpreadsheetFileDataRowIterator defines the structure of spreadsheet file data table row iterator
+type SpreadsheetFileDataRowIterator struct {
+	dataTable              *SpreadsheetFileImportedDataTable
+	currentSheetIndex      int
+	currentRowIndexInSheet int
+}
+
+// DataRowCount returns the total count of data row
+func (t *SpreadsheetFileImportedDataTable) DataRowCount() int {
+	totalDataRowCount := 0
+
+	for i := 0; i < len(t.allSheets); i++ {
+		if len(t.allSheets[i]) < 1 {
+			continue
+		}
+
+		totalDataRowCount += len(t.allSheets[i]) - 1
+	}
+
+	return totalDataRowCount
+}
+
+// HeaderColumnNames returns the header column name list
+func (t *SpreadsheetFileImportedDataTable) HeaderColumnNames() []string {
+	return t.headerLineColumnNames
+}
+
+// DataRowIterator returns the iterator of data row
+func (t *SpreadsheetFileImportedDataTable) DataRowIterator() datatable.ImportedDataRowIterator {
+	return &SpreadsheetFileDataRowIterator{
+		dataTable:              t,
+		currentSheetIndex:      0,
+		currentRowIndexInSheet: 0,
+	}
+}
+
+// ColumnCount returns the total count of column in this data row
+func (r *SpreadsheetFileDataRow) ColumnCount() int {
+	return len(r.allItems)
+}
+
+// GetData returns the data in the specified column index
+func (r *SpreadsheetFileDataRow) GetData(columnIndex int) string {
+	if columnIndex < 0 || columnIndex >= len(r.allItems) {
+		return ""
+	}
+
+	return r.allItems[columnIndex]
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *SpreadsheetFileDataRowIterator) HasNext() bool {
+	allSheets := t.dataTable.allSheets
+
+	if t.currentSheetIndex >= len(allSheets) {
+		return false
+	}
+
+	if t.currentRowIndexInSheet+1 < len(allSheets[t.currentSheetIndex]) {
+		return true
+	}
+
+	for i := t.currentSheetIndex + 1; i < len(allSheets); i++ {
+		if len(allSheets[i]) < 2 {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// CurrentRowId returns current index
+func (t *SpreadsheetFileDataRowIterator) CurrentRowId() string {
+	return fmt.Sprintf("table#%d-row#%d", t.currentSheetIndex, t.currentRowIndexInSheet)
+}
+
+// Next returns the next imported data row
+func (t *SpreadsheetFileDataRowIterator) Next() datatable.ImportedDataRow {
+	allSheets := t.dataTable.allSheets
+
+	for t.currentSheetIndex < len(allSheets) {
+		if t.currentRowIndexInSheet+1 < len(allSheets[t.currentSheetIndex]) {
+			t.currentRowIndexInSheet++
+
+			return &SpreadsheetFileDataRow{
+				allItems: allSheets[t.currentSheetIndex][t.currentRowIndexInSheet],
+			}
+		}
+
+		if t.currentSheetIndex+1 >= len(allSheets) {
+			break
+		}
+
+		t.currentSheetIndex++
+		t.currentRowIndexInSheet = 0
+	}
+
+	return nil
+}
+
+// CreateNewSpreadsheetFileImportedDataTable returns spreadsheet data table by file binary data, the file can be excel xls, excel xlsx or opendocument spreadsheet (ods) file
+func CreateNewSpreadsheetFileImportedDataTable(data []byte) (datatable.ImportedDataTable, error) {
+	if bytes.HasPrefix(data, xlsFileHeader) {
+		return CreateNewExcelFileImportedDataTable(data)
+	}
+
+	if !bytes.HasPrefix(data, zipFileHeader) {
+		return nil, errs.ErrInvalidExcelFile
+	}
+
+	if isOdsFile(data) {
+		return CreateNewOdsFileImportedDataTable(data)
+	}
+
+	return CreateNewExcelXlsxFileImportedDataTable(data)
+}
+
+func createNewSpreadsheetFileImportedDataTable(allSheets [][][]string) (*SpreadsheetFileImportedDataTable, error) {
+	var headerRowItems []string
+
+	for i := 0; i < len(allSheets); i++ {
+		sheet := allSheets[i]
+
+		if len(sheet) < 1 {
+			continue
+		}
+
+		row := sheet[0]
+
+		if headerRowItems == nil {
+			for j := 0; j < len(row); j++ {
+				headerItem := row[j]
+
+				if headerItem == "" {
+					break
+				}
+
+				headerRowItems = append(headerRowItems, headerItem)
+			}
+		} else {
+			for j := 0; j < min(len(row), len(headerRowItems)); j++ {
+				headerItem := row[j]
+
+				if headerItem != headerRowItems[j] {
+					return nil, errs.ErrFieldsInMultiTableAreDifferent
+				}
+			}
+		}
+	}
+
+	return &SpreadsheetFileImportedDataTable{
+		allSheets:             allSheets,
+		headerLineColumnNames: headerRowItems,
+	}, nil
+}
+
+func isBlankSpreadsheetRow(row []string) bool {
+	for i := 0; i < len(row); i++ {
+		if row[i] != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,34 @@
+package excel
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestCreateNewSpreadsheetFileImportedDataTable(t *testing.T) {
+	allFileNames := []string{"simple_excel_file.xls", "simple_excel_file.xlsx", "simple_ods_file.ods"}
+
+	for i := 0; i < len(allFileNames); i++ {
+		testdata, err := os.ReadFile("../../../testdata/" + allFileNames[i])
+		assert.Nil(t, err)
+
+		datatable, err := CreateNewSpreadsheetFileImportedDataTable(testdata)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, datatable.DataRowCount())
+		assert.EqualValues(t, []string{"A1", "B1", "C1"}, datatable.HeaderColumnNames())
+
+		row1 := datatable.DataRowIterator().Next()
+		assert.Equal(t, "A2", row1.GetData(0))
+		assert.Equal(t, "B2", row1.GetData(1))
+		assert.Equal(t, "C2", row1.GetData(2))
+	}
+}
+
+func TestCreateNewSpreadsheetFileImportedDataTable_InvalidFile(t *testing.T) {
+	_, err := CreateNewSpreadsheetFileImportedDataTable([]byte("A1,B1,C1\nA2,B2,C2\n"))
+	assert.EqualError(t, err, errs.ErrInvalidExcelFile.Message)
+}
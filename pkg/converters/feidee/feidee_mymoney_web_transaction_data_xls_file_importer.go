@@ -30,7 +30,7 @@ var (
 
 // ParseImportedData returns the imported data by parsing the feidee mymoney (web) transaction xls data
 func (c *feideeMymoneyWebTransactionDataXlsFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
-	dataTable, err := excel.CreateNewExcelFileImportedDataTable(data)
+	dataTable, err := excel.CreateNewSpreadsheetFileImportedDataTable(data)
 
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, err
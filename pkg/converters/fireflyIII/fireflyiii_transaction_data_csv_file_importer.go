@@ -1,8 +1,9 @@
 package fireflyIII
 
 import (
-	"bytes"
+	"golang.org/x/text/encoding"
 
+	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/csv"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
@@ -31,7 +32,9 @@ var fireflyIIITransactionTypeNameMapping = map[models.TransactionType]string{
 }
 
 // fireflyIIITransactionDataCsvFileImporter defines the structure of firefly III csv importer for transaction data
-type fireflyIIITransactionDataCsvFileImporter struct{}
+type fireflyIIITransactionDataCsvFileImporter struct {
+	textEncoding encoding.Encoding
+}
 
 // Initialize a firefly III transaction data csv file importer singleton instance
 var (
@@ -40,8 +43,7 @@ var (
 
 // ParseImportedData returns the imported data by parsing the firefly III transaction csv data
 func (c *fireflyIIITransactionDataCsvFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
-	reader := bytes.NewReader(data)
-	dataTable, err := csv.CreateNewCsvImportedDataTable(ctx, reader)
+	dataTable, err := csv.CreateNewCsvImportedDataTableFromData(ctx, data, c.textEncoding)
 
 	if err != nil {
 		return nil, nil, nil, nil, nil, nil, err
@@ -53,3 +55,11 @@ func (c *fireflyIIITransactionDataCsvFileImporter) ParseImportedData(ctx core.Co
 
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
+
+// WithTextEncoding returns a copy of the importer which decodes the imported data by the specified text encoding
+func (c *fireflyIIITransactionDataCsvFileImporter) WithTextEncoding(enc encoding.Encoding) base.TransactionDataImporter {
+	importer := *c
+	importer.textEncoding = enc
+
+	return &importer
+}
@@ -0,0 +1,50 @@
+package journal
+
+// journalType represents the type of plain-text accounting journal
+type journalType byte
+
+// Plain-text accounting journal types
+const (
+	journalTypeBeancount journalType = 1
+	journalTypeHledger   journalType = 2
+	journalTypeLedger    journalType = 3
+)
+
+const beancountIndent = "  "
+const hledgerIndent = "    "
+
+const beancountTagsMetadataKey = "tags"
+
+// journalData defines the structure of plain-text accounting journal data
+type journalData struct {
+	accounts     map[string]*journalAccountData
+	transactions []*journalTransactionData
+}
+
+// journalAccountData defines the structure of plain-text accounting journal account data
+type journalAccountData struct {
+	name       string
+	currencies []string
+}
+
+// journalTransactionData defines the structure of plain-text accounting journal transaction data
+type journalTransactionData struct {
+	lineNumber  int
+	date        string
+	payee       string
+	description string
+	comments    []string
+	tags        []string
+	postings    []*journalTransactionPostingData
+}
+
+// journalTransactionPostingData defines the structure of plain-text accounting journal transaction posting data
+type journalTransactionPostingData struct {
+	lineNumber     int
+	accountName    string
+	amount         string
+	commodity      string
+	price          string
+	priceCommodity string
+	totalPrice     bool
+}
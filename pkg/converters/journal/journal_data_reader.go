@@ -0,0 +1,642 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode"
+
+	textunicode "golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+)
+
+const beancountOpenDirective = "open"
+const beancountTransactionDirective = "txn"
+const beancountPushTagDirective = "pushtag"
+const beancountPopTagDirective = "poptag"
+const ledgerAccountDirective = "account"
+const ledgerBlockCommentDirective = "comment"
+const ledgerBlockTestDirective = "test"
+const ledgerEndBlockCommentDirective = "end comment"
+const ledgerEndBlockTestDirective = "end test"
+
+const journalCommentRune = ';'
+const beancountTagPrefix = "#"
+const beancountLinkPrefix = "^"
+
+var beancountMetadataPattern = regexp.MustCompile("^([a-z][A-Za-z0-9_-]*):(.*)$")
+var ledgerBracketTagsPattern = regexp.MustCompile("(^|\\s):((?:[^\\s:]+:)+)")
+var ledgerWordTagPattern = regexp.MustCompile("(^|[\\s,])([^\\s:,]+):")
+
+// journalDataReader defines the structure of plain-text accounting journal data reader
+type journalDataReader struct {
+	journalType journalType
+	allLines    []string
+}
+
+// journalHeaderToken represents a token in the header line of beancount entry
+type journalHeaderToken struct {
+	value  string
+	quoted bool
+}
+
+// read returns the imported plain-text accounting journal data
+// Reference: https://beancount.github.io/docs/beancount_language_syntax.html
+// Reference: https://ledger-cli.org/doc/ledger3.html#Journal-Format
+// Reference: https://hledger.org/hledger.html#journal
+func (r *journalDataReader) read(ctx core.Context) (*journalData, error) {
+	if len(r.allLines) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	data := &journalData{
+		accounts:     make(map[string]*journalAccountData),
+		transactions: make([]*journalTransactionData, 0),
+	}
+
+	var currentTransaction *journalTransactionData
+	var pushedTags []string
+	inBlockComment := false
+
+	for i := 0; i < len(r.allLines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimRight(r.allLines[i], " \t\r")
+
+		if inBlockComment {
+			if line == ledgerEndBlockCommentDirective || line == ledgerEndBlockTestDirective {
+				inBlockComment = false
+			}
+
+			continue
+		}
+
+		if len(line) < 1 {
+			currentTransaction = nil
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if currentTransaction == nil { // indented lines of ignored directives
+				continue
+			}
+
+			err := r.readIndentedLine(ctx, currentTransaction, strings.TrimSpace(line), lineNumber)
+
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		currentTransaction = nil
+
+		if strings.ContainsRune(";#%|*", rune(line[0])) { // comments or org-mode headings
+			continue
+		}
+
+		if r.journalType != journalTypeBeancount && (line == ledgerBlockCommentDirective || line == ledgerBlockTestDirective) {
+			inBlockComment = true
+			continue
+		}
+
+		if '0' <= line[0] && line[0] <= '9' {
+			var transaction *journalTransactionData
+			var err error
+
+			if r.journalType == journalTypeBeancount {
+				transaction, err = r.readBeancountDatedEntry(ctx, data, line, lineNumber, pushedTags)
+			} else {
+				transaction, err = r.readLedgerTransactionHeader(line, lineNumber), nil
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			if transaction != nil {
+				data.transactions = append(data.transactions, transaction)
+				currentTransaction = transaction
+			}
+
+			continue
+		}
+
+		directive, value := r.splitDirective(line)
+
+		if r.journalType == journalTypeBeancount && directive == beancountPushTagDirective {
+			pushedTags = append(pushedTags, strings.TrimPrefix(value, beancountTagPrefix))
+		} else if r.journalType == journalTypeBeancount && directive == beancountPopTagDirective {
+			tag := strings.TrimPrefix(value, beancountTagPrefix)
+
+			for j := len(pushedTags) - 1; j >= 0; j-- {
+				if pushedTags[j] == tag {
+					pushedTags = append(pushedTags[:j], pushedTags[j+1:]...)
+					break
+				}
+			}
+		} else if r.journalType != journalTypeBeancount && directive == ledgerAccountDirective {
+			accountName, _ := r.splitComment(value)
+			accountName = strings.TrimSpace(accountName)
+
+			if accountName != "" {
+				r.addAccount(data, accountName, nil)
+			}
+		}
+	}
+
+	if len(data.transactions) < 1 {
+		log.Errorf(ctx, "[journal_data_reader.read] cannot find any transaction in journal file")
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	return data, nil
+}
+
+func (r *journalDataReader) readBeancountDatedEntry(ctx core.Context, data *journalData, line string, lineNumber int, pushedTags []string) (*journalTransactionData, error) {
+	content, comment := r.splitComment(line)
+	tokens, err := r.tokenizeBeancountHeader(content)
+
+	if err != nil {
+		log.Errorf(ctx, "[journal_data_reader.readBeancountDatedEntry] cannot parse line#%d \"%s\", because %s", lineNumber, line, err.Error())
+		return nil, errs.ErrInvalidJournalFile
+	}
+
+	if len(tokens) < 2 || tokens[1].quoted {
+		log.Errorf(ctx, "[journal_data_reader.readBeancountDatedEntry] cannot parse line#%d \"%s\", because missing directive", lineNumber, line)
+		return nil, errs.ErrInvalidJournalFile
+	}
+
+	directive := tokens[1].value
+
+	if directive == beancountOpenDirective {
+		if len(tokens) < 3 {
+			log.Errorf(ctx, "[journal_data_reader.readBeancountDatedEntry] cannot parse line#%d \"%s\", because missing account name", lineNumber, line)
+			return nil, errs.ErrInvalidJournalFile
+		}
+
+		var currencies []string
+
+		for i := 3; i < len(tokens); i++ {
+			if tokens[i].quoted { // booking method
+				continue
+			}
+
+			for _, currency := range strings.Split(tokens[i].value, ",") {
+				if currency != "" {
+					currencies = append(currencies, currency)
+				}
+			}
+		}
+
+		r.addAccount(data, tokens[2].value, currencies)
+		return nil, nil
+	}
+
+	if directive != beancountTransactionDirective && (len(directive) != 1 || unicode.IsLower(rune(directive[0]))) {
+		return nil, nil // other directives (e.g. close, balance, pad, price, note, etc.) are not imported
+	}
+
+	transaction := &journalTransactionData{
+		lineNumber: lineNumber,
+		date:       tokens[0].value,
+	}
+
+	transaction.tags = append(transaction.tags, pushedTags...)
+
+	var texts []string
+
+	for i := 2; i < len(tokens); i++ {
+		token := tokens[i]
+
+		if token.quoted {
+			texts = append(texts, token.value)
+		} else if strings.HasPrefix(token.value, beancountTagPrefix) {
+			transaction.tags = append(transaction.tags, token.value[1:])
+		} else if !strings.HasPrefix(token.value, beancountLinkPrefix) {
+			log.Errorf(ctx, "[journal_data_reader.readBeancountDatedEntry] cannot parse line#%d \"%s\", because token \"%s\" is invalid", lineNumber, line, token.value)
+			return nil, errs.ErrInvalidJournalFile
+		}
+	}
+
+	if len(texts) == 1 {
+		transaction.description = texts[0]
+	} else if len(texts) == 2 {
+		transaction.payee = texts[0]
+		transaction.description = texts[1]
+	} else if len(texts) > 2 {
+		log.Errorf(ctx, "[journal_data_reader.readBeancountDatedEntry] cannot parse line#%d \"%s\", because there are too many strings", lineNumber, line)
+		return nil, errs.ErrInvalidJournalFile
+	}
+
+	if comment != "" {
+		transaction.comments = append(transaction.comments, comment)
+	}
+
+	return transaction, nil
+}
+
+func (r *journalDataReader) readLedgerTransactionHeader(line string, lineNumber int) *journalTransactionData {
+	content, comment := r.splitComment(line)
+	date, description := r.splitDirective(content)
+
+	if index := strings.Index(date, "="); index >= 0 { // primary date = secondary date
+		date = date[:index]
+	}
+
+	if len(description) > 0 && (description[0] == '*' || description[0] == '!') { // cleared or pending status
+		description = strings.TrimSpace(description[1:])
+	}
+
+	if len(description) > 0 && description[0] == '(' { // transaction code
+		if index := strings.Index(description, ")"); index > 0 {
+			description = strings.TrimSpace(description[index+1:])
+		}
+	}
+
+	transaction := &journalTransactionData{
+		lineNumber:  lineNumber,
+		date:        date,
+		description: description,
+	}
+
+	r.addLedgerComment(transaction, comment)
+
+	return transaction
+}
+
+func (r *journalDataReader) readIndentedLine(ctx core.Context, transaction *journalTransactionData, line string, lineNumber int) error {
+	if line[0] == journalCommentRune {
+		if r.journalType == journalTypeBeancount {
+			transaction.comments = append(transaction.comments, strings.TrimSpace(line[1:]))
+		} else {
+			r.addLedgerComment(transaction, line[1:])
+		}
+
+		return nil
+	}
+
+	if r.journalType == journalTypeBeancount {
+		if matches := beancountMetadataPattern.FindStringSubmatch(line); len(matches) == 3 {
+			if matches[1] == beancountTagsMetadataKey {
+				value, _ := r.splitComment(matches[2])
+				tokens, err := r.tokenizeBeancountHeader(value)
+
+				if err != nil || len(tokens) != 1 {
+					log.Errorf(ctx, "[journal_data_reader.readIndentedLine] cannot parse tags metadata in line#%d \"%s\"", lineNumber, line)
+					return errs.ErrInvalidJournalFile
+				}
+
+				for _, tag := range strings.Split(tokens[0].value, ",") {
+					if tag = strings.TrimSpace(tag); tag != "" {
+						transaction.tags = append(transaction.tags, tag)
+					}
+				}
+			}
+
+			return nil
+		}
+	}
+
+	posting, err := r.readPosting(ctx, transaction, line, lineNumber)
+
+	if err != nil {
+		return err
+	}
+
+	transaction.postings = append(transaction.postings, posting)
+
+	return nil
+}
+
+func (r *journalDataReader) readPosting(ctx core.Context, transaction *journalTransactionData, line string, lineNumber int) (*journalTransactionPostingData, error) {
+	content, comment := r.splitComment(line)
+
+	if len(content) > 1 && (content[0] == '*' || content[0] == '!') && (content[1] == ' ' || content[1] == '\t') { // posting status
+		content = strings.TrimSpace(content[1:])
+	}
+
+	var accountName, amountText string
+
+	if r.journalType == journalTypeBeancount {
+		accountName, amountText = r.splitDirective(content)
+		amountText = r.removeBeancountCost(amountText)
+
+		if comment != "" {
+			transaction.comments = append(transaction.comments, comment)
+		}
+	} else {
+		accountName, amountText = r.splitLedgerAccountName(content)
+
+		if index := strings.Index(amountText, "="); index >= 0 { // balance assertion
+			amountText = strings.TrimSpace(amountText[:index])
+		}
+
+		r.addLedgerComment(transaction, comment)
+	}
+
+	if len(accountName) > 2 && ((accountName[0] == '(' && accountName[len(accountName)-1] == ')') || (accountName[0] == '[' && accountName[len(accountName)-1] == ']')) { // virtual posting
+		accountName = accountName[1 : len(accountName)-1]
+	}
+
+	if accountName == "" {
+		log.Errorf(ctx, "[journal_data_reader.readPosting] cannot parse posting in line#%d \"%s\", because missing account name", lineNumber, line)
+		return nil, errs.ErrInvalidJournalFile
+	}
+
+	posting := &journalTransactionPostingData{
+		lineNumber:  lineNumber,
+		accountName: accountName,
+	}
+
+	priceText := ""
+
+	if index := strings.Index(amountText, "@@"); index >= 0 {
+		priceText = amountText[index+2:]
+		amountText = amountText[:index]
+		posting.totalPrice = true
+	} else if index := strings.Index(amountText, "@"); index >= 0 {
+		priceText = amountText[index+1:]
+		amountText = amountText[:index]
+	}
+
+	var ok bool
+	posting.amount, posting.commodity, ok = r.parseAmount(amountText)
+
+	if !ok {
+		log.Errorf(ctx, "[journal_data_reader.readPosting] cannot parse amount in line#%d \"%s\"", lineNumber, line)
+		return nil, errs.ErrAmountInvalid
+	}
+
+	if priceText != "" {
+		posting.price, posting.priceCommodity, ok = r.parseAmount(priceText)
+
+		if !ok || posting.price == "" || posting.amount == "" {
+			log.Errorf(ctx, "[journal_data_reader.readPosting] cannot parse price in line#%d \"%s\"", lineNumber, line)
+			return nil, errs.ErrAmountInvalid
+		}
+	}
+
+	return posting, nil
+}
+
+// parseAmount returns the number and the commodity of the amount text, e.g. "-12.34 USD", "USD -12.34", "-$12.34", "$-12.34" or "1,234.56"
+func (r *journalDataReader) parseAmount(text string) (string, string, bool) {
+	text = strings.TrimSpace(text)
+
+	if text == "" {
+		return "", "", true
+	}
+
+	negative := false
+
+	if text[0] == '-' || text[0] == '+' {
+		negative = text[0] == '-'
+		text = strings.TrimSpace(text[1:])
+	}
+
+	var number, commodity string
+
+	if text != "" && (('0' <= text[0] && text[0] <= '9') || text[0] == '.') {
+		index := strings.IndexFunc(text, func(c rune) bool {
+			return !('0' <= c && c <= '9') && c != '.' && c != ','
+		})
+
+		if index < 0 {
+			index = len(text)
+		}
+
+		number = text[:index]
+		commodity = strings.TrimSpace(text[index:])
+	} else if text != "" && text[0] == '"' {
+		index := strings.Index(text[1:], "\"")
+
+		if index < 0 {
+			return "", "", false
+		}
+
+		commodity = text[1 : index+1]
+		number = strings.TrimSpace(text[index+2:])
+	} else {
+		index := strings.IndexFunc(text, func(c rune) bool {
+			return ('0' <= c && c <= '9') || c == '.' || c == '-' || c == '+' || unicode.IsSpace(c)
+		})
+
+		if index < 0 {
+			return "", "", false
+		}
+
+		commodity = text[:index]
+		number = strings.TrimSpace(text[index:])
+	}
+
+	if len(commodity) > 1 && commodity[0] == '"' && commodity[len(commodity)-1] == '"' {
+		commodity = commodity[1 : len(commodity)-1]
+	} else if strings.IndexFunc(commodity, unicode.IsSpace) >= 0 {
+		return "", "", false
+	}
+
+	if number != "" && (number[0] == '-' || number[0] == '+') {
+		negative = negative != (number[0] == '-')
+		number = number[1:]
+	}
+
+	number = strings.ReplaceAll(number, ",", "") // trim thousands separator
+
+	if number == "" || strings.IndexFunc(number, func(c rune) bool { return !('0' <= c && c <= '9') && c != '.' }) >= 0 {
+		return "", "", false
+	}
+
+	if negative {
+		number = "-" + number
+	}
+
+	return number, commodity, true
+}
+
+func (r *journalDataReader) addAccount(data *journalData, accountName string, currencies []string) {
+	account, exists := data.accounts[accountName]
+
+	if !exists {
+		account = &journalAccountData{
+			name: accountName,
+		}
+
+		data.accounts[accountName] = account
+	}
+
+	account.currencies = append(account.currencies, currencies...)
+}
+
+// addLedgerComment appends the comment text and the tags in comment (e.g. ":tag1:tag2:" or "tag1:, tag2: value") to the transaction
+func (r *journalDataReader) addLedgerComment(transaction *journalTransactionData, comment string) {
+	comment = strings.TrimSpace(comment)
+
+	if comment == "" {
+		return
+	}
+
+	textEndIndex := len(comment)
+
+	for _, matches := range ledgerBracketTagsPattern.FindAllStringSubmatchIndex(comment, -1) {
+		textEndIndex = min(textEndIndex, matches[4]-1)
+
+		for _, tag := range strings.Split(comment[matches[4]:matches[5]], ":") {
+			if tag != "" {
+				transaction.tags = append(transaction.tags, tag)
+			}
+		}
+	}
+
+	remainComment := ledgerBracketTagsPattern.ReplaceAllString(comment, "$1")
+
+	if textEndIndex == len(comment) {
+		if matches := ledgerWordTagPattern.FindStringIndex(remainComment); matches != nil {
+			textEndIndex = matches[0]
+		}
+	}
+
+	for _, matches := range ledgerWordTagPattern.FindAllStringSubmatch(remainComment, -1) {
+		transaction.tags = append(transaction.tags, matches[2])
+	}
+
+	if text := strings.TrimRight(strings.TrimSpace(comment[:textEndIndex]), ","); text != "" {
+		transaction.comments = append(transaction.comments, text)
+	}
+}
+
+func (r *journalDataReader) tokenizeBeancountHeader(content string) ([]*journalHeaderToken, error) {
+	tokens := make([]*journalHeaderToken, 0)
+	runes := []rune(content)
+
+	for i := 0; i < len(runes); i++ {
+		if unicode.IsSpace(runes[i]) {
+			continue
+		}
+
+		if runes[i] == '"' {
+			var value strings.Builder
+			closed := false
+
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					value.WriteRune(runes[i])
+				} else if runes[i] == '"' {
+					closed = true
+					break
+				} else {
+					value.WriteRune(runes[i])
+				}
+			}
+
+			if !closed {
+				return nil, errs.ErrInvalidJournalFile
+			}
+
+			tokens = append(tokens, &journalHeaderToken{
+				value:  value.String(),
+				quoted: true,
+			})
+
+			continue
+		}
+
+		start := i
+
+		for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '"' {
+			i++
+		}
+
+		tokens = append(tokens, &journalHeaderToken{
+			value: string(runes[start:i]),
+		})
+
+		i--
+	}
+
+	return tokens, nil
+}
+
+// splitComment returns the content and the comment of the line, the semicolon in double quotes is not regarded as the start of comment
+func (r *journalDataReader) splitComment(line string) (string, string) {
+	inQuotes := false
+
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && inQuotes {
+			i++
+		} else if line[i] == '"' && r.journalType == journalTypeBeancount {
+			inQuotes = !inQuotes
+		} else if line[i] == journalCommentRune && !inQuotes {
+			return strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+	}
+
+	return strings.TrimSpace(line), ""
+}
+
+// splitDirective returns the first word and the remaining content of the line
+func (r *journalDataReader) splitDirective(line string) (string, string) {
+	index := strings.IndexFunc(line, unicode.IsSpace)
+
+	if index < 0 {
+		return line, ""
+	}
+
+	return line[:index], strings.TrimSpace(line[index:])
+}
+
+// splitLedgerAccountName returns the account name and the amount of the posting, which are separated by two or more spaces or a tab
+func (r *journalDataReader) splitLedgerAccountName(content string) (string, string) {
+	tabIndex := strings.Index(content, "\t")
+	spacesIndex := strings.Index(content, "  ")
+	index := tabIndex
+
+	if index < 0 || (spacesIndex >= 0 && spacesIndex < index) {
+		index = spacesIndex
+	}
+
+	if index < 0 {
+		return content, ""
+	}
+
+	return content[:index], strings.TrimSpace(content[index:])
+}
+
+// removeBeancountCost returns the amount text without cost specification (e.g. "{100.00 USD}" or "{{100.00 USD}}")
+func (r *journalDataReader) removeBeancountCost(amountText string) string {
+	startIndex := strings.Index(amountText, "{")
+
+	if startIndex < 0 {
+		return amountText
+	}
+
+	endIndex := strings.LastIndex(amountText, "}")
+
+	if endIndex < startIndex {
+		return amountText
+	}
+
+	return strings.TrimSpace(amountText[:startIndex]) + " " + strings.TrimSpace(amountText[endIndex+1:])
+}
+
+func createNewJournalDataReader(journalType journalType, data []byte) *journalDataReader {
+	fallback := textunicode.UTF8.NewDecoder()
+	reader := transform.NewReader(bytes.NewReader(data), textunicode.BOMOverride(fallback))
+	scanner := bufio.NewScanner(reader)
+	allLines := make([]string, 0)
+
+	for scanner.Scan() {
+		allLines = append(allLines, scanner.Text())
+	}
+
+	return &journalDataReader{
+		journalType: journalType,
+		allLines:    allLines,
+	}
+}
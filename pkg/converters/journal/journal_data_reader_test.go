@@ -0,0 +1,202 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestBeancountDataReaderParse(t *testing.T) {
+	reader := &journalDataReader{
+		journalType: journalTypeBeancount,
+		allLines: []string{
+			"option \"title\" \"Test\"",
+			"* Accounts",
+			"2024-09-01 open Assets:Cash USD,EUR",
+			"2024-09-01 open Expenses:Food",
+			"2024-09-01 balance Assets:Cash 0 USD",
+			"  note: \"ignored\"",
+			"",
+			"pushtag #trip",
+			"2024-09-02 * \"Shop\" \"Lunch; with \\\"friends\\\"\" #food ^link ; header comment",
+			"  tags: \"Tag A, Tag:B\"",
+			"  ; line comment",
+			"  Expenses:Food  12.34 USD",
+			"  ! Assets:Cash",
+			"poptag #trip",
+			"",
+			"2024-09-03 txn \"Exchange\"",
+			"  Assets:Cash  -100 USD {1.00 USD} @@ 90.00 EUR",
+			"  Assets:Euro  90.00 EUR",
+		},
+	}
+	context := core.NewNullContext()
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(actualData.accounts))
+	assert.Equal(t, []string{"USD", "EUR"}, actualData.accounts["Assets:Cash"].currencies)
+	assert.Nil(t, actualData.accounts["Expenses:Food"].currencies)
+
+	assert.Equal(t, 2, len(actualData.transactions))
+
+	assert.Equal(t, 9, actualData.transactions[0].lineNumber)
+	assert.Equal(t, "2024-09-02", actualData.transactions[0].date)
+	assert.Equal(t, "Shop", actualData.transactions[0].payee)
+	assert.Equal(t, "Lunch; with \"friends\"", actualData.transactions[0].description)
+	assert.Equal(t, []string{"header comment", "line comment"}, actualData.transactions[0].comments)
+	assert.Equal(t, []string{"trip", "food", "Tag A", "Tag:B"}, actualData.transactions[0].tags)
+	assert.Equal(t, 2, len(actualData.transactions[0].postings))
+	assert.Equal(t, "Expenses:Food", actualData.transactions[0].postings[0].accountName)
+	assert.Equal(t, "12.34", actualData.transactions[0].postings[0].amount)
+	assert.Equal(t, "USD", actualData.transactions[0].postings[0].commodity)
+	assert.Equal(t, "Assets:Cash", actualData.transactions[0].postings[1].accountName)
+	assert.Equal(t, "", actualData.transactions[0].postings[1].amount)
+
+	assert.Equal(t, "Exchange", actualData.transactions[1].description)
+	assert.Equal(t, 0, len(actualData.transactions[1].tags))
+	assert.Equal(t, "-100", actualData.transactions[1].postings[0].amount)
+	assert.Equal(t, "USD", actualData.transactions[1].postings[0].commodity)
+	assert.Equal(t, "90.00", actualData.transactions[1].postings[0].price)
+	assert.Equal(t, "EUR", actualData.transactions[1].postings[0].priceCommodity)
+	assert.True(t, actualData.transactions[1].postings[0].totalPrice)
+}
+
+func TestLedgerDataReaderParse(t *testing.T) {
+	reader := &journalDataReader{
+		journalType: journalTypeLedger,
+		allLines: []string{
+			"; comment",
+			"account Assets:Checking Account  ; note",
+			"commodity $1,000.00",
+			"",
+			"2024/09/02=2024/09/03 * (123) Grocery Store  ; :food:daily:",
+			"    ; paid by card, trip:, project: home",
+			"    Expenses:Food & Drinks    $1,234.5",
+			"    Assets:Checking Account   = $100",
+			"",
+			"comment",
+			"2024-09-04 Ignored",
+			"end comment",
+			"2024.09.05 Exchange",
+			"    [Assets:Checking Account]\t-USD 100 @ 0.9 EUR",
+			"    (Assets:Euro)  90EUR",
+		},
+	}
+	context := core.NewNullContext()
+	actualData, err := reader.read(context)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(actualData.accounts))
+	assert.NotNil(t, actualData.accounts["Assets:Checking Account"])
+
+	assert.Equal(t, 2, len(actualData.transactions))
+
+	assert.Equal(t, "2024/09/02", actualData.transactions[0].date)
+	assert.Equal(t, "Grocery Store", actualData.transactions[0].description)
+	assert.Equal(t, []string{"paid by card"}, actualData.transactions[0].comments)
+	assert.Equal(t, []string{"food", "daily", "trip", "project"}, actualData.transactions[0].tags)
+	assert.Equal(t, "Expenses:Food & Drinks", actualData.transactions[0].postings[0].accountName)
+	assert.Equal(t, "1234.5", actualData.transactions[0].postings[0].amount)
+	assert.Equal(t, "$", actualData.transactions[0].postings[0].commodity)
+	assert.Equal(t, "Assets:Checking Account", actualData.transactions[0].postings[1].accountName)
+	assert.Equal(t, "", actualData.transactions[0].postings[1].amount)
+
+	assert.Equal(t, "2024.09.05", actualData.transactions[1].date)
+	assert.Equal(t, "Assets:Checking Account", actualData.transactions[1].postings[0].accountName)
+	assert.Equal(t, "-100", actualData.transactions[1].postings[0].amount)
+	assert.Equal(t, "USD", actualData.transactions[1].postings[0].commodity)
+	assert.Equal(t, "0.9", actualData.transactions[1].postings[0].price)
+	assert.False(t, actualData.transactions[1].postings[0].totalPrice)
+	assert.Equal(t, "Assets:Euro", actualData.transactions[1].postings[1].accountName)
+	assert.Equal(t, "90", actualData.transactions[1].postings[1].amount)
+	assert.Equal(t, "EUR", actualData.transactions[1].postings[1].commodity)
+}
+
+func TestJournalDataReaderParseAmount(t *testing.T) {
+	reader := &journalDataReader{}
+
+	testCases := []struct {
+		text      string
+		number    string
+		commodity string
+	}{
+		{"12.34 USD", "12.34", "USD"},
+		{"-12.34 USD", "-12.34", "USD"},
+		{"USD -12.34", "-12.34", "USD"},
+		{"$12.34", "12.34", "$"},
+		{"-$12.34", "-12.34", "$"},
+		{"$-12.34", "-12.34", "$"},
+		{"1,234.56", "1234.56", ""},
+		{"+5 \"ABC 1\"", "5", "ABC 1"},
+	}
+
+	for _, testCase := range testCases {
+		number, commodity, ok := reader.parseAmount(testCase.text)
+		assert.True(t, ok)
+		assert.Equal(t, testCase.number, number)
+		assert.Equal(t, testCase.commodity, commodity)
+	}
+
+	_, _, ok := reader.parseAmount("10 / 3 USD")
+	assert.False(t, ok)
+
+	_, _, ok = reader.parseAmount("USD")
+	assert.False(t, ok)
+}
+
+func TestJournalDataReaderParse_EmptyContent(t *testing.T) {
+	reader := &journalDataReader{
+		journalType: journalTypeBeancount,
+		allLines:    []string{},
+	}
+	context := core.NewNullContext()
+	_, err := reader.read(context)
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+
+	reader = &journalDataReader{
+		journalType: journalTypeLedger,
+		allLines: []string{
+			"account Assets:Cash",
+		},
+	}
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+}
+
+func TestJournalDataReaderParse_InvalidContent(t *testing.T) {
+	context := core.NewNullContext()
+
+	reader := &journalDataReader{
+		journalType: journalTypeBeancount,
+		allLines: []string{
+			"2024-09-01 * \"Unclosed",
+			"  Assets:Cash  1 USD",
+		},
+	}
+	_, err := reader.read(context)
+	assert.EqualError(t, err, errs.ErrInvalidJournalFile.Message)
+
+	reader = &journalDataReader{
+		journalType: journalTypeBeancount,
+		allLines: []string{
+			"2024-09-01 * \"a\" \"b\" \"c\"",
+			"  Assets:Cash  1 USD",
+		},
+	}
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrInvalidJournalFile.Message)
+
+	reader = &journalDataReader{
+		journalType: journalTypeLedger,
+		allLines: []string{
+			"2024-09-01 Test",
+			"    Assets:Cash  1 + 2",
+		},
+	}
+	_, err = reader.read(context)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
@@ -0,0 +1,435 @@
+package journal
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const journalDateFormat = "2006-01-02"
+
+const journalAssetsRootAccountName = "Assets"
+const journalLiabilitiesRootAccountName = "Liabilities"
+const journalIncomeRootAccountName = "Income"
+const journalExpensesRootAccountName = "Expenses"
+const journalEquityRootAccountName = "Equity"
+
+const journalOpeningBalancesAccountName = "Opening Balances"
+const journalTransferFeesAccountName = "Transfer Fees"
+const journalUnknownAccountName = "Unknown"
+const journalBeancountAccountNameComponentPrefix = "X-"
+const journalOpeningBalanceDescription = "Opening Balance"
+
+// journalTransactionDataExporter defines the structure of plain-text accounting journal exporter for transaction data
+type journalTransactionDataExporter struct {
+	journalType journalType
+}
+
+// Initialize a plain-text accounting journal transaction data exporter singleton instance
+var (
+	BeancountTransactionDataExporter = &journalTransactionDataExporter{
+		journalType: journalTypeBeancount,
+	}
+
+	HledgerTransactionDataExporter = &journalTransactionDataExporter{
+		journalType: journalTypeHledger,
+	}
+)
+
+// journalPosting represents a posting line of a journal transaction
+type journalPosting struct {
+	accountName   string
+	amount        int64
+	currency      string
+	totalPrice    int64
+	priceCurrency string
+	elideAmount   bool
+}
+
+// ToExportedContent returns the exported plain-text accounting journal data
+func (e *journalTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64]models.TransactionSplitSlice) ([]byte, error) {
+	exportedTransactions := make([]*models.Transaction, 0, len(transactions))
+
+	// the transactions are sorted by transaction time in descending order, but the transactions in journal file should be in ascending order
+	for i := len(transactions) - 1; i >= 0; i-- {
+		transaction := transactions[i]
+
+		// the transfer in transaction would be created automatically by the transfer out transaction
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		if _, exists := accountMap[transaction.AccountId]; !exists {
+			log.Warnf(ctx, "[journal_transaction_data_file_exporter.ToExportedContent] cannot find account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.AccountId, transaction.TransactionId, uid)
+			continue
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			if _, exists := accountMap[transaction.RelatedAccountId]; !exists {
+				log.Warnf(ctx, "[journal_transaction_data_file_exporter.ToExportedContent] cannot find related account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.RelatedAccountId, transaction.TransactionId, uid)
+				continue
+			}
+		} else if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE &&
+			transaction.Type != models.TRANSACTION_DB_TYPE_INCOME &&
+			transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			log.Warnf(ctx, "[journal_transaction_data_file_exporter.ToExportedContent] cannot export transaction \"id:%d\" for user \"uid:%d\", because type \"%d\" is invalid", transaction.TransactionId, uid, transaction.Type)
+			return nil, errs.ErrTransactionTypeInvalid
+		}
+
+		exportedTransactions = append(exportedTransactions, transaction)
+	}
+
+	if len(exportedTransactions) < 1 {
+		return []byte{}, nil
+	}
+
+	allPostings := make([][]*journalPosting, len(exportedTransactions))
+	openedAccountCurrencies := make(map[string]map[string]bool)
+
+	for i := 0; i < len(exportedTransactions); i++ {
+		postings := e.getPostings(exportedTransactions[i], accountMap, categoryMap, allSplits)
+
+		for j := 0; j < len(postings); j++ {
+			posting := postings[j]
+
+			if _, exists := openedAccountCurrencies[posting.accountName]; !exists {
+				openedAccountCurrencies[posting.accountName] = make(map[string]bool)
+			}
+
+			if posting.currency != "" {
+				openedAccountCurrencies[posting.accountName][posting.currency] = true
+			}
+		}
+
+		allPostings[i] = postings
+	}
+
+	var sb strings.Builder
+
+	e.writeAccountOpenings(&sb, e.formatDate(exportedTransactions[0]), openedAccountCurrencies)
+
+	for i := 0; i < len(exportedTransactions); i++ {
+		sb.WriteString("\n")
+		e.writeTransaction(&sb, exportedTransactions[i], allPostings[i], tagMap, allTagIndexes)
+	}
+
+	return []byte(sb.String()), nil
+}
+
+func (e *journalTransactionDataExporter) getPostings(transaction *models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, allSplits map[int64]models.TransactionSplitSlice) []*journalPosting {
+	account := accountMap[transaction.AccountId]
+	accountPosting := &journalPosting{
+		accountName: e.getAccountName(account, accountMap),
+		currency:    account.Currency,
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		accountPosting.amount = transaction.Amount
+
+		return []*journalPosting{
+			accountPosting,
+			{
+				accountName: e.getEquityAccountName(journalOpeningBalancesAccountName),
+				amount:      -transaction.Amount,
+				currency:    account.Currency,
+			},
+		}
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		relatedAccount := accountMap[transaction.RelatedAccountId]
+		accountPosting.amount = -transaction.Amount
+
+		relatedAccountPosting := &journalPosting{
+			accountName: e.getAccountName(relatedAccount, accountMap),
+			amount:      transaction.RelatedAccountAmount,
+			currency:    relatedAccount.Currency,
+		}
+
+		postings := []*journalPosting{accountPosting, relatedAccountPosting}
+
+		if account.Currency != relatedAccount.Currency {
+			accountPosting.totalPrice = transaction.RelatedAccountAmount
+			accountPosting.priceCurrency = relatedAccount.Currency
+		} else if transaction.Amount != transaction.RelatedAccountAmount {
+			postings = append(postings, &journalPosting{
+				accountName: e.getTransferFeesAccountName(transaction.CategoryId, categoryMap),
+				elideAmount: true,
+			})
+		}
+
+		return postings
+	}
+
+	amountSign := int64(1)
+	categoryRootAccountName := journalIncomeRootAccountName
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+		amountSign = -1
+		categoryRootAccountName = journalExpensesRootAccountName
+	}
+
+	accountPosting.amount = amountSign * transaction.Amount
+	postings := []*journalPosting{accountPosting}
+	splits := allSplits[transaction.TransactionId]
+
+	if len(splits) > 0 {
+		for i := 0; i < len(splits); i++ {
+			postings = append(postings, &journalPosting{
+				accountName: e.getCategoryAccountName(categoryRootAccountName, splits[i].CategoryId, categoryMap),
+				amount:      -amountSign * splits[i].Amount,
+				currency:    account.Currency,
+			})
+		}
+	} else {
+		postings = append(postings, &journalPosting{
+			accountName: e.getCategoryAccountName(categoryRootAccountName, transaction.CategoryId, categoryMap),
+			amount:      -amountSign * transaction.Amount,
+			currency:    account.Currency,
+		})
+	}
+
+	return postings
+}
+
+func (e *journalTransactionDataExporter) writeAccountOpenings(sb *strings.Builder, openDate string, openedAccountCurrencies map[string]map[string]bool) {
+	accountNames := make([]string, 0, len(openedAccountCurrencies))
+
+	for accountName := range openedAccountCurrencies {
+		accountNames = append(accountNames, accountName)
+	}
+
+	sort.Strings(accountNames)
+
+	for i := 0; i < len(accountNames); i++ {
+		accountName := accountNames[i]
+		if e.journalType == journalTypeBeancount {
+			currencies := make([]string, 0, len(openedAccountCurrencies[accountName]))
+
+			for currency := range openedAccountCurrencies[accountName] {
+				currencies = append(currencies, currency)
+			}
+
+			sort.Strings(currencies)
+			sb.WriteString(openDate + " open " + accountName)
+
+			if len(currencies) > 0 {
+				sb.WriteString(" " + strings.Join(currencies, ","))
+			}
+
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString("account " + accountName + "\n")
+		}
+	}
+}
+
+func (e *journalTransactionDataExporter) writeTransaction(sb *strings.Builder, transaction *models.Transaction, postings []*journalPosting, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) {
+	description := e.replaceLineBreaks(transaction.Comment)
+
+	if description == "" && transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		description = journalOpeningBalanceDescription
+	}
+
+	tagNames := e.getTagNames(transaction.TransactionId, tagMap, allTagIndexes)
+
+	if e.journalType == journalTypeBeancount {
+		sb.WriteString(e.formatDate(transaction) + " * " + e.quoteBeancountString(description) + "\n")
+
+		if len(tagNames) > 0 {
+			sb.WriteString(beancountIndent + beancountTagsMetadataKey + ": " + e.quoteBeancountString(strings.Join(tagNames, ", ")) + "\n")
+		}
+	} else {
+		sb.WriteString(e.formatDate(transaction))
+
+		if description != "" {
+			sb.WriteString(" " + strings.ReplaceAll(description, ";", ","))
+		}
+
+		if len(tagNames) > 0 {
+			hledgerTags := make([]string, len(tagNames))
+
+			for i := 0; i < len(tagNames); i++ {
+				hledgerTags[i] = e.getHledgerTagName(tagNames[i]) + ":"
+			}
+
+			sb.WriteString("  ; " + strings.Join(hledgerTags, ", "))
+		}
+
+		sb.WriteString("\n")
+	}
+
+	indent := beancountIndent
+
+	if e.journalType == journalTypeHledger {
+		indent = hledgerIndent
+	}
+
+	for i := 0; i < len(postings); i++ {
+		posting := postings[i]
+		sb.WriteString(indent + posting.accountName)
+
+		if !posting.elideAmount {
+			sb.WriteString("  " + utils.FormatAmount(posting.amount) + " " + posting.currency)
+
+			if posting.priceCurrency != "" {
+				sb.WriteString(" @@ " + utils.FormatAmount(posting.totalPrice) + " " + posting.priceCurrency)
+			}
+		}
+
+		sb.WriteString("\n")
+	}
+}
+
+func (e *journalTransactionDataExporter) getAccountName(account *models.Account, accountMap map[int64]*models.Account) string {
+	rootAccountName := journalAssetsRootAccountName
+
+	if account.Category.IsLiability() {
+		rootAccountName = journalLiabilitiesRootAccountName
+	}
+
+	accountName := e.getAccountNameComponent(account.Name)
+
+	if account.ParentAccountId != models.LevelOneAccountParentId {
+		if parentAccount, exists := accountMap[account.ParentAccountId]; exists {
+			accountName = e.getAccountNameComponent(parentAccount.Name) + ":" + accountName
+		}
+	}
+
+	return rootAccountName + ":" + accountName
+}
+
+func (e *journalTransactionDataExporter) getCategoryAccountName(rootAccountName string, categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	category, exists := categoryMap[categoryId]
+
+	if !exists {
+		return rootAccountName + ":" + e.getAccountNameComponent(journalUnknownAccountName)
+	}
+
+	categoryName := e.getAccountNameComponent(category.Name)
+
+	if category.ParentCategoryId != models.LevelOneTransactionParentId {
+		if parentCategory, exists := categoryMap[category.ParentCategoryId]; exists {
+			categoryName = e.getAccountNameComponent(parentCategory.Name) + ":" + categoryName
+		}
+	}
+
+	return rootAccountName + ":" + categoryName
+}
+
+func (e *journalTransactionDataExporter) getTransferFeesAccountName(categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	if _, exists := categoryMap[categoryId]; exists {
+		return e.getCategoryAccountName(journalExpensesRootAccountName, categoryId, categoryMap)
+	}
+
+	return journalExpensesRootAccountName + ":" + e.getAccountNameComponent(journalTransferFeesAccountName)
+}
+
+func (e *journalTransactionDataExporter) getEquityAccountName(name string) string {
+	return journalEquityRootAccountName + ":" + e.getAccountNameComponent(name)
+}
+
+func (e *journalTransactionDataExporter) getAccountNameComponent(name string) string {
+	if e.journalType == journalTypeBeancount {
+		return e.getBeancountAccountNameComponent(name)
+	}
+
+	return e.getHledgerAccountNameComponent(name)
+}
+
+// getBeancountAccountNameComponent returns the account name component which only contains letters, digits and dashes, and starts with a capital letter or digit
+func (e *journalTransactionDataExporter) getBeancountAccountNameComponent(name string) string {
+	var sb strings.Builder
+	lastIsDash := true
+
+	for _, ch := range name {
+		if unicode.IsLetter(ch) || unicode.IsDigit(ch) {
+			if sb.Len() == 0 {
+				ch = unicode.ToUpper(ch)
+
+				// each component of beancount account name must start with an uppercase letter or a digit
+				if !unicode.IsUpper(ch) && !unicode.IsDigit(ch) {
+					sb.WriteString(journalBeancountAccountNameComponentPrefix)
+				}
+			}
+
+			sb.WriteRune(ch)
+			lastIsDash = false
+		} else if !lastIsDash {
+			sb.WriteRune('-')
+			lastIsDash = true
+		}
+	}
+
+	component := strings.TrimRight(sb.String(), "-")
+
+	if component == "" {
+		return journalUnknownAccountName
+	}
+
+	return component
+}
+
+// getHledgerAccountNameComponent returns the account name component which does not contain colons, semicolons or consecutive spaces
+func (e *journalTransactionDataExporter) getHledgerAccountNameComponent(name string) string {
+	name = strings.ReplaceAll(name, ":", "-")
+	name = strings.ReplaceAll(name, ";", "-")
+	component := strings.Join(strings.Fields(name), " ")
+
+	if component == "" {
+		return journalUnknownAccountName
+	}
+
+	return component
+}
+
+func (e *journalTransactionDataExporter) getHledgerTagName(tagName string) string {
+	tagName = strings.ReplaceAll(tagName, ":", "-")
+	tagName = strings.ReplaceAll(tagName, ",", "-")
+
+	return strings.Join(strings.Fields(tagName), "-")
+}
+
+func (e *journalTransactionDataExporter) getTagNames(transactionId int64, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64) []string {
+	tagIds := allTagIndexes[transactionId]
+	tagNames := make([]string, 0, len(tagIds))
+
+	for i := 0; i < len(tagIds); i++ {
+		tag, exists := tagMap[tagIds[i]]
+
+		if !exists {
+			continue
+		}
+
+		tagNames = append(tagNames, e.replaceLineBreaks(tag.Name))
+	}
+
+	return tagNames
+}
+
+func (e *journalTransactionDataExporter) quoteBeancountString(text string) string {
+	text = strings.ReplaceAll(text, "\\", "\\\\")
+	text = strings.ReplaceAll(text, "\"", "\\\"")
+
+	return "\"" + text + "\""
+}
+
+func (e *journalTransactionDataExporter) formatDate(transaction *models.Transaction) string {
+	transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+	transactionTime := time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(transactionTimeZone)
+
+	return transactionTime.Format(journalDateFormat)
+}
+
+func (e *journalTransactionDataExporter) replaceLineBreaks(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", " ")
+	text = strings.ReplaceAll(text, "\r", " ")
+	text = strings.ReplaceAll(text, "\n", " ")
+
+	return text
+}
@@ -0,0 +1,274 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+func TestBeancountTransactionDataFileExporterToExportedContent(t *testing.T) {
+	exporter := BeancountTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits := createJournalExporterTestData()
+
+	expectedContent := "2024-09-01 open Assets:Euro-Wallet EUR\n" +
+		"2024-09-01 open Assets:Test-Account USD\n" +
+		"2024-09-01 open Equity:Opening-Balances USD\n" +
+		"2024-09-01 open Expenses:Bank-Fee\n" +
+		"2024-09-01 open Expenses:Food USD\n" +
+		"2024-09-01 open Expenses:Food:Drinks-Snacks USD\n" +
+		"2024-09-01 open Income:Work:Salary USD\n" +
+		"2024-09-01 open Liabilities:Bank-Cards:My-card USD\n" +
+		"\n" +
+		"2024-09-01 * \"Opening Balance\"\n" +
+		"  Assets:Test-Account  1000.00 USD\n" +
+		"  Equity:Opening-Balances  -1000.00 USD\n" +
+		"\n" +
+		"2024-09-01 * \"Say \\\"Hi\\\"\"\n" +
+		"  tags: \"Tag A, Tag:B\"\n" +
+		"  Assets:Test-Account  123.45 USD\n" +
+		"  Income:Work:Salary  -123.45 USD\n" +
+		"\n" +
+		"2024-09-02 * \"Exchange\"\n" +
+		"  Assets:Test-Account  -100.00 USD @@ 90.00 EUR\n" +
+		"  Assets:Euro-Wallet  90.00 EUR\n" +
+		"\n" +
+		"2024-09-03 * \"\"\n" +
+		"  Liabilities:Bank-Cards:My-card  -12.34 USD\n" +
+		"  Expenses:Food  10.00 USD\n" +
+		"  Expenses:Food:Drinks-Snacks  2.34 USD\n" +
+		"\n" +
+		"2024-09-04 * \"Repay\"\n" +
+		"  Assets:Test-Account  -50.00 USD\n" +
+		"  Liabilities:Bank-Cards:My-card  49.50 USD\n" +
+		"  Expenses:Bank-Fee\n"
+
+	actualContent, err := exporter.ToExportedContent(context, 123456789, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestHledgerTransactionDataFileExporterToExportedContent(t *testing.T) {
+	exporter := HledgerTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits := createJournalExporterTestData()
+
+	expectedContent := "account Assets:Euro Wallet\n" +
+		"account Assets:Test Account\n" +
+		"account Equity:Opening Balances\n" +
+		"account Expenses:Bank Fee\n" +
+		"account Expenses:Food\n" +
+		"account Expenses:Food:Drinks & Snacks\n" +
+		"account Income:Work:Salary\n" +
+		"account Liabilities:Bank Cards:my card\n" +
+		"\n" +
+		"2024-09-01 Opening Balance\n" +
+		"    Assets:Test Account  1000.00 USD\n" +
+		"    Equity:Opening Balances  -1000.00 USD\n" +
+		"\n" +
+		"2024-09-01 Say \"Hi\"  ; Tag-A:, Tag-B:\n" +
+		"    Assets:Test Account  123.45 USD\n" +
+		"    Income:Work:Salary  -123.45 USD\n" +
+		"\n" +
+		"2024-09-02 Exchange\n" +
+		"    Assets:Test Account  -100.00 USD @@ 90.00 EUR\n" +
+		"    Assets:Euro Wallet  90.00 EUR\n" +
+		"\n" +
+		"2024-09-03\n" +
+		"    Liabilities:Bank Cards:my card  -12.34 USD\n" +
+		"    Expenses:Food  10.00 USD\n" +
+		"    Expenses:Food:Drinks & Snacks  2.34 USD\n" +
+		"\n" +
+		"2024-09-04 Repay\n" +
+		"    Assets:Test Account  -50.00 USD\n" +
+		"    Liabilities:Bank Cards:my card  49.50 USD\n" +
+		"    Expenses:Bank Fee\n"
+
+	actualContent, err := exporter.ToExportedContent(context, 123456789, transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestJournalTransactionDataFileExporterToExportedContent_EmptyContent(t *testing.T) {
+	context := core.NewNullContext()
+
+	actualContent, err := BeancountTransactionDataExporter.ToExportedContent(context, 123456789, []*models.Transaction{}, map[int64]*models.Account{}, map[int64]*models.TransactionCategory{}, map[int64]*models.TransactionTag{}, map[int64][]int64{}, map[int64]models.TransactionSplitSlice{})
+	assert.Nil(t, err)
+	assert.Equal(t, "", string(actualContent))
+
+	actualContent, err = HledgerTransactionDataExporter.ToExportedContent(context, 123456789, []*models.Transaction{}, map[int64]*models.Account{}, map[int64]*models.TransactionCategory{}, map[int64]*models.TransactionTag{}, map[int64][]int64{}, map[int64]models.TransactionSplitSlice{})
+	assert.Nil(t, err)
+	assert.Equal(t, "", string(actualContent))
+}
+
+func TestJournalTransactionDataFileExporterGetBeancountAccountNameComponent(t *testing.T) {
+	exporter := BeancountTransactionDataExporter
+
+	assert.Equal(t, "Cash", exporter.getBeancountAccountNameComponent("cash"))
+	assert.Equal(t, "Bank-of-Test", exporter.getBeancountAccountNameComponent("  Bank of  Test! "))
+	assert.Equal(t, "2024-Savings", exporter.getBeancountAccountNameComponent("2024 Savings"))
+	assert.Equal(t, "Élan", exporter.getBeancountAccountNameComponent("élan"))
+	assert.Equal(t, "Ωmega-Fund", exporter.getBeancountAccountNameComponent("ωmega Fund"))
+	assert.Equal(t, "X-银行卡", exporter.getBeancountAccountNameComponent("银行卡"))
+	assert.Equal(t, "X-银行卡", exporter.getBeancountAccountNameComponent(" 银行卡 "))
+	assert.Equal(t, "Unknown", exporter.getBeancountAccountNameComponent("!!!"))
+}
+
+func createJournalExporterTestData() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory, map[int64]*models.TransactionTag, map[int64][]int64, map[int64]models.TransactionSplitSlice) {
+	// transactions are sorted by transaction time in descending order
+	transactions := []*models.Transaction{
+		{
+			TransactionId:        6,
+			TransactionTime:      1725408000000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_IN,
+			AccountId:            2,
+			Amount:               4950,
+			RelatedAccountId:     1,
+			RelatedAccountAmount: 5000,
+		},
+		{
+			TransactionId:        5,
+			TransactionTime:      1725408000000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			CategoryId:           6,
+			AccountId:            1,
+			Amount:               5000,
+			RelatedAccountId:     2,
+			RelatedAccountAmount: 4950,
+			Comment:              "Repay",
+		},
+		{
+			TransactionId:     4,
+			TransactionTime:   1725321600000,
+			Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+			TimezoneUtcOffset: 480,
+			CategoryId:        4,
+			AccountId:         2,
+			Amount:            1234,
+		},
+		{
+			TransactionId:        3,
+			TransactionTime:      1725235200000,
+			Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+			AccountId:            1,
+			Amount:               10000,
+			RelatedAccountId:     3,
+			RelatedAccountAmount: 9000,
+			Comment:              "Exchange",
+		},
+		{
+			TransactionId:   2,
+			TransactionTime: 1725150000000,
+			Type:            models.TRANSACTION_DB_TYPE_INCOME,
+			CategoryId:      2,
+			AccountId:       1,
+			Amount:          12345,
+			Comment:         "Say \"Hi\"",
+		},
+		{
+			TransactionId:   1,
+			TransactionTime: 1725148800000,
+			Type:            models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+			AccountId:       1,
+			Amount:          100000,
+		},
+	}
+
+	accountMap := map[int64]*models.Account{
+		1: {
+			AccountId: 1,
+			Name:      "Test Account",
+			Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+			Currency:  "USD",
+		},
+		2: {
+			AccountId:       2,
+			Name:            "my card",
+			Category:        models.ACCOUNT_CATEGORY_CREDIT_CARD,
+			ParentAccountId: 4,
+			Currency:        "USD",
+		},
+		3: {
+			AccountId: 3,
+			Name:      "Euro Wallet",
+			Category:  models.ACCOUNT_CATEGORY_CASH,
+			Currency:  "EUR",
+		},
+		4: {
+			AccountId: 4,
+			Name:      "Bank Cards",
+			Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+			Type:      models.ACCOUNT_TYPE_MULTI_SUB_ACCOUNTS,
+			Currency:  "USD",
+		},
+	}
+
+	categoryMap := map[int64]*models.TransactionCategory{
+		1: {
+			CategoryId: 1,
+			Name:       "Work",
+			Type:       models.CATEGORY_TYPE_INCOME,
+		},
+		2: {
+			CategoryId:       2,
+			Name:             "Salary",
+			Type:             models.CATEGORY_TYPE_INCOME,
+			ParentCategoryId: 1,
+		},
+		3: {
+			CategoryId: 3,
+			Name:       "Food",
+			Type:       models.CATEGORY_TYPE_EXPENSE,
+		},
+		4: {
+			CategoryId:       4,
+			Name:             "Drinks & Snacks",
+			Type:             models.CATEGORY_TYPE_EXPENSE,
+			ParentCategoryId: 3,
+		},
+		6: {
+			CategoryId: 6,
+			Name:       "Bank Fee",
+			Type:       models.CATEGORY_TYPE_TRANSFER,
+		},
+	}
+
+	tagMap := map[int64]*models.TransactionTag{
+		1: {
+			TagId: 1,
+			Name:  "Tag A",
+		},
+		2: {
+			TagId: 2,
+			Name:  "Tag:B",
+		},
+	}
+
+	allTagIndexes := map[int64][]int64{
+		2: {1, 2, 3},
+	}
+
+	allSplits := map[int64]models.TransactionSplitSlice{
+		4: {
+			{
+				SplitId:       1,
+				TransactionId: 4,
+				CategoryId:    3,
+				Amount:        1000,
+			},
+			{
+				SplitId:       2,
+				TransactionId: 4,
+				CategoryId:    4,
+				Amount:        234,
+			},
+		},
+	}
+
+	return transactions, accountMap, categoryMap, tagMap, allTagIndexes, allSplits
+}
@@ -0,0 +1,233 @@
+package journal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestBeancountTransactionDataFileParseImportedData_MinimumValidData(t *testing.T) {
+	converter := BeancountTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 open Assets:Test-Account USD\n"+
+			"2024-09-01 open Assets:Euro-Wallet EUR\n"+
+			"\n"+
+			"2024-09-01 * \"Opening Balance\"\n"+
+			"  Assets:Test-Account  1000.00 USD\n"+
+			"  Equity:Opening-Balances  -1000.00 USD\n"+
+			"\n"+
+			"2024-09-02 * \"Say \\\"Hi\\\"\" #Tag-C\n"+
+			"  tags: \"Tag A, Tag:B\"\n"+
+			"  Assets:Test-Account  123.45 USD\n"+
+			"  Income:Work:Salary\n"+
+			"\n"+
+			"2024-09-03 * \"Exchange\"\n"+
+			"  Assets:Test-Account  -100.00 USD @@ 90.00 EUR\n"+
+			"  Assets:Euro-Wallet\n"+
+			"\n"+
+			"2024-09-04 * \"Shop\" \"\" ; paid by cash\n"+
+			"  Expenses:Food:Drinks-Snacks  2.34 EUR\n"+
+			"  Assets:Euro-Wallet  -2.34 EUR\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 3, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Test-Account", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[0].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Opening Balance", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725235200), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[1].Amount)
+	assert.Equal(t, "Test-Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Salary", allNewTransactions[1].OriginalCategoryName)
+	assert.Equal(t, []string{"Tag-C", "Tag A", "Tag:B"}, allNewTransactions[1].OriginalTagNames)
+	assert.Equal(t, "Say \"Hi\"", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[2].Amount)
+	assert.Equal(t, "Test-Account", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[2].OriginalSourceAccountCurrency)
+	assert.Equal(t, int64(9000), allNewTransactions[2].RelatedAccountAmount)
+	assert.Equal(t, "Euro-Wallet", allNewTransactions[2].OriginalDestinationAccountName)
+	assert.Equal(t, "EUR", allNewTransactions[2].OriginalDestinationAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[3].Type)
+	assert.Equal(t, int64(234), allNewTransactions[3].Amount)
+	assert.Equal(t, "Euro-Wallet", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Drinks-Snacks", allNewTransactions[3].OriginalCategoryName)
+	assert.Equal(t, "Shop", allNewTransactions[3].Comment)
+
+	assert.Equal(t, "Test-Account", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+	assert.Equal(t, "Euro-Wallet", allNewAccounts[1].Name)
+	assert.Equal(t, "EUR", allNewAccounts[1].Currency)
+
+	assert.Equal(t, "Drinks-Snacks", allNewSubExpenseCategories[0].Name)
+	assert.Equal(t, "Salary", allNewSubIncomeCategories[0].Name)
+	assert.Equal(t, "", allNewSubTransferCategories[0].Name)
+
+	assert.Equal(t, "Tag-C", allNewTags[0].Name)
+	assert.Equal(t, "Tag A", allNewTags[1].Name)
+	assert.Equal(t, "Tag:B", allNewTags[2].Name)
+}
+
+func TestLedgerTransactionDataFileParseImportedData_MinimumValidData(t *testing.T) {
+	converter := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(
+		"account Assets:Test Account\n"+
+			"\n"+
+			"2024/09/01 * Lunch  ; Tag-A:, Tag-B:\n"+
+			"    Expenses:Food:Drinks & Snacks    $12.34\n"+
+			"    Liabilities:Bank Cards:my card\n"+
+			"\n"+
+			"2024-09-02\n"+
+			"    ; refund\n"+
+			"    Assets:Test Account  1,000\n"+
+			"    Assets:Wallet  -1,000\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 0, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 2, len(allNewTags))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(1234), allNewTransactions[0].Amount)
+	assert.Equal(t, "my card", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[0].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Drinks & Snacks", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, []string{"Tag-A", "Tag-B"}, allNewTransactions[0].OriginalTagNames)
+	assert.Equal(t, "Lunch", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[1].Type)
+	assert.Equal(t, int64(100000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Wallet", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[1].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Test Account", allNewTransactions[1].OriginalDestinationAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[1].OriginalDestinationAccountCurrency)
+	assert.Equal(t, "refund", allNewTransactions[1].Comment)
+}
+
+func TestJournalTransactionDataFileParseImportedData_ParseAmountWithUnitPrice(t *testing.T) {
+	converter := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 Exchange\n"+
+			"    Assets:Cash  -100.005 USD @ 0.9 EUR\n"+
+			"    Assets:Euro\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10001), allNewTransactions[0].Amount)
+	assert.Equal(t, int64(9000), allNewTransactions[0].RelatedAccountAmount)
+	assert.Equal(t, "EUR", allNewTransactions[0].OriginalDestinationAccountCurrency)
+}
+
+func TestJournalTransactionDataFileParseImportedData_MultiPostingTransaction(t *testing.T) {
+	converter := BeancountTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"2024-09-03 * \"\"\n"+
+			"  Liabilities:Bank-Cards:My-card  -12.34 USD\n"+
+			"  Expenses:Food  10.00 USD\n"+
+			"  Expenses:Food:Drinks-Snacks  2.34 USD\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotSupportedMultiPostingTransactions.Message)
+
+	customErr, ok := err.(*errs.Error)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]any{"lineNumber": 1}, customErr.Context)
+}
+
+func TestJournalTransactionDataFileParseImportedData_NotSupportedTransactionType(t *testing.T) {
+	converter := BeancountTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"2024-09-03 * \"\"\n"+
+			"  Income:Salary  -12.34 USD\n"+
+			"  Expenses:Food  12.34 USD\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrThereAreNotSupportedTransactionType.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"2024-09-03 * \"\"\n"+
+			"  Assets:Cash  -12.34 USD\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidJournalFile.Message)
+}
+
+func TestJournalTransactionDataFileParseImportedData_ParseInvalidData(t *testing.T) {
+	converter := LedgerTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"09/01 Test\n"+
+			"    Assets:Cash  1 USD\n"+
+			"    Expenses:Food\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 Test\n"+
+			"    Assets:Cash\n"+
+			"    Expenses:Food\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		"2024-09-01 Test\n"+
+			"    Assets:Cash  1 XYZ\n"+
+			"    Expenses:Food\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAccountCurrencyInvalid.Message)
+}
@@ -0,0 +1,415 @@
+package journal
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const journalTransactionTagSeparator = "\t"
+
+var journalTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:         true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:         true,
+	datatable.TRANSACTION_DATA_TABLE_CATEGORY:                 true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:             true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:         true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:                   true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME:     true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
+	datatable.TRANSACTION_DATA_TABLE_TAGS:                     true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+}
+
+var journalCommoditySymbolCurrencyMapping = map[string]string{
+	"$": "USD",
+	"€": "EUR",
+	"£": "GBP",
+}
+
+// journalAccountType represents the type of journal account according to its root account name
+type journalAccountType byte
+
+// Journal account types
+const (
+	journalAccountTypeAccount journalAccountType = 1
+	journalAccountTypeIncome  journalAccountType = 2
+	journalAccountTypeExpense journalAccountType = 3
+	journalAccountTypeEquity  journalAccountType = 4
+)
+
+var journalRootAccountNameTypeMapping = map[string]journalAccountType{
+	"assets":      journalAccountTypeAccount,
+	"asset":       journalAccountTypeAccount,
+	"liabilities": journalAccountTypeAccount,
+	"liability":   journalAccountTypeAccount,
+	"income":      journalAccountTypeIncome,
+	"revenue":     journalAccountTypeIncome,
+	"revenues":    journalAccountTypeIncome,
+	"expenses":    journalAccountTypeExpense,
+	"expense":     journalAccountTypeExpense,
+	"equity":      journalAccountTypeEquity,
+}
+
+// journalTransactionDataTable defines the structure of plain-text accounting journal transaction data table
+type journalTransactionDataTable struct {
+	accounts map[string]*journalAccountData
+	allData  []*journalTransactionData
+}
+
+// journalTransactionDataRow defines the structure of plain-text accounting journal transaction data row
+type journalTransactionDataRow struct {
+	dataTable  *journalTransactionDataTable
+	data       *journalTransactionData
+	finalItems map[datatable.TransactionDataTableColumn]string
+}
+
+// journalTransactionDataRowIterator defines the structure of plain-text accounting journal transaction data row iterator
+type journalTransactionDataRowIterator struct {
+	dataTable    *journalTransactionDataTable
+	currentIndex int
+}
+
+// journalImportedPosting represents a posting whose amount has been parsed or inferred
+type journalImportedPosting struct {
+	accountType  journalAccountType
+	accountPath  []string
+	amount       int64
+	currency     string
+	weight       *big.Rat
+	weightSymbol string
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *journalTransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := journalTransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *journalTransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *journalTransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &journalTransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *journalTransactionDataRow) IsValid() bool {
+	return true
+}
+
+// GetData returns the data in the specified column type
+func (r *journalTransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := journalTransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *journalTransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next imported data row
+func (t *journalTransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	rowItems, err := t.parseTransaction(ctx, user, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &journalTransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+	}, nil
+}
+
+func (t *journalTransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, journalTransaction *journalTransactionData) (map[datatable.TransactionDataTableColumn]string, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(journalTransactionSupportedColumns))
+
+	transactionTime, err := t.parseTransactionTime(ctx, journalTransaction)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
+
+	if len(journalTransaction.postings) > 2 {
+		log.Errorf(ctx, "[journal_transaction_data_table.parseTransaction] cannot import transaction in line#%d for user \"uid:%d\", because it has %d postings", journalTransaction.lineNumber, user.Uid, len(journalTransaction.postings))
+		return nil, errs.NewErrorWithContext(errs.ErrNotSupportedMultiPostingTransactions, map[string]any{
+			"lineNumber": journalTransaction.lineNumber,
+		})
+	} else if len(journalTransaction.postings) < 2 {
+		log.Errorf(ctx, "[journal_transaction_data_table.parseTransaction] cannot import transaction in line#%d for user \"uid:%d\", because it has %d postings", journalTransaction.lineNumber, user.Uid, len(journalTransaction.postings))
+		return nil, errs.ErrInvalidJournalFile
+	}
+
+	postings, err := t.parsePostings(ctx, user, journalTransaction)
+
+	if err != nil {
+		return nil, err
+	}
+
+	accountPosting := postings[0]
+	otherPosting := postings[1]
+
+	if accountPosting.accountType != journalAccountTypeAccount {
+		accountPosting, otherPosting = otherPosting, accountPosting
+	}
+
+	if accountPosting.accountType != journalAccountTypeAccount {
+		log.Errorf(ctx, "[journal_transaction_data_table.parseTransaction] cannot import transaction in line#%d for user \"uid:%d\", because there is no asset or liability posting", journalTransaction.lineNumber, user.Uid)
+		return nil, errs.ErrThereAreNotSupportedTransactionType
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = accountPosting.accountPath[len(accountPosting.accountPath)-1]
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = accountPosting.currency
+
+	if otherPosting.accountType == journalAccountTypeAccount { // transfer
+		fromPosting := accountPosting
+		toPosting := otherPosting
+
+		if fromPosting.amount > 0 {
+			fromPosting, toPosting = toPosting, fromPosting
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = journalTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = fromPosting.accountPath[len(fromPosting.accountPath)-1]
+		data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = fromPosting.currency
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-fromPosting.amount)
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = toPosting.accountPath[len(toPosting.accountPath)-1]
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = toPosting.currency
+		data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(toPosting.amount)
+		data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = ""
+	} else if otherPosting.accountType == journalAccountTypeEquity { // balance modification
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = journalTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(accountPosting.amount)
+	} else { // income/expense
+		if otherPosting.accountType == journalAccountTypeIncome {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = journalTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(accountPosting.amount)
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = journalTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-accountPosting.amount)
+		}
+
+		categoryNames := otherPosting.accountPath
+
+		if len(categoryNames) > 1 { // remove root account name
+			categoryNames = categoryNames[1:]
+		}
+
+		if len(categoryNames) > 1 {
+			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = categoryNames[0]
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = ""
+		}
+
+		data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = categoryNames[len(categoryNames)-1]
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TAGS] = t.getTags(journalTransaction)
+
+	if journalTransaction.description != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = journalTransaction.description
+	} else if journalTransaction.payee != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = journalTransaction.payee
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = strings.Join(journalTransaction.comments, " ")
+	}
+
+	return data, nil
+}
+
+func (t *journalTransactionDataRowIterator) parsePostings(ctx core.Context, user *models.User, journalTransaction *journalTransactionData) ([]*journalImportedPosting, error) {
+	postings := make([]*journalImportedPosting, len(journalTransaction.postings))
+	elidedPostingIndex := -1
+
+	for i := 0; i < len(journalTransaction.postings); i++ {
+		journalPosting := journalTransaction.postings[i]
+		accountPath := strings.Split(journalPosting.accountName, ":")
+
+		for j := 0; j < len(accountPath); j++ {
+			accountPath[j] = strings.TrimSpace(accountPath[j])
+		}
+
+		posting := &journalImportedPosting{
+			accountType: t.getAccountType(accountPath[0]),
+			accountPath: accountPath,
+			currency:    t.getCurrency(user, journalPosting.accountName, journalPosting.commodity),
+		}
+
+		postings[i] = posting
+
+		if journalPosting.amount == "" {
+			if elidedPostingIndex >= 0 {
+				log.Errorf(ctx, "[journal_transaction_data_table.parsePostings] cannot import posting in line#%d for user \"uid:%d\", because there is more than one posting without amount", journalPosting.lineNumber, user.Uid)
+				return nil, errs.ErrAmountInvalid
+			}
+
+			elidedPostingIndex = i
+			continue
+		}
+
+		amount, ok := new(big.Rat).SetString(journalPosting.amount)
+
+		if !ok {
+			log.Errorf(ctx, "[journal_transaction_data_table.parsePostings] cannot parse amount \"%s\" in line#%d for user \"uid:%d\"", journalPosting.amount, journalPosting.lineNumber, user.Uid)
+			return nil, errs.ErrAmountInvalid
+		}
+
+		posting.amount = t.getAmount(amount)
+		posting.weight = amount
+		posting.weightSymbol = journalPosting.commodity
+
+		if journalPosting.price != "" {
+			price, ok := new(big.Rat).SetString(journalPosting.price)
+
+			if !ok {
+				log.Errorf(ctx, "[journal_transaction_data_table.parsePostings] cannot parse price \"%s\" in line#%d for user \"uid:%d\"", journalPosting.price, journalPosting.lineNumber, user.Uid)
+				return nil, errs.ErrAmountInvalid
+			}
+
+			if journalPosting.totalPrice {
+				posting.weight = price.Abs(price)
+
+				if amount.Sign() < 0 {
+					posting.weight.Neg(posting.weight)
+				}
+			} else {
+				posting.weight = new(big.Rat).Mul(amount, price)
+			}
+
+			posting.weightSymbol = journalPosting.priceCommodity
+		}
+	}
+
+	if elidedPostingIndex >= 0 {
+		otherPosting := postings[1-elidedPostingIndex]
+		elidedPosting := postings[elidedPostingIndex]
+		elidedPosting.amount = -t.getAmount(otherPosting.weight)
+
+		if journalTransaction.postings[elidedPostingIndex].commodity == "" {
+			elidedPosting.currency = t.getCurrency(user, journalTransaction.postings[elidedPostingIndex].accountName, otherPosting.weightSymbol)
+		}
+	}
+
+	return postings, nil
+}
+
+func (t *journalTransactionDataRowIterator) parseTransactionTime(ctx core.Context, journalTransaction *journalTransactionData) (string, error) {
+	date := journalTransaction.date
+
+	if !utils.IsValidYearMonthDayLongOrShortDateFormat(date) {
+		log.Errorf(ctx, "[journal_transaction_data_table.parseTransactionTime] cannot parse date \"%s\" in line#%d", date, journalTransaction.lineNumber)
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	date = strings.ReplaceAll(date, ".", "-")
+	date = strings.ReplaceAll(date, "/", "-")
+	date = strings.ReplaceAll(date, "'", "-")
+	items := strings.Split(date, "-")
+
+	year := items[0]
+	month := items[1]
+	day := items[2]
+
+	if len(month) < 2 {
+		month = "0" + month
+	}
+
+	if len(day) < 2 {
+		day = "0" + day
+	}
+
+	return fmt.Sprintf("%s-%s-%s 00:00:00", year, month, day), nil
+}
+
+func (t *journalTransactionDataRowIterator) getAccountType(rootAccountName string) journalAccountType {
+	accountType, exists := journalRootAccountNameTypeMapping[strings.ToLower(rootAccountName)]
+
+	if !exists {
+		return journalAccountTypeAccount
+	}
+
+	return accountType
+}
+
+func (t *journalTransactionDataRowIterator) getCurrency(user *models.User, accountName string, commodity string) string {
+	if commodity == "" {
+		if account, exists := t.dataTable.accounts[accountName]; exists && len(account.currencies) > 0 {
+			commodity = account.currencies[0]
+		} else {
+			return user.DefaultCurrency
+		}
+	}
+
+	if currency, exists := journalCommoditySymbolCurrencyMapping[commodity]; exists {
+		return currency
+	}
+
+	return commodity
+}
+
+func (t *journalTransactionDataRowIterator) getAmount(amount *big.Rat) int64 {
+	value, _ := new(big.Rat).Mul(amount, big.NewRat(100, 1)).Float64()
+	return int64(math.Round(value))
+}
+
+func (t *journalTransactionDataRowIterator) getTags(journalTransaction *journalTransactionData) string {
+	tagNames := make([]string, 0, len(journalTransaction.tags))
+	tagNameExists := make(map[string]bool, len(journalTransaction.tags))
+
+	for i := 0; i < len(journalTransaction.tags); i++ {
+		tagName := strings.ReplaceAll(journalTransaction.tags[i], journalTransactionTagSeparator, " ")
+
+		if tagName == "" || tagNameExists[tagName] {
+			continue
+		}
+
+		tagNames = append(tagNames, tagName)
+		tagNameExists[tagName] = true
+	}
+
+	return strings.Join(tagNames, journalTransactionTagSeparator)
+}
+
+func createNewJournalTransactionDataTable(journalData *journalData) (*journalTransactionDataTable, error) {
+	if journalData == nil {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	return &journalTransactionDataTable{
+		accounts: journalData.accounts,
+		allData:  journalData.transactions,
+	}, nil
+}
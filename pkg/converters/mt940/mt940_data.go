@@ -0,0 +1,71 @@
+package mt940
+
+// mt940CreditDebitMark represents the debit or credit mark in mt940 file
+type mt940CreditDebitMark string
+
+// MT940 debit or credit marks
+const (
+	mt940CreditMark         mt940CreditDebitMark = "C"
+	mt940DebitMark          mt940CreditDebitMark = "D"
+	mt940ReversalCreditMark mt940CreditDebitMark = "RC"
+	mt940ReversalDebitMark  mt940CreditDebitMark = "RD"
+)
+
+// MT940 field tags
+const (
+	mt940TransactionReferenceNumberTag = "20"
+	mt940AccountIdentificationTag      = "25"
+	mt940StatementNumberTag            = "28C"
+	mt940OpeningBalanceTag             = "60F"
+	mt940IntermediateOpeningBalanceTag = "60M"
+	mt940StatementLineTag              = "61"
+	mt940InformationToAccountOwnerTag  = "86"
+	mt940ClosingBalanceTag             = "62F"
+	mt940IntermediateClosingBalanceTag = "62M"
+)
+
+// mt940Data defines the structure of mt940 data
+type mt940Data struct {
+	statements []*mt940Statement
+}
+
+// mt940Statement defines the structure of mt940 statement
+type mt940Statement struct {
+	referenceNumber string
+	accountId       string
+	statementNumber string
+	openingBalance  *mt940Balance
+	closingBalance  *mt940Balance
+	transactions    []*mt940TransactionData
+}
+
+// mt940Balance defines the structure of mt940 opening or closing balance
+type mt940Balance struct {
+	creditDebitMark mt940CreditDebitMark
+	date            string
+	currency        string
+	amount          string
+}
+
+// mt940TransactionData defines the structure of mt940 statement line and its information to account owner
+type mt940TransactionData struct {
+	valueDate            string
+	entryDate            string
+	creditDebitMark      mt940CreditDebitMark
+	amount               string
+	transactionType      string
+	customerReference    string
+	bankReference        string
+	supplementaryDetails string
+	information          *mt940InformationToAccountOwner
+}
+
+// mt940InformationToAccountOwner defines the structure of mt940 information to account owner
+type mt940InformationToAccountOwner struct {
+	transactionCode     string
+	bookingText         string
+	purpose             string
+	counterpartyName    string
+	counterpartyAccount string
+	text                string
+}
@@ -0,0 +1,340 @@
+package mt940
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mt940MessageEndTag = "-"
+const mt940TextBlockHeader = "{4:"
+
+var mt940FieldPattern = regexp.MustCompile("^:([0-9]{2}[A-Z]?):(.*)$")
+var mt940BalancePattern = regexp.MustCompile("^(C|D)([0-9]{6})([A-Z]{3})([0-9]+,[0-9]*)$")
+var mt940StatementLinePattern = regexp.MustCompile("^([0-9]{6})([0-9]{4})?(RC|RD|C|D)([A-Z])?([0-9]+,[0-9]*)([SNF][A-Z0-9]{3})(.*)$")
+var mt940SepaPurposeKeyPattern = regexp.MustCompile("(EREF|KREF|MREF|CRED|DEBT|COAM|OAMT|SVWZ|ABWA|ABWE|IBAN|BIC)\\+")
+var mt940SlashCodePattern = regexp.MustCompile("/(EREF|CNTP|REMI|NAME|PURP|ORDP|BENM|MARF|CSID|IREF|BUSP|ULTC|ULTD|RTRN|ISDT|ACCW|BYOR|FEES|OCMT|TRCD)/")
+
+// mt940Field defines the structure of a field (tag and lines) in mt940 file
+type mt940Field struct {
+	tag        string
+	lines      []string
+	lineNumber int
+}
+
+// mt940DataReader defines the structure of mt940 data reader
+type mt940DataReader struct {
+	allLines []string
+}
+
+// read returns the imported mt940 data
+// Reference: https://www2.swift.com/knowledgecentre/publications/us9m_20230720/2.0?topic=mt940.htm
+func (r *mt940DataReader) read(ctx core.Context) (*mt940Data, error) {
+	if len(r.allLines) < 1 {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	allFields := r.readFields()
+	data := &mt940Data{}
+	var currentStatement *mt940Statement
+
+	for i := 0; i < len(allFields); i++ {
+		field := allFields[i]
+
+		if field.tag == mt940MessageEndTag {
+			if currentStatement != nil {
+				data.statements = append(data.statements, currentStatement)
+				currentStatement = nil
+			}
+
+			continue
+		}
+
+		if field.tag == mt940TransactionReferenceNumberTag {
+			if currentStatement != nil {
+				data.statements = append(data.statements, currentStatement)
+			}
+
+			currentStatement = &mt940Statement{
+				referenceNumber: strings.TrimSpace(field.lines[0]),
+			}
+
+			continue
+		}
+
+		if currentStatement == nil {
+			log.Errorf(ctx, "[mt940_data_reader.read] read field \":%s:\" in line#%d before transaction reference number field", field.tag, field.lineNumber)
+			return nil, errs.ErrInvalidMT940File
+		}
+
+		var err error
+
+		switch field.tag {
+		case mt940AccountIdentificationTag:
+			currentStatement.accountId = strings.TrimSpace(field.lines[0])
+		case mt940StatementNumberTag:
+			currentStatement.statementNumber = strings.TrimSpace(field.lines[0])
+		case mt940OpeningBalanceTag, mt940IntermediateOpeningBalanceTag:
+			currentStatement.openingBalance, err = r.parseBalance(ctx, field)
+		case mt940ClosingBalanceTag, mt940IntermediateClosingBalanceTag:
+			currentStatement.closingBalance, err = r.parseBalance(ctx, field)
+		case mt940StatementLineTag:
+			var transaction *mt940TransactionData
+			transaction, err = r.parseStatementLine(ctx, field)
+
+			if err == nil {
+				currentStatement.transactions = append(currentStatement.transactions, transaction)
+			}
+		case mt940InformationToAccountOwnerTag:
+			if len(currentStatement.transactions) > 0 && currentStatement.closingBalance == nil {
+				lastTransaction := currentStatement.transactions[len(currentStatement.transactions)-1]
+
+				if lastTransaction.information == nil {
+					lastTransaction.information = r.parseInformationToAccountOwner(field.lines)
+				}
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if currentStatement != nil {
+		data.statements = append(data.statements, currentStatement)
+	}
+
+	if len(data.statements) < 1 {
+		log.Errorf(ctx, "[mt940_data_reader.read] cannot find any statement in mt940 file")
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	return data, nil
+}
+
+// readFields returns all fields in the text blocks, the continuation lines are appended to the previous field
+func (r *mt940DataReader) readFields() []*mt940Field {
+	allFields := make([]*mt940Field, 0)
+	var currentField *mt940Field
+
+	for i := 0; i < len(r.allLines); i++ {
+		line := strings.TrimRight(r.allLines[i], " \t\r")
+
+		if len(line) < 1 {
+			continue
+		}
+
+		if line[0] == '{' { // block header, e.g. "{1:...}{2:...}{4:"
+			index := strings.Index(line, mt940TextBlockHeader)
+
+			if index < 0 {
+				continue
+			}
+
+			line = strings.TrimSpace(line[index+len(mt940TextBlockHeader):])
+
+			if len(line) < 1 {
+				continue
+			}
+		}
+
+		if line == mt940MessageEndTag || strings.HasPrefix(line, mt940MessageEndTag+"}") {
+			allFields = append(allFields, &mt940Field{
+				tag:        mt940MessageEndTag,
+				lineNumber: i + 1,
+			})
+			currentField = nil
+			continue
+		}
+
+		if matches := mt940FieldPattern.FindStringSubmatch(line); len(matches) == 3 {
+			currentField = &mt940Field{
+				tag:        matches[1],
+				lines:      []string{matches[2]},
+				lineNumber: i + 1,
+			}
+			allFields = append(allFields, currentField)
+			continue
+		}
+
+		if currentField != nil {
+			currentField.lines = append(currentField.lines, line)
+		}
+	}
+
+	return allFields
+}
+
+func (r *mt940DataReader) parseBalance(ctx core.Context, field *mt940Field) (*mt940Balance, error) {
+	matches := mt940BalancePattern.FindStringSubmatch(strings.TrimSpace(field.lines[0]))
+
+	if len(matches) != 5 {
+		log.Errorf(ctx, "[mt940_data_reader.parseBalance] cannot parse balance \"%s\" in line#%d", field.lines[0], field.lineNumber)
+		return nil, errs.ErrInvalidMT940File
+	}
+
+	return &mt940Balance{
+		creditDebitMark: mt940CreditDebitMark(matches[1]),
+		date:            matches[2],
+		currency:        matches[3],
+		amount:          matches[4],
+	}, nil
+}
+
+func (r *mt940DataReader) parseStatementLine(ctx core.Context, field *mt940Field) (*mt940TransactionData, error) {
+	matches := mt940StatementLinePattern.FindStringSubmatch(strings.TrimSpace(field.lines[0]))
+
+	if len(matches) != 8 {
+		log.Errorf(ctx, "[mt940_data_reader.parseStatementLine] cannot parse statement line \"%s\" in line#%d", field.lines[0], field.lineNumber)
+		return nil, errs.ErrInvalidMT940File
+	}
+
+	transaction := &mt940TransactionData{
+		valueDate:       matches[1],
+		entryDate:       matches[2],
+		creditDebitMark: mt940CreditDebitMark(matches[3]),
+		amount:          matches[5],
+		transactionType: matches[6],
+	}
+
+	references := strings.SplitN(matches[7], "//", 2)
+	transaction.customerReference = strings.TrimSpace(references[0])
+
+	if len(references) > 1 {
+		transaction.bankReference = strings.TrimSpace(references[1])
+	}
+
+	if len(field.lines) > 1 {
+		transaction.supplementaryDetails = strings.TrimSpace(strings.Join(field.lines[1:], " "))
+	}
+
+	return transaction, nil
+}
+
+// parseInformationToAccountOwner returns the information to account owner, which supports structured subfields (e.g. "166?00BOOKING TEXT?20PURPOSE?32NAME") or codes (e.g. "/NAME/NAME/REMI/PURPOSE")
+func (r *mt940DataReader) parseInformationToAccountOwner(lines []string) *mt940InformationToAccountOwner {
+	content := strings.Join(lines, "")
+	information := &mt940InformationToAccountOwner{}
+
+	if len(content) > 4 && utils.IsStringOnlyContainsDigits(content[0:3]) && !r.isAlphanumeric(content[3]) { // structured subfields
+		information.transactionCode = content[0:3]
+		separator := content[3]
+		subfields := strings.Split(content[4:], string(separator))
+		var purposes []string
+		var names []string
+
+		for i := 0; i < len(subfields); i++ {
+			subfield := subfields[i]
+
+			if len(subfield) < 2 || !utils.IsStringOnlyContainsDigits(subfield[0:2]) {
+				continue
+			}
+
+			code := subfield[0:2]
+			value := subfield[2:]
+
+			if code == "00" {
+				information.bookingText = strings.TrimSpace(value)
+			} else if ("20" <= code && code <= "29") || ("60" <= code && code <= "63") {
+				purposes = append(purposes, value)
+			} else if code == "31" {
+				information.counterpartyAccount = strings.TrimSpace(value)
+			} else if code == "32" || code == "33" {
+				names = append(names, value)
+			}
+		}
+
+		information.purpose = r.getSepaPurpose(strings.TrimSpace(strings.Join(purposes, "")))
+		information.counterpartyName = strings.TrimSpace(strings.Join(names, ""))
+	} else if allIndexes := mt940SlashCodePattern.FindAllStringSubmatchIndex(content, -1); len(allIndexes) > 0 && allIndexes[0][0] == 0 { // codes
+		for i := 0; i < len(allIndexes); i++ {
+			code := content[allIndexes[i][2]:allIndexes[i][3]]
+			valueEndIndex := len(content)
+
+			if i+1 < len(allIndexes) {
+				valueEndIndex = allIndexes[i+1][0]
+			}
+
+			value := strings.Trim(content[allIndexes[i][1]:valueEndIndex], "/ ")
+
+			if code == "NAME" && information.counterpartyName == "" {
+				information.counterpartyName = value
+			} else if code == "CNTP" { // account/bic/name/city
+				items := strings.Split(value, "/")
+				information.counterpartyAccount = items[0]
+
+				if len(items) > 2 && information.counterpartyName == "" {
+					information.counterpartyName = strings.TrimSpace(items[2])
+				}
+			} else if code == "REMI" {
+				information.purpose = strings.TrimSpace(strings.TrimPrefix(value, "USTD//"))
+			}
+		}
+	} else {
+		information.text = strings.TrimSpace(strings.Join(lines, " "))
+	}
+
+	return information
+}
+
+// getSepaPurpose returns the remittance information (SVWZ+) in the purpose if it contains sepa purpose keys, or returns the original purpose
+func (r *mt940DataReader) getSepaPurpose(purpose string) string {
+	allIndexes := mt940SepaPurposeKeyPattern.FindAllStringSubmatchIndex(purpose, -1)
+
+	for i := 0; i < len(allIndexes); i++ {
+		if purpose[allIndexes[i][2]:allIndexes[i][3]] != "SVWZ" {
+			continue
+		}
+
+		valueEndIndex := len(purpose)
+
+		if i+1 < len(allIndexes) {
+			valueEndIndex = allIndexes[i+1][0]
+		}
+
+		return strings.TrimSpace(purpose[allIndexes[i][1]:valueEndIndex])
+	}
+
+	return purpose
+}
+
+func (r *mt940DataReader) isAlphanumeric(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func createNewMT940DataReader(data []byte) (*mt940DataReader, error) {
+	var reader *transform.Reader
+
+	if utf8.Valid(data) {
+		fallback := unicode.UTF8.NewDecoder()
+		reader = transform.NewReader(bytes.NewReader(data), unicode.BOMOverride(fallback))
+	} else { // mt940 files are usually encoded in latin-1 if they are not utf-8
+		reader = transform.NewReader(bytes.NewReader(data), charmap.ISO8859_1.NewDecoder())
+	}
+
+	scanner := bufio.NewScanner(reader)
+	allLines := make([]string, 0)
+
+	for scanner.Scan() {
+		allLines = append(allLines, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &mt940DataReader{
+		allLines: allLines,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package mt940
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+func TestMT940DataReaderRead(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewMT940DataReader([]byte(
+		":20:STMT001\n" +
+			":25:DE001\n" +
+			":28C:00001/001\n" +
+			":60F:C240831EUR100,00\n" +
+			":61:2409010901DR12,34NTRFCUSTREF//BANKREF\n" +
+			"Supplementary details\n" +
+			":86:Payment\n" +
+			":62F:C240901EUR87,66\n" +
+			":86:Statement information\n" +
+			"-"))
+	assert.Nil(t, err)
+
+	data, err := reader.read(context)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(data.statements))
+
+	statement := data.statements[0]
+	assert.Equal(t, "STMT001", statement.referenceNumber)
+	assert.Equal(t, "DE001", statement.accountId)
+	assert.Equal(t, "00001/001", statement.statementNumber)
+	assert.Equal(t, mt940CreditMark, statement.openingBalance.creditDebitMark)
+	assert.Equal(t, "240831", statement.openingBalance.date)
+	assert.Equal(t, "EUR", statement.openingBalance.currency)
+	assert.Equal(t, "100,00", statement.openingBalance.amount)
+	assert.Equal(t, "87,66", statement.closingBalance.amount)
+	assert.Equal(t, 1, len(statement.transactions))
+
+	transaction := statement.transactions[0]
+	assert.Equal(t, "240901", transaction.valueDate)
+	assert.Equal(t, "0901", transaction.entryDate)
+	assert.Equal(t, mt940DebitMark, transaction.creditDebitMark)
+	assert.Equal(t, "12,34", transaction.amount)
+	assert.Equal(t, "NTRF", transaction.transactionType)
+	assert.Equal(t, "CUSTREF", transaction.customerReference)
+	assert.Equal(t, "BANKREF", transaction.bankReference)
+	assert.Equal(t, "Supplementary details", transaction.supplementaryDetails)
+	assert.Equal(t, "Payment", transaction.information.text)
+}
+
+func TestMT940DataReaderParseInformationToAccountOwner_StructuredSubfields(t *testing.T) {
+	reader := &mt940DataReader{}
+	information := reader.parseInformationToAccountOwner([]string{
+		"166?00GUTSCHRIFT?109075?20EREF+E2E001?21SVWZ+Salary Sep",
+		"?22tember?30BANKDEFF?31DE002?32ACME?33 Corp",
+	})
+
+	assert.Equal(t, "166", information.transactionCode)
+	assert.Equal(t, "GUTSCHRIFT", information.bookingText)
+	assert.Equal(t, "Salary September", information.purpose)
+	assert.Equal(t, "ACME Corp", information.counterpartyName)
+	assert.Equal(t, "DE002", information.counterpartyAccount)
+	assert.Equal(t, "", information.text)
+}
+
+func TestMT940DataReaderParseInformationToAccountOwner_Codes(t *testing.T) {
+	reader := &mt940DataReader{}
+	information := reader.parseInformationToAccountOwner([]string{
+		"/EREF/NOTPROVIDED//CNTP/NL003/INGBNL2A/John Doe/Amsterdam///REMI/US",
+		"TD//Rent September/",
+	})
+
+	assert.Equal(t, "John Doe", information.counterpartyName)
+	assert.Equal(t, "NL003", information.counterpartyAccount)
+	assert.Equal(t, "Rent September", information.purpose)
+
+	information = reader.parseInformationToAccountOwner([]string{
+		"/NAME/Jane Doe/REMI/Invoice 1",
+	})
+
+	assert.Equal(t, "Jane Doe", information.counterpartyName)
+	assert.Equal(t, "Invoice 1", information.purpose)
+}
+
+func TestMT940DataReaderRead_Latin1Encoding(t *testing.T) {
+	context := core.NewNullContext()
+	reader, err := createNewMT940DataReader([]byte(
+		":20:STMT001\n" +
+			":25:DE001\n" +
+			":61:2409010901C1,00NTRFNONREF\n" +
+			":86:M\xfcller\n" +
+			"-"))
+	assert.Nil(t, err)
+
+	data, err := reader.read(context)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Müller", data.statements[0].transactions[0].information.text)
+}
+
+func TestCreateNewMT940DataReader_LineTooLong(t *testing.T) {
+	_, err := createNewMT940DataReader([]byte(
+		":20:STMT001\n" +
+			":86:" + strings.Repeat("A", bufio.MaxScanTokenSize) + "\n" +
+			"-"))
+
+	assert.Equal(t, bufio.ErrTooLong, err)
+}
@@ -0,0 +1,222 @@
+package mt940
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestMT940TransactionDataFileParseImportedData_MinimumValidData(t *testing.T) {
+	converter := MT940TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(
+		":20:STMT001\n"+
+			":25:DE001\n"+
+			":28C:1/1\n"+
+			":60F:C240831EUR100,00\n"+
+			":61:2409010901C123,45NTRFNONREF\n"+
+			":61:2409020902D0,12NTRFNONREF\n"+
+			":61:240903D1,NTRFNONREF\n"+
+			":62F:C240903EUR222,33\n"+
+			"-"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 0, len(allNewSubTransferCategories))
+	assert.Equal(t, 0, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "DE001", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "EUR", allNewTransactions[0].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725235200), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(12), allNewTransactions[1].Amount)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725321600), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int64(100), allNewTransactions[2].Amount)
+
+	assert.Equal(t, "DE001", allNewAccounts[0].Name)
+	assert.Equal(t, "EUR", allNewAccounts[0].Currency)
+}
+
+func TestMT940TransactionDataFileParseImportedData_MultipleStatements(t *testing.T) {
+	converter := MT940TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"{1:F01BANKDEFFXXXX0000000000}{2:I940BANKDEFFXXXXN}{4:\n"+
+			":20:STMT001\n"+
+			":25:DE001\n"+
+			":28C:1/1\n"+
+			":60F:C240831EUR100,00\n"+
+			":61:2409010901C10,00NTRFNONREF\n"+
+			":62F:C240901EUR110,00\n"+
+			"-}\n"+
+			"{1:F01BANKDEFFXXXX0000000000}{2:I940BANKDEFFXXXXN}{4:\n"+
+			":20:STMT002\n"+
+			":25:US001\n"+
+			":28C:1/1\n"+
+			":60F:D240831USD5,00\n"+
+			":61:2409020902RD1,00NTRFNONREF\n"+
+			":61:2409020902RC2,00NTRFNONREF\n"+
+			":62F:D240902USD6,00\n"+
+			"-}\n"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, "DE001", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "EUR", allNewTransactions[0].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, int64(100), allNewTransactions[1].Amount)
+	assert.Equal(t, "US001", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[1].OriginalSourceAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(200), allNewTransactions[2].Amount)
+	assert.Equal(t, "US001", allNewTransactions[2].OriginalSourceAccountName)
+}
+
+func TestMT940TransactionDataFileParseImportedData_ParseEntryDateAcrossYearBoundary(t *testing.T) {
+	converter := MT940TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		":20:STMT001\n"+
+			":25:DE001\n"+
+			":61:2412310101C1,00NTRFNONREF\n"+
+			":61:2501011231C1,00NTRFNONREF\n"+
+			"-"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, int64(1735603200), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int64(1735689600), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+}
+
+func TestMT940TransactionDataFileParseImportedData_ParseDescription(t *testing.T) {
+	converter := MT940TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		":20:STMT001\n"+
+			":25:DE001\n"+
+			":60F:C240831EUR100,00\n"+
+			":61:2409010901D12,34NDDTNONREF//BANKREF\n"+
+			":86:105?00SEPA-LASTSCHRIFT?20EREF+123?21SVWZ+Invoice 2024-\n"+
+			"?2209 Customer 1?30BANKDEFF?31DE002?32Power Com\n"+
+			"?33pany\n"+
+			":61:2409020902C10,00NTRFNONREF\n"+
+			":86:/EREF/NOTPROVIDED//CNTP/NL003/INGBNL2A/John Doe/Amsterdam///REMI/USTD//Rent/\n"+
+			":61:2409030903D1,00NCHGNONREF\n"+
+			":86:Account fee\n"+
+			"September\n"+
+			":61:2409040904D1,00NCHGNONREF\n"+
+			"Supplementary details\n"+
+			":62F:C240904EUR95,66\n"+
+			"-"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, "Power Company - Invoice 2024-09 Customer 1", allNewTransactions[0].Comment)
+	assert.Equal(t, "John Doe - Rent", allNewTransactions[1].Comment)
+	assert.Equal(t, "Account fee September", allNewTransactions[2].Comment)
+	assert.Equal(t, "Supplementary details", allNewTransactions[3].Comment)
+}
+
+func TestMT940TransactionDataFileParseImportedData_ClosingBalanceMismatch(t *testing.T) {
+	converter := MT940TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		":20:STMT001\n"+
+			":25:DE001\n"+
+			":60F:C240831EUR100,00\n"+
+			":61:2409010901C10,00NTRFNONREF\n"+
+			":62F:C240901EUR100,00\n"+
+			"-"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMT940ClosingBalanceMismatch.Message)
+}
+
+func TestMT940TransactionDataFileParseImportedData_InvalidData(t *testing.T) {
+	converter := MT940TransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	// Missing transaction reference number
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		":25:DE001\n"+
+			":61:2409010901C10,00NTRFNONREF\n"+
+			"-"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidMT940File.Message)
+
+	// Invalid statement line
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		":20:STMT001\n"+
+			":25:DE001\n"+
+			":61:2409010901X10,00NTRFNONREF\n"+
+			"-"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidMT940File.Message)
+
+	// Invalid balance
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(
+		":20:STMT001\n"+
+			":25:DE001\n"+
+			":60F:C240831EUR\n"+
+			"-"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrInvalidMT940File.Message)
+
+	// Empty file
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte(""), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrNotFoundTransactionDataInFile.Message)
+}
@@ -0,0 +1,319 @@
+package mt940
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const mt940DateFormat = "060102"
+const mt940TransactionTimeFormat = "2006-01-02 15:04:05"
+
+var mt940TransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME:     true,
+	datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE:     true,
+	datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY:         true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME:         true,
+	datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY:     true,
+	datatable.TRANSACTION_DATA_TABLE_AMOUNT:               true,
+	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME: true,
+	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:          true,
+}
+
+// mt940TransactionDataTable defines the structure of mt940 transaction data table
+type mt940TransactionDataTable struct {
+	allData []*mt940TransactionData
+	// statement of each transaction data, which has the same index of allData
+	allStatements []*mt940Statement
+}
+
+// mt940TransactionDataRow defines the structure of mt940 transaction data row
+type mt940TransactionDataRow struct {
+	dataTable  *mt940TransactionDataTable
+	data       *mt940TransactionData
+	finalItems map[datatable.TransactionDataTableColumn]string
+}
+
+// mt940TransactionDataRowIterator defines the structure of mt940 transaction data row iterator
+type mt940TransactionDataRowIterator struct {
+	dataTable    *mt940TransactionDataTable
+	currentIndex int
+}
+
+// HasColumn returns whether the transaction data table has specified column
+func (t *mt940TransactionDataTable) HasColumn(column datatable.TransactionDataTableColumn) bool {
+	_, exists := mt940TransactionSupportedColumns[column]
+	return exists
+}
+
+// TransactionRowCount returns the total count of transaction data row
+func (t *mt940TransactionDataTable) TransactionRowCount() int {
+	return len(t.allData)
+}
+
+// TransactionRowIterator returns the iterator of transaction data row
+func (t *mt940TransactionDataTable) TransactionRowIterator() datatable.TransactionDataRowIterator {
+	return &mt940TransactionDataRowIterator{
+		dataTable:    t,
+		currentIndex: -1,
+	}
+}
+
+// IsValid returns whether this row is valid data for importing
+func (r *mt940TransactionDataRow) IsValid() bool {
+	return true
+}
+
+// GetData returns the data in the specified column type
+func (r *mt940TransactionDataRow) GetData(column datatable.TransactionDataTableColumn) string {
+	_, exists := mt940TransactionSupportedColumns[column]
+
+	if exists {
+		return r.finalItems[column]
+	}
+
+	return ""
+}
+
+// HasNext returns whether the iterator does not reach the end
+func (t *mt940TransactionDataRowIterator) HasNext() bool {
+	return t.currentIndex+1 < len(t.dataTable.allData)
+}
+
+// Next returns the next imported data row
+func (t *mt940TransactionDataRowIterator) Next(ctx core.Context, user *models.User) (daraRow datatable.TransactionDataRow, err error) {
+	if t.currentIndex+1 >= len(t.dataTable.allData) {
+		return nil, nil
+	}
+
+	t.currentIndex++
+
+	data := t.dataTable.allData[t.currentIndex]
+	statement := t.dataTable.allStatements[t.currentIndex]
+	rowItems, err := t.parseTransaction(ctx, user, statement, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &mt940TransactionDataRow{
+		dataTable:  t.dataTable,
+		data:       data,
+		finalItems: rowItems,
+	}, nil
+}
+
+func (t *mt940TransactionDataRowIterator) parseTransaction(ctx core.Context, user *models.User, statement *mt940Statement, mt940Transaction *mt940TransactionData) (map[datatable.TransactionDataTableColumn]string, error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(mt940TransactionSupportedColumns))
+
+	transactionTime, err := getMT940TransactionTime(mt940Transaction)
+
+	if err != nil {
+		log.Errorf(ctx, "[mt940_transaction_table.parseTransaction] cannot parse transaction date \"%s\" (entry date \"%s\"), because %s", mt940Transaction.valueDate, mt940Transaction.entryDate, err.Error())
+		return nil, errs.ErrTransactionTimeInvalid
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime.Format(mt940TransactionTimeFormat)
+
+	if statement.accountId == "" {
+		return nil, errs.ErrMissingAccountData
+	}
+
+	amount, err := parseMT940Amount(mt940Transaction.amount)
+
+	if err != nil {
+		log.Errorf(ctx, "[mt940_transaction_table.parseTransaction] cannot parse amount \"%s\", because %s", mt940Transaction.amount, err.Error())
+		return nil, errs.ErrAmountInvalid
+	}
+
+	currency := getMT940StatementCurrency(statement)
+
+	if currency == "" { // use default currency if the statement has no balance
+		currency = user.DefaultCurrency
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = statement.accountId
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY] = currency
+	data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+
+	switch mt940Transaction.creditDebitMark {
+	case mt940CreditMark, mt940ReversalDebitMark:
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = mt940TransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+	case mt940DebitMark, mt940ReversalCreditMark:
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = mt940TransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+	default:
+		log.Errorf(ctx, "[mt940_transaction_table.parseTransaction] cannot parse debit or credit mark \"%s\"", mt940Transaction.creditDebitMark)
+		return nil, errs.ErrTransactionTypeInvalid
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = t.getDescription(mt940Transaction)
+
+	return data, nil
+}
+
+func (t *mt940TransactionDataRowIterator) getDescription(mt940Transaction *mt940TransactionData) string {
+	information := mt940Transaction.information
+
+	if information == nil {
+		return mt940Transaction.supplementaryDetails
+	}
+
+	if information.text != "" {
+		return information.text
+	}
+
+	purpose := information.purpose
+
+	if purpose == "" {
+		purpose = information.bookingText
+	}
+
+	if information.counterpartyName != "" && purpose != "" {
+		return information.counterpartyName + " - " + purpose
+	} else if information.counterpartyName != "" {
+		return information.counterpartyName
+	} else if purpose != "" {
+		return purpose
+	}
+
+	return mt940Transaction.supplementaryDetails
+}
+
+func createNewMT940TransactionDataTable(ctx core.Context, data *mt940Data) (*mt940TransactionDataTable, error) {
+	if data == nil {
+		return nil, errs.ErrNotFoundTransactionDataInFile
+	}
+
+	allData := make([]*mt940TransactionData, 0)
+	allStatements := make([]*mt940Statement, 0)
+
+	for i := 0; i < len(data.statements); i++ {
+		statement := data.statements[i]
+
+		if err := checkMT940StatementClosingBalance(ctx, statement); err != nil {
+			return nil, err
+		}
+
+		for j := 0; j < len(statement.transactions); j++ {
+			allData = append(allData, statement.transactions[j])
+			allStatements = append(allStatements, statement)
+		}
+	}
+
+	return &mt940TransactionDataTable{
+		allData:       allData,
+		allStatements: allStatements,
+	}, nil
+}
+
+// checkMT940StatementClosingBalance returns error if the opening balance plus all transactions in the statement does not equal to the closing balance
+func checkMT940StatementClosingBalance(ctx core.Context, statement *mt940Statement) error {
+	if statement.openingBalance == nil || statement.closingBalance == nil {
+		return nil
+	}
+
+	openingBalance, err := parseMT940BalanceAmount(statement.openingBalance)
+
+	if err != nil {
+		log.Errorf(ctx, "[mt940_transaction_table.checkMT940StatementClosingBalance] cannot parse opening balance \"%s\" of statement \"%s\", because %s", statement.openingBalance.amount, statement.referenceNumber, err.Error())
+		return errs.ErrAmountInvalid
+	}
+
+	closingBalance, err := parseMT940BalanceAmount(statement.closingBalance)
+
+	if err != nil {
+		log.Errorf(ctx, "[mt940_transaction_table.checkMT940StatementClosingBalance] cannot parse closing balance \"%s\" of statement \"%s\", because %s", statement.closingBalance.amount, statement.referenceNumber, err.Error())
+		return errs.ErrAmountInvalid
+	}
+
+	computedBalance := openingBalance
+
+	for i := 0; i < len(statement.transactions); i++ {
+		transaction := statement.transactions[i]
+		amount, err := parseMT940Amount(transaction.amount)
+
+		if err != nil {
+			log.Errorf(ctx, "[mt940_transaction_table.checkMT940StatementClosingBalance] cannot parse amount \"%s\" of statement \"%s\", because %s", transaction.amount, statement.referenceNumber, err.Error())
+			return errs.ErrAmountInvalid
+		}
+
+		if transaction.creditDebitMark == mt940CreditMark || transaction.creditDebitMark == mt940ReversalDebitMark {
+			computedBalance += amount
+		} else {
+			computedBalance -= amount
+		}
+	}
+
+	if computedBalance != closingBalance {
+		log.Errorf(ctx, "[mt940_transaction_table.checkMT940StatementClosingBalance] the computed closing balance \"%s\" does not match the closing balance \"%s\" of statement \"%s\"", utils.FormatAmount(computedBalance), utils.FormatAmount(closingBalance), statement.referenceNumber)
+		return errs.ErrMT940ClosingBalanceMismatch
+	}
+
+	return nil
+}
+
+// getMT940TransactionTime returns the entry date of the transaction, or returns the value date if the entry date is missing
+func getMT940TransactionTime(mt940Transaction *mt940TransactionData) (time.Time, error) {
+	valueDate, err := time.Parse(mt940DateFormat, mt940Transaction.valueDate)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if mt940Transaction.entryDate == "" {
+		return valueDate, nil
+	}
+
+	// the entry date only contains month and day, so use the year of value date, and adjust it if the entry date is near the year boundary
+	entryDate, err := time.Parse(mt940DateFormat, valueDate.Format("06")+mt940Transaction.entryDate)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if entryDate.Sub(valueDate) > 180*24*time.Hour {
+		entryDate = entryDate.AddDate(-1, 0, 0)
+	} else if valueDate.Sub(entryDate) > 180*24*time.Hour {
+		entryDate = entryDate.AddDate(1, 0, 0)
+	}
+
+	return entryDate, nil
+}
+
+func getMT940StatementCurrency(statement *mt940Statement) string {
+	if statement.openingBalance != nil {
+		return statement.openingBalance.currency
+	}
+
+	if statement.closingBalance != nil {
+		return statement.closingBalance.currency
+	}
+
+	return ""
+}
+
+func parseMT940BalanceAmount(balance *mt940Balance) (int64, error) {
+	amount, err := parseMT940Amount(balance.amount)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if balance.creditDebitMark == mt940DebitMark {
+		amount = -amount
+	}
+
+	return amount, nil
+}
+
+// parseMT940Amount returns the amount in cents, the amount in mt940 file uses comma as decimal separator (e.g. "123,45" or "123,")
+func parseMT940Amount(amount string) (int64, error) {
+	amount = strings.TrimSuffix(strings.Replace(strings.TrimSpace(amount), ",", ".", 1), ".")
+	return utils.ParseAmount(amount)
+}
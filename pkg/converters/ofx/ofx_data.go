@@ -71,10 +71,11 @@ var ofxTransactionTypeMapping = map[ofxTransactionType]models.TransactionType{
 
 // ofxFile represents the struct of open financial exchange (ofx) file
 type ofxFile struct {
-	XMLName                     xml.Name `xml:"OFX"`
-	FileHeader                  *ofxFileHeader
-	BankMessageResponseV1       *ofxBankMessageResponseV1       `xml:"BANKMSGSRSV1"`
-	CreditCardMessageResponseV1 *ofxCreditCardMessageResponseV1 `xml:"CREDITCARDMSGSRSV1"`
+	XMLName                     xml.Name                        `xml:"OFX"`
+	FileHeader                  *ofxFileHeader                  `xml:"-"`
+	SignOnMessageResponseV1     *ofxSignOnMessageResponseV1     `xml:"SIGNONMSGSRSV1,omitempty"`
+	BankMessageResponseV1       *ofxBankMessageResponseV1       `xml:"BANKMSGSRSV1,omitempty"`
+	CreditCardMessageResponseV1 *ofxCreditCardMessageResponseV1 `xml:"CREDITCARDMSGSRSV1,omitempty"`
 }
 
 // ofxFileHeader represents the struct of open financial exchange (ofx) file header
@@ -86,23 +87,45 @@ type ofxFileHeader struct {
 	NewFileUid            string
 }
 
+// ofxSignOnMessageResponseV1 represents the struct of open financial exchange (ofx) sign on message response v1
+type ofxSignOnMessageResponseV1 struct {
+	SignOnResponse *ofxSignOnResponse `xml:"SONRS"`
+}
+
+// ofxSignOnResponse represents the struct of open financial exchange (ofx) sign on response
+type ofxSignOnResponse struct {
+	Status     *ofxStatus `xml:"STATUS"`
+	ServerDate string     `xml:"DTSERVER"`
+	Language   string     `xml:"LANGUAGE"`
+}
+
+// ofxStatus represents the struct of open financial exchange (ofx) status
+type ofxStatus struct {
+	Code     string `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
 // ofxBankMessageResponseV1 represents the struct of open financial exchange (ofx) bank message response v1
 type ofxBankMessageResponseV1 struct {
-	StatementTransactionResponse *ofxBankStatementTransactionResponse `xml:"STMTTRNRS"`
+	StatementTransactionResponses []*ofxBankStatementTransactionResponse `xml:"STMTTRNRS"`
 }
 
 // ofxCreditCardMessageResponseV1 represents the struct of open financial exchange (ofx) credit card message response v1
 type ofxCreditCardMessageResponseV1 struct {
-	StatementTransactionResponse *ofxCreditCardStatementTransactionResponse `xml:"CCSTMTTRNRS"`
+	StatementTransactionResponses []*ofxCreditCardStatementTransactionResponse `xml:"CCSTMTTRNRS"`
 }
 
 // ofxBankStatementTransactionResponse represents the struct of open financial exchange (ofx) bank statement transaction response
 type ofxBankStatementTransactionResponse struct {
+	TransactionUid    string                    `xml:"TRNUID,omitempty"`
+	Status            *ofxStatus                `xml:"STATUS,omitempty"`
 	StatementResponse *ofxBankStatementResponse `xml:"STMTRS"`
 }
 
 // ofxCreditCardStatementTransactionResponse represents the struct of open financial exchange (ofx) credit card statement transaction response
 type ofxCreditCardStatementTransactionResponse struct {
+	TransactionUid    string                          `xml:"TRNUID,omitempty"`
+	Status            *ofxStatus                      `xml:"STATUS,omitempty"`
 	StatementResponse *ofxCreditCardStatementResponse `xml:"CCSTMTRS"`
 }
 
@@ -111,6 +134,7 @@ type ofxBankStatementResponse struct {
 	DefaultCurrency string                  `xml:"CURDEF"`
 	AccountFrom     *ofxBankAccount         `xml:"BANKACCTFROM"`
 	TransactionList *ofxBankTransactionList `xml:"BANKTRANLIST"`
+	LedgerBalance   *ofxBalance             `xml:"LEDGERBAL,omitempty"`
 }
 
 // ofxCreditCardStatementResponse represents the struct of open financial exchange (ofx) credit card statement response
@@ -118,21 +142,28 @@ type ofxCreditCardStatementResponse struct {
 	DefaultCurrency string                        `xml:"CURDEF"`
 	AccountFrom     *ofxCreditCardAccount         `xml:"CCACCTFROM"`
 	TransactionList *ofxCreditCardTransactionList `xml:"BANKTRANLIST"`
+	LedgerBalance   *ofxBalance                   `xml:"LEDGERBAL,omitempty"`
+}
+
+// ofxBalance represents the struct of open financial exchange (ofx) balance
+type ofxBalance struct {
+	Amount   string `xml:"BALAMT"`
+	AsOfDate string `xml:"DTASOF"`
 }
 
 // ofxBankAccount represents the struct of open financial exchange (ofx) bank account
 type ofxBankAccount struct {
 	BankId      string         `xml:"BANKID"`
-	BranchId    string         `xml:"BRANCHID"`
+	BranchId    string         `xml:"BRANCHID,omitempty"`
 	AccountId   string         `xml:"ACCTID"`
 	AccountType ofxAccountType `xml:"ACCTTYPE"`
-	AccountKey  string         `xml:"ACCTKEY"`
+	AccountKey  string         `xml:"ACCTKEY,omitempty"`
 }
 
 // ofxCreditCardAccount represents the struct of open financial exchange (ofx) credit card account
 type ofxCreditCardAccount struct {
 	AccountId  string `xml:"ACCTID"`
-	AccountKey string `xml:"ACCTKEY"`
+	AccountKey string `xml:"ACCTKEY,omitempty"`
 }
 
 // ofxBankTransactionList represents the struct of open financial exchange (ofx) bank transaction list
@@ -151,27 +182,27 @@ type ofxCreditCardTransactionList struct {
 
 // ofxBaseStatementTransaction represents the struct of open financial exchange (ofx) base statement transaction
 type ofxBaseStatementTransaction struct {
-	TransactionId    string             `xml:"FITID"`
 	TransactionType  ofxTransactionType `xml:"TRNTYPE"`
 	PostedDate       string             `xml:"DTPOSTED"`
 	Amount           string             `xml:"TRNAMT"`
-	Name             string             `xml:"NAME"`
-	Payee            *ofxPayee          `xml:"PAYEE"`
-	Memo             string             `xml:"MEMO"`
-	Currency         string             `xml:"CURRENCY"`
-	OriginalCurrency string             `xml:"ORIGCURRENCY"`
+	TransactionId    string             `xml:"FITID"`
+	Name             string             `xml:"NAME,omitempty"`
+	Payee            *ofxPayee          `xml:"PAYEE,omitempty"`
+	Memo             string             `xml:"MEMO,omitempty"`
+	Currency         string             `xml:"CURRENCY,omitempty"`
+	OriginalCurrency string             `xml:"ORIGCURRENCY,omitempty"`
 }
 
 // ofxBankStatementTransaction represents the struct of open financial exchange (ofx) bank statement transaction
 type ofxBankStatementTransaction struct {
 	ofxBaseStatementTransaction
-	AccountTo *ofxBankAccount `xml:"BANKACCTTO"`
+	AccountTo *ofxBankAccount `xml:"BANKACCTTO,omitempty"`
 }
 
 // ofxCreditCardStatementTransaction represents the struct of open financial exchange (ofx) credit card statement transaction
 type ofxCreditCardStatementTransaction struct {
 	ofxBaseStatementTransaction
-	AccountTo *ofxCreditCardAccount `xml:"CCACCTTO"`
+	AccountTo *ofxCreditCardAccount `xml:"CCACCTTO,omitempty"`
 }
 
 // ofxPayee represents the struct of open financial exchange (ofx) payee info
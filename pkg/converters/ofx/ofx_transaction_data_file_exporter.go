@@ -0,0 +1,303 @@
+package ofx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ofxExportedFileHeader = "<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n" +
+	"<?OFX OFXHEADER=\"200\" VERSION=\"220\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n"
+
+const ofxExportedBankId = "0"
+const ofxExportedStatusSuccessCode = "0"
+const ofxExportedStatusInfoSeverity = "INFO"
+const ofxExportedLanguage = "ENG"
+const ofxExportedNameMaxLength = 32
+
+var ofxAccountTypeMapping = map[models.AccountCategory]ofxAccountType{
+	models.ACCOUNT_CATEGORY_CASH:                   ofxCheckingAccount,
+	models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT:       ofxCheckingAccount,
+	models.ACCOUNT_CATEGORY_VIRTUAL:                ofxCheckingAccount,
+	models.ACCOUNT_CATEGORY_DEBT:                   ofxLineOfCreditAccount,
+	models.ACCOUNT_CATEGORY_RECEIVABLES:            ofxCheckingAccount,
+	models.ACCOUNT_CATEGORY_INVESTMENT:             ofxMoneyMarketAccount,
+	models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT:        ofxSavingsAccount,
+	models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT: ofxCertificateOfDepositAccount,
+}
+
+// ofxTransactionDataExporter defines the structure of open financial exchange (ofx) file exporter for transaction data
+type ofxTransactionDataExporter struct {
+}
+
+// ofxExportedStatement defines the structure of the statement of one account in exported open financial exchange (ofx) file
+type ofxExportedStatement struct {
+	account      *models.Account
+	transactions []*ofxBaseStatementTransaction
+	accountsTo   []*models.Account
+	startTime    int64
+	endTime      int64
+}
+
+// Initialize a open financial exchange (ofx) transaction data exporter singleton instance
+var (
+	OFXTransactionDataExporter = &ofxTransactionDataExporter{}
+)
+
+// ToExportedContent returns the exported open financial exchange (ofx) 2.x file content, each account has its own statement
+func (e *ofxTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64]models.TransactionSplitSlice) ([]byte, error) {
+	statements := make(map[int64]*ofxExportedStatement)
+
+	// the transactions are sorted by transaction time in descending order, but the transactions in ofx file should be in ascending order
+	for i := len(transactions) - 1; i >= 0; i-- {
+		transaction := transactions[i]
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		account, exists := accountMap[transaction.AccountId]
+
+		if !exists {
+			log.Warnf(ctx, "[ofx_transaction_data_file_exporter.ToExportedContent] cannot find account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.AccountId, transaction.TransactionId, uid)
+			continue
+		}
+
+		unixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		postedDate := e.formatDateTime(unixTime, transaction.TimezoneUtcOffset)
+		name, memo := e.getNameAndMemo(transaction.Comment)
+
+		ofxTransaction := &ofxBaseStatementTransaction{
+			PostedDate:    postedDate,
+			TransactionId: utils.Int64ToString(transaction.TransactionId),
+			Name:          name,
+			Memo:          memo,
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+			ofxTransaction.TransactionType = ofxDepositTransaction
+			ofxTransaction.Amount = utils.FormatAmount(transaction.Amount)
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+			ofxTransaction.TransactionType = ofxGenericDebitTransaction
+			ofxTransaction.Amount = utils.FormatAmount(-transaction.Amount)
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			relatedAccount, exists := accountMap[transaction.RelatedAccountId]
+
+			if !exists {
+				log.Warnf(ctx, "[ofx_transaction_data_file_exporter.ToExportedContent] cannot find related account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.RelatedAccountId, transaction.TransactionId, uid)
+				continue
+			}
+
+			ofxTransaction.TransactionType = ofxTransferTransaction
+			ofxTransaction.Amount = utils.FormatAmount(-transaction.Amount)
+
+			// the transfer in transaction has the same transaction id as the transfer out transaction, so that it can be merged when importing
+			relatedOFXTransaction := &ofxBaseStatementTransaction{
+				TransactionType: ofxTransferTransaction,
+				PostedDate:      postedDate,
+				Amount:          utils.FormatAmount(transaction.RelatedAccountAmount),
+				TransactionId:   ofxTransaction.TransactionId,
+				Name:            name,
+				Memo:            memo,
+			}
+
+			e.getOrCreateStatement(statements, relatedAccount).appendTransaction(relatedOFXTransaction, nil, unixTime)
+			e.getOrCreateStatement(statements, account).appendTransaction(ofxTransaction, relatedAccount, unixTime)
+			continue
+		} else if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+			ofxTransaction.TransactionType = ofxOtherTransaction
+			ofxTransaction.Amount = utils.FormatAmount(transaction.Amount)
+		} else {
+			log.Warnf(ctx, "[ofx_transaction_data_file_exporter.ToExportedContent] cannot export transaction \"id:%d\" for user \"uid:%d\", because type \"%d\" is invalid", transaction.TransactionId, uid, transaction.Type)
+			return nil, errs.ErrTransactionTypeInvalid
+		}
+
+		e.getOrCreateStatement(statements, account).appendTransaction(ofxTransaction, nil, unixTime)
+	}
+
+	file := e.buildOFXFile(statements)
+	content, err := xml.MarshalIndent(file, "", "  ")
+
+	if err != nil {
+		log.Errorf(ctx, "[ofx_transaction_data_file_exporter.ToExportedContent] failed to marshal ofx file for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrOperationFailed
+	}
+
+	return append([]byte(ofxExportedFileHeader), content...), nil
+}
+
+func (e *ofxTransactionDataExporter) getOrCreateStatement(statements map[int64]*ofxExportedStatement, account *models.Account) *ofxExportedStatement {
+	statement, exists := statements[account.AccountId]
+
+	if !exists {
+		statement = &ofxExportedStatement{
+			account:      account,
+			transactions: make([]*ofxBaseStatementTransaction, 0),
+			accountsTo:   make([]*models.Account, 0),
+		}
+
+		statements[account.AccountId] = statement
+	}
+
+	return statement
+}
+
+func (e *ofxTransactionDataExporter) buildOFXFile(statements map[int64]*ofxExportedStatement) *ofxFile {
+	now := time.Now().Unix()
+	accountIds := make([]int64, 0, len(statements))
+
+	for accountId := range statements {
+		accountIds = append(accountIds, accountId)
+	}
+
+	sort.Slice(accountIds, func(i, j int) bool {
+		return accountIds[i] < accountIds[j]
+	})
+
+	file := &ofxFile{
+		SignOnMessageResponseV1: &ofxSignOnMessageResponseV1{
+			SignOnResponse: &ofxSignOnResponse{
+				Status:     e.buildSuccessStatus(),
+				ServerDate: e.formatDateTime(now, 0),
+				Language:   ofxExportedLanguage,
+			},
+		},
+	}
+
+	for i := 0; i < len(accountIds); i++ {
+		statement := statements[accountIds[i]]
+		account := statement.account
+		ledgerBalance := &ofxBalance{
+			Amount:   utils.FormatAmount(account.Balance),
+			AsOfDate: e.formatDateTime(now, 0),
+		}
+
+		if account.Category == models.ACCOUNT_CATEGORY_CREDIT_CARD {
+			if file.CreditCardMessageResponseV1 == nil {
+				file.CreditCardMessageResponseV1 = &ofxCreditCardMessageResponseV1{}
+			}
+
+			statementTransactions := make([]*ofxCreditCardStatementTransaction, len(statement.transactions))
+
+			for j := 0; j < len(statement.transactions); j++ {
+				statementTransactions[j] = &ofxCreditCardStatementTransaction{
+					ofxBaseStatementTransaction: *statement.transactions[j],
+				}
+
+				if statement.accountsTo[j] != nil {
+					statementTransactions[j].AccountTo = &ofxCreditCardAccount{
+						AccountId: statement.accountsTo[j].Name,
+					}
+				}
+			}
+
+			file.CreditCardMessageResponseV1.StatementTransactionResponses = append(file.CreditCardMessageResponseV1.StatementTransactionResponses, &ofxCreditCardStatementTransactionResponse{
+				TransactionUid: utils.Int64ToString(account.AccountId),
+				Status:         e.buildSuccessStatus(),
+				StatementResponse: &ofxCreditCardStatementResponse{
+					DefaultCurrency: account.Currency,
+					AccountFrom: &ofxCreditCardAccount{
+						AccountId: account.Name,
+					},
+					TransactionList: &ofxCreditCardTransactionList{
+						StartDate:             e.formatDateTime(statement.startTime, 0),
+						EndDate:               e.formatDateTime(statement.endTime, 0),
+						StatementTransactions: statementTransactions,
+					},
+					LedgerBalance: ledgerBalance,
+				},
+			})
+		} else {
+			if file.BankMessageResponseV1 == nil {
+				file.BankMessageResponseV1 = &ofxBankMessageResponseV1{}
+			}
+
+			statementTransactions := make([]*ofxBankStatementTransaction, len(statement.transactions))
+
+			for j := 0; j < len(statement.transactions); j++ {
+				statementTransactions[j] = &ofxBankStatementTransaction{
+					ofxBaseStatementTransaction: *statement.transactions[j],
+				}
+
+				if statement.accountsTo[j] != nil {
+					statementTransactions[j].AccountTo = &ofxBankAccount{
+						BankId:      ofxExportedBankId,
+						AccountId:   statement.accountsTo[j].Name,
+						AccountType: e.getAccountType(statement.accountsTo[j]),
+					}
+				}
+			}
+
+			file.BankMessageResponseV1.StatementTransactionResponses = append(file.BankMessageResponseV1.StatementTransactionResponses, &ofxBankStatementTransactionResponse{
+				TransactionUid: utils.Int64ToString(account.AccountId),
+				Status:         e.buildSuccessStatus(),
+				StatementResponse: &ofxBankStatementResponse{
+					DefaultCurrency: account.Currency,
+					AccountFrom: &ofxBankAccount{
+						BankId:      ofxExportedBankId,
+						AccountId:   account.Name,
+						AccountType: e.getAccountType(account),
+					},
+					TransactionList: &ofxBankTransactionList{
+						StartDate:             e.formatDateTime(statement.startTime, 0),
+						EndDate:               e.formatDateTime(statement.endTime, 0),
+						StatementTransactions: statementTransactions,
+					},
+					LedgerBalance: ledgerBalance,
+				},
+			})
+		}
+	}
+
+	return file
+}
+
+func (e *ofxTransactionDataExporter) buildSuccessStatus() *ofxStatus {
+	return &ofxStatus{
+		Code:     ofxExportedStatusSuccessCode,
+		Severity: ofxExportedStatusInfoSeverity,
+	}
+}
+
+func (e *ofxTransactionDataExporter) getAccountType(account *models.Account) ofxAccountType {
+	if accountType, exists := ofxAccountTypeMapping[account.Category]; exists {
+		return accountType
+	}
+
+	return ofxCheckingAccount
+}
+
+func (e *ofxTransactionDataExporter) getNameAndMemo(comment string) (string, string) {
+	runes := []rune(comment)
+
+	if len(runes) <= ofxExportedNameMaxLength {
+		return comment, ""
+	}
+
+	return string(runes[0:ofxExportedNameMaxLength]), comment
+}
+
+func (e *ofxTransactionDataExporter) formatDateTime(unixTime int64, utcOffset int16) string {
+	dateTime := time.Unix(unixTime, 0).In(time.FixedZone("Transaction Timezone", int(utcOffset)*60))
+	return fmt.Sprintf("%s.000[%s]", dateTime.Format("20060102150405"), utils.Float64ToString(float64(utcOffset)/60))
+}
+
+func (s *ofxExportedStatement) appendTransaction(transaction *ofxBaseStatementTransaction, accountTo *models.Account, unixTime int64) {
+	s.transactions = append(s.transactions, transaction)
+	s.accountsTo = append(s.accountsTo, accountTo)
+
+	if s.startTime == 0 || unixTime < s.startTime {
+		s.startTime = unixTime
+	}
+
+	if unixTime > s.endTime {
+		s.endTime = unixTime
+	}
+}
@@ -0,0 +1,161 @@
+package ofx
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestOFXTransactionDataFileExporterToExportedContent(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions, accountMap := createOFXExporterTestTransactionsAndAccounts()
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	content := string(actualContent)
+	assert.True(t, strings.HasPrefix(content, "<?xml version=\"1.0\" encoding=\"UTF-8\" standalone=\"no\"?>\n<?OFX OFXHEADER=\"200\" VERSION=\"220\" SECURITY=\"NONE\" OLDFILEUID=\"NONE\" NEWFILEUID=\"NONE\"?>\n<OFX>\n"))
+	assert.Equal(t, 1, strings.Count(content, "<STMTTRNRS>"))
+	assert.Equal(t, 1, strings.Count(content, "<CCSTMTTRNRS>"))
+	assert.Equal(t, 5, strings.Count(content, "<STMTTRN>"))
+
+	assert.Contains(t, content, "<ACCTID>Test Account</ACCTID>")
+	assert.Contains(t, content, "<ACCTTYPE>SAVINGS</ACCTTYPE>")
+	assert.Contains(t, content, "<ACCTID>Test Credit Card</ACCTID>")
+	assert.Contains(t, content, "<DTPOSTED>20240901123456.000[8]</DTPOSTED>")
+	assert.Contains(t, content, "<DTPOSTED>20240901123456.000[-5]</DTPOSTED>")
+	assert.Contains(t, content, "<TRNAMT>-0.10</TRNAMT>")
+	assert.Contains(t, content, "<NAME>This is a very long comment whic</NAME>")
+	assert.Contains(t, content, "<MEMO>This is a very long comment which cannot be put in name field</MEMO>")
+	assert.Contains(t, content, "<BANKACCTTO>\n")
+	assert.Contains(t, content, "<BALAMT>1234.56</BALAMT>")
+}
+
+func TestOFXTransactionDataFileExporterToExportedContent_RoundTrip(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	importer := OFXTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	transactions, accountMap := createOFXExporterTestTransactionsAndAccounts()
+	exportedContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, _, _, _, _, err := importer.ParseImportedData(context, user, exportedContent, 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[0].Type)
+	assert.Equal(t, int64(1725165296), utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime))
+	assert.Equal(t, int16(480), allNewTransactions[0].TimezoneUtcOffset)
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[0].OriginalSourceAccountCurrency)
+	assert.Equal(t, "Hello,World", allNewTransactions[0].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(1725194096), utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime))
+	assert.Equal(t, int64(10), allNewTransactions[1].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "This is a very long comment which cannot be put in name field", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725212096), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int16(-300), allNewTransactions[2].TimezoneUtcOffset)
+	assert.Equal(t, int64(12345), allNewTransactions[2].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "CNY", allNewTransactions[2].OriginalSourceAccountCurrency)
+	assert.Equal(t, int64(1735), allNewTransactions[2].RelatedAccountAmount)
+	assert.Equal(t, "Test Credit Card", allNewTransactions[2].OriginalDestinationAccountName)
+	assert.Equal(t, "USD", allNewTransactions[2].OriginalDestinationAccountCurrency)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1725220000), utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime))
+	assert.Equal(t, int64(500), allNewTransactions[3].Amount)
+	assert.Equal(t, "Test Credit Card", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "USD", allNewTransactions[3].OriginalSourceAccountCurrency)
+}
+
+func TestOFXTransactionDataFileExporterToExportedContent_EmptyTransactions(t *testing.T) {
+	exporter := OFXTransactionDataExporter
+	context := core.NewNullContext()
+
+	actualContent, err := exporter.ToExportedContent(context, 123, []*models.Transaction{}, map[int64]*models.Account{}, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	content := string(actualContent)
+	assert.Contains(t, content, "<SIGNONMSGSRSV1>")
+	assert.NotContains(t, content, "<BANKMSGSRSV1>")
+	assert.NotContains(t, content, "<CREDITCARDMSGSRSV1>")
+}
+
+func createOFXExporterTestTransactionsAndAccounts() ([]*models.Transaction, map[int64]*models.Account) {
+	// transactions are sorted by transaction time in descending order
+	transactions := make([]*models.Transaction, 4)
+	transactions[0] = &models.Transaction{
+		TransactionId:     4,
+		TransactionTime:   1725220000000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		AccountId:         2,
+		Amount:            500,
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:        3,
+		TransactionTime:      1725212096000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    -300,
+		AccountId:            1,
+		Amount:               12345,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 1735,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725194096000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		AccountId:         1,
+		Amount:            10,
+		Comment:           "This is a very long comment which cannot be put in name field",
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725165296000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 480,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Hello,World",
+	}
+
+	accountMap := make(map[int64]*models.Account, 2)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Category:  models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT,
+		Currency:  "CNY",
+		Balance:   123456,
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Name:      "Test Credit Card",
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Currency:  "USD",
+		Balance:   -2235,
+	}
+
+	return transactions, accountMap
+}
@@ -739,3 +739,46 @@ func TestOFXTransactionDataFileParseImportedData_MissingTransactionRequiredNode(
 			"</OFX>"), 0, nil, nil, nil, nil, nil)
 	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
 }
+
+func TestOFXTransactionDataFileParseImportedData_ParseTransactionId(t *testing.T) {
+	converter := OFXTransactionDataImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte(
+		"<OFX>\n"+
+			"  <BANKMSGSRSV1>\n"+
+			"    <STMTTRNRS>\n"+
+			"      <STMTRS>\n"+
+			"        <CURDEF>CNY</CURDEF>\n"+
+			"        <BANKACCTFROM>\n"+
+			"          <ACCTID>123</ACCTID>\n"+
+			"        </BANKACCTFROM>\n"+
+			"        <BANKTRANLIST>\n"+
+			"          <STMTTRN>\n"+
+			"            <TRNTYPE>DEP</TRNTYPE>\n"+
+			"            <DTPOSTED>20240901012345.000[+8:CST]</DTPOSTED>\n"+
+			"            <TRNAMT>123.45</TRNAMT>\n"+
+			"            <FITID> 2024090100001 </FITID>\n"+
+			"          </STMTTRN>\n"+
+			"          <STMTTRN>\n"+
+			"            <TRNTYPE>CHECK</TRNTYPE>\n"+
+			"            <DTPOSTED>20240901123456.000[+8:CST]</DTPOSTED>\n"+
+			"            <TRNAMT>-0.12</TRNAMT>\n"+
+			"          </STMTTRN>\n"+
+			"        </BANKTRANLIST>\n"+
+			"      </STMTRS>\n"+
+			"    </STMTTRNRS>\n"+
+			"  </BANKMSGSRSV1>\n"+
+			"</OFX>"), 0, nil, nil, nil, nil, nil)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, "2024090100001", allNewTransactions[0].ExternalId)
+	assert.Equal(t, "", allNewTransactions[1].ExternalId)
+}
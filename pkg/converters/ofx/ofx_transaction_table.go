@@ -24,6 +24,7 @@ var ofxTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bo
 	datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY: true,
 	datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT:           true,
 	datatable.TRANSACTION_DATA_TABLE_DESCRIPTION:              true,
+	datatable.TRANSACTION_DATA_TABLE_EXTERNAL_ID:              true,
 }
 
 // ofxTransactionData defines the structure of open financial exchange (ofx) transaction data
@@ -33,6 +34,8 @@ type ofxTransactionData struct {
 	FromAccountId     string
 	FromCreditAccount bool
 	ToAccountId       string
+	RelatedAmount     string
+	RelatedCurrency   string
 }
 
 // ofxTransactionDataTable defines the structure of open financial exchange (ofx) transaction data table
@@ -176,6 +179,17 @@ func (t *ofxTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = ofxTransaction.ToAccountId
 				data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_CURRENCY]
 				data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = data[datatable.TRANSACTION_DATA_TABLE_AMOUNT]
+
+				if ofxTransaction.RelatedAmount != "" { // the transfer in transaction of the same transfer is in another statement
+					relatedAmount, err := utils.ParseAmount(strings.ReplaceAll(ofxTransaction.RelatedAmount, ",", "."))
+
+					if err != nil {
+						return nil, errs.ErrAmountInvalid
+					}
+
+					data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_CURRENCY] = ofxTransaction.RelatedCurrency
+					data[datatable.TRANSACTION_DATA_TABLE_RELATED_AMOUNT] = utils.FormatAmount(relatedAmount)
+				}
 			}
 		}
 	} else { // transaction type depends on signage of amount
@@ -214,6 +228,8 @@ func (t *ofxTransactionDataRowIterator) parseTransaction(ctx core.Context, user
 		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = ""
 	}
 
+	data[datatable.TRANSACTION_DATA_TABLE_EXTERNAL_ID] = strings.TrimSpace(ofxTransaction.TransactionId)
+
 	return data, nil
 }
 
@@ -274,70 +290,131 @@ func createNewOFXTransactionDataTable(file *ofxFile) (*ofxTransactionDataTable,
 
 	allData := make([]*ofxTransactionData, 0)
 
-	if file.BankMessageResponseV1 != nil &&
-		file.BankMessageResponseV1.StatementTransactionResponse != nil &&
-		file.BankMessageResponseV1.StatementTransactionResponse.StatementResponse != nil &&
-		file.BankMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList != nil {
-		statement := file.BankMessageResponseV1.StatementTransactionResponse.StatementResponse
-		bankTransactions := statement.TransactionList.StatementTransactions
-		fromAccountId := ""
-		fromCreditAccount := false
-
-		if statement.AccountFrom != nil {
-			fromAccountId = statement.AccountFrom.AccountId
+	if file.BankMessageResponseV1 != nil {
+		for i := 0; i < len(file.BankMessageResponseV1.StatementTransactionResponses); i++ {
+			statementTransactionResponse := file.BankMessageResponseV1.StatementTransactionResponses[i]
 
-			if statement.AccountFrom.AccountType == ofxLineOfCreditAccount {
-				fromCreditAccount = true
+			if statementTransactionResponse == nil ||
+				statementTransactionResponse.StatementResponse == nil ||
+				statementTransactionResponse.StatementResponse.TransactionList == nil {
+				continue
 			}
-		}
 
-		for i := 0; i < len(bankTransactions); i++ {
-			toAccountId := ""
+			statement := statementTransactionResponse.StatementResponse
+			bankTransactions := statement.TransactionList.StatementTransactions
+			fromAccountId := ""
+			fromCreditAccount := false
+
+			if statement.AccountFrom != nil {
+				fromAccountId = statement.AccountFrom.AccountId
 
-			if bankTransactions[i].AccountTo != nil {
-				toAccountId = bankTransactions[i].AccountTo.AccountId
+				if statement.AccountFrom.AccountType == ofxLineOfCreditAccount {
+					fromCreditAccount = true
+				}
 			}
 
-			allData = append(allData, &ofxTransactionData{
-				ofxBaseStatementTransaction: bankTransactions[i].ofxBaseStatementTransaction,
-				DefaultCurrency:             statement.DefaultCurrency,
-				FromAccountId:               fromAccountId,
-				FromCreditAccount:           fromCreditAccount,
-				ToAccountId:                 toAccountId,
-			})
+			for j := 0; j < len(bankTransactions); j++ {
+				toAccountId := ""
+
+				if bankTransactions[j].AccountTo != nil {
+					toAccountId = bankTransactions[j].AccountTo.AccountId
+				}
+
+				allData = append(allData, &ofxTransactionData{
+					ofxBaseStatementTransaction: bankTransactions[j].ofxBaseStatementTransaction,
+					DefaultCurrency:             statement.DefaultCurrency,
+					FromAccountId:               fromAccountId,
+					FromCreditAccount:           fromCreditAccount,
+					ToAccountId:                 toAccountId,
+				})
+			}
 		}
 	}
 
-	if file.CreditCardMessageResponseV1 != nil &&
-		file.CreditCardMessageResponseV1.StatementTransactionResponse != nil &&
-		file.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse != nil &&
-		file.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse.TransactionList != nil {
-		statement := file.CreditCardMessageResponseV1.StatementTransactionResponse.StatementResponse
-		bankTransactions := statement.TransactionList.StatementTransactions
-		fromAccountId := ""
+	if file.CreditCardMessageResponseV1 != nil {
+		for i := 0; i < len(file.CreditCardMessageResponseV1.StatementTransactionResponses); i++ {
+			statementTransactionResponse := file.CreditCardMessageResponseV1.StatementTransactionResponses[i]
 
-		if statement.AccountFrom != nil {
-			fromAccountId = statement.AccountFrom.AccountId
-		}
+			if statementTransactionResponse == nil ||
+				statementTransactionResponse.StatementResponse == nil ||
+				statementTransactionResponse.StatementResponse.TransactionList == nil {
+				continue
+			}
 
-		for i := 0; i < len(bankTransactions); i++ {
-			toAccountId := ""
+			statement := statementTransactionResponse.StatementResponse
+			bankTransactions := statement.TransactionList.StatementTransactions
+			fromAccountId := ""
 
-			if bankTransactions[i].AccountTo != nil {
-				toAccountId = bankTransactions[i].AccountTo.AccountId
+			if statement.AccountFrom != nil {
+				fromAccountId = statement.AccountFrom.AccountId
 			}
 
-			allData = append(allData, &ofxTransactionData{
-				ofxBaseStatementTransaction: bankTransactions[i].ofxBaseStatementTransaction,
-				DefaultCurrency:             statement.DefaultCurrency,
-				FromAccountId:               fromAccountId,
-				FromCreditAccount:           true,
-				ToAccountId:                 toAccountId,
-			})
+			for j := 0; j < len(bankTransactions); j++ {
+				toAccountId := ""
+
+				if bankTransactions[j].AccountTo != nil {
+					toAccountId = bankTransactions[j].AccountTo.AccountId
+				}
+
+				allData = append(allData, &ofxTransactionData{
+					ofxBaseStatementTransaction: bankTransactions[j].ofxBaseStatementTransaction,
+					DefaultCurrency:             statement.DefaultCurrency,
+					FromAccountId:               fromAccountId,
+					FromCreditAccount:           true,
+					ToAccountId:                 toAccountId,
+				})
+			}
 		}
 	}
 
 	return &ofxTransactionDataTable{
-		allData: allData,
+		allData: mergeOFXTransferTransactions(allData),
 	}, nil
 }
+
+// mergeOFXTransferTransactions merges the transfer in transaction into the transfer out transaction which has the same transaction id in another statement of the same file
+func mergeOFXTransferTransactions(allData []*ofxTransactionData) []*ofxTransactionData {
+	transferOutTransactions := make(map[string]*ofxTransactionData)
+
+	for i := 0; i < len(allData); i++ {
+		data := allData[i]
+
+		if data.TransactionType == ofxTransferTransaction && data.TransactionId != "" && data.ToAccountId != "" && isOFXNegativeAmount(data.Amount) {
+			transferOutTransactions[data.TransactionId] = data
+		}
+	}
+
+	if len(transferOutTransactions) < 1 {
+		return allData
+	}
+
+	finalData := make([]*ofxTransactionData, 0, len(allData))
+
+	for i := 0; i < len(allData); i++ {
+		data := allData[i]
+
+		if data.TransactionType == ofxTransferTransaction && data.TransactionId != "" && !isOFXNegativeAmount(data.Amount) {
+			transferOutTransaction, exists := transferOutTransactions[data.TransactionId]
+
+			if exists && transferOutTransaction.ToAccountId == data.FromAccountId && transferOutTransaction.FromAccountId != data.FromAccountId {
+				transferOutTransaction.RelatedAmount = data.Amount
+
+				if data.Currency != "" {
+					transferOutTransaction.RelatedCurrency = data.Currency
+				} else {
+					transferOutTransaction.RelatedCurrency = data.DefaultCurrency
+				}
+
+				continue
+			}
+		}
+
+		finalData = append(finalData, data)
+	}
+
+	return finalData
+}
+
+func isOFXNegativeAmount(amount string) bool {
+	return strings.HasPrefix(strings.TrimSpace(amount), "-")
+}
@@ -0,0 +1,308 @@
+package qif
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const qifAutoSwitchOptionHeader = "!Option:AutoSwitch"
+const qifAutoSwitchClearHeader = "!Clear:AutoSwitch"
+
+const qifBankAccountType = "Bank"
+const qifCashAccountType = "Cash"
+const qifCreditCardAccountType = "CCard"
+const qifAssetAccountType = "Oth A"
+const qifLiabilityAccountType = "Oth L"
+
+var qifAccountTypeMapping = map[models.AccountCategory]string{
+	models.ACCOUNT_CATEGORY_CASH:                   qifCashAccountType,
+	models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT:       qifBankAccountType,
+	models.ACCOUNT_CATEGORY_CREDIT_CARD:            qifCreditCardAccountType,
+	models.ACCOUNT_CATEGORY_VIRTUAL:                qifBankAccountType,
+	models.ACCOUNT_CATEGORY_DEBT:                   qifLiabilityAccountType,
+	models.ACCOUNT_CATEGORY_RECEIVABLES:            qifAssetAccountType,
+	models.ACCOUNT_CATEGORY_INVESTMENT:             qifBankAccountType,
+	models.ACCOUNT_CATEGORY_SAVINGS_ACCOUNT:        qifBankAccountType,
+	models.ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT: qifBankAccountType,
+}
+
+var qifDateFormatMapping = map[qifDateFormatType]string{
+	qifYearMonthDayDateFormat: "2006-01-02",
+	qifMonthDayYearDateFormat: "01/02/2006",
+	qifDayMonthYearDateFormat: "02/01/2006",
+}
+
+// qifTransactionDataExporter defines the structure of quicken interchange format (qif) exporter for transaction data
+type qifTransactionDataExporter struct {
+	dateFormatType qifDateFormatType
+}
+
+// Initialize a quicken interchange format (qif) transaction data exporter singleton instance
+var (
+	QifYearMonthDayTransactionDataExporter = &qifTransactionDataExporter{
+		dateFormatType: qifYearMonthDayDateFormat,
+	}
+
+	QifMonthDayYearTransactionDataExporter = &qifTransactionDataExporter{
+		dateFormatType: qifMonthDayYearDateFormat,
+	}
+
+	QifDayMonthYearTransactionDataExporter = &qifTransactionDataExporter{
+		dateFormatType: qifDayMonthYearDateFormat,
+	}
+)
+
+// ToExportedContent returns the exported quicken interchange format (qif) data, the transactions of each account are in their own section
+func (e *qifTransactionDataExporter) ToExportedContent(ctx core.Context, uid int64, transactions []*models.Transaction, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, tagMap map[int64]*models.TransactionTag, allTagIndexes map[int64][]int64, allSplits map[int64]models.TransactionSplitSlice) ([]byte, error) {
+	accountTransactions := make(map[int64][]*models.Transaction)
+
+	// the transactions are sorted by transaction time in descending order, but the transactions in qif file should be in ascending order
+	for i := len(transactions) - 1; i >= 0; i-- {
+		transaction := transactions[i]
+
+		// the transfer in transaction would be created automatically by the transfer out transaction
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		if _, exists := accountMap[transaction.AccountId]; !exists {
+			log.Warnf(ctx, "[qif_transaction_data_file_exporter.ToExportedContent] cannot find account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.AccountId, transaction.TransactionId, uid)
+			continue
+		}
+
+		if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+			if _, exists := accountMap[transaction.RelatedAccountId]; !exists {
+				log.Warnf(ctx, "[qif_transaction_data_file_exporter.ToExportedContent] cannot find related account \"id:%d\" of transaction \"id:%d\" for user \"uid:%d\"", transaction.RelatedAccountId, transaction.TransactionId, uid)
+				continue
+			}
+		} else if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE &&
+			transaction.Type != models.TRANSACTION_DB_TYPE_INCOME &&
+			transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+			log.Warnf(ctx, "[qif_transaction_data_file_exporter.ToExportedContent] cannot export transaction \"id:%d\" for user \"uid:%d\", because type \"%d\" is invalid", transaction.TransactionId, uid, transaction.Type)
+			return nil, errs.ErrTransactionTypeInvalid
+		}
+
+		accountTransactions[transaction.AccountId] = append(accountTransactions[transaction.AccountId], transaction)
+	}
+
+	accountIds := make([]int64, 0, len(accountTransactions))
+
+	for accountId := range accountTransactions {
+		accountIds = append(accountIds, accountId)
+	}
+
+	sort.Slice(accountIds, func(i, j int) bool {
+		return accountIds[i] < accountIds[j]
+	})
+
+	var sb strings.Builder
+
+	e.writeAccountList(&sb, accountIds, accountMap)
+	e.writeCategoryList(&sb, categoryMap)
+
+	for i := 0; i < len(accountIds); i++ {
+		account := accountMap[accountIds[i]]
+		accountType := e.getAccountType(account)
+
+		e.writeAccount(&sb, account)
+		sb.WriteString(qifTypeHeaderPrefix + accountType + "\n")
+
+		transactionsInAccount := accountTransactions[account.AccountId]
+
+		for j := 0; j < len(transactionsInAccount); j++ {
+			e.writeTransaction(&sb, transactionsInAccount[j], account, accountMap, categoryMap, allSplits)
+		}
+	}
+
+	return []byte(sb.String()), nil
+}
+
+func (e *qifTransactionDataExporter) writeAccountList(sb *strings.Builder, accountIds []int64, accountMap map[int64]*models.Account) {
+	if len(accountIds) < 1 {
+		return
+	}
+
+	sb.WriteString(qifAutoSwitchOptionHeader + "\n")
+
+	for i := 0; i < len(accountIds); i++ {
+		e.writeAccount(sb, accountMap[accountIds[i]])
+	}
+
+	sb.WriteString(qifAutoSwitchClearHeader + "\n")
+}
+
+func (e *qifTransactionDataExporter) writeAccount(sb *strings.Builder, account *models.Account) {
+	sb.WriteString(qifAccountHeader + "\n")
+	sb.WriteString("N" + e.replaceLineBreaks(account.Name) + "\n")
+	sb.WriteString("T" + e.getAccountType(account) + "\n")
+
+	if account.Comment != "" {
+		sb.WriteString("D" + e.replaceLineBreaks(account.Comment) + "\n")
+	}
+
+	sb.WriteString(string(qifEntryEnd) + "\n")
+}
+
+func (e *qifTransactionDataExporter) writeCategoryList(sb *strings.Builder, categoryMap map[int64]*models.TransactionCategory) {
+	primaryCategories := make([]*models.TransactionCategory, 0)
+	subCategories := make(map[int64][]*models.TransactionCategory)
+
+	for _, category := range categoryMap {
+		if category.Type != models.CATEGORY_TYPE_INCOME && category.Type != models.CATEGORY_TYPE_EXPENSE {
+			continue
+		}
+
+		if category.ParentCategoryId == models.LevelOneTransactionParentId {
+			primaryCategories = append(primaryCategories, category)
+		} else {
+			subCategories[category.ParentCategoryId] = append(subCategories[category.ParentCategoryId], category)
+		}
+	}
+
+	if len(primaryCategories) < 1 {
+		return
+	}
+
+	e.sortCategories(primaryCategories)
+	sb.WriteString(qifCategoryHeader + "\n")
+
+	for i := 0; i < len(primaryCategories); i++ {
+		primaryCategory := primaryCategories[i]
+		e.writeCategory(sb, primaryCategory, categoryMap)
+
+		children := subCategories[primaryCategory.CategoryId]
+		e.sortCategories(children)
+
+		for j := 0; j < len(children); j++ {
+			e.writeCategory(sb, children[j], categoryMap)
+		}
+	}
+}
+
+func (e *qifTransactionDataExporter) writeCategory(sb *strings.Builder, category *models.TransactionCategory, categoryMap map[int64]*models.TransactionCategory) {
+	sb.WriteString("N" + e.getCategoryName(category.CategoryId, categoryMap) + "\n")
+
+	if category.Comment != "" {
+		sb.WriteString("D" + e.replaceLineBreaks(category.Comment) + "\n")
+	}
+
+	if category.Type == models.CATEGORY_TYPE_INCOME {
+		sb.WriteString(string(qifIncomeTransaction) + "\n")
+	} else {
+		sb.WriteString(string(qifExpenseTransaction) + "\n")
+	}
+
+	sb.WriteString(string(qifEntryEnd) + "\n")
+}
+
+func (e *qifTransactionDataExporter) writeTransaction(sb *strings.Builder, transaction *models.Transaction, account *models.Account, accountMap map[int64]*models.Account, categoryMap map[int64]*models.TransactionCategory, allSplits map[int64]models.TransactionSplitSlice) {
+	amountSign := int64(1)
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		amountSign = -1
+	}
+
+	sb.WriteString("D" + e.formatDate(transaction) + "\n")
+	sb.WriteString("T" + utils.FormatAmount(amountSign*transaction.Amount) + "\n")
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		sb.WriteString("P" + qifOpeningBalancePayeeText + "\n")
+	}
+
+	if transaction.Comment != "" {
+		sb.WriteString("M" + e.replaceLineBreaks(transaction.Comment) + "\n")
+	}
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+		sb.WriteString("L[" + e.replaceLineBreaks(account.Name) + "]\n")
+	} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+		sb.WriteString("L[" + e.replaceLineBreaks(accountMap[transaction.RelatedAccountId].Name) + "]\n")
+	} else {
+		categoryName := e.getCategoryName(transaction.CategoryId, categoryMap)
+
+		if categoryName != "" {
+			sb.WriteString("L" + categoryName + "\n")
+		}
+
+		splits := allSplits[transaction.TransactionId]
+
+		for i := 0; i < len(splits); i++ {
+			split := splits[i]
+			sb.WriteString("S" + e.getCategoryName(split.CategoryId, categoryMap) + "\n")
+
+			if split.Comment != "" {
+				sb.WriteString("E" + e.replaceLineBreaks(split.Comment) + "\n")
+			}
+
+			sb.WriteString("$" + utils.FormatAmount(amountSign*split.Amount) + "\n")
+		}
+	}
+
+	sb.WriteString(string(qifEntryEnd) + "\n")
+}
+
+func (e *qifTransactionDataExporter) getAccountType(account *models.Account) string {
+	if accountType, exists := qifAccountTypeMapping[account.Category]; exists {
+		return accountType
+	}
+
+	return qifBankAccountType
+}
+
+func (e *qifTransactionDataExporter) getCategoryName(categoryId int64, categoryMap map[int64]*models.TransactionCategory) string {
+	category, exists := categoryMap[categoryId]
+
+	if !exists {
+		return ""
+	}
+
+	categoryName := e.replaceLineBreaks(category.Name)
+
+	if category.ParentCategoryId == models.LevelOneTransactionParentId {
+		return categoryName
+	}
+
+	parentCategory, exists := categoryMap[category.ParentCategoryId]
+
+	if !exists {
+		return categoryName
+	}
+
+	return e.replaceLineBreaks(parentCategory.Name) + ":" + categoryName
+}
+
+func (e *qifTransactionDataExporter) sortCategories(categories []*models.TransactionCategory) {
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Type != categories[j].Type {
+			return categories[i].Type < categories[j].Type
+		}
+
+		if categories[i].DisplayOrder != categories[j].DisplayOrder {
+			return categories[i].DisplayOrder < categories[j].DisplayOrder
+		}
+
+		return categories[i].CategoryId < categories[j].CategoryId
+	})
+}
+
+func (e *qifTransactionDataExporter) formatDate(transaction *models.Transaction) string {
+	transactionTimeZone := time.FixedZone("Transaction Timezone", int(transaction.TimezoneUtcOffset)*60)
+	transactionTime := time.Unix(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime), 0).In(transactionTimeZone)
+
+	return transactionTime.Format(qifDateFormatMapping[e.dateFormatType])
+}
+
+func (e *qifTransactionDataExporter) replaceLineBreaks(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", " ")
+	text = strings.ReplaceAll(text, "\r", " ")
+	text = strings.ReplaceAll(text, "\n", " ")
+
+	return text
+}
@@ -0,0 +1,306 @@
+package qif
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestQIFTransactionDataFileExporterToExportedContent(t *testing.T) {
+	exporter := QifYearMonthDayTransactionDataExporter
+	context := core.NewNullContext()
+
+	transactions, accountMap, categoryMap, allSplits := createQIFExporterTestData()
+
+	expectedContent := "!Option:AutoSwitch\n" +
+		"!Account\n" +
+		"NTest Account\n" +
+		"TBank\n" +
+		"^\n" +
+		"!Account\n" +
+		"NTest Credit Card\n" +
+		"TCCard\n" +
+		"^\n" +
+		"!Account\n" +
+		"NTest Cash\n" +
+		"TCash\n" +
+		"DCash in wallet\n" +
+		"^\n" +
+		"!Clear:AutoSwitch\n" +
+		"!Type:Cat\n" +
+		"NTest Category\n" +
+		"I\n" +
+		"^\n" +
+		"NTest Category:Test Sub Category\n" +
+		"I\n" +
+		"^\n" +
+		"NTest Category2\n" +
+		"E\n" +
+		"^\n" +
+		"NTest Category2:Test Sub Category2\n" +
+		"E\n" +
+		"^\n" +
+		"NTest Category2:Test Sub Category3\n" +
+		"E\n" +
+		"^\n" +
+		"!Account\n" +
+		"NTest Account\n" +
+		"TBank\n" +
+		"^\n" +
+		"!Type:Bank\n" +
+		"D2024-09-01\n" +
+		"T1000.00\n" +
+		"POpening Balance\n" +
+		"L[Test Account]\n" +
+		"^\n" +
+		"D2024-09-01\n" +
+		"T123.45\n" +
+		"MHello World\n" +
+		"LTest Category:Test Sub Category\n" +
+		"^\n" +
+		"D2024-08-31\n" +
+		"T-100.00\n" +
+		"MRepay\n" +
+		"L[Test Credit Card]\n" +
+		"^\n" +
+		"D2024-09-02\n" +
+		"T-12.34\n" +
+		"LTest Category2:Test Sub Category2\n" +
+		"STest Category2:Test Sub Category2\n" +
+		"EFoo\n" +
+		"$-10.00\n" +
+		"STest Category2:Test Sub Category3\n" +
+		"$-2.34\n" +
+		"^\n" +
+		"!Account\n" +
+		"NTest Credit Card\n" +
+		"TCCard\n" +
+		"^\n" +
+		"!Type:CCard\n" +
+		"D2024-09-03\n" +
+		"T-5.00\n" +
+		"LTest Category2:Test Sub Category3\n" +
+		"^\n" +
+		"!Account\n" +
+		"NTest Cash\n" +
+		"TCash\n" +
+		"DCash in wallet\n" +
+		"^\n" +
+		"!Type:Cash\n" +
+		"D2024-09-04\n" +
+		"T-1.00\n" +
+		"^\n"
+	actualContent, err := exporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, allSplits)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedContent, string(actualContent))
+}
+
+func TestQIFTransactionDataFileExporterToExportedContent_DateFormats(t *testing.T) {
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, allSplits := createQIFExporterTestData()
+
+	actualContent, err := QifMonthDayYearTransactionDataExporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, allSplits)
+	assert.Nil(t, err)
+	assert.Contains(t, string(actualContent), "D09/02/2024\nT-12.34\n")
+	assert.Contains(t, string(actualContent), "D08/31/2024\nT-100.00\n")
+
+	actualContent, err = QifDayMonthYearTransactionDataExporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, allSplits)
+	assert.Nil(t, err)
+	assert.Contains(t, string(actualContent), "D02/09/2024\nT-12.34\n")
+	assert.Contains(t, string(actualContent), "D31/08/2024\nT-100.00\n")
+}
+
+func TestQIFTransactionDataFileExporterToExportedContent_RoundTrip(t *testing.T) {
+	context := core.NewNullContext()
+	transactions, accountMap, categoryMap, allSplits := createQIFExporterTestData()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "CNY",
+	}
+
+	exportedContent, err := QifDayMonthYearTransactionDataExporter.ToExportedContent(context, 123, transactions, accountMap, categoryMap, nil, nil, allSplits)
+	assert.Nil(t, err)
+
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, _, _, err := QifDayMonthYearTransactionDataImporter.ParseImportedData(context, user, exportedContent, 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 6, len(allNewTransactions))
+	assert.Equal(t, 3, len(allNewAccounts))
+	assert.Equal(t, 3, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(100000), allNewTransactions[0].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[0].OriginalSourceAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[1].Type)
+	assert.Equal(t, int64(10000), allNewTransactions[1].Amount)
+	assert.Equal(t, "Test Account", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Test Credit Card", allNewTransactions[1].OriginalDestinationAccountName)
+	assert.Equal(t, "Repay", allNewTransactions[1].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[2].Type)
+	assert.Equal(t, int64(1725148800), utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime))
+	assert.Equal(t, int64(12345), allNewTransactions[2].Amount)
+	assert.Equal(t, "Test Sub Category", allNewTransactions[2].OriginalCategoryName)
+	assert.Equal(t, "Hello World", allNewTransactions[2].Comment)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1234), allNewTransactions[3].Amount)
+	assert.Equal(t, "Test Sub Category2", allNewTransactions[3].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[4].Type)
+	assert.Equal(t, int64(500), allNewTransactions[4].Amount)
+	assert.Equal(t, "Test Credit Card", allNewTransactions[4].OriginalSourceAccountName)
+	assert.Equal(t, "Test Sub Category3", allNewTransactions[4].OriginalCategoryName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[5].Type)
+	assert.Equal(t, int64(100), allNewTransactions[5].Amount)
+	assert.Equal(t, "Test Cash", allNewTransactions[5].OriginalSourceAccountName)
+}
+
+func TestQIFTransactionDataFileExporterToExportedContent_EmptyTransactions(t *testing.T) {
+	context := core.NewNullContext()
+
+	actualContent, err := QifYearMonthDayTransactionDataExporter.ToExportedContent(context, 123, []*models.Transaction{}, map[int64]*models.Account{}, map[int64]*models.TransactionCategory{}, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "", string(actualContent))
+}
+
+func createQIFExporterTestData() ([]*models.Transaction, map[int64]*models.Account, map[int64]*models.TransactionCategory, map[int64]models.TransactionSplitSlice) {
+	// transactions are sorted by transaction time in descending order
+	transactions := make([]*models.Transaction, 6)
+	transactions[0] = &models.Transaction{
+		TransactionId:     6,
+		TransactionTime:   1725408000000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		AccountId:         3,
+		Amount:            100,
+	}
+	transactions[1] = &models.Transaction{
+		TransactionId:     5,
+		TransactionTime:   1725321600000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 0,
+		CategoryId:        5,
+		AccountId:         2,
+		Amount:            500,
+	}
+	transactions[2] = &models.Transaction{
+		TransactionId:     4,
+		TransactionTime:   1725230000000,
+		Type:              models.TRANSACTION_DB_TYPE_EXPENSE,
+		TimezoneUtcOffset: 480,
+		CategoryId:        4,
+		AccountId:         1,
+		Amount:            1234,
+	}
+	transactions[3] = &models.Transaction{
+		TransactionId:        3,
+		TransactionTime:      1725160000000,
+		Type:                 models.TRANSACTION_DB_TYPE_TRANSFER_OUT,
+		TimezoneUtcOffset:    -300,
+		AccountId:            1,
+		Amount:               10000,
+		RelatedAccountId:     2,
+		RelatedAccountAmount: 10000,
+		Comment:              "Repay",
+	}
+	transactions[4] = &models.Transaction{
+		TransactionId:     2,
+		TransactionTime:   1725150000000,
+		Type:              models.TRANSACTION_DB_TYPE_INCOME,
+		TimezoneUtcOffset: 0,
+		CategoryId:        2,
+		AccountId:         1,
+		Amount:            12345,
+		Comment:           "Hello\nWorld",
+	}
+	transactions[5] = &models.Transaction{
+		TransactionId:     1,
+		TransactionTime:   1725148800000,
+		Type:              models.TRANSACTION_DB_TYPE_MODIFY_BALANCE,
+		TimezoneUtcOffset: 0,
+		AccountId:         1,
+		Amount:            100000,
+	}
+
+	accountMap := make(map[int64]*models.Account, 3)
+	accountMap[1] = &models.Account{
+		AccountId: 1,
+		Name:      "Test Account",
+		Category:  models.ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Currency:  "CNY",
+	}
+	accountMap[2] = &models.Account{
+		AccountId: 2,
+		Name:      "Test Credit Card",
+		Category:  models.ACCOUNT_CATEGORY_CREDIT_CARD,
+		Currency:  "CNY",
+	}
+	accountMap[3] = &models.Account{
+		AccountId: 3,
+		Name:      "Test Cash",
+		Category:  models.ACCOUNT_CATEGORY_CASH,
+		Currency:  "CNY",
+		Comment:   "Cash in wallet",
+	}
+
+	categoryMap := make(map[int64]*models.TransactionCategory, 7)
+	categoryMap[1] = &models.TransactionCategory{
+		CategoryId: 1,
+		Type:       models.CATEGORY_TYPE_INCOME,
+		Name:       "Test Category",
+	}
+	categoryMap[2] = &models.TransactionCategory{
+		CategoryId:       2,
+		Type:             models.CATEGORY_TYPE_INCOME,
+		ParentCategoryId: 1,
+		Name:             "Test Sub Category",
+	}
+	categoryMap[3] = &models.TransactionCategory{
+		CategoryId: 3,
+		Type:       models.CATEGORY_TYPE_EXPENSE,
+		Name:       "Test Category2",
+	}
+	categoryMap[4] = &models.TransactionCategory{
+		CategoryId:       4,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 3,
+		Name:             "Test Sub Category2",
+		DisplayOrder:     1,
+	}
+	categoryMap[5] = &models.TransactionCategory{
+		CategoryId:       5,
+		Type:             models.CATEGORY_TYPE_EXPENSE,
+		ParentCategoryId: 3,
+		Name:             "Test Sub Category3",
+		DisplayOrder:     2,
+	}
+	categoryMap[6] = &models.TransactionCategory{
+		CategoryId: 6,
+		Type:       models.CATEGORY_TYPE_TRANSFER,
+		Name:       "Test Category3",
+	}
+	categoryMap[7] = &models.TransactionCategory{
+		CategoryId:       7,
+		Type:             models.CATEGORY_TYPE_TRANSFER,
+		ParentCategoryId: 6,
+		Name:             "Test Sub Category4",
+	}
+
+	allSplits := make(map[int64]models.TransactionSplitSlice, 1)
+	allSplits[4] = models.TransactionSplitSlice{
+		{TransactionId: 4, CategoryId: 4, Amount: 1000, Comment: "Foo"},
+		{TransactionId: 4, CategoryId: 5, Amount: 234},
+	}
+
+	return transactions, accountMap, categoryMap, allSplits
+}
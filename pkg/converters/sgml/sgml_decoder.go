@@ -4,6 +4,7 @@ import (
 	"encoding/xml"
 	"io"
 	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -246,7 +247,11 @@ func (d *Decoder) getStructTypeInfo(reflectType reflect.Type) (*sgmlTypeInfo, er
 			sgmlFieldName = field.Tag.Get(xmlTagName)
 		}
 
-		if sgmlFieldName == "" || field.Name == sgmlNameFieldName || field.Name == xmlNameFieldName {
+		if commaIndex := strings.Index(sgmlFieldName, ","); commaIndex >= 0 { // ignore the options (e.g. omitempty) in tag
+			sgmlFieldName = sgmlFieldName[:commaIndex]
+		}
+
+		if sgmlFieldName == "" || sgmlFieldName == "-" || field.Name == sgmlNameFieldName || field.Name == xmlNameFieldName {
 			continue
 		}
 
@@ -1,17 +1,24 @@
 package converters
 
 import (
+	"github.com/mayswind/ezbookkeeping/pkg/converters/actualbudget"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/alipay"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/camt"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/custom"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/default"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/feidee"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/fireflyIII"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/gnucash"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/iif"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/journal"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/mt940"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/ofx"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/qif"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/wechat"
+	"github.com/mayswind/ezbookkeeping/pkg/converters/ynab"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
 )
 
 // GetTransactionDataExporter returns the transaction data exporter according to the file type
@@ -20,21 +27,48 @@ func GetTransactionDataExporter(fileType string) base.TransactionDataExporter {
 		return _default.DefaultTransactionDataCSVFileConverter
 	} else if fileType == "tsv" {
 		return _default.DefaultTransactionDataTSVFileConverter
+	} else if fileType == "xlsx" {
+		return _default.DefaultTransactionDataXlsxFileConverter
+	} else if fileType == "ofx" {
+		return ofx.OFXTransactionDataExporter
+	} else if fileType == "qif_ymd" {
+		return qif.QifYearMonthDayTransactionDataExporter
+	} else if fileType == "qif_mdy" {
+		return qif.QifMonthDayYearTransactionDataExporter
+	} else if fileType == "qif_dmy" {
+		return qif.QifDayMonthYearTransactionDataExporter
+	} else if fileType == "beancount" {
+		return journal.BeancountTransactionDataExporter
+	} else if fileType == "hledger" {
+		return journal.HledgerTransactionDataExporter
 	} else {
 		return nil
 	}
 }
 
+// GetXlsxTransactionDataExporter returns the excel xlsx transaction data exporter which also exports the specified optional sheets
+func GetXlsxTransactionDataExporter(withAccountsSheet bool, withCategoriesSheet bool, withMonthlySummarySheet bool) base.TransactionDataExporter {
+	return _default.CreateNewDefaultTransactionDataXlsxFileConverter(withAccountsSheet, withCategoriesSheet, withMonthlySummarySheet)
+}
+
 // GetTransactionDataImporter returns the transaction data importer according to the file type
 func GetTransactionDataImporter(fileType string) (base.TransactionDataImporter, error) {
 	if fileType == "ezbookkeeping_csv" {
 		return _default.DefaultTransactionDataCSVFileConverter, nil
 	} else if fileType == "ezbookkeeping_tsv" {
 		return _default.DefaultTransactionDataTSVFileConverter, nil
+	} else if fileType == "ezbookkeeping_xlsx" {
+		return _default.DefaultTransactionDataXlsxFileConverter, nil
 	} else if fileType == "ofx" {
 		return ofx.OFXTransactionDataImporter, nil
 	} else if fileType == "qfx" {
 		return ofx.OFXTransactionDataImporter, nil
+	} else if fileType == "camt053" {
+		return camt.CAMTTransactionDataImporter, nil
+	} else if fileType == "camt052" {
+		return camt.CAMTTransactionDataImporter, nil
+	} else if fileType == "mt940" {
+		return mt940.MT940TransactionDataImporter, nil
 	} else if fileType == "qif_ymd" {
 		return qif.QifYearMonthDayTransactionDataImporter, nil
 	} else if fileType == "qif_mdy" {
@@ -45,8 +79,20 @@ func GetTransactionDataImporter(fileType string) (base.TransactionDataImporter,
 		return iif.IifTransactionDataFileImporter, nil
 	} else if fileType == "gnucash" {
 		return gnucash.GnuCashTransactionDataImporter, nil
+	} else if fileType == "beancount" {
+		return journal.BeancountTransactionDataImporter, nil
+	} else if fileType == "ledger" {
+		return journal.LedgerTransactionDataImporter, nil
 	} else if fileType == "firefly_iii_csv" {
 		return fireflyIII.FireflyIIITransactionDataCsvFileImporter, nil
+	} else if fileType == "ynab_csv_ymd" {
+		return ynab.YnabYearMonthDayTransactionDataCsvFileImporter, nil
+	} else if fileType == "ynab_csv_mdy" {
+		return ynab.YnabMonthDayYearTransactionDataCsvFileImporter, nil
+	} else if fileType == "ynab_csv_dmy" {
+		return ynab.YnabDayMonthYearTransactionDataCsvFileImporter, nil
+	} else if fileType == "actual_budget_csv" {
+		return actualbudget.ActualBudgetTransactionDataCsvFileImporter, nil
 	} else if fileType == "feidee_mymoney_csv" {
 		return feidee.FeideeMymoneyAppTransactionDataCsvFileImporter, nil
 	} else if fileType == "feidee_mymoney_xls" {
@@ -61,3 +107,14 @@ func GetTransactionDataImporter(fileType string) (base.TransactionDataImporter,
 		return nil, errs.ErrImportFileTypeNotSupported
 	}
 }
+
+// GetCustomTransactionDataImporter returns the transaction data importer according to the column mapping of transaction import profile
+func GetCustomTransactionDataImporter(profile *models.TransactionImportProfile) (base.TransactionDataImporter, error) {
+	importer, err := custom.CreateNewCustomTransactionDataFileImporter(profile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return importer, nil
+}
@@ -3,17 +3,19 @@ package wechat
 import (
 	"bytes"
 	"encoding/csv"
-	"golang.org/x/text/encoding/unicode"
-	"golang.org/x/text/transform"
 	"io"
 	"strings"
 
+	"golang.org/x/text/encoding"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/base"
 	csvdatatable "github.com/mayswind/ezbookkeeping/pkg/converters/csv"
 	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
 	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
 	"github.com/mayswind/ezbookkeeping/pkg/log"
 	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
 var wechatPayTransactionSupportedColumns = map[datatable.TransactionDataTableColumn]bool{
@@ -36,6 +38,7 @@ var wechatPayTransactionTypeNameMapping = map[models.TransactionType]string{
 type wechatPayTransactionDataCsvFileImporter struct {
 	fileHeaderLineBeginning         string
 	dataHeaderStartContentBeginning string
+	textEncoding                    encoding.Encoding
 }
 
 // Initialize a webchat pay transaction data csv file importer singleton instance
@@ -45,8 +48,14 @@ var (
 
 // ParseImportedData returns the imported data by parsing the wechat pay transaction csv data
 func (c *wechatPayTransactionDataCsvFileImporter) ParseImportedData(ctx core.Context, user *models.User, data []byte, defaultTimezoneOffset int16, accountMap map[string]*models.Account, expenseCategoryMap map[string]*models.TransactionCategory, incomeCategoryMap map[string]*models.TransactionCategory, transferCategoryMap map[string]*models.TransactionCategory, tagMap map[string]*models.TransactionTag) (models.ImportedTransactionSlice, []*models.Account, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionCategory, []*models.TransactionTag, error) {
-	fallback := unicode.UTF8.NewDecoder()
-	reader := transform.NewReader(bytes.NewReader(data), unicode.BOMOverride(fallback))
+	content, err := utils.DecodeTextData(data, c.textEncoding)
+
+	if err != nil {
+		log.Errorf(ctx, "[wechat_pay_transaction_data_csv_file_importer.ParseImportedData] cannot decode imported data, because %s", err.Error())
+		return nil, nil, nil, nil, nil, nil, errs.ErrInvalidCSVFile
+	}
+
+	reader := bytes.NewReader(content)
 
 	dataTable, err := c.createNewWeChatPayImportedDataTable(ctx, reader)
 
@@ -72,6 +81,14 @@ func (c *wechatPayTransactionDataCsvFileImporter) ParseImportedData(ctx core.Con
 	return dataTableImporter.ParseImportedData(ctx, user, transactionDataTable, defaultTimezoneOffset, accountMap, expenseCategoryMap, incomeCategoryMap, transferCategoryMap, tagMap)
 }
 
+// WithTextEncoding returns a copy of the importer which decodes the imported data by the specified text encoding
+func (c *wechatPayTransactionDataCsvFileImporter) WithTextEncoding(enc encoding.Encoding) base.TransactionDataImporter {
+	importer := *c
+	importer.textEncoding = enc
+
+	return &importer
+}
+
 func (c *wechatPayTransactionDataCsvFileImporter) createNewWeChatPayImportedDataTable(ctx core.Context, reader io.Reader) (datatable.ImportedDataTable, error) {
 	csvReader := csv.NewReader(reader)
 	csvReader.FieldsPerRecord = -1
@@ -0,0 +1,327 @@
+package ynab
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+func TestYnabCsvFileImporterParseImportedData_MinimumValidData(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	data := "\"Account\",\"Flag\",\"Date\",\"Payee\",\"Category Group/Category\",\"Category Group\",\"Category\",\"Memo\",\"Outflow\",\"Inflow\",\"Cleared\"\n" +
+		"\"Checking\",\"\",\"09/01/2024\",\"Starting Balance\",\"Inflow: Ready to Assign\",\"Inflow\",\"Ready to Assign\",\"\",$0.00,$123.45,\"Reconciled\"\n" +
+		"\"Checking\",\"\",\"09/02/2024\",\"Employer\",\"Inflow: Ready to Assign\",\"Inflow\",\"Ready to Assign\",\"\",$0.00,$0.12,\"Cleared\"\n" +
+		"\"Checking\",\"\",\"09/03/2024\",\"Grocery Store\",\"Everyday Expenses: Groceries\",\"Everyday Expenses\",\"Groceries\",\"\",$1.00,$0.00,\"Cleared\"\n" +
+		"\"Checking\",\"\",\"09/04/2024\",\"Transfer : Savings\",\"\",\"\",\"\",\"\",$0.05,$0.00,\"Cleared\"\n" +
+		"\"Savings\",\"\",\"09/04/2024\",\"Transfer : Checking\",\"\",\"\",\"\",\"\",$0.00,$0.05,\"Cleared\"\n"
+	allNewTransactions, allNewAccounts, allNewSubExpenseCategories, allNewSubIncomeCategories, allNewSubTransferCategories, allNewTags, err := converter.ParseImportedData(context, user, []byte(data), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, 2, len(allNewAccounts))
+	assert.Equal(t, 1, len(allNewSubExpenseCategories))
+	assert.Equal(t, 1, len(allNewSubIncomeCategories))
+	assert.Equal(t, 1, len(allNewSubTransferCategories))
+	assert.Equal(t, 0, len(allNewTags))
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[0].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_MODIFY_BALANCE, allNewTransactions[0].Type)
+	assert.Equal(t, "2024-09-01 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+	assert.Equal(t, int64(12345), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "", allNewTransactions[0].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[1].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_INCOME, allNewTransactions[1].Type)
+	assert.Equal(t, "2024-09-02 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[1].TransactionTime), time.UTC))
+	assert.Equal(t, int64(12), allNewTransactions[1].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Inflow", allNewTransactions[1].OriginalParentCategoryName)
+	assert.Equal(t, "Ready to Assign", allNewTransactions[1].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[2].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, "2024-09-03 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[2].TransactionTime), time.UTC))
+	assert.Equal(t, int64(100), allNewTransactions[2].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[2].OriginalSourceAccountName)
+	assert.Equal(t, "Everyday Expenses", allNewTransactions[2].OriginalParentCategoryName)
+	assert.Equal(t, "Groceries", allNewTransactions[2].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewTransactions[3].Uid)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[3].Type)
+	assert.Equal(t, "2024-09-04 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[3].TransactionTime), time.UTC))
+	assert.Equal(t, int64(5), allNewTransactions[3].Amount)
+	assert.Equal(t, int64(5), allNewTransactions[3].RelatedAccountAmount)
+	assert.Equal(t, "Checking", allNewTransactions[3].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[3].OriginalDestinationAccountName)
+	assert.Equal(t, "", allNewTransactions[3].OriginalCategoryName)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[0].Uid)
+	assert.Equal(t, "Checking", allNewAccounts[0].Name)
+	assert.Equal(t, "USD", allNewAccounts[0].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewAccounts[1].Uid)
+	assert.Equal(t, "Savings", allNewAccounts[1].Name)
+	assert.Equal(t, "USD", allNewAccounts[1].Currency)
+
+	assert.Equal(t, int64(1234567890), allNewSubExpenseCategories[0].Uid)
+	assert.Equal(t, "Groceries", allNewSubExpenseCategories[0].Name)
+
+	assert.Equal(t, int64(1234567890), allNewSubIncomeCategories[0].Uid)
+	assert.Equal(t, "Ready to Assign", allNewSubIncomeCategories[0].Name)
+
+	assert.Equal(t, int64(1234567890), allNewSubTransferCategories[0].Uid)
+	assert.Equal(t, "", allNewSubTransferCategories[0].Name)
+}
+
+func TestYnabCsvFileImporterParseImportedData_ParseDateFormat(t *testing.T) {
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := YnabYearMonthDayTransactionDataCsvFileImporter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow,Inflow\n"+
+		"Checking,2024/09/05,Grocery Store,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+
+	allNewTransactions, _, _, _, _, _, err = YnabMonthDayYearTransactionDataCsvFileImporter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow,Inflow\n"+
+		"Checking,09/05/2024,Grocery Store,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+
+	allNewTransactions, _, _, _, _, _, err = YnabDayMonthYearTransactionDataCsvFileImporter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow,Inflow\n"+
+		"Checking,5.9.2024,Grocery Store,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, "2024-09-05 00:00:00", utils.FormatUnixTimeToLongDateTime(utils.GetUnixTimeFromTransactionTime(allNewTransactions[0].TransactionTime), time.UTC))
+}
+
+func TestYnabCsvFileImporterParseImportedData_ParseInvalidTime(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow,Inflow\n"+
+		"Checking,2024-09-05,Grocery Store,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow,Inflow\n"+
+		"Checking,13/05/2024,Grocery Store,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrTransactionTimeInvalid.Message)
+}
+
+func TestYnabCsvFileImporterParseImportedData_ParseAmount(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow,Inflow\n"+
+		"Checking,09/01/2024,Store,\"$1,234.56\",$0.00\n"+
+		"Checking,09/02/2024,Store,\"1.234,56 €\",\"0,00 €\"\n"+
+		"Checking,09/03/2024,Store,\"€1.234.567\",\"€0\"\n"+
+		"Checking,09/04/2024,Store,\"12,3\",\"\"\n"+
+		"Checking,09/05/2024,Refund,\"\",\"-$0.50\"\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 5, len(allNewTransactions))
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[0].Type)
+	assert.Equal(t, int64(123456), allNewTransactions[0].Amount)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[1].Type)
+	assert.Equal(t, int64(123456), allNewTransactions[1].Amount)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[2].Type)
+	assert.Equal(t, int64(123456700), allNewTransactions[2].Amount)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[3].Type)
+	assert.Equal(t, int64(1230), allNewTransactions[3].Amount)
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_EXPENSE, allNewTransactions[4].Type)
+	assert.Equal(t, int64(50), allNewTransactions[4].Amount)
+}
+
+func TestYnabCsvFileImporterParseImportedData_ParseInvalidAmount(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow,Inflow\n"+
+		"Checking,09/01/2024,Store,\"$0.123\",$0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrAmountInvalid.Message)
+}
+
+func TestYnabCsvFileImporterParseImportedData_ParseCategory(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Category Group/Category,Outflow,Inflow\n"+
+		"Checking,09/01/2024,Store,Bills: Rent,1.00,0.00\n"+
+		"Checking,09/02/2024,Store,Uncategorized,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, "Bills", allNewTransactions[0].OriginalParentCategoryName)
+	assert.Equal(t, "Rent", allNewTransactions[0].OriginalCategoryName)
+	assert.Equal(t, "", allNewTransactions[1].OriginalParentCategoryName)
+	assert.Equal(t, "Uncategorized", allNewTransactions[1].OriginalCategoryName)
+}
+
+func TestYnabCsvFileImporterParseImportedData_ParseFlagAsTag(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, allNewTags, err := converter.ParseImportedData(context, user, []byte("Account,Flag,Date,Payee,Outflow,Inflow\n"+
+		"Checking,Red,09/01/2024,Store,1.00,0.00\n"+
+		"Checking,,09/02/2024,Store,1.00,0.00\n"+
+		"Checking,Red,09/03/2024,Store,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 3, len(allNewTransactions))
+	assert.Equal(t, 1, len(allNewTags))
+	assert.Equal(t, "Red", allNewTags[0].Name)
+
+	assert.Equal(t, []string{"Red"}, allNewTransactions[0].OriginalTagNames)
+	assert.Equal(t, 0, len(allNewTransactions[1].OriginalTagNames))
+	assert.Equal(t, []string{"Red"}, allNewTransactions[2].OriginalTagNames)
+}
+
+func TestYnabCsvFileImporterParseImportedData_ParseDescription(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Memo,Outflow,Inflow\n"+
+		"Checking,09/01/2024,Grocery Store,weekly shopping,1.00,0.00\n"+
+		"Checking,09/02/2024,Grocery Store,,1.00,0.00\n"+
+		"Checking,09/03/2024,,weekly shopping,1.00,0.00\n"+
+		"Checking,09/04/2024,Transfer : Savings,monthly saving,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 4, len(allNewTransactions))
+	assert.Equal(t, "Grocery Store - weekly shopping", allNewTransactions[0].Comment)
+	assert.Equal(t, "Grocery Store", allNewTransactions[1].Comment)
+	assert.Equal(t, "weekly shopping", allNewTransactions[2].Comment)
+	assert.Equal(t, "monthly saving", allNewTransactions[3].Comment)
+}
+
+func TestYnabCsvFileImporterParseImportedData_SkipInflowSideOfTransfer(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow,Inflow\n"+
+		"Savings,09/01/2024,Transfer : Checking,0.00,1.00\n"+
+		"Checking,09/01/2024,Transfer : Savings,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(allNewTransactions))
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, int64(100), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[0].OriginalDestinationAccountName)
+}
+
+func TestYnabCsvFileImporterParseImportedData_ParseUnmatchedInflowSideOfTransfer(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	allNewTransactions, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow,Inflow\n"+
+		"Savings,09/01/2024,Transfer : Checking,0.00,1.00\n"+
+		"Checking,09/02/2024,Transfer : Savings,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, len(allNewTransactions))
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[0].Type)
+	assert.Equal(t, int64(100), allNewTransactions[0].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[0].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[0].OriginalDestinationAccountName)
+
+	assert.Equal(t, models.TRANSACTION_DB_TYPE_TRANSFER_OUT, allNewTransactions[1].Type)
+	assert.Equal(t, int64(100), allNewTransactions[1].Amount)
+	assert.Equal(t, "Checking", allNewTransactions[1].OriginalSourceAccountName)
+	assert.Equal(t, "Savings", allNewTransactions[1].OriginalDestinationAccountName)
+}
+
+func TestYnabCsvFileImporterParseImportedData_MissingRequiredColumn(t *testing.T) {
+	converter := YnabMonthDayYearTransactionDataCsvFileImporter
+	context := core.NewNullContext()
+
+	user := &models.User{
+		Uid:             1234567890,
+		DefaultCurrency: "USD",
+	}
+
+	// Missing Account Column
+	_, _, _, _, _, _, err := converter.ParseImportedData(context, user, []byte("Date,Payee,Outflow,Inflow\n"+
+		"09/01/2024,Store,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	// Missing Date Column
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Account,Payee,Outflow,Inflow\n"+
+		"Checking,Store,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	// Missing Payee Column
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Account,Date,Outflow,Inflow\n"+
+		"Checking,09/01/2024,1.00,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	// Missing Outflow Column
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Inflow\n"+
+		"Checking,09/01/2024,Store,0.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+
+	// Missing Inflow Column
+	_, _, _, _, _, _, err = converter.ParseImportedData(context, user, []byte("Account,Date,Payee,Outflow\n"+
+		"Checking,09/01/2024,Store,1.00\n"), 0, nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, errs.ErrMissingRequiredFieldInHeaderRow.Message)
+}
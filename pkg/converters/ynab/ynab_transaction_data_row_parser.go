@@ -0,0 +1,319 @@
+package ynab
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/converters/datatable"
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const ynabTransactionAccountColumnName = "Account"
+const ynabTransactionFlagColumnName = "Flag"
+const ynabTransactionDateColumnName = "Date"
+const ynabTransactionPayeeColumnName = "Payee"
+const ynabTransactionCategoryGroupAndCategoryColumnName = "Category Group/Category"
+const ynabTransactionCategoryGroupColumnName = "Category Group"
+const ynabTransactionCategoryColumnName = "Category"
+const ynabTransactionMemoColumnName = "Memo"
+const ynabTransactionOutflowColumnName = "Outflow"
+const ynabTransactionInflowColumnName = "Inflow"
+
+const ynabTransactionTransferPayeePrefix = "Transfer :"
+const ynabTransactionStartingBalancePayee = "Starting Balance"
+const ynabTransactionCategoryGroupAndCategorySeparator = ": "
+
+// ynabDateFormatType represents the you need a budget (ynab) date format type
+type ynabDateFormatType byte
+
+const (
+	ynabYearMonthDayDateFormat ynabDateFormatType = 0
+	ynabMonthDayYearDateFormat ynabDateFormatType = 1
+	ynabDayMonthYearDateFormat ynabDateFormatType = 2
+)
+
+// ynabTransactionDataRowParser defines the structure of you need a budget (ynab) transaction data row parser
+type ynabTransactionDataRowParser struct {
+	dateFormatType              ynabDateFormatType
+	matchedTransferInflowRowIds map[string]bool
+}
+
+// Parse returns the converted transaction data row
+func (p *ynabTransactionDataRowParser) Parse(ctx core.Context, user *models.User, dataTable *datatable.CommonTransactionDataTable, dataRow datatable.CommonDataRow, rowId string) (rowData map[datatable.TransactionDataTableColumn]string, rowDataValid bool, err error) {
+	data := make(map[datatable.TransactionDataTableColumn]string, len(ynabTransactionSupportedColumns))
+
+	transactionTime, err := p.parseTransactionTime(ctx, dataRow.GetData(ynabTransactionDateColumnName))
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TIME] = transactionTime
+
+	outflow, err := p.parseAmount(dataRow.GetData(ynabTransactionOutflowColumnName))
+
+	if err != nil {
+		log.Errorf(ctx, "[ynab_transaction_data_row_parser.Parse] cannot parse outflow \"%s\" of transaction in row \"%s\"", dataRow.GetData(ynabTransactionOutflowColumnName), rowId)
+		return nil, false, errs.ErrAmountInvalid
+	}
+
+	inflow, err := p.parseAmount(dataRow.GetData(ynabTransactionInflowColumnName))
+
+	if err != nil {
+		log.Errorf(ctx, "[ynab_transaction_data_row_parser.Parse] cannot parse inflow \"%s\" of transaction in row \"%s\"", dataRow.GetData(ynabTransactionInflowColumnName), rowId)
+		return nil, false, errs.ErrAmountInvalid
+	}
+
+	amount := inflow - outflow
+	payee := strings.TrimSpace(dataRow.GetData(ynabTransactionPayeeColumnName))
+	memo := ""
+
+	if dataTable.HasOriginalColumn(ynabTransactionMemoColumnName) {
+		memo = strings.TrimSpace(dataRow.GetData(ynabTransactionMemoColumnName))
+	}
+
+	categoryGroup, category := p.getCategoryGroupAndCategory(dataTable, dataRow)
+
+	data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = dataRow.GetData(ynabTransactionAccountColumnName)
+	data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = ""
+	data[datatable.TRANSACTION_DATA_TABLE_CATEGORY] = categoryGroup
+	data[datatable.TRANSACTION_DATA_TABLE_SUB_CATEGORY] = category
+
+	if dataTable.HasOriginalColumn(ynabTransactionFlagColumnName) {
+		data[datatable.TRANSACTION_DATA_TABLE_TAGS] = strings.TrimSpace(dataRow.GetData(ynabTransactionFlagColumnName))
+	}
+
+	if strings.HasPrefix(payee, ynabTransactionTransferPayeePrefix) {
+		// both sides of transfer are exported in ynab register, so the inflow side which matches an outflow side is skipped
+		if p.matchedTransferInflowRowIds[rowId] {
+			log.Warnf(ctx, "[ynab_transaction_data_row_parser.Parse] skip parsing transaction in row \"%s\", because it is the inflow side of transfer", rowId)
+			return nil, false, nil
+		}
+
+		relatedAccountName := strings.TrimSpace(payee[len(ynabTransactionTransferPayeePrefix):])
+
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_TRANSFER]
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = memo
+
+		if amount > 0 {
+			data[datatable.TRANSACTION_DATA_TABLE_ACCOUNT_NAME] = relatedAccountName
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = dataRow.GetData(ynabTransactionAccountColumnName)
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		} else {
+			data[datatable.TRANSACTION_DATA_TABLE_RELATED_ACCOUNT_NAME] = relatedAccountName
+			data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+		}
+
+		return data, true, nil
+	}
+
+	if payee == ynabTransactionStartingBalancePayee {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_MODIFY_BALANCE]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = memo
+
+		return data, true, nil
+	}
+
+	if amount < 0 {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_EXPENSE]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(-amount)
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_TRANSACTION_TYPE] = ynabTransactionTypeNameMapping[models.TRANSACTION_TYPE_INCOME]
+		data[datatable.TRANSACTION_DATA_TABLE_AMOUNT] = utils.FormatAmount(amount)
+	}
+
+	if payee != "" && memo != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = payee + " - " + memo
+	} else if payee != "" {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = payee
+	} else {
+		data[datatable.TRANSACTION_DATA_TABLE_DESCRIPTION] = memo
+	}
+
+	return data, true, nil
+}
+
+func (p *ynabTransactionDataRowParser) getCategoryGroupAndCategory(dataTable *datatable.CommonTransactionDataTable, dataRow datatable.CommonDataRow) (string, string) {
+	if dataTable.HasOriginalColumn(ynabTransactionCategoryColumnName) {
+		categoryGroup := ""
+
+		if dataTable.HasOriginalColumn(ynabTransactionCategoryGroupColumnName) {
+			categoryGroup = strings.TrimSpace(dataRow.GetData(ynabTransactionCategoryGroupColumnName))
+		}
+
+		return categoryGroup, strings.TrimSpace(dataRow.GetData(ynabTransactionCategoryColumnName))
+	}
+
+	if dataTable.HasOriginalColumn(ynabTransactionCategoryGroupAndCategoryColumnName) {
+		categoryGroupAndCategory := dataRow.GetData(ynabTransactionCategoryGroupAndCategoryColumnName)
+		separatorIndex := strings.Index(categoryGroupAndCategory, ynabTransactionCategoryGroupAndCategorySeparator)
+
+		if separatorIndex < 0 {
+			return "", strings.TrimSpace(categoryGroupAndCategory)
+		}
+
+		return strings.TrimSpace(categoryGroupAndCategory[:separatorIndex]), strings.TrimSpace(categoryGroupAndCategory[separatorIndex+len(ynabTransactionCategoryGroupAndCategorySeparator):])
+	}
+
+	return "", ""
+}
+
+func (p *ynabTransactionDataRowParser) parseTransactionTime(ctx core.Context, date string) (string, error) {
+	var year, month, day string
+
+	if (p.dateFormatType == ynabYearMonthDayDateFormat && utils.IsValidYearMonthDayLongOrShortDateFormat(date)) ||
+		(p.dateFormatType == ynabMonthDayYearDateFormat && utils.IsValidMonthDayYearLongOrShortDateFormat(date)) ||
+		(p.dateFormatType == ynabDayMonthYearDateFormat && utils.IsValidDayMonthYearLongOrShortDateFormat(date)) {
+		date = strings.ReplaceAll(date, ".", "-")
+		date = strings.ReplaceAll(date, "/", "-")
+		date = strings.ReplaceAll(date, "'", "-")
+		items := strings.Split(date, "-")
+
+		if p.dateFormatType == ynabYearMonthDayDateFormat {
+			year = items[0]
+			month = items[1]
+			day = items[2]
+		} else if p.dateFormatType == ynabMonthDayYearDateFormat {
+			month = items[0]
+			day = items[1]
+			year = items[2]
+		} else if p.dateFormatType == ynabDayMonthYearDateFormat {
+			day = items[0]
+			month = items[1]
+			year = items[2]
+		}
+	}
+
+	if year == "" || month == "" || day == "" {
+		log.Errorf(ctx, "[ynab_transaction_data_row_parser.parseTransactionTime] cannot parse date \"%s\"", date)
+		return "", errs.ErrTransactionTimeInvalid
+	}
+
+	if len(month) < 2 {
+		month = "0" + month
+	}
+
+	if len(day) < 2 {
+		day = "0" + day
+	}
+
+	return fmt.Sprintf("%s-%s-%s 00:00:00", year, month, day), nil
+}
+
+// parseAmount returns the amount which is formatted by the currency format of ynab budget (e.g. $1,234.56 or 1.234,56€)
+func (p *ynabTransactionDataRowParser) parseAmount(amount string) (int64, error) {
+	var builder strings.Builder
+	negative := false
+
+	for i := 0; i < len(amount); i++ {
+		if amount[i] == '-' {
+			negative = true
+		} else if (amount[i] >= '0' && amount[i] <= '9') || amount[i] == '.' || amount[i] == ',' {
+			builder.WriteByte(amount[i])
+		}
+	}
+
+	number := builder.String()
+
+	if number == "" {
+		return 0, nil
+	}
+
+	lastDotIndex := strings.LastIndex(number, ".")
+	lastCommaIndex := strings.LastIndex(number, ",")
+
+	// the last separator is decimal separator if there are both dot and comma, or there is only one comma which is followed by one or two digits
+	if lastCommaIndex > lastDotIndex && (lastDotIndex >= 0 || (strings.Count(number, ",") == 1 && len(number)-lastCommaIndex-1 <= 2)) {
+		number = strings.ReplaceAll(number, ".", "")
+		number = strings.Replace(number, ",", ".", 1)
+	} else if lastDotIndex >= 0 && strings.Count(number, ".") > 1 {
+		number = strings.ReplaceAll(number, ".", "")
+		number = strings.ReplaceAll(number, ",", "")
+	} else {
+		number = strings.ReplaceAll(number, ",", "")
+	}
+
+	result, err := utils.ParseAmount(utils.TrimTrailingZerosInDecimal(number))
+
+	if err != nil {
+		return 0, err
+	}
+
+	if negative {
+		return -result, nil
+	}
+
+	return result, nil
+}
+
+// getMatchedTransferInflowRowIds returns the row ids of inflow side of transfers which have the corresponding outflow side in the data table
+func (p *ynabTransactionDataRowParser) getMatchedTransferInflowRowIds(commonDataTable datatable.CommonDataTable) map[string]bool {
+	outflowTransferCounts := make(map[string]int)
+	inflowTransferRowIds := make([]string, 0)
+	inflowTransferKeys := make([]string, 0)
+	iterator := commonDataTable.DataRowIterator()
+
+	for iterator.HasNext() {
+		dataRow := iterator.Next()
+		rowId := iterator.CurrentRowId()
+		payee := strings.TrimSpace(dataRow.GetData(ynabTransactionPayeeColumnName))
+
+		if !strings.HasPrefix(payee, ynabTransactionTransferPayeePrefix) {
+			continue
+		}
+
+		outflow, err := p.parseAmount(dataRow.GetData(ynabTransactionOutflowColumnName))
+
+		if err != nil {
+			continue
+		}
+
+		inflow, err := p.parseAmount(dataRow.GetData(ynabTransactionInflowColumnName))
+
+		if err != nil {
+			continue
+		}
+
+		amount := inflow - outflow
+		accountName := strings.TrimSpace(dataRow.GetData(ynabTransactionAccountColumnName))
+		relatedAccountName := strings.TrimSpace(payee[len(ynabTransactionTransferPayeePrefix):])
+		date := strings.TrimSpace(dataRow.GetData(ynabTransactionDateColumnName))
+
+		if amount > 0 {
+			inflowTransferRowIds = append(inflowTransferRowIds, rowId)
+			inflowTransferKeys = append(inflowTransferKeys, p.getTransferKey(relatedAccountName, accountName, date, amount))
+		} else {
+			outflowTransferCounts[p.getTransferKey(accountName, relatedAccountName, date, -amount)]++
+		}
+	}
+
+	matchedInflowRowIds := make(map[string]bool, len(inflowTransferRowIds))
+
+	for i := 0; i < len(inflowTransferRowIds); i++ {
+		if outflowTransferCounts[inflowTransferKeys[i]] > 0 {
+			outflowTransferCounts[inflowTransferKeys[i]]--
+			matchedInflowRowIds[inflowTransferRowIds[i]] = true
+		}
+	}
+
+	return matchedInflowRowIds
+}
+
+func (p *ynabTransactionDataRowParser) getTransferKey(sourceAccountName string, destinationAccountName string, date string, amount int64) string {
+	return fmt.Sprintf("%s\n%s\n%s\n%d", sourceAccountName, destinationAccountName, date, amount)
+}
+
+// createYnabTransactionDataRowParser returns you need a budget (ynab) transaction data row parser
+func createYnabTransactionDataRowParser(commonDataTable datatable.CommonDataTable, dateFormatType ynabDateFormatType) datatable.CommonTransactionDataRowParser {
+	parser := &ynabTransactionDataRowParser{
+		dateFormatType: dateFormatType,
+	}
+	parser.matchedTransferInflowRowIds = parser.getMatchedTransferInflowRowIds(commonDataTable)
+
+	return parser
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"io"
 	"net/http/httputil"
 
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
@@ -18,6 +19,12 @@ type ApiHandlerFunc func(*WebContext) (any, *errs.Error)
 // DataHandlerFunc represents the handler function that returns file data byte array and file name
 type DataHandlerFunc func(*WebContext) ([]byte, string, *errs.Error)
 
+// DataStreamWriterFunc represents the function that writes file data to the specified writer
+type DataStreamWriterFunc func(io.Writer) error
+
+// DataStreamHandlerFunc represents the handler function that returns the file data writer function and file name
+type DataStreamHandlerFunc func(*WebContext) (DataStreamWriterFunc, string, *errs.Error)
+
 // ImageHandlerFunc represents the handler function that returns image byte array and content type
 type ImageHandlerFunc func(*WebContext) ([]byte, string, *errs.Error)
 
@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/exchangerates"
 	"github.com/mayswind/ezbookkeeping/pkg/services"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
 )
 
 // RemoveExpiredTokensJob represents the cron job which periodically remove expired user tokens from the database
@@ -22,7 +24,7 @@ var RemoveExpiredTokensJob = &CronJob{
 // CreateScheduledTransactionJob represents the cron job which periodically create transaction by scheduled transaction template
 var CreateScheduledTransactionJob = &CronJob{
 	Name:        "CreateScheduledTransaction",
-	Description: "Periodically create transaction by scheduled transaction template.",
+	Description: "Periodically create transaction by scheduled transaction template, and create the missed ones since last run.",
 	Period: CronJobEvery15MinutesPeriod{
 		Second: 0,
 	},
@@ -30,3 +32,21 @@ var CreateScheduledTransactionJob = &CronJob{
 		return services.Transactions.CreateScheduledTransactions(c, time.Now().Unix(), c.GetInterval())
 	},
 }
+
+// UpdateExchangeRatesJob represents the cron job which periodically save the latest exchange rates into the database
+var UpdateExchangeRatesJob = &CronJob{
+	Name:        "UpdateExchangeRates",
+	Description: "Periodically save the latest exchange rates from exchange rates data source into the database.",
+	Period: CronJobFixedHourPeriod{
+		Hour: 1,
+	},
+	Run: func(c *core.CronContext) error {
+		exchangeRateResponse, err := exchangerates.Container.GetLatestExchangeRates(c, 0, settings.Container.Current)
+
+		if err != nil {
+			return err
+		}
+
+		return services.ExchangeRates.SaveExchangeRates(c, exchangeRateResponse)
+	},
+}
@@ -0,0 +1,12 @@
+package errs
+
+import "net/http"
+
+// Error codes related to budgets
+var (
+	ErrBudgetIdInvalid           = NewNormalError(NormalSubcategoryBudget, 0, http.StatusBadRequest, "budget id is invalid")
+	ErrBudgetNotFound            = NewNormalError(NormalSubcategoryBudget, 1, http.StatusBadRequest, "budget not found")
+	ErrBudgetPeriodTypeInvalid   = NewNormalError(NormalSubcategoryBudget, 2, http.StatusBadRequest, "budget period type is invalid")
+	ErrBudgetRolloverTypeInvalid = NewNormalError(NormalSubcategoryBudget, 3, http.StatusBadRequest, "budget rollover type is invalid")
+	ErrBudgetCategoryTypeInvalid = NewNormalError(NormalSubcategoryBudget, 4, http.StatusBadRequest, "budget category type is invalid")
+)
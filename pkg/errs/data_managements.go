@@ -6,7 +6,12 @@ import (
 
 // Error codes related to data management
 var (
-	ErrDataExportNotAllowed     = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
-	ErrDataImportNotAllowed     = NewNormalError(NormalSubcategoryDataManagement, 2, http.StatusBadRequest, "data import not allowed")
-	ErrImportTooManyTransaction = NewNormalError(NormalSubcategoryDataManagement, 3, http.StatusBadRequest, "import too many transactions")
+	ErrDataExportNotAllowed              = NewNormalError(NormalSubcategoryDataManagement, 1, http.StatusBadRequest, "data export not allowed")
+	ErrDataImportNotAllowed              = NewNormalError(NormalSubcategoryDataManagement, 2, http.StatusBadRequest, "data import not allowed")
+	ErrImportTooManyTransaction          = NewNormalError(NormalSubcategoryDataManagement, 3, http.StatusBadRequest, "import too many transactions")
+	ErrUserDataBackupFileInvalid         = NewNormalError(NormalSubcategoryDataManagement, 4, http.StatusBadRequest, "user data backup file invalid")
+	ErrUserDataBackupVersionNotSupported = NewNormalError(NormalSubcategoryDataManagement, 5, http.StatusBadRequest, "user data backup version not supported")
+	ErrUserDataBackupReferenceInvalid    = NewNormalError(NormalSubcategoryDataManagement, 6, http.StatusBadRequest, "user data backup reference invalid")
+	ErrUserDataRestoreTargetUserNotEmpty = NewNormalError(NormalSubcategoryDataManagement, 7, http.StatusBadRequest, "user data restore target user not empty")
+	ErrUserDataBackupFileTooLarge        = NewNormalError(NormalSubcategoryDataManagement, 8, http.StatusBadRequest, "user data backup file too large")
 )
@@ -38,6 +38,12 @@ const (
 	NormalSubcategoryTemplate       = 10
 	NormalSubcategoryPicture        = 11
 	NormalSubcategoryConverter      = 12
+	NormalSubcategoryBudget         = 13
+	NormalSubcategoryRule           = 14
+	NormalSubcategoryExchangeRate   = 15
+	NormalSubcategoryImportProfile  = 16
+	NormalSubcategoryImportBatch    = 17
+	NormalSubcategoryImportJob      = 18
 )
 
 // Error represents the specific error returned to user
@@ -0,0 +1,13 @@
+package errs
+
+import "net/http"
+
+// Error codes related to exchange rates
+var (
+	ErrExchangeRateNotFound                       = NewNormalError(NormalSubcategoryExchangeRate, 0, http.StatusBadRequest, "exchange rate not found")
+	ErrExchangeRateInvalid                        = NewNormalError(NormalSubcategoryExchangeRate, 1, http.StatusBadRequest, "exchange rate is invalid")
+	ErrUserCustomExchangeRateIdInvalid            = NewNormalError(NormalSubcategoryExchangeRate, 2, http.StatusBadRequest, "custom exchange rate id is invalid")
+	ErrUserCustomExchangeRateNotFound             = NewNormalError(NormalSubcategoryExchangeRate, 3, http.StatusBadRequest, "custom exchange rate not found")
+	ErrUserCustomExchangeRateValidFromDateInvalid = NewNormalError(NormalSubcategoryExchangeRate, 4, http.StatusBadRequest, "custom exchange rate valid from date is invalid")
+	ErrUserCustomExchangeRateCurrencySameAsBase   = NewNormalError(NormalSubcategoryExchangeRate, 5, http.StatusBadRequest, "custom exchange rate currency cannot be the same as base currency")
+)
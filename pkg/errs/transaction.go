@@ -35,4 +35,10 @@ var (
 	ErrCannotAddTransactionBeforeBalanceModificationTransaction = NewSystemError(NormalSubcategoryTransaction, 28, http.StatusBadRequest, "cannot add transaction before balance modification transaction")
 	ErrBalanceModificationTransactionCannotModifyTime           = NewSystemError(NormalSubcategoryTransaction, 29, http.StatusBadRequest, "balance modification transaction cannot modify transaction time")
 	ErrTransferTransactionAmountCannotBeLessThanZero            = NewNormalError(NormalSubcategoryTransaction, 30, http.StatusBadRequest, "transfer transaction amount cannot be less than zero")
+	ErrTransactionSplitsNotAllowed                              = NewNormalError(NormalSubcategoryTransaction, 31, http.StatusBadRequest, "only income or expense transaction can be split")
+	ErrTransactionSplitsTooFew                                  = NewNormalError(NormalSubcategoryTransaction, 32, http.StatusBadRequest, "transaction must be split into at least two items")
+	ErrTransactionHasTooManySplits                              = NewNormalError(NormalSubcategoryTransaction, 33, http.StatusBadRequest, "transaction has too many splits")
+	ErrTransactionSplitsAmountNotEqual                          = NewNormalError(NormalSubcategoryTransaction, 34, http.StatusBadRequest, "total amount of transaction splits not equal to transaction amount")
+	ErrTransactionSplitHasTooManyTags                           = NewNormalError(NormalSubcategoryTransaction, 35, http.StatusBadRequest, "transaction split has too many tags")
+	ErrImportFileEncodingNotSupported                           = NewNormalError(NormalSubcategoryTransaction, 36, http.StatusBadRequest, "import file encoding not supported")
 )
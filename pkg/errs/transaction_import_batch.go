@@ -0,0 +1,9 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction import batches
+var (
+	ErrTransactionImportBatchIdInvalid = NewNormalError(NormalSubcategoryImportBatch, 0, http.StatusBadRequest, "transaction import batch id is invalid")
+	ErrTransactionImportBatchNotFound  = NewNormalError(NormalSubcategoryImportBatch, 1, http.StatusBadRequest, "transaction import batch not found")
+)
@@ -0,0 +1,12 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction import jobs
+var (
+	ErrTransactionImportJobIdInvalid       = NewNormalError(NormalSubcategoryImportJob, 0, http.StatusBadRequest, "transaction import job id is invalid")
+	ErrTransactionImportJobNotFound        = NewNormalError(NormalSubcategoryImportJob, 1, http.StatusBadRequest, "transaction import job not found")
+	ErrTransactionImportJobAlreadyRunning  = NewNormalError(NormalSubcategoryImportJob, 2, http.StatusBadRequest, "there is another transaction import job running")
+	ErrTransactionImportJobInterrupted     = NewNormalError(NormalSubcategoryImportJob, 3, http.StatusBadRequest, "transaction import job is interrupted")
+	ErrTransactionImportJobTooManyNewItems = NewNormalError(NormalSubcategoryImportJob, 4, http.StatusBadRequest, "too many new accounts or categories in transaction import job")
+)
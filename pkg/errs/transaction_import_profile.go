@@ -0,0 +1,14 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction import profiles
+var (
+	ErrTransactionImportProfileIdInvalid             = NewNormalError(NormalSubcategoryImportProfile, 0, http.StatusBadRequest, "transaction import profile id is invalid")
+	ErrTransactionImportProfileNotFound              = NewNormalError(NormalSubcategoryImportProfile, 1, http.StatusBadRequest, "transaction import profile not found")
+	ErrTransactionImportProfileColumnMappingInvalid  = NewNormalError(NormalSubcategoryImportProfile, 2, http.StatusBadRequest, "transaction import profile column mapping is invalid")
+	ErrTransactionImportProfileDateTimeFormatInvalid = NewNormalError(NormalSubcategoryImportProfile, 3, http.StatusBadRequest, "transaction import profile date time format is invalid")
+	ErrTransactionImportProfileNumberFormatInvalid   = NewNormalError(NormalSubcategoryImportProfile, 4, http.StatusBadRequest, "transaction import profile number format is invalid")
+	ErrTransactionImportProfileDelimiterInvalid      = NewNormalError(NormalSubcategoryImportProfile, 5, http.StatusBadRequest, "transaction import profile delimiter is invalid")
+	ErrTransactionImportProfileTypeValueInvalid      = NewNormalError(NormalSubcategoryImportProfile, 6, http.StatusBadRequest, "transaction import profile type value is invalid")
+)
@@ -0,0 +1,15 @@
+package errs
+
+import "net/http"
+
+// Error codes related to transaction rules
+var (
+	ErrTransactionRuleIdInvalid                   = NewNormalError(NormalSubcategoryRule, 0, http.StatusBadRequest, "transaction rule id is invalid")
+	ErrTransactionRuleNotFound                    = NewNormalError(NormalSubcategoryRule, 1, http.StatusBadRequest, "transaction rule not found")
+	ErrTransactionRuleKeywordInvalid              = NewNormalError(NormalSubcategoryRule, 2, http.StatusBadRequest, "transaction rule keyword is invalid")
+	ErrTransactionRuleAmountRangeInvalid          = NewNormalError(NormalSubcategoryRule, 3, http.StatusBadRequest, "transaction rule amount range is invalid")
+	ErrTransactionRuleHasNoAction                 = NewNormalError(NormalSubcategoryRule, 4, http.StatusBadRequest, "transaction rule has no action")
+	ErrTransactionRuleCategoryRequiresType        = NewNormalError(NormalSubcategoryRule, 5, http.StatusBadRequest, "transaction rule must match transaction type to set category")
+	ErrTransactionRuleDestinationRequiresTransfer = NewNormalError(NormalSubcategoryRule, 6, http.StatusBadRequest, "transaction rule must match transfer transaction to set destination account")
+	ErrTransactionRuleHasTooManyTags              = NewNormalError(NormalSubcategoryRule, 7, http.StatusBadRequest, "transaction rule has too many tags")
+)
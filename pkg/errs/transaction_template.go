@@ -10,4 +10,7 @@ var (
 	ErrScheduledTransactionNotEnabled       = NewNormalError(NormalSubcategoryTemplate, 3, http.StatusBadRequest, "scheduled transaction is not enabled")
 	ErrScheduledTransactionFrequencyInvalid = NewNormalError(NormalSubcategoryTemplate, 4, http.StatusBadRequest, "scheduled transaction frequency is invalid")
 	ErrTransactionTemplateHasTooManyTags    = NewNormalError(NormalSubcategoryTemplate, 5, http.StatusBadRequest, "transaction template has too many tags")
+	ErrScheduledTransactionTimeRangeInvalid = NewNormalError(NormalSubcategoryTemplate, 6, http.StatusBadRequest, "scheduled transaction time range is invalid")
+	ErrScheduledTransactionAlreadyCreated   = NewNormalError(NormalSubcategoryTemplate, 7, http.StatusBadRequest, "scheduled transaction has already been created")
+	ErrTransactionForecastTimeRangeInvalid  = NewNormalError(NormalSubcategoryTemplate, 8, http.StatusBadRequest, "transaction forecast time range is invalid")
 )
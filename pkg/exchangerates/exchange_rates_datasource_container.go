@@ -1,13 +1,34 @@
 package exchangerates
 
 import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
 	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
 	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
 
-// ExchangeRatesDataSourceContainer contains the current exchange rates data source
+// exchangeRatesMinRetryInterval is the minimum interval (in seconds) before requesting the data sources again after the last request failed
+const exchangeRatesMinRetryInterval = 60
+
+// ExchangeRatesDataSourceContainer contains the current exchange rates data sources
 type ExchangeRatesDataSourceContainer struct {
-	Current ExchangeRatesDataSource
+	DataSources         []ExchangeRatesDataSource
+	cacheMutex          sync.Mutex
+	cachedExchangeRates *models.LatestExchangeRateResponse
+	cachedUnixTime      int64
+	lastRequestFailed   bool
+	lastFailedUnixTime  int64
+	refreshingDone      chan struct{}
 }
 
 // Initialize a exchange rates data source container singleton instance
@@ -15,57 +36,402 @@ var (
 	Container = &ExchangeRatesDataSourceContainer{}
 )
 
-// InitializeExchangeRatesDataSource initializes the current exchange rates data source according to the config
+// InitializeExchangeRatesDataSource initializes the current exchange rates data sources according to the config
 func InitializeExchangeRatesDataSource(config *settings.Config) error {
-	if config.ExchangeRatesDataSource == settings.ReserveBankOfAustraliaDataSource {
-		Container.Current = &ReserveBankOfAustraliaDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfCanadaDataSource {
-		Container.Current = &BankOfCanadaDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CzechNationalBankDataSource {
-		Container.Current = &CzechNationalBankDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.DanmarksNationalbankDataSource {
-		Container.Current = &DanmarksNationalbankDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.EuroCentralBankDataSource {
-		Container.Current = &EuroCentralBankDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfGeorgiaDataSource {
-		Container.Current = &NationalBankOfGeorgiaDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfHungaryDataSource {
-		Container.Current = &CentralBankOfHungaryDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfIsraelDataSource {
-		Container.Current = &BankOfIsraelDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfMyanmarDataSource {
-		Container.Current = &CentralBankOfMyanmarDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NorgesBankDataSource {
-		Container.Current = &NorgesBankDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfPolandDataSource {
-		Container.Current = &NationalBankOfPolandDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.NationalBankOfRomaniaDataSource {
-		Container.Current = &NationalBankOfRomaniaDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.BankOfRussiaDataSource {
-		Container.Current = &BankOfRussiaDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.SwissNationalBankDataSource {
-		Container.Current = &SwissNationalBankDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.CentralBankOfUzbekistanDataSource {
-		Container.Current = &CentralBankOfUzbekistanDataSource{}
-		return nil
-	} else if config.ExchangeRatesDataSource == settings.InternationalMonetaryFundDataSource {
-		Container.Current = &InternationalMonetaryFundDataSource{}
-		return nil
-	}
-
-	return errs.ErrInvalidExchangeRatesDataSource
+	Container.clearCache()
+
+	dataSourceTypes := config.ExchangeRatesDataSources
+
+	if len(dataSourceTypes) < 1 {
+		dataSourceTypes = []string{config.ExchangeRatesDataSource}
+	}
+
+	dataSources := make([]ExchangeRatesDataSource, 0, len(dataSourceTypes))
+
+	for i := 0; i < len(dataSourceTypes); i++ {
+		dataSource := newExchangeRatesDataSource(dataSourceTypes[i])
+
+		if dataSource == nil {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		dataSources = append(dataSources, dataSource)
+	}
+
+	Container.DataSources = dataSources
+
+	return nil
+}
+
+// GetLatestExchangeRates returns the latest exchange rates data, the cached data would be returned if it is not expired or the data source is unavailable
+func (e *ExchangeRatesDataSourceContainer) GetLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	cacheExpiration := int64(currentConfig.ExchangeRatesCacheExpiration)
+
+	if cacheExpiration <= 0 {
+		return e.requestLatestExchangeRates(c, uid, currentConfig)
+	}
+
+	e.cacheMutex.Lock()
+	now := time.Now().Unix()
+
+	if e.cachedExchangeRates != nil && now-e.cachedUnixTime < cacheExpiration {
+		exchangeRateResponse := e.getCachedExchangeRates(now, false)
+		e.cacheMutex.Unlock()
+		return exchangeRateResponse, nil
+	}
+
+	// the data sources would not be requested again until the minimum retry interval elapsed after the last request failed
+	if e.lastRequestFailed && now-e.lastFailedUnixTime < exchangeRatesMinRetryInterval {
+		defer e.cacheMutex.Unlock()
+
+		if e.cachedExchangeRates == nil {
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		return e.getCachedExchangeRates(now, true), nil
+	}
+
+	// the data sources are only requested by one caller at the same time, and the other callers get the cached data or wait for the result without holding the lock
+	if e.refreshingDone != nil {
+		if e.cachedExchangeRates != nil {
+			exchangeRateResponse := e.getCachedExchangeRates(now, e.lastRequestFailed)
+			e.cacheMutex.Unlock()
+			return exchangeRateResponse, nil
+		}
+
+		refreshingDone := e.refreshingDone
+		e.cacheMutex.Unlock()
+		<-refreshingDone
+
+		e.cacheMutex.Lock()
+		defer e.cacheMutex.Unlock()
+
+		if e.cachedExchangeRates == nil {
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		return e.getCachedExchangeRates(time.Now().Unix(), e.lastRequestFailed), nil
+	}
+
+	refreshingDone := make(chan struct{})
+	e.refreshingDone = refreshingDone
+	e.cacheMutex.Unlock()
+
+	exchangeRateResponse, err := e.requestLatestExchangeRates(c, uid, currentConfig)
+
+	e.cacheMutex.Lock()
+	defer e.cacheMutex.Unlock()
+
+	e.refreshingDone = nil
+	close(refreshingDone)
+	now = time.Now().Unix()
+
+	if err != nil {
+		e.lastRequestFailed = true
+		e.lastFailedUnixTime = now
+
+		if e.cachedExchangeRates != nil {
+			log.Warnf(c, "[exchange_rates_datasource_container.GetLatestExchangeRates] failed to request latest exchange rate data for user \"uid:%d\", return the cached data of %d seconds ago", uid, now-e.cachedUnixTime)
+			return e.getCachedExchangeRates(now, true), nil
+		}
+
+		return nil, err
+	}
+
+	e.cachedExchangeRates = exchangeRateResponse
+	e.cachedUnixTime = now
+	e.lastRequestFailed = false
+	e.lastFailedUnixTime = 0
+
+	return e.getCachedExchangeRates(now, false), nil
+}
+
+// GetCacheStatus returns the status and the age of the cached latest exchange rates data
+func (e *ExchangeRatesDataSourceContainer) GetCacheStatus(currentConfig *settings.Config) (string, int64) {
+	if currentConfig.ExchangeRatesCacheExpiration <= 0 {
+		return "disabled", 0
+	}
+
+	e.cacheMutex.Lock()
+	defer e.cacheMutex.Unlock()
+
+	if e.cachedExchangeRates == nil {
+		return "empty", 0
+	}
+
+	cacheAge := time.Now().Unix() - e.cachedUnixTime
+
+	if e.lastRequestFailed {
+		return "stale", cacheAge
+	} else if cacheAge >= int64(currentConfig.ExchangeRatesCacheExpiration) {
+		return "expired", cacheAge
+	}
+
+	return "fresh", cacheAge
+}
+
+func (e *ExchangeRatesDataSourceContainer) getCachedExchangeRates(now int64, isStale bool) *models.LatestExchangeRateResponse {
+	return &models.LatestExchangeRateResponse{
+		DataSource:    e.cachedExchangeRates.DataSource,
+		ReferenceUrl:  e.cachedExchangeRates.ReferenceUrl,
+		UpdateTime:    e.cachedExchangeRates.UpdateTime,
+		BaseCurrency:  e.cachedExchangeRates.BaseCurrency,
+		ExchangeRates: e.cachedExchangeRates.ExchangeRates,
+		CacheAge:      now - e.cachedUnixTime,
+		IsStale:       isStale,
+	}
+}
+
+func (e *ExchangeRatesDataSourceContainer) clearCache() {
+	e.cacheMutex.Lock()
+	defer e.cacheMutex.Unlock()
+
+	e.cachedExchangeRates = nil
+	e.cachedUnixTime = 0
+	e.lastRequestFailed = false
+	e.lastFailedUnixTime = 0
+}
+
+func (e *ExchangeRatesDataSourceContainer) requestLatestExchangeRates(c core.Context, uid int64, currentConfig *settings.Config) (*models.LatestExchangeRateResponse, error) {
+	if len(e.DataSources) < 1 {
+		return nil, errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	utils.SetProxyUrl(transport, currentConfig.ExchangeRatesProxy)
+
+	if currentConfig.ExchangeRatesSkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(currentConfig.ExchangeRatesRequestTimeout) * time.Millisecond,
+	}
+
+	var primaryExchangeRateResponse *models.LatestExchangeRateResponse
+	var lastErr error
+	allExchangeRatesMap := make(map[string]*models.LatestExchangeRate)
+
+	for i := 0; i < len(e.DataSources); i++ {
+		exchangeRateResponse, err := e.requestExchangeRatesFromDataSource(c, uid, client, e.DataSources[i])
+
+		if err != nil {
+			log.Warnf(c, "[exchange_rates_datasource_container.requestLatestExchangeRates] failed to request exchange rate data from data source #%d for user \"uid:%d\", because %s", i, uid, err.Error())
+			lastErr = err
+			continue
+		}
+
+		// the first available data source is the primary data source, and its base currency is the base currency of all exchange rates
+		if primaryExchangeRateResponse == nil {
+			primaryExchangeRateResponse = exchangeRateResponse
+
+			for j := 0; j < len(exchangeRateResponse.ExchangeRates); j++ {
+				exchangeRate := exchangeRateResponse.ExchangeRates[j]
+				allExchangeRatesMap[exchangeRate.Currency] = exchangeRate
+			}
+
+			continue
+		}
+
+		mergedCount, err := mergeExchangeRates(allExchangeRatesMap, primaryExchangeRateResponse.BaseCurrency, exchangeRateResponse)
+
+		if err != nil {
+			log.Warnf(c, "[exchange_rates_datasource_container.requestLatestExchangeRates] cannot merge exchange rate data from data source #%d, because %s", i, err.Error())
+			continue
+		}
+
+		log.Debugf(c, "[exchange_rates_datasource_container.requestLatestExchangeRates] %d exchange rates have been merged from data source #%d", mergedCount, i)
+	}
+
+	if primaryExchangeRateResponse == nil {
+		return nil, lastErr
+	}
+
+	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(allExchangeRatesMap))
+
+	for _, exchangeRate := range allExchangeRatesMap {
+		allExchangeRates = append(allExchangeRates, exchangeRate)
+	}
+
+	sort.Sort(allExchangeRates)
+
+	finalExchangeRateResponse := &models.LatestExchangeRateResponse{
+		DataSource:    primaryExchangeRateResponse.DataSource,
+		ReferenceUrl:  primaryExchangeRateResponse.ReferenceUrl,
+		UpdateTime:    primaryExchangeRateResponse.UpdateTime,
+		BaseCurrency:  primaryExchangeRateResponse.BaseCurrency,
+		ExchangeRates: allExchangeRates,
+	}
+
+	return finalExchangeRateResponse, nil
+}
+
+func (e *ExchangeRatesDataSourceContainer) requestExchangeRatesFromDataSource(c core.Context, uid int64, client *http.Client, dataSource ExchangeRatesDataSource) (*models.LatestExchangeRateResponse, error) {
+	requests, err := dataSource.BuildRequests()
+
+	if err != nil {
+		log.Errorf(c, "[exchange_rates_datasource_container.requestExchangeRatesFromDataSource] failed to build requests for user \"uid:%d\", because %s", uid, err.Error())
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	exchangeRateResps := make([]*models.LatestExchangeRateResponse, 0, len(requests))
+
+	for i := 0; i < len(requests); i++ {
+		req := requests[i]
+		req.Header.Set("User-Agent", fmt.Sprintf("ezBookkeeping/%s ", settings.Version))
+
+		resp, err := client.Do(req)
+
+		if err != nil {
+			log.Errorf(c, "[exchange_rates_datasource_container.requestExchangeRatesFromDataSource] failed to request latest exchange rate data for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		if resp.StatusCode != 200 {
+			log.Errorf(c, "[exchange_rates_datasource_container.requestExchangeRatesFromDataSource] failed to get latest exchange rate data response for user \"uid:%d\", because response code is not 200", uid)
+			return nil, errs.ErrFailedToRequestRemoteApi
+		}
+
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+
+		log.Debugf(c, "[exchange_rates_datasource_container.requestExchangeRatesFromDataSource] response#%d is %s", i, body)
+
+		exchangeRateResp, err := dataSource.Parse(c, body)
+
+		if err != nil {
+			log.Errorf(c, "[exchange_rates_datasource_container.requestExchangeRatesFromDataSource] failed to parse response for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, errs.Or(err, errs.ErrFailedToRequestRemoteApi)
+		}
+
+		exchangeRateResps = append(exchangeRateResps, exchangeRateResp)
+	}
+
+	if len(exchangeRateResps) < 1 {
+		return nil, errs.ErrFailedToRequestRemoteApi
+	}
+
+	lastExchangeRateResponse := exchangeRateResps[len(exchangeRateResps)-1]
+	allExchangeRatesMap := make(map[string]string)
+
+	for i := 0; i < len(exchangeRateResps); i++ {
+		exchangeRateResp := exchangeRateResps[i]
+
+		for j := 0; j < len(exchangeRateResp.ExchangeRates); j++ {
+			exchangeRate := exchangeRateResp.ExchangeRates[j]
+			allExchangeRatesMap[exchangeRate.Currency] = exchangeRate.Rate
+		}
+	}
+
+	allExchangeRatesMap[lastExchangeRateResponse.BaseCurrency] = "1"
+	allExchangeRates := make(models.LatestExchangeRateSlice, 0, len(allExchangeRatesMap))
+
+	for currency, rate := range allExchangeRatesMap {
+		allExchangeRates = append(allExchangeRates, &models.LatestExchangeRate{
+			Currency:   currency,
+			Rate:       rate,
+			DataSource: lastExchangeRateResponse.DataSource,
+		})
+	}
+
+	finalExchangeRateResponse := &models.LatestExchangeRateResponse{
+		DataSource:    lastExchangeRateResponse.DataSource,
+		ReferenceUrl:  lastExchangeRateResponse.ReferenceUrl,
+		UpdateTime:    lastExchangeRateResponse.UpdateTime,
+		BaseCurrency:  lastExchangeRateResponse.BaseCurrency,
+		ExchangeRates: allExchangeRates,
+	}
+
+	return finalExchangeRateResponse, nil
+}
+
+func newExchangeRatesDataSource(dataSourceType string) ExchangeRatesDataSource {
+	if dataSourceType == settings.ReserveBankOfAustraliaDataSource {
+		return &ReserveBankOfAustraliaDataSource{}
+	} else if dataSourceType == settings.BankOfCanadaDataSource {
+		return &BankOfCanadaDataSource{}
+	} else if dataSourceType == settings.CzechNationalBankDataSource {
+		return &CzechNationalBankDataSource{}
+	} else if dataSourceType == settings.DanmarksNationalbankDataSource {
+		return &DanmarksNationalbankDataSource{}
+	} else if dataSourceType == settings.EuroCentralBankDataSource {
+		return &EuroCentralBankDataSource{}
+	} else if dataSourceType == settings.NationalBankOfGeorgiaDataSource {
+		return &NationalBankOfGeorgiaDataSource{}
+	} else if dataSourceType == settings.CentralBankOfHungaryDataSource {
+		return &CentralBankOfHungaryDataSource{}
+	} else if dataSourceType == settings.BankOfIsraelDataSource {
+		return &BankOfIsraelDataSource{}
+	} else if dataSourceType == settings.CentralBankOfMyanmarDataSource {
+		return &CentralBankOfMyanmarDataSource{}
+	} else if dataSourceType == settings.NorgesBankDataSource {
+		return &NorgesBankDataSource{}
+	} else if dataSourceType == settings.NationalBankOfPolandDataSource {
+		return &NationalBankOfPolandDataSource{}
+	} else if dataSourceType == settings.NationalBankOfRomaniaDataSource {
+		return &NationalBankOfRomaniaDataSource{}
+	} else if dataSourceType == settings.BankOfRussiaDataSource {
+		return &BankOfRussiaDataSource{}
+	} else if dataSourceType == settings.SwissNationalBankDataSource {
+		return &SwissNationalBankDataSource{}
+	} else if dataSourceType == settings.CentralBankOfUzbekistanDataSource {
+		return &CentralBankOfUzbekistanDataSource{}
+	} else if dataSourceType == settings.InternationalMonetaryFundDataSource {
+		return &InternationalMonetaryFundDataSource{}
+	}
+
+	return nil
+}
+
+// mergeExchangeRates adds the exchange rates of the currencies which are not in the exchange rates map, and converts them to the specified base currency
+func mergeExchangeRates(allExchangeRatesMap map[string]*models.LatestExchangeRate, baseCurrency string, exchangeRateResponse *models.LatestExchangeRateResponse) (int, error) {
+	var baseCurrencyRate float64
+
+	for i := 0; i < len(exchangeRateResponse.ExchangeRates); i++ {
+		exchangeRate := exchangeRateResponse.ExchangeRates[i]
+
+		if exchangeRate.Currency == baseCurrency {
+			rate, err := utils.StringToFloat64(exchangeRate.Rate)
+
+			if err != nil {
+				return 0, err
+			}
+
+			baseCurrencyRate = rate
+			break
+		}
+	}
+
+	if baseCurrencyRate <= 0 {
+		return 0, errs.ErrExchangeRateNotFound
+	}
+
+	mergedCount := 0
+
+	for i := 0; i < len(exchangeRateResponse.ExchangeRates); i++ {
+		exchangeRate := exchangeRateResponse.ExchangeRates[i]
+
+		if _, exists := allExchangeRatesMap[exchangeRate.Currency]; exists {
+			continue
+		}
+
+		rate, err := utils.StringToFloat64(exchangeRate.Rate)
+
+		if err != nil {
+			continue
+		}
+
+		allExchangeRatesMap[exchangeRate.Currency] = &models.LatestExchangeRate{
+			Currency:   exchangeRate.Currency,
+			Rate:       utils.Float64ToString(rate / baseCurrencyRate),
+			DataSource: exchangeRate.DataSource,
+		}
+
+		mergedCount++
+	}
+
+	return mergedCount, nil
 }
@@ -0,0 +1,335 @@
+package exchangerates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+)
+
+type testExchangeRatesDataSource struct {
+	url          string
+	name         string
+	baseCurrency string
+	currency     string
+}
+
+func (e *testExchangeRatesDataSource) BuildRequests() ([]*http.Request, error) {
+	req, err := http.NewRequest("GET", e.url, nil)
+	return []*http.Request{req}, err
+}
+
+func (e *testExchangeRatesDataSource) Parse(c core.Context, content []byte) (*models.LatestExchangeRateResponse, error) {
+	dataSourceName := "Test"
+	baseCurrency := "USD"
+	currency := "EUR"
+
+	if e.name != "" {
+		dataSourceName = e.name
+	}
+
+	if e.baseCurrency != "" {
+		baseCurrency = e.baseCurrency
+	}
+
+	if e.currency != "" {
+		currency = e.currency
+	}
+
+	return &models.LatestExchangeRateResponse{
+		DataSource:   dataSourceName,
+		BaseCurrency: baseCurrency,
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: currency, Rate: string(content)},
+		},
+	}, nil
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_Cache(t *testing.T) {
+	var requestCount atomic.Int32
+	var failed atomic.Bool
+	rate := atomic.Value{}
+	rate.Store("0.9")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+
+		if failed.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = w.Write([]byte(rate.Load().(string)))
+	}))
+	defer server.Close()
+
+	config := &settings.Config{
+		ExchangeRatesProxy:           "none",
+		ExchangeRatesRequestTimeout:  10000,
+		ExchangeRatesCacheExpiration: 3600,
+	}
+
+	container := &ExchangeRatesDataSourceContainer{
+		DataSources: []ExchangeRatesDataSource{&testExchangeRatesDataSource{url: server.URL}},
+	}
+
+	status, _ := container.GetCacheStatus(config)
+	assert.Equal(t, "empty", status)
+
+	exchangeRateResponse, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.9", exchangeRateResponse.ExchangeRates[0].Rate)
+	assert.False(t, exchangeRateResponse.IsStale)
+	assert.Equal(t, int32(1), requestCount.Load())
+
+	rate.Store("0.8")
+	exchangeRateResponse, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.9", exchangeRateResponse.ExchangeRates[0].Rate)
+	assert.Equal(t, int32(1), requestCount.Load())
+
+	status, _ = container.GetCacheStatus(config)
+	assert.Equal(t, "fresh", status)
+
+	container.cachedUnixTime -= 3600
+	failed.Store(true)
+
+	exchangeRateResponse, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.9", exchangeRateResponse.ExchangeRates[0].Rate)
+	assert.True(t, exchangeRateResponse.IsStale)
+	assert.GreaterOrEqual(t, exchangeRateResponse.CacheAge, int64(3600))
+	assert.Equal(t, int32(2), requestCount.Load())
+
+	status, cacheAge := container.GetCacheStatus(config)
+	assert.Equal(t, "stale", status)
+	assert.GreaterOrEqual(t, cacheAge, int64(3600))
+
+	failed.Store(false)
+
+	exchangeRateResponse, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.9", exchangeRateResponse.ExchangeRates[0].Rate)
+	assert.True(t, exchangeRateResponse.IsStale)
+	assert.Equal(t, int32(2), requestCount.Load())
+
+	container.lastFailedUnixTime -= exchangeRatesMinRetryInterval
+
+	exchangeRateResponse, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.8", exchangeRateResponse.ExchangeRates[0].Rate)
+	assert.False(t, exchangeRateResponse.IsStale)
+	assert.Equal(t, int64(0), exchangeRateResponse.CacheAge)
+	assert.Equal(t, int32(3), requestCount.Load())
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_ServeCacheWhileRefreshing(t *testing.T) {
+	var requestCount atomic.Int32
+	requestStarted := make(chan struct{}, 1)
+	releaseRequest := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		requestStarted <- struct{}{}
+		<-releaseRequest
+		_, _ = w.Write([]byte("0.8"))
+	}))
+	defer server.Close()
+
+	config := &settings.Config{
+		ExchangeRatesProxy:           "none",
+		ExchangeRatesRequestTimeout:  10000,
+		ExchangeRatesCacheExpiration: 3600,
+	}
+
+	container := &ExchangeRatesDataSourceContainer{
+		DataSources:    []ExchangeRatesDataSource{&testExchangeRatesDataSource{url: server.URL}},
+		cachedUnixTime: time.Now().Unix() - 3600,
+		cachedExchangeRates: &models.LatestExchangeRateResponse{
+			DataSource:    "Test",
+			BaseCurrency:  "USD",
+			ExchangeRates: models.LatestExchangeRateSlice{{Currency: "EUR", Rate: "0.9"}},
+		},
+	}
+
+	refreshedResponse := make(chan *models.LatestExchangeRateResponse, 1)
+
+	go func() {
+		exchangeRateResponse, _ := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+		refreshedResponse <- exchangeRateResponse
+	}()
+
+	<-requestStarted
+
+	exchangeRateResponse, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.9", exchangeRateResponse.ExchangeRates[0].Rate)
+
+	status, _ := container.GetCacheStatus(config)
+	assert.Equal(t, "expired", status)
+
+	close(releaseRequest)
+
+	exchangeRateResponse = <-refreshedResponse
+	assert.Equal(t, "0.8", exchangeRateResponse.ExchangeRates[0].Rate)
+	assert.Equal(t, int32(1), requestCount.Load())
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_CacheDisabled(t *testing.T) {
+	var requestCount atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		_, _ = w.Write([]byte("0.9"))
+	}))
+	defer server.Close()
+
+	config := &settings.Config{
+		ExchangeRatesProxy:          "none",
+		ExchangeRatesRequestTimeout: 10000,
+	}
+
+	container := &ExchangeRatesDataSourceContainer{
+		DataSources: []ExchangeRatesDataSource{&testExchangeRatesDataSource{url: server.URL}},
+	}
+
+	_, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+
+	_, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), requestCount.Load())
+
+	status, _ := container.GetCacheStatus(config)
+	assert.Equal(t, "disabled", status)
+
+	server.Close()
+
+	_, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.NotNil(t, err)
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_Fallback(t *testing.T) {
+	failedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failedServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0.9"))
+	}))
+	defer server.Close()
+
+	config := &settings.Config{
+		ExchangeRatesProxy:          "none",
+		ExchangeRatesRequestTimeout: 10000,
+	}
+
+	container := &ExchangeRatesDataSourceContainer{
+		DataSources: []ExchangeRatesDataSource{
+			&testExchangeRatesDataSource{url: failedServer.URL, name: "Test1"},
+			&testExchangeRatesDataSource{url: server.URL, name: "Test2"},
+		},
+	}
+
+	exchangeRateResponse, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "Test2", exchangeRateResponse.DataSource)
+	assert.Equal(t, "USD", exchangeRateResponse.BaseCurrency)
+	assert.Equal(t, 2, len(exchangeRateResponse.ExchangeRates))
+
+	assert.Equal(t, "EUR", exchangeRateResponse.ExchangeRates[0].Currency)
+	assert.Equal(t, "0.9", exchangeRateResponse.ExchangeRates[0].Rate)
+	assert.Equal(t, "Test2", exchangeRateResponse.ExchangeRates[0].DataSource)
+
+	container.DataSources = []ExchangeRatesDataSource{
+		&testExchangeRatesDataSource{url: failedServer.URL, name: "Test1"},
+	}
+
+	_, err = container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.NotNil(t, err)
+}
+
+func TestExchangeRatesDataSourceContainerGetLatestExchangeRates_MergeFromOtherDataSources(t *testing.T) {
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0.9"))
+	}))
+	defer primaryServer.Close()
+
+	secondaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0.5"))
+	}))
+	defer secondaryServer.Close()
+
+	config := &settings.Config{
+		ExchangeRatesProxy:          "none",
+		ExchangeRatesRequestTimeout: 10000,
+	}
+
+	container := &ExchangeRatesDataSourceContainer{
+		DataSources: []ExchangeRatesDataSource{
+			&testExchangeRatesDataSource{url: primaryServer.URL, name: "Test1", baseCurrency: "USD", currency: "EUR"},
+			&testExchangeRatesDataSource{url: secondaryServer.URL, name: "Test2", baseCurrency: "JPY", currency: "CNY"},
+			&testExchangeRatesDataSource{url: secondaryServer.URL, name: "Test3", baseCurrency: "USD", currency: "GBP"},
+		},
+	}
+
+	exchangeRateResponse, err := container.GetLatestExchangeRates(core.NewNullContext(), 0, config)
+	assert.Nil(t, err)
+	assert.Equal(t, "Test1", exchangeRateResponse.DataSource)
+	assert.Equal(t, "USD", exchangeRateResponse.BaseCurrency)
+	assert.Equal(t, 3, len(exchangeRateResponse.ExchangeRates))
+
+	assert.Equal(t, "EUR", exchangeRateResponse.ExchangeRates[0].Currency)
+	assert.Equal(t, "0.9", exchangeRateResponse.ExchangeRates[0].Rate)
+	assert.Equal(t, "Test1", exchangeRateResponse.ExchangeRates[0].DataSource)
+
+	assert.Equal(t, "GBP", exchangeRateResponse.ExchangeRates[1].Currency)
+	assert.Equal(t, "0.5", exchangeRateResponse.ExchangeRates[1].Rate)
+	assert.Equal(t, "Test3", exchangeRateResponse.ExchangeRates[1].DataSource)
+
+	assert.Equal(t, "USD", exchangeRateResponse.ExchangeRates[2].Currency)
+	assert.Equal(t, "1", exchangeRateResponse.ExchangeRates[2].Rate)
+	assert.Equal(t, "Test1", exchangeRateResponse.ExchangeRates[2].DataSource)
+}
+
+func TestMergeExchangeRates(t *testing.T) {
+	allExchangeRatesMap := map[string]*models.LatestExchangeRate{
+		"USD": {Currency: "USD", Rate: "1", DataSource: "Test1"},
+		"EUR": {Currency: "EUR", Rate: "0.9", DataSource: "Test1"},
+	}
+
+	mergedCount, err := mergeExchangeRates(allExchangeRatesMap, "USD", &models.LatestExchangeRateResponse{
+		BaseCurrency: "EUR",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "1", DataSource: "Test2"},
+			{Currency: "USD", Rate: "2", DataSource: "Test2"},
+			{Currency: "GBP", Rate: "0.5", DataSource: "Test2"},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, mergedCount)
+	assert.Equal(t, 3, len(allExchangeRatesMap))
+
+	assert.Equal(t, "0.9", allExchangeRatesMap["EUR"].Rate)
+	assert.Equal(t, "Test1", allExchangeRatesMap["EUR"].DataSource)
+	assert.Equal(t, "0.25", allExchangeRatesMap["GBP"].Rate)
+	assert.Equal(t, "Test2", allExchangeRatesMap["GBP"].DataSource)
+
+	_, err = mergeExchangeRates(allExchangeRatesMap, "USD", &models.LatestExchangeRateResponse{
+		BaseCurrency: "JPY",
+		ExchangeRates: models.LatestExchangeRateSlice{
+			{Currency: "JPY", Rate: "1", DataSource: "Test3"},
+			{Currency: "CNY", Rate: "0.05", DataSource: "Test3"},
+		},
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, len(allExchangeRatesMap))
+}
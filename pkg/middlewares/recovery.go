@@ -3,6 +3,7 @@ package middlewares
 import (
 	"bytes"
 	"fmt"
+	"net/http"
 	"os"
 	"runtime"
 
@@ -23,6 +24,11 @@ var (
 func Recovery(c *core.WebContext) {
 	defer func() {
 		if err := recover(); err != nil {
+			// the http server would abort the response without logging
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+
 			stack := stack(3)
 
 			log.ErrorfWithExtra(c, string(stack), "System Error! because %s", err)
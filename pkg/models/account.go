@@ -45,6 +45,16 @@ var liabilityAccountCategory = map[AccountCategory]bool{
 	ACCOUNT_CATEGORY_CERTIFICATE_OF_DEPOSIT: false,
 }
 
+// IsAsset returns whether the account category belongs to assets
+func (c AccountCategory) IsAsset() bool {
+	return assetAccountCategory[c]
+}
+
+// IsLiability returns whether the account category belongs to liabilities
+func (c AccountCategory) IsLiability() bool {
+	return liabilityAccountCategory[c]
+}
+
 // AccountType represents account type
 type AccountType byte
 
@@ -189,8 +199,8 @@ func (a *Account) ToAccountInfoResponse() *AccountInfoResponse {
 		Comment:                 a.Comment,
 		CreditCardStatementDate: creditCardStatementDate,
 		DisplayOrder:            a.DisplayOrder,
-		IsAsset:                 assetAccountCategory[a.Category],
-		IsLiability:             liabilityAccountCategory[a.Category],
+		IsAsset:                 a.Category.IsAsset(),
+		IsLiability:             a.Category.IsLiability(),
 		Hidden:                  a.Hidden,
 	}
 }
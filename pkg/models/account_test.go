@@ -49,3 +49,14 @@ func TestAccountInfoResponseSliceLess(t *testing.T) {
 	assert.Equal(t, int64(5), accountRespSlice[4].Id)
 	assert.Equal(t, int64(3), accountRespSlice[5].Id)
 }
+
+func TestAccountCategoryIsAssetOrLiability(t *testing.T) {
+	assert.True(t, ACCOUNT_CATEGORY_CASH.IsAsset())
+	assert.False(t, ACCOUNT_CATEGORY_CASH.IsLiability())
+	assert.True(t, ACCOUNT_CATEGORY_RECEIVABLES.IsAsset())
+	assert.False(t, ACCOUNT_CATEGORY_CREDIT_CARD.IsAsset())
+	assert.True(t, ACCOUNT_CATEGORY_CREDIT_CARD.IsLiability())
+	assert.True(t, ACCOUNT_CATEGORY_DEBT.IsLiability())
+	assert.False(t, AccountCategory(0).IsAsset())
+	assert.False(t, AccountCategory(0).IsLiability())
+}
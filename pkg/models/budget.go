@@ -0,0 +1,205 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+// BudgetPeriodType represents budget period type
+type BudgetPeriodType byte
+
+// Budget period types
+const (
+	BUDGET_PERIOD_TYPE_WEEKLY  BudgetPeriodType = 1
+	BUDGET_PERIOD_TYPE_MONTHLY BudgetPeriodType = 2
+	BUDGET_PERIOD_TYPE_YEARLY  BudgetPeriodType = 3
+)
+
+// BudgetRolloverType represents budget rollover type
+type BudgetRolloverType byte
+
+// Budget rollover types
+const (
+	BUDGET_ROLLOVER_TYPE_NONE           BudgetRolloverType = 0
+	BUDGET_ROLLOVER_TYPE_REMAINING_ONLY BudgetRolloverType = 1
+	BUDGET_ROLLOVER_TYPE_ALL            BudgetRolloverType = 2
+)
+
+// Budget represents budget data stored in database
+type Budget struct {
+	BudgetId        int64              `xorm:"PK"`
+	Uid             int64              `xorm:"INDEX(IDX_budget_uid_deleted_order) NOT NULL"`
+	Deleted         bool               `xorm:"INDEX(IDX_budget_uid_deleted_order) NOT NULL"`
+	CategoryId      int64              `xorm:"NOT NULL"`
+	AccountId       int64              `xorm:"NOT NULL"`
+	PeriodType      BudgetPeriodType   `xorm:"NOT NULL"`
+	Amount          int64              `xorm:"NOT NULL"`
+	RolloverType    BudgetRolloverType `xorm:"NOT NULL"`
+	Comment         string             `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder    int32              `xorm:"INDEX(IDX_budget_uid_deleted_order) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// BudgetGetRequest represents all parameters of budget getting request
+type BudgetGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// BudgetCreateRequest represents all parameters of budget creation request
+type BudgetCreateRequest struct {
+	CategoryId      int64              `json:"categoryId,string" binding:"required,min=1"`
+	AccountId       int64              `json:"accountId,string" binding:"min=0"`
+	PeriodType      BudgetPeriodType   `json:"periodType" binding:"required"`
+	Amount          int64              `json:"amount" binding:"min=1,max=99999999999"`
+	RolloverType    BudgetRolloverType `json:"rolloverType"`
+	Comment         string             `json:"comment" binding:"max=255"`
+	ClientSessionId string             `json:"clientSessionId"`
+}
+
+// BudgetModifyRequest represents all parameters of budget modification request
+type BudgetModifyRequest struct {
+	Id           int64              `json:"id,string" binding:"required,min=1"`
+	CategoryId   int64              `json:"categoryId,string" binding:"required,min=1"`
+	AccountId    int64              `json:"accountId,string" binding:"min=0"`
+	PeriodType   BudgetPeriodType   `json:"periodType" binding:"required"`
+	Amount       int64              `json:"amount" binding:"min=1,max=99999999999"`
+	RolloverType BudgetRolloverType `json:"rolloverType"`
+	Comment      string             `json:"comment" binding:"max=255"`
+}
+
+// BudgetMoveRequest represents all parameters of budget moving request
+type BudgetMoveRequest struct {
+	NewDisplayOrders []*BudgetNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// BudgetNewDisplayOrderRequest represents a data pair of id and display order
+type BudgetNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// BudgetDeleteRequest represents all parameters of budget deleting request
+type BudgetDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// BudgetProgressRequest represents all parameters of budget progress request
+type BudgetProgressRequest struct {
+	Id   int64 `form:"id,string" binding:"min=0"`
+	Time int64 `form:"time" binding:"min=0"`
+}
+
+// BudgetInfoResponse represents a view-object of budget
+type BudgetInfoResponse struct {
+	Id           int64              `json:"id,string"`
+	CategoryId   int64              `json:"categoryId,string"`
+	AccountId    int64              `json:"accountId,string"`
+	PeriodType   BudgetPeriodType   `json:"periodType"`
+	Amount       int64              `json:"amount"`
+	RolloverType BudgetRolloverType `json:"rolloverType"`
+	Comment      string             `json:"comment"`
+	DisplayOrder int32              `json:"displayOrder"`
+}
+
+// BudgetProgressResponse represents a view-object of budget progress
+type BudgetProgressResponse struct {
+	BudgetId        int64 `json:"budgetId,string"`
+	StartTime       int64 `json:"startTime"`
+	EndTime         int64 `json:"endTime"`
+	BudgetAmount    int64 `json:"budgetAmount"`
+	RolloverAmount  int64 `json:"rolloverAmount"`
+	PlannedAmount   int64 `json:"plannedAmount"`
+	SpentAmount     int64 `json:"spentAmount"`
+	RemainingAmount int64 `json:"remainingAmount"`
+}
+
+// IsValid returns whether the budget period type is valid
+func (t BudgetPeriodType) IsValid() bool {
+	return t == BUDGET_PERIOD_TYPE_WEEKLY || t == BUDGET_PERIOD_TYPE_MONTHLY || t == BUDGET_PERIOD_TYPE_YEARLY
+}
+
+// IsValid returns whether the budget rollover type is valid
+func (t BudgetRolloverType) IsValid() bool {
+	return t == BUDGET_ROLLOVER_TYPE_NONE || t == BUDGET_ROLLOVER_TYPE_REMAINING_ONLY || t == BUDGET_ROLLOVER_TYPE_ALL
+}
+
+// GetPeriodTimeRange returns the first and the last unix time of the budget period which contains the specified unix time
+func (b *Budget) GetPeriodTimeRange(unixTime int64, timezone *time.Location, firstDayOfWeek core.WeekDay) (int64, int64) {
+	currentTime := time.Unix(unixTime, 0).In(timezone)
+	var startTime, nextStartTime time.Time
+
+	if b.PeriodType == BUDGET_PERIOD_TYPE_WEEKLY {
+		dayOfWeek := int(currentTime.Weekday()) - int(firstDayOfWeek)
+
+		if dayOfWeek < 0 {
+			dayOfWeek += 7
+		}
+
+		startTime = time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day()-dayOfWeek, 0, 0, 0, 0, timezone)
+		nextStartTime = startTime.AddDate(0, 0, 7)
+	} else if b.PeriodType == BUDGET_PERIOD_TYPE_YEARLY {
+		startTime = time.Date(currentTime.Year(), 1, 1, 0, 0, 0, 0, timezone)
+		nextStartTime = startTime.AddDate(1, 0, 0)
+	} else {
+		startTime = time.Date(currentTime.Year(), currentTime.Month(), 1, 0, 0, 0, 0, timezone)
+		nextStartTime = startTime.AddDate(0, 1, 0)
+	}
+
+	return startTime.Unix(), nextStartTime.Unix() - 1
+}
+
+// GetRolloverAmount returns the amount carried over from all the previous periods (in chronological order) according to the rollover type
+func (b *Budget) GetRolloverAmount(previousPeriodSpentAmounts []int64) int64 {
+	if b.RolloverType == BUDGET_ROLLOVER_TYPE_NONE {
+		return 0
+	}
+
+	rolloverAmount := int64(0)
+
+	for i := 0; i < len(previousPeriodSpentAmounts); i++ {
+		remainingAmount := b.Amount + rolloverAmount - previousPeriodSpentAmounts[i]
+
+		if b.RolloverType == BUDGET_ROLLOVER_TYPE_REMAINING_ONLY && remainingAmount < 0 {
+			remainingAmount = 0
+		}
+
+		rolloverAmount = remainingAmount
+	}
+
+	return rolloverAmount
+}
+
+// ToBudgetInfoResponse returns a view-object according to database model
+func (b *Budget) ToBudgetInfoResponse() *BudgetInfoResponse {
+	return &BudgetInfoResponse{
+		Id:           b.BudgetId,
+		CategoryId:   b.CategoryId,
+		AccountId:    b.AccountId,
+		PeriodType:   b.PeriodType,
+		Amount:       b.Amount,
+		RolloverType: b.RolloverType,
+		Comment:      b.Comment,
+		DisplayOrder: b.DisplayOrder,
+	}
+}
+
+// BudgetInfoResponseSlice represents the slice data structure of BudgetInfoResponse
+type BudgetInfoResponseSlice []*BudgetInfoResponse
+
+// Len returns the count of items
+func (s BudgetInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s BudgetInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s BudgetInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+)
+
+func TestBudgetGetPeriodTimeRange_Weekly(t *testing.T) {
+	budget := &Budget{
+		PeriodType: BUDGET_PERIOD_TYPE_WEEKLY,
+	}
+
+	timezone := time.FixedZone("Test Timezone", 8*60*60)
+	unixTime := time.Date(2024, 9, 18, 10, 30, 0, 0, timezone).Unix() // Wednesday
+
+	startTime, endTime := budget.GetPeriodTimeRange(unixTime, timezone, core.WEEKDAY_MONDAY)
+	assert.Equal(t, time.Date(2024, 9, 16, 0, 0, 0, 0, timezone).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, 9, 22, 23, 59, 59, 0, timezone).Unix(), endTime)
+
+	startTime, endTime = budget.GetPeriodTimeRange(unixTime, timezone, core.WEEKDAY_THURSDAY)
+	assert.Equal(t, time.Date(2024, 9, 12, 0, 0, 0, 0, timezone).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, 9, 18, 23, 59, 59, 0, timezone).Unix(), endTime)
+}
+
+func TestBudgetGetPeriodTimeRange_Monthly(t *testing.T) {
+	budget := &Budget{
+		PeriodType: BUDGET_PERIOD_TYPE_MONTHLY,
+	}
+
+	timezone := time.FixedZone("Test Timezone", -5*60*60)
+	unixTime := time.Date(2024, 2, 29, 23, 0, 0, 0, timezone).Unix()
+
+	startTime, endTime := budget.GetPeriodTimeRange(unixTime, timezone, core.WEEKDAY_SUNDAY)
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, timezone).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, 2, 29, 23, 59, 59, 0, timezone).Unix(), endTime)
+}
+
+func TestBudgetGetPeriodTimeRange_Yearly(t *testing.T) {
+	budget := &Budget{
+		PeriodType: BUDGET_PERIOD_TYPE_YEARLY,
+	}
+
+	timezone := time.UTC
+	unixTime := time.Date(2024, 7, 1, 0, 0, 0, 0, timezone).Unix()
+
+	startTime, endTime := budget.GetPeriodTimeRange(unixTime, timezone, core.WEEKDAY_SUNDAY)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, timezone).Unix(), startTime)
+	assert.Equal(t, time.Date(2024, 12, 31, 23, 59, 59, 0, timezone).Unix(), endTime)
+}
+
+func TestBudgetGetRolloverAmount(t *testing.T) {
+	budget := &Budget{
+		Amount:       60000,
+		RolloverType: BUDGET_ROLLOVER_TYPE_NONE,
+	}
+
+	assert.Equal(t, int64(0), budget.GetRolloverAmount([]int64{50000}))
+	assert.Equal(t, int64(0), budget.GetRolloverAmount([]int64{70000}))
+
+	budget.RolloverType = BUDGET_ROLLOVER_TYPE_REMAINING_ONLY
+	assert.Equal(t, int64(10000), budget.GetRolloverAmount([]int64{50000}))
+	assert.Equal(t, int64(0), budget.GetRolloverAmount([]int64{70000}))
+
+	budget.RolloverType = BUDGET_ROLLOVER_TYPE_ALL
+	assert.Equal(t, int64(10000), budget.GetRolloverAmount([]int64{50000}))
+	assert.Equal(t, int64(-10000), budget.GetRolloverAmount([]int64{70000}))
+}
+
+func TestBudgetGetRolloverAmount_MultiplePeriods(t *testing.T) {
+	budget := &Budget{
+		Amount:       60000,
+		RolloverType: BUDGET_ROLLOVER_TYPE_NONE,
+	}
+
+	assert.Equal(t, int64(0), budget.GetRolloverAmount([]int64{50000, 40000, 70000}))
+
+	budget.RolloverType = BUDGET_ROLLOVER_TYPE_REMAINING_ONLY
+	assert.Equal(t, int64(0), budget.GetRolloverAmount([]int64{}))
+	assert.Equal(t, int64(20000), budget.GetRolloverAmount([]int64{50000, 40000, 70000}))
+	assert.Equal(t, int64(30000), budget.GetRolloverAmount([]int64{90000, 40000, 50000}))
+	assert.Equal(t, int64(0), budget.GetRolloverAmount([]int64{30000, 40000, 150000}))
+
+	budget.RolloverType = BUDGET_ROLLOVER_TYPE_ALL
+	assert.Equal(t, int64(0), budget.GetRolloverAmount([]int64{}))
+	assert.Equal(t, int64(20000), budget.GetRolloverAmount([]int64{50000, 40000, 70000}))
+	assert.Equal(t, int64(0), budget.GetRolloverAmount([]int64{90000, 40000, 50000}))
+	assert.Equal(t, int64(-40000), budget.GetRolloverAmount([]int64{30000, 40000, 150000, 60000}))
+}
+
+func TestBudgetInfoResponseSliceLess(t *testing.T) {
+	var budgetRespSlice BudgetInfoResponseSlice
+	budgetRespSlice = append(budgetRespSlice, &BudgetInfoResponse{
+		Id:           1,
+		DisplayOrder: 3,
+	})
+	budgetRespSlice = append(budgetRespSlice, &BudgetInfoResponse{
+		Id:           2,
+		DisplayOrder: 1,
+	})
+	budgetRespSlice = append(budgetRespSlice, &BudgetInfoResponse{
+		Id:           3,
+		DisplayOrder: 2,
+	})
+
+	sort.Sort(budgetRespSlice)
+
+	assert.Equal(t, int64(2), budgetRespSlice[0].Id)
+	assert.Equal(t, int64(3), budgetRespSlice[1].Id)
+	assert.Equal(t, int64(1), budgetRespSlice[2].Id)
+}
@@ -5,6 +5,19 @@ type ClearDataRequest struct {
 	Password string `json:"password" binding:"omitempty,min=6,max=128"`
 }
 
+// ExportDataRequest represents all parameters of export user data request
+type ExportDataRequest struct {
+	StartTime     int64                    `form:"startTime" binding:"min=0"`
+	EndTime       int64                    `form:"endTime" binding:"min=0"`
+	Type          TransactionDbType        `form:"type" binding:"min=0,max=4"`
+	CategoryIds   string                   `form:"category_ids"`
+	AccountIds    string                   `form:"account_ids"`
+	TagIds        string                   `form:"tag_ids"`
+	TagFilterType TransactionTagFilterType `form:"tag_filter_type" binding:"min=0,max=3"`
+	AmountFilter  string                   `form:"amount_filter" binding:"validAmountFilter"`
+	Keyword       string                   `form:"keyword"`
+}
+
 // DataStatisticsResponse represents a view-object of user data statistic
 type DataStatisticsResponse struct {
 	TotalAccountCount              int64 `json:"totalAccountCount,string"`
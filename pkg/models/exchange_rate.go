@@ -2,6 +2,35 @@ package models
 
 import "strings"
 
+// ExchangeRate represents exchange rate of one currency from one data source on one date stored in database
+type ExchangeRate struct {
+	Date            string `xorm:"PK VARCHAR(10)"`
+	DataSource      string `xorm:"PK VARCHAR(64)"`
+	Currency        string `xorm:"PK VARCHAR(3)"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	RateDataSource  string `xorm:"VARCHAR(64) NOT NULL"`
+	UpdateTime      int64  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+}
+
+// HistoricalExchangeRateRequest represents all parameters of historical exchange rate request
+type HistoricalExchangeRateRequest struct {
+	FromCurrency string `form:"fromCurrency" binding:"required,len=3,validCurrency"`
+	ToCurrency   string `form:"toCurrency" binding:"required,len=3,validCurrency"`
+	Time         int64  `form:"time" binding:"required,min=1"`
+}
+
+// HistoricalExchangeRateResponse returns a view-object which contains the exchange rate of one currency pair on one date
+type HistoricalExchangeRateResponse struct {
+	DataSource   string `json:"dataSource"`
+	Date         string `json:"date"`
+	FromCurrency string `json:"fromCurrency"`
+	ToCurrency   string `json:"toCurrency"`
+	Rate         string `json:"rate"`
+}
+
 // LatestExchangeRateResponse returns a view-object which contains latest exchange rate
 type LatestExchangeRateResponse struct {
 	DataSource    string                  `json:"dataSource"`
@@ -9,12 +38,15 @@ type LatestExchangeRateResponse struct {
 	UpdateTime    int64                   `json:"updateTime"`
 	BaseCurrency  string                  `json:"baseCurrency"`
 	ExchangeRates LatestExchangeRateSlice `json:"exchangeRates"`
+	CacheAge      int64                   `json:"cacheAge"`
+	IsStale       bool                    `json:"isStale,omitempty"`
 }
 
 // LatestExchangeRate represents a data pair of currency and exchange rate
 type LatestExchangeRate struct {
-	Currency string `json:"currency"`
-	Rate     string `json:"rate"`
+	Currency   string `json:"currency"`
+	Rate       string `json:"rate"`
+	DataSource string `json:"dataSource,omitempty"`
 }
 
 // LatestExchangeRateSlice represents the slice data structure of LatestExchangeRate
@@ -1,23 +1,44 @@
 package models
 
-import "github.com/mayswind/ezbookkeeping/pkg/utils"
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// IMPORT_TRANSACTION_DUPLICATE_TIME_WINDOW represents the maximum seconds between the time of imported transaction and existed transaction which are considered as likely duplicate
+const IMPORT_TRANSACTION_DUPLICATE_TIME_WINDOW = 86400
 
 // ImportTransaction represents the imported transaction data
 type ImportTransaction struct {
 	*Transaction
 	TagIds                             []string
+	OriginalParentCategoryName         string
 	OriginalCategoryName               string
+	OriginalCategoryKey                string
 	OriginalSourceAccountName          string
 	OriginalSourceAccountCurrency      string
 	OriginalDestinationAccountName     string
 	OriginalDestinationAccountCurrency string
 	OriginalTagNames                   []string
+	LikelyDuplicate                    bool
+	DuplicateTransactionId             int64
+}
+
+// importTransactionDuplicateCandidate represents a pair of imported transaction and existed transaction which may be duplicate
+type importTransactionDuplicateCandidate struct {
+	importTransactionIndex int
+	existedTransaction     *Transaction
+	timeDifference         int64
 }
 
 // ImportTransactionResponse represents a view-object of the imported transaction data
 type ImportTransactionResponse struct {
 	Type                               TransactionType                 `json:"type"`
 	CategoryId                         int64                           `json:"categoryId,string"`
+	OriginalParentCategoryName         string                          `json:"originalParentCategoryName,omitempty"`
 	OriginalCategoryName               string                          `json:"originalCategoryName"`
 	Time                               int64                           `json:"time"`
 	UtcOffset                          int16                           `json:"utcOffset"`
@@ -33,6 +54,9 @@ type ImportTransactionResponse struct {
 	OriginalTagNames                   []string                        `json:"originalTagNames"`
 	Comment                            string                          `json:"comment"`
 	GeoLocation                        *TransactionGeoLocationResponse `json:"geoLocation,omitempty"`
+	ExternalId                         string                          `json:"externalId,omitempty"`
+	LikelyDuplicate                    bool                            `json:"likelyDuplicate"`
+	DuplicateTransactionId             int64                           `json:"duplicateTransactionId,string,omitempty"`
 }
 
 // ImportTransactionResponsePageWrapper represents a response of imported transaction which contains items and count
@@ -61,6 +85,7 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 	return &ImportTransactionResponse{
 		Type:                               transactionType,
 		CategoryId:                         t.CategoryId,
+		OriginalParentCategoryName:         t.OriginalParentCategoryName,
 		OriginalCategoryName:               t.OriginalCategoryName,
 		Time:                               utils.GetUnixTimeFromTransactionTime(t.TransactionTime),
 		UtcOffset:                          t.TimezoneUtcOffset,
@@ -76,6 +101,9 @@ func (t ImportTransaction) ToImportTransactionResponse() *ImportTransactionRespo
 		OriginalTagNames:                   t.OriginalTagNames,
 		Comment:                            t.Comment,
 		GeoLocation:                        geoLocation,
+		ExternalId:                         t.ExternalId,
+		LikelyDuplicate:                    t.LikelyDuplicate,
+		DuplicateTransactionId:             t.DuplicateTransactionId,
 	}
 }
 
@@ -157,6 +185,147 @@ func (s ImportedTransactionSlice) ToTransactionTagIdsMap() (map[int][]int64, err
 	return transactionTagIdsMap, nil
 }
 
+// ApplyTransactionRules changes the imported transaction according to the first matched transaction rule, and returns the matched rule
+func (t *ImportTransaction) ApplyTransactionRules(rules TransactionRuleSlice) *TransactionRule {
+	transactionType, err := t.Type.ToTransactionType()
+
+	if err != nil {
+		return nil
+	}
+
+	transactionCreateReq := &TransactionCreateRequest{
+		Type:                 transactionType,
+		CategoryId:           t.CategoryId,
+		SourceAccountId:      t.AccountId,
+		DestinationAccountId: t.RelatedAccountId,
+		SourceAmount:         t.Amount,
+		DestinationAmount:    t.RelatedAccountAmount,
+		TagIds:               t.TagIds,
+		Comment:              t.Comment,
+	}
+
+	rule := rules.ApplyTo(transactionCreateReq)
+
+	if rule == nil {
+		return nil
+	}
+
+	t.CategoryId = transactionCreateReq.CategoryId
+	t.RelatedAccountId = transactionCreateReq.DestinationAccountId
+	t.TagIds = transactionCreateReq.TagIds
+	t.Comment = transactionCreateReq.Comment
+
+	return rule
+}
+
+// GetDuplicateFingerprint returns the fingerprint (account, type, amount and normalized comment) of imported transaction for detecting likely duplicate transaction, the transaction time is not included and should be compared within time window
+func (t ImportTransaction) GetDuplicateFingerprint() string {
+	return getTransactionDuplicateFingerprint(t.Transaction)
+}
+
+// MarkLikelyDuplicates marks the imported transactions which are likely the same as the existed transactions, each existed transaction can only match one imported transaction
+func (s ImportedTransactionSlice) MarkLikelyDuplicates(existedTransactions []*Transaction) int {
+	matchedTransactionIds := make(map[int64]bool, len(existedTransactions))
+	existedTransactionsByFingerprint := make(map[string][]*Transaction, len(existedTransactions))
+	existedTransactionsByExternalId := make(map[string]*Transaction)
+	duplicateCount := 0
+
+	for i := 0; i < len(existedTransactions); i++ {
+		transaction := existedTransactions[i]
+
+		if transaction.Type == TRANSACTION_DB_TYPE_TRANSFER_IN {
+			continue
+		}
+
+		fingerprint := getTransactionDuplicateFingerprint(transaction)
+		existedTransactionsByFingerprint[fingerprint] = append(existedTransactionsByFingerprint[fingerprint], transaction)
+
+		if transaction.ExternalId != "" {
+			existedTransactionsByExternalId[fmt.Sprintf("%d|%s", transaction.AccountId, transaction.ExternalId)] = transaction
+		}
+	}
+
+	// the transaction id assigned by financial institution is more reliable, so match by it first
+	for i := 0; i < s.Len(); i++ {
+		importTransaction := s[i]
+
+		if importTransaction.AccountId <= 0 || importTransaction.ExternalId == "" {
+			continue
+		}
+
+		existedTransaction, exists := existedTransactionsByExternalId[fmt.Sprintf("%d|%s", importTransaction.AccountId, importTransaction.ExternalId)]
+
+		if exists && !matchedTransactionIds[existedTransaction.TransactionId] {
+			importTransaction.LikelyDuplicate = true
+			importTransaction.DuplicateTransactionId = existedTransaction.TransactionId
+			matchedTransactionIds[existedTransaction.TransactionId] = true
+			duplicateCount++
+		}
+	}
+
+	var candidatePairs []*importTransactionDuplicateCandidate
+
+	for i := 0; i < s.Len(); i++ {
+		importTransaction := s[i]
+
+		if importTransaction.AccountId <= 0 || importTransaction.LikelyDuplicate {
+			continue
+		}
+
+		candidates := existedTransactionsByFingerprint[importTransaction.GetDuplicateFingerprint()]
+		importUnixTime := utils.GetUnixTimeFromTransactionTime(importTransaction.TransactionTime)
+
+		for j := 0; j < len(candidates); j++ {
+			candidate := candidates[j]
+
+			if matchedTransactionIds[candidate.TransactionId] {
+				continue
+			}
+
+			if importTransaction.ExternalId != "" && candidate.ExternalId != "" && importTransaction.ExternalId != candidate.ExternalId { // different transactions in financial institution
+				continue
+			}
+
+			timeDifference := utils.GetUnixTimeFromTransactionTime(candidate.TransactionTime) - importUnixTime
+
+			if timeDifference < 0 {
+				timeDifference = -timeDifference
+			}
+
+			if timeDifference > IMPORT_TRANSACTION_DUPLICATE_TIME_WINDOW {
+				continue
+			}
+
+			candidatePairs = append(candidatePairs, &importTransactionDuplicateCandidate{
+				importTransactionIndex: i,
+				existedTransaction:     candidate,
+				timeDifference:         timeDifference,
+			})
+		}
+	}
+
+	// the pairs with closer transaction time are matched first
+	sort.SliceStable(candidatePairs, func(i, j int) bool {
+		return candidatePairs[i].timeDifference < candidatePairs[j].timeDifference
+	})
+
+	for i := 0; i < len(candidatePairs); i++ {
+		importTransaction := s[candidatePairs[i].importTransactionIndex]
+		existedTransaction := candidatePairs[i].existedTransaction
+
+		if importTransaction.LikelyDuplicate || matchedTransactionIds[existedTransaction.TransactionId] {
+			continue
+		}
+
+		importTransaction.LikelyDuplicate = true
+		importTransaction.DuplicateTransactionId = existedTransaction.TransactionId
+		matchedTransactionIds[existedTransaction.TransactionId] = true
+		duplicateCount++
+	}
+
+	return duplicateCount
+}
+
 // ToImportTransactionResponseList returns the a list of view-objects according to imported transaction data
 func (s ImportedTransactionSlice) ToImportTransactionResponseList() []*ImportTransactionResponse {
 	transactionResps := make([]*ImportTransactionResponse, 0, s.Len())
@@ -174,3 +343,8 @@ func (s ImportedTransactionSlice) ToImportTransactionResponseList() []*ImportTra
 
 	return transactionResps
 }
+
+func getTransactionDuplicateFingerprint(transaction *Transaction) string {
+	normalizedComment := strings.Join(strings.Fields(strings.ToLower(transaction.Comment)), " ")
+	return fmt.Sprintf("%d|%d|%d|%s", transaction.AccountId, transaction.Type, transaction.Amount, normalizedComment)
+}
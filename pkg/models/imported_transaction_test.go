@@ -395,3 +395,96 @@ func TestImportTransactionSliceLess_SameTransactionTimeSameTypeSameSubCategoryNa
 	assert.Equal(t, int64(5), transactionSlice[6].TransactionId)
 	assert.Equal(t, int64(1), transactionSlice[7].TransactionId)
 }
+
+func TestImportTransactionSliceMarkLikelyDuplicates(t *testing.T) {
+	importTransactions := ImportedTransactionSlice{
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: 1725148800000, Amount: 1000, Comment: "Coffee  Shop"}},
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: 1725152400000, Amount: 1000, Comment: "coffee shop"}},
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 1, TransactionTime: 1725148800000, Amount: 5000, ExternalId: "FIT001"}},
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2, TransactionTime: 1725148800000, Amount: 1000, Comment: "Coffee Shop"}},
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 0, TransactionTime: 1725148800000, Amount: 1000, Comment: "Coffee Shop"}},
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: 1725148800000, Amount: 200, ExternalId: "FIT002"}},
+	}
+
+	existedTransactions := []*Transaction{
+		{TransactionId: 101, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: 1725152500000, Amount: 1000, Comment: "Coffee Shop"},
+		{TransactionId: 102, Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 1, TransactionTime: 1725400000000, Amount: 4900, ExternalId: "FIT001"},
+		{TransactionId: 103, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 2, TransactionTime: 1725148800000 + (IMPORT_TRANSACTION_DUPLICATE_TIME_WINDOW+1)*1000, Amount: 1000, Comment: "Coffee Shop"},
+		{TransactionId: 104, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 1, TransactionTime: 1725148800000, Amount: 200, ExternalId: "FIT003"},
+	}
+
+	duplicateCount := importTransactions.MarkLikelyDuplicates(existedTransactions)
+	assert.Equal(t, 2, duplicateCount)
+
+	// the existed transaction can only match the closest imported transaction
+	assert.False(t, importTransactions[0].LikelyDuplicate)
+	assert.True(t, importTransactions[1].LikelyDuplicate)
+	assert.Equal(t, int64(101), importTransactions[1].DuplicateTransactionId)
+
+	// matched by the transaction id assigned by financial institution
+	assert.True(t, importTransactions[2].LikelyDuplicate)
+	assert.Equal(t, int64(102), importTransactions[2].DuplicateTransactionId)
+
+	// out of time window
+	assert.False(t, importTransactions[3].LikelyDuplicate)
+
+	// account does not exist
+	assert.False(t, importTransactions[4].LikelyDuplicate)
+
+	// different transaction ids assigned by financial institution
+	assert.False(t, importTransactions[5].LikelyDuplicate)
+}
+
+func TestImportTransactionSliceMarkLikelyDuplicates_IgnoreTransferIn(t *testing.T) {
+	importTransactions := ImportedTransactionSlice{
+		{Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, TransactionTime: 1725148800000, Amount: 1000}},
+	}
+
+	existedTransactions := []*Transaction{
+		{TransactionId: 101, Type: TRANSACTION_DB_TYPE_TRANSFER_IN, AccountId: 1, TransactionTime: 1725148800001, Amount: 1000},
+	}
+
+	assert.Equal(t, 0, importTransactions.MarkLikelyDuplicates(existedTransactions))
+	assert.False(t, importTransactions[0].LikelyDuplicate)
+
+	existedTransactions = append(existedTransactions, &Transaction{TransactionId: 100, Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 1, TransactionTime: 1725148800000, Amount: 1000})
+
+	assert.Equal(t, 1, importTransactions.MarkLikelyDuplicates(existedTransactions))
+	assert.Equal(t, int64(100), importTransactions[0].DuplicateTransactionId)
+}
+
+func TestImportTransactionApplyTransactionRules(t *testing.T) {
+	rules := TransactionRuleSlice{
+		{RuleId: 1, MatchType: TRANSACTION_TYPE_EXPENSE, MatchKeyword: "coffee", SetCategoryId: 20, AddTagIds: "30"},
+		{RuleId: 2, MatchType: TRANSACTION_TYPE_TRANSFER, SetDestinationAccountId: 12, SetComment: "Credit card repayment"},
+	}
+
+	expenseTransaction := &ImportTransaction{
+		Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 11, Amount: 1000, Comment: "Coffee Shop"},
+		TagIds:      []string{"31"},
+	}
+
+	rule := expenseTransaction.ApplyTransactionRules(rules)
+	assert.NotNil(t, rule)
+	assert.Equal(t, int64(1), rule.RuleId)
+	assert.Equal(t, int64(20), expenseTransaction.CategoryId)
+	assert.Equal(t, []string{"31", "30"}, expenseTransaction.TagIds)
+	assert.Equal(t, "Coffee Shop", expenseTransaction.Comment)
+
+	transferTransaction := &ImportTransaction{
+		Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_TRANSFER_OUT, AccountId: 11, RelatedAccountId: 13, Amount: 1000},
+	}
+
+	rule = transferTransaction.ApplyTransactionRules(rules)
+	assert.NotNil(t, rule)
+	assert.Equal(t, int64(2), rule.RuleId)
+	assert.Equal(t, int64(12), transferTransaction.RelatedAccountId)
+	assert.Equal(t, "Credit card repayment", transferTransaction.Comment)
+
+	incomeTransaction := &ImportTransaction{
+		Transaction: &Transaction{Type: TRANSACTION_DB_TYPE_INCOME, AccountId: 11, CategoryId: 21, Amount: 1000, Comment: "Coffee refund"},
+	}
+
+	assert.Nil(t, incomeTransaction.ApplyTransactionRules(rules))
+	assert.Equal(t, int64(21), incomeTransaction.CategoryId)
+}
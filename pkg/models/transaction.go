@@ -66,6 +66,9 @@ func (s TransactionDbType) ToTransactionType() (TransactionType, error) {
 	}
 }
 
+// TRANSACTION_EXTERNAL_ID_MAX_LENGTH represents the maximum length of the transaction id assigned by financial institution (e.g. FITID in ofx file)
+const TRANSACTION_EXTERNAL_ID_MAX_LENGTH = 64
+
 // TransactionTagFilterType represents transaction tag filter type
 type TransactionTagFilterType byte
 
@@ -80,8 +83,8 @@ const (
 // Transaction represents transaction data stored in database
 type Transaction struct {
 	TransactionId        int64             `xorm:"PK"`
-	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
-	Deleted              bool              `xorm:"INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) NOT NULL"`
+	Uid                  int64             `xorm:"UNIQUE(UQE_transaction_uid_time) INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) INDEX(IDX_transaction_uid_deleted_import_batch_id) NOT NULL"`
+	Deleted              bool              `xorm:"INDEX(IDX_transaction_uid_deleted_time) INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) INDEX(IDX_transaction_uid_deleted_category_id_time) INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_time_longitude_latitude) INDEX(IDX_transaction_uid_deleted_import_batch_id) NOT NULL"`
 	Type                 TransactionDbType `xorm:"INDEX(IDX_transaction_uid_deleted_type_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
 	CategoryId           int64             `xorm:"INDEX(IDX_transaction_uid_deleted_category_id_time) NOT NULL"`
 	AccountId            int64             `xorm:"INDEX(IDX_transaction_uid_deleted_account_id_time) INDEX(IDX_transaction_uid_deleted_type_account_id_time) NOT NULL"`
@@ -93,6 +96,8 @@ type Transaction struct {
 	RelatedAccountAmount int64             `xorm:"NOT NULL"`
 	HideAmount           bool              `xorm:"NOT NULL"`
 	Comment              string            `xorm:"VARCHAR(255) NOT NULL"`
+	ExternalId           string            `xorm:"VARCHAR(64)"`
+	ImportBatchId        int64             `xorm:"INDEX(IDX_transaction_uid_deleted_import_batch_id)"`
 	GeoLongitude         float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
 	GeoLatitude          float64           `xorm:"INDEX(IDX_transaction_uid_deleted_time_longitude_latitude)"`
 	CreatedIp            string            `xorm:"VARCHAR(39)"`
@@ -102,6 +107,15 @@ type Transaction struct {
 	DeletedUnixTime      int64
 }
 
+// TransactionTotalAmount represents the total amount of transactions in one category of one account
+type TransactionTotalAmount struct {
+	CategoryId                 int64
+	AccountId                  int64
+	Amount                     int64
+	AmountInDefaultCurrency    int64
+	HasAmountInDefaultCurrency bool
+}
+
 // TransactionGeoLocationRequest represents all parameters of transaction geographic location info update request
 type TransactionGeoLocationRequest struct {
 	Latitude  float64 `json:"latitude" binding:"required"`
@@ -123,6 +137,9 @@ type TransactionCreateRequest struct {
 	PictureIds           []string                       `json:"pictureIds"`
 	Comment              string                         `json:"comment" binding:"max=255"`
 	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Splits               []*TransactionSplitRequest     `json:"splits" binding:"omitempty,dive"`
+	ApplyRules           bool                           `json:"applyRules"`
+	ExternalId           string                         `json:"externalId" binding:"max=64"`
 	ClientSessionId      string                         `json:"clientSessionId"`
 }
 
@@ -141,11 +158,14 @@ type TransactionModifyRequest struct {
 	PictureIds           []string                       `json:"pictureIds"`
 	Comment              string                         `json:"comment" binding:"max=255"`
 	GeoLocation          *TransactionGeoLocationRequest `json:"geoLocation" binding:"omitempty"`
+	Splits               []*TransactionSplitRequest     `json:"splits" binding:"omitempty,dive"`
 }
 
 // TransactionImportRequest represents all parameters of transaction import request
 type TransactionImportRequest struct {
 	Transactions    []*TransactionCreateRequest `json:"transactions"`
+	FileType        string                      `json:"fileType" binding:"max=32"`
+	FileName        string                      `json:"fileName" binding:"max=255"`
 	ClientSessionId string                      `json:"clientSessionId"`
 }
 
@@ -276,6 +296,7 @@ type TransactionInfoResponse struct {
 	Pictures             TransactionPictureInfoBasicResponseSlice `json:"pictures,omitempty"`
 	Comment              string                                   `json:"comment"`
 	GeoLocation          *TransactionGeoLocationResponse          `json:"geoLocation,omitempty"`
+	Splits               []*TransactionSplitInfoResponse          `json:"splits,omitempty"`
 	Editable             bool                                     `json:"editable"`
 }
 
@@ -306,9 +327,10 @@ type TransactionStatisticResponse struct {
 
 // TransactionStatisticResponseItem represents total amount item for a response
 type TransactionStatisticResponseItem struct {
-	CategoryId  int64 `json:"categoryId,string"`
-	AccountId   int64 `json:"accountId,string"`
-	TotalAmount int64 `json:"amount"`
+	CategoryId              int64  `json:"categoryId,string"`
+	AccountId               int64  `json:"accountId,string"`
+	TotalAmount             int64  `json:"amount"`
+	AmountInDefaultCurrency *int64 `json:"amountInDefaultCurrency,omitempty"`
 }
 
 // TransactionStatisticTrendsItem represents the data within each statistic interval
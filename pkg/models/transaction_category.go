@@ -3,6 +3,9 @@ package models
 // LevelOneTransactionParentId represents the parent id of level-one transaction category
 const LevelOneTransactionParentId = 0
 
+// transactionCategoryHierarchyNameSeparator represents the separator between primary category name and secondary category name in hierarchy name
+const transactionCategoryHierarchyNameSeparator = "\x00"
+
 // TransactionCategoryType represents transaction category type
 type TransactionCategoryType byte
 
@@ -31,6 +34,15 @@ type TransactionCategory struct {
 	DeletedUnixTime  int64
 }
 
+// GetTransactionCategoryHierarchyName returns the name which contains both primary category name and secondary category name, or the category name itself if primary category name is empty
+func GetTransactionCategoryHierarchyName(primaryCategoryName string, categoryName string) string {
+	if primaryCategoryName == "" {
+		return categoryName
+	}
+
+	return primaryCategoryName + transactionCategoryHierarchyNameSeparator + categoryName
+}
+
 // TransactionCategoryListRequest represents all parameters of transaction category listing request
 type TransactionCategoryListRequest struct {
 	Type     TransactionCategoryType `form:"type" binding:"min=0"`
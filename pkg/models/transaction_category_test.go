@@ -28,3 +28,9 @@ func TestTransactionCategoryInfoResponseSliceLess(t *testing.T) {
 	assert.Equal(t, int64(3), transactionCategoryRespSlice[1].Id)
 	assert.Equal(t, int64(1), transactionCategoryRespSlice[2].Id)
 }
+
+func TestGetTransactionCategoryHierarchyName(t *testing.T) {
+	assert.Equal(t, "Food", GetTransactionCategoryHierarchyName("", "Food"))
+	assert.NotEqual(t, "Food", GetTransactionCategoryHierarchyName("Travel", "Food"))
+	assert.NotEqual(t, GetTransactionCategoryHierarchyName("Usual Expenses", "Food"), GetTransactionCategoryHierarchyName("Travel", "Food"))
+}
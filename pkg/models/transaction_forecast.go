@@ -0,0 +1,125 @@
+package models
+
+// TRANSACTION_FORECAST_MAX_DAYS represents the maximum days of transaction forecast
+const TRANSACTION_FORECAST_MAX_DAYS = 366
+
+// TransactionForecastRequest represents all parameters of transaction forecast request
+type TransactionForecastRequest struct {
+	StartTime int64 `form:"startTime" binding:"min=0"`
+	EndTime   int64 `form:"endTime" binding:"required,min=1"`
+}
+
+// TransactionForecastResponse represents a view-object of upcoming scheduled transactions and projected account balances
+type TransactionForecastResponse struct {
+	StartTime    int64                                       `json:"startTime"`
+	EndTime      int64                                       `json:"endTime"`
+	Dates        []string                                    `json:"dates"`
+	Transactions TransactionForecastTransactionResponseSlice `json:"transactions"`
+	Accounts     []*AccountForecastResponse                  `json:"accounts"`
+	Totals       []*AccountForecastTotalResponse             `json:"totals"`
+}
+
+// TransactionForecastTransactionResponse represents a view-object of upcoming scheduled transaction which has not been created
+type TransactionForecastTransactionResponse struct {
+	TemplateId           int64           `json:"templateId,string"`
+	TemplateName         string          `json:"templateName"`
+	Type                 TransactionType `json:"type"`
+	CategoryId           int64           `json:"categoryId,string"`
+	Time                 int64           `json:"time"`
+	UtcOffset            int16           `json:"utcOffset"`
+	SourceAccountId      int64           `json:"sourceAccountId,string"`
+	DestinationAccountId int64           `json:"destinationAccountId,string"`
+	SourceAmount         int64           `json:"sourceAmount"`
+	DestinationAmount    int64           `json:"destinationAmount"`
+	HideAmount           bool            `json:"hideAmount"`
+	TagIds               []string        `json:"tagIds"`
+	Comment              string          `json:"comment"`
+}
+
+// AccountForecastResponse represents a view-object of projected daily balances of account
+type AccountForecastResponse struct {
+	AccountId      int64   `json:"accountId,string"`
+	Currency       string  `json:"currency"`
+	IsAsset        bool    `json:"isAsset,omitempty"`
+	IsLiability    bool    `json:"isLiability,omitempty"`
+	CurrentBalance int64   `json:"currentBalance"`
+	Balances       []int64 `json:"balances"`
+}
+
+// AccountForecastTotalResponse represents a view-object of projected daily total assets and liabilities of one currency
+type AccountForecastTotalResponse struct {
+	Currency         string  `json:"currency"`
+	TotalAssets      []int64 `json:"totalAssets"`
+	TotalLiabilities []int64 `json:"totalLiabilities"`
+	NetAssets        []int64 `json:"netAssets"`
+}
+
+// ToAccountForecastResponse returns a view-object of projected daily balances according to database model
+func (a *Account) ToAccountForecastResponse() *AccountForecastResponse {
+	return &AccountForecastResponse{
+		AccountId:      a.AccountId,
+		Currency:       a.Currency,
+		IsAsset:        assetAccountCategory[a.Category],
+		IsLiability:    liabilityAccountCategory[a.Category],
+		CurrentBalance: a.Balance,
+		Balances:       make([]int64, 0),
+	}
+}
+
+// GetAccountForecastTotals returns the projected daily total assets and liabilities of each currency
+func GetAccountForecastTotals(accounts []*AccountForecastResponse, daysCount int) []*AccountForecastTotalResponse {
+	totals := make([]*AccountForecastTotalResponse, 0)
+	totalsMap := make(map[string]*AccountForecastTotalResponse)
+
+	for i := 0; i < len(accounts); i++ {
+		account := accounts[i]
+		total, exists := totalsMap[account.Currency]
+
+		if !exists {
+			total = &AccountForecastTotalResponse{
+				Currency:         account.Currency,
+				TotalAssets:      make([]int64, daysCount),
+				TotalLiabilities: make([]int64, daysCount),
+				NetAssets:        make([]int64, daysCount),
+			}
+
+			totals = append(totals, total)
+			totalsMap[account.Currency] = total
+		}
+
+		for j := 0; j < daysCount && j < len(account.Balances); j++ {
+			// the balance of liability account is negative when there is debt, so the liabilities is the opposite number of the balance
+			if account.IsLiability {
+				total.TotalLiabilities[j] -= account.Balances[j]
+			} else if account.IsAsset {
+				total.TotalAssets[j] += account.Balances[j]
+			}
+
+			total.NetAssets[j] = total.TotalAssets[j] - total.TotalLiabilities[j]
+		}
+	}
+
+	return totals
+}
+
+// TransactionForecastTransactionResponseSlice represents the slice data structure of TransactionForecastTransactionResponse
+type TransactionForecastTransactionResponseSlice []*TransactionForecastTransactionResponse
+
+// Len returns the count of items
+func (s TransactionForecastTransactionResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionForecastTransactionResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionForecastTransactionResponseSlice) Less(i, j int) bool {
+	if s[i].Time != s[j].Time {
+		return s[i].Time < s[j].Time
+	}
+
+	return s[i].TemplateId < s[j].TemplateId
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionTemplateGetAccountBalanceChanges(t *testing.T) {
+	template := &TransactionTemplate{
+		Type:      TRANSACTION_TYPE_EXPENSE,
+		AccountId: 1,
+		Amount:    1000,
+	}
+	assert.Equal(t, map[int64]int64{1: -1000}, template.GetAccountBalanceChanges())
+
+	template.Type = TRANSACTION_TYPE_INCOME
+	assert.Equal(t, map[int64]int64{1: 1000}, template.GetAccountBalanceChanges())
+
+	template.Type = TRANSACTION_TYPE_TRANSFER
+	template.RelatedAccountId = 2
+	template.RelatedAccountAmount = 150
+	assert.Equal(t, map[int64]int64{1: -1000, 2: 150}, template.GetAccountBalanceChanges())
+}
+
+func TestAccountToAccountForecastResponse(t *testing.T) {
+	account := &Account{
+		AccountId: 1,
+		Category:  ACCOUNT_CATEGORY_CHECKING_ACCOUNT,
+		Currency:  "USD",
+		Balance:   1000,
+	}
+
+	accountResp := account.ToAccountForecastResponse()
+	assert.Equal(t, int64(1), accountResp.AccountId)
+	assert.Equal(t, int64(1000), accountResp.CurrentBalance)
+	assert.True(t, accountResp.IsAsset)
+	assert.False(t, accountResp.IsLiability)
+
+	account.Category = ACCOUNT_CATEGORY_CREDIT_CARD
+	accountResp = account.ToAccountForecastResponse()
+	assert.False(t, accountResp.IsAsset)
+	assert.True(t, accountResp.IsLiability)
+}
+
+func TestGetAccountForecastTotals(t *testing.T) {
+	accounts := []*AccountForecastResponse{
+		{AccountId: 1, Currency: "USD", IsAsset: true, Balances: []int64{1000, 800, 3000}},
+		{AccountId: 2, Currency: "USD", IsLiability: true, Balances: []int64{-200, -400, 0}},
+		{AccountId: 3, Currency: "EUR", IsAsset: true, Balances: []int64{500, 500, 500}},
+		{AccountId: 4, Currency: "EUR", IsLiability: true, Balances: []int64{100, 100, 100}},
+	}
+
+	totals := GetAccountForecastTotals(accounts, 3)
+	assert.Equal(t, 2, len(totals))
+
+	assert.Equal(t, "USD", totals[0].Currency)
+	assert.Equal(t, []int64{1000, 800, 3000}, totals[0].TotalAssets)
+	assert.Equal(t, []int64{200, 400, 0}, totals[0].TotalLiabilities)
+	assert.Equal(t, []int64{800, 400, 3000}, totals[0].NetAssets)
+
+	assert.Equal(t, "EUR", totals[1].Currency)
+	assert.Equal(t, []int64{500, 500, 500}, totals[1].TotalAssets)
+	assert.Equal(t, []int64{-100, -100, -100}, totals[1].TotalLiabilities)
+	assert.Equal(t, []int64{600, 600, 600}, totals[1].NetAssets)
+}
+
+func TestTransactionForecastTransactionResponseSliceLess(t *testing.T) {
+	transactionResps := TransactionForecastTransactionResponseSlice{
+		{TemplateId: 2, Time: 2000},
+		{TemplateId: 3, Time: 1000},
+		{TemplateId: 1, Time: 2000},
+	}
+
+	sort.Sort(transactionResps)
+
+	assert.Equal(t, int64(3), transactionResps[0].TemplateId)
+	assert.Equal(t, int64(1), transactionResps[1].TemplateId)
+	assert.Equal(t, int64(2), transactionResps[2].TemplateId)
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TRANSACTION_IMPORT_BATCH_MAX_CREATED_ITEMS represents the maximum count of accounts or categories which are created for one import batch
+const TRANSACTION_IMPORT_BATCH_MAX_CREATED_ITEMS = 100
+
+// TransactionImportBatch represents transaction import batch data stored in database
+type TransactionImportBatch struct {
+	BatchId            int64  `xorm:"PK"`
+	Uid                int64  `xorm:"INDEX(IDX_transaction_import_batch_uid_deleted_created_time) NOT NULL"`
+	Deleted            bool   `xorm:"INDEX(IDX_transaction_import_batch_uid_deleted_created_time) NOT NULL"`
+	FileType           string `xorm:"VARCHAR(32) NOT NULL"`
+	FileName           string `xorm:"VARCHAR(255) NOT NULL"`
+	TransactionCount   int32  `xorm:"NOT NULL"`
+	CreatedAccountIds  string `xorm:"VARCHAR(2048) NOT NULL"`
+	CreatedCategoryIds string `xorm:"VARCHAR(2048) NOT NULL"`
+	CreatedUnixTime    int64  `xorm:"INDEX(IDX_transaction_import_batch_uid_deleted_created_time)"`
+	UpdatedUnixTime    int64
+	DeletedUnixTime    int64
+}
+
+// TransactionImportBatchRevertRequest represents all parameters of transaction import batch reverting request
+type TransactionImportBatchRevertRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionImportBatchRevertResponse represents a view-object of transaction import batch reverting result
+type TransactionImportBatchRevertResponse struct {
+	TransactionCount     int `json:"transactionCount"`
+	DeletedAccountCount  int `json:"deletedAccountCount"`
+	DeletedCategoryCount int `json:"deletedCategoryCount"`
+}
+
+// TransactionImportBatchInfoResponse represents a view-object of transaction import batch
+type TransactionImportBatchInfoResponse struct {
+	Id                   int64  `json:"id,string"`
+	FileType             string `json:"fileType"`
+	FileName             string `json:"fileName"`
+	TransactionCount     int32  `json:"transactionCount"`
+	CreatedAccountCount  int    `json:"createdAccountCount"`
+	CreatedCategoryCount int    `json:"createdCategoryCount"`
+	ImportedTime         int64  `json:"importedTime"`
+}
+
+// GetCreatedAccountIds returns the ids of all accounts which are created for this import batch
+func (b *TransactionImportBatch) GetCreatedAccountIds() []int64 {
+	return getTransactionImportBatchIds(b.CreatedAccountIds)
+}
+
+// GetCreatedCategoryIds returns the ids of all categories which are created for this import batch
+func (b *TransactionImportBatch) GetCreatedCategoryIds() []int64 {
+	return getTransactionImportBatchIds(b.CreatedCategoryIds)
+}
+
+// ToTransactionImportBatchInfoResponse returns a view-object according to database model
+func (b *TransactionImportBatch) ToTransactionImportBatchInfoResponse() *TransactionImportBatchInfoResponse {
+	return &TransactionImportBatchInfoResponse{
+		Id:                   b.BatchId,
+		FileType:             b.FileType,
+		FileName:             b.FileName,
+		TransactionCount:     b.TransactionCount,
+		CreatedAccountCount:  len(b.GetCreatedAccountIds()),
+		CreatedCategoryCount: len(b.GetCreatedCategoryIds()),
+		ImportedTime:         b.CreatedUnixTime,
+	}
+}
+
+func getTransactionImportBatchIds(ids string) []int64 {
+	if ids == "" {
+		return nil
+	}
+
+	items := strings.Split(ids, ",")
+	result := make([]int64, 0, len(items))
+
+	for i := 0; i < len(items); i++ {
+		id, err := utils.StringToInt64(items[i])
+
+		if err != nil || id <= 0 {
+			continue
+		}
+
+		result = append(result, id)
+	}
+
+	return result
+}
+
+// TransactionImportBatchInfoResponseSlice represents the slice data structure of TransactionImportBatchInfoResponse
+type TransactionImportBatchInfoResponseSlice []*TransactionImportBatchInfoResponse
+
+// Len returns the count of items
+func (s TransactionImportBatchInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionImportBatchInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionImportBatchInfoResponseSlice) Less(i, j int) bool {
+	if s[i].ImportedTime != s[j].ImportedTime {
+		return s[i].ImportedTime > s[j].ImportedTime
+	}
+
+	return s[i].Id > s[j].Id
+}
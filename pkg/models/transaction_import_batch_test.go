@@ -0,0 +1,54 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionImportBatchGetCreatedAccountIds(t *testing.T) {
+	assert.Nil(t, (&TransactionImportBatch{}).GetCreatedAccountIds())
+	assert.Equal(t, []int64{1, 2, 3}, (&TransactionImportBatch{CreatedAccountIds: "1,2,3"}).GetCreatedAccountIds())
+	assert.Equal(t, []int64{1, 3}, (&TransactionImportBatch{CreatedAccountIds: "1,foo,3,-4,0"}).GetCreatedAccountIds())
+}
+
+func TestTransactionImportBatchGetCreatedCategoryIds(t *testing.T) {
+	assert.Nil(t, (&TransactionImportBatch{}).GetCreatedCategoryIds())
+	assert.Equal(t, []int64{10, 20}, (&TransactionImportBatch{CreatedCategoryIds: "10,20"}).GetCreatedCategoryIds())
+}
+
+func TestTransactionImportBatchToTransactionImportBatchInfoResponse(t *testing.T) {
+	batch := &TransactionImportBatch{
+		BatchId:            100,
+		FileType:           "ofx",
+		FileName:           "statement.ofx",
+		TransactionCount:   12,
+		CreatedAccountIds:  "1,2",
+		CreatedCategoryIds: "3",
+		CreatedUnixTime:    1735660800,
+	}
+
+	response := batch.ToTransactionImportBatchInfoResponse()
+	assert.Equal(t, int64(100), response.Id)
+	assert.Equal(t, "ofx", response.FileType)
+	assert.Equal(t, "statement.ofx", response.FileName)
+	assert.Equal(t, int32(12), response.TransactionCount)
+	assert.Equal(t, 2, response.CreatedAccountCount)
+	assert.Equal(t, 1, response.CreatedCategoryCount)
+	assert.Equal(t, int64(1735660800), response.ImportedTime)
+}
+
+func TestTransactionImportBatchInfoResponseSliceSort(t *testing.T) {
+	responses := TransactionImportBatchInfoResponseSlice{
+		{Id: 1, ImportedTime: 100},
+		{Id: 3, ImportedTime: 300},
+		{Id: 2, ImportedTime: 300},
+	}
+
+	sort.Sort(responses)
+
+	assert.Equal(t, int64(3), responses[0].Id)
+	assert.Equal(t, int64(2), responses[1].Id)
+	assert.Equal(t, int64(1), responses[2].Id)
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TRANSACTION_IMPORT_JOB_MAX_ROW_ERRORS represents the maximum count of row errors which are stored for one import job
+const TRANSACTION_IMPORT_JOB_MAX_ROW_ERRORS = 100
+
+// TRANSACTION_IMPORT_JOB_MAX_IDLE_SECONDS represents the maximum seconds since the last progress update of an unfinished import job, the job will be considered as interrupted after that
+const TRANSACTION_IMPORT_JOB_MAX_IDLE_SECONDS = 1800
+
+// TransactionImportJobStatus represents transaction import job status
+type TransactionImportJobStatus byte
+
+// Transaction import job statuses
+const (
+	TRANSACTION_IMPORT_JOB_STATUS_PENDING   TransactionImportJobStatus = 1
+	TRANSACTION_IMPORT_JOB_STATUS_PARSING   TransactionImportJobStatus = 2
+	TRANSACTION_IMPORT_JOB_STATUS_IMPORTING TransactionImportJobStatus = 3
+	TRANSACTION_IMPORT_JOB_STATUS_SUCCEEDED TransactionImportJobStatus = 4
+	TRANSACTION_IMPORT_JOB_STATUS_FAILED    TransactionImportJobStatus = 5
+)
+
+// IsFinished returns whether the import job with this status has finished
+func (s TransactionImportJobStatus) IsFinished() bool {
+	return s == TRANSACTION_IMPORT_JOB_STATUS_SUCCEEDED || s == TRANSACTION_IMPORT_JOB_STATUS_FAILED
+}
+
+// TransactionImportJob represents transaction import job data stored in database
+type TransactionImportJob struct {
+	JobId                 int64                           `xorm:"PK"`
+	Uid                   int64                           `xorm:"INDEX(IDX_transaction_import_job_uid_deleted_created_time) NOT NULL"`
+	Deleted               bool                            `xorm:"INDEX(IDX_transaction_import_job_uid_deleted_created_time) NOT NULL"`
+	Status                TransactionImportJobStatus      `xorm:"NOT NULL"`
+	FileType              string                          `xorm:"VARCHAR(32) NOT NULL"`
+	FileName              string                          `xorm:"VARCHAR(255) NOT NULL"`
+	SkipLikelyDuplicates  bool                            `xorm:"NOT NULL"`
+	TotalCount            int32                           `xorm:"NOT NULL"`
+	ProcessedCount        int32                           `xorm:"NOT NULL"`
+	ImportedCount         int32                           `xorm:"NOT NULL"`
+	SkippedDuplicateCount int32                           `xorm:"NOT NULL"`
+	FailedCount           int32                           `xorm:"NOT NULL"`
+	ImportBatchId         int64                           `xorm:"NOT NULL"`
+	ErrorMessage          string                          `xorm:"VARCHAR(255) NOT NULL"`
+	RowErrors             []*TransactionImportJobRowError `xorm:"BLOB"`
+	CreatedUnixTime       int64                           `xorm:"INDEX(IDX_transaction_import_job_uid_deleted_created_time)"`
+	UpdatedUnixTime       int64
+	FinishedUnixTime      int64
+	DeletedUnixTime       int64
+}
+
+// TransactionImportJobRowError represents the error of one row which cannot be imported in transaction import job
+type TransactionImportJobRowError struct {
+	Index        int    `json:"index"`
+	Time         int64  `json:"time"`
+	AccountName  string `json:"accountName"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// TransactionImportJobGetRequest represents all parameters of transaction import job getting request
+type TransactionImportJobGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionImportJobInfoResponse represents a view-object of transaction import job
+type TransactionImportJobInfoResponse struct {
+	Id                    int64                           `json:"id,string"`
+	Status                TransactionImportJobStatus      `json:"status"`
+	FileType              string                          `json:"fileType"`
+	FileName              string                          `json:"fileName"`
+	SkipLikelyDuplicates  bool                            `json:"skipLikelyDuplicates"`
+	TotalCount            int32                           `json:"totalCount"`
+	ProcessedCount        int32                           `json:"processedCount"`
+	ImportedCount         int32                           `json:"importedCount"`
+	SkippedDuplicateCount int32                           `json:"skippedDuplicateCount"`
+	FailedCount           int32                           `json:"failedCount"`
+	Progress              int32                           `json:"progress"`
+	ImportBatchId         int64                           `json:"importBatchId,string,omitempty"`
+	ErrorMessage          string                          `json:"errorMessage,omitempty"`
+	RowErrors             []*TransactionImportJobRowError `json:"rowErrors"`
+	CreatedTime           int64                           `json:"createdTime"`
+	UpdatedTime           int64                           `json:"updatedTime"`
+	FinishedTime          int64                           `json:"finishedTime,omitempty"`
+}
+
+// AddRowError increases the failed count and saves the row error if the count of saved row errors does not exceed the limit
+func (j *TransactionImportJob) AddRowError(index int, transaction *ImportTransaction, errorMessage string) {
+	j.FailedCount++
+
+	if len(j.RowErrors) >= TRANSACTION_IMPORT_JOB_MAX_ROW_ERRORS {
+		return
+	}
+
+	rowError := &TransactionImportJobRowError{
+		Index:        index,
+		ErrorMessage: errorMessage,
+	}
+
+	if transaction != nil {
+		rowError.AccountName = transaction.OriginalSourceAccountName
+
+		if transaction.Transaction != nil {
+			rowError.Time = utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
+		}
+	}
+
+	j.RowErrors = append(j.RowErrors, rowError)
+}
+
+// IsInterrupted returns whether the unfinished import job has not been updated for a long time (e.g. the server was restarted)
+func (j *TransactionImportJob) IsInterrupted(currentUnixTime int64) bool {
+	return !j.Status.IsFinished() && currentUnixTime-j.UpdatedUnixTime > TRANSACTION_IMPORT_JOB_MAX_IDLE_SECONDS
+}
+
+// GetProgress returns the percentage of processed rows
+func (j *TransactionImportJob) GetProgress() int32 {
+	if j.Status == TRANSACTION_IMPORT_JOB_STATUS_SUCCEEDED {
+		return 100
+	}
+
+	if j.TotalCount < 1 {
+		return 0
+	}
+
+	progress := int32(int64(j.ProcessedCount) * 100 / int64(j.TotalCount))
+
+	if progress > 100 {
+		progress = 100
+	}
+
+	return progress
+}
+
+// ToTransactionImportJobInfoResponse returns a view-object according to database model
+func (j *TransactionImportJob) ToTransactionImportJobInfoResponse(currentUnixTime int64) *TransactionImportJobInfoResponse {
+	status := j.Status
+	errorMessage := j.ErrorMessage
+
+	if j.IsInterrupted(currentUnixTime) {
+		status = TRANSACTION_IMPORT_JOB_STATUS_FAILED
+		errorMessage = errs.ErrTransactionImportJobInterrupted.Message
+	}
+
+	rowErrors := j.RowErrors
+
+	if rowErrors == nil {
+		rowErrors = make([]*TransactionImportJobRowError, 0)
+	}
+
+	return &TransactionImportJobInfoResponse{
+		Id:                    j.JobId,
+		Status:                status,
+		FileType:              j.FileType,
+		FileName:              j.FileName,
+		SkipLikelyDuplicates:  j.SkipLikelyDuplicates,
+		TotalCount:            j.TotalCount,
+		ProcessedCount:        j.ProcessedCount,
+		ImportedCount:         j.ImportedCount,
+		SkippedDuplicateCount: j.SkippedDuplicateCount,
+		FailedCount:           j.FailedCount,
+		Progress:              j.GetProgress(),
+		ImportBatchId:         j.ImportBatchId,
+		ErrorMessage:          errorMessage,
+		RowErrors:             rowErrors,
+		CreatedTime:           j.CreatedUnixTime,
+		UpdatedTime:           j.UpdatedUnixTime,
+		FinishedTime:          j.FinishedUnixTime,
+	}
+}
+
+// TransactionImportJobInfoResponseSlice represents the slice data structure of TransactionImportJobInfoResponse
+type TransactionImportJobInfoResponseSlice []*TransactionImportJobInfoResponse
+
+// Len returns the count of items
+func (s TransactionImportJobInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionImportJobInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionImportJobInfoResponseSlice) Less(i, j int) bool {
+	if s[i].CreatedTime != s[j].CreatedTime {
+		return s[i].CreatedTime > s[j].CreatedTime
+	}
+
+	return s[i].Id > s[j].Id
+}
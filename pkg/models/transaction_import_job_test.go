@@ -0,0 +1,62 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestTransactionImportJobAddRowError(t *testing.T) {
+	job := &TransactionImportJob{}
+	transaction := &ImportTransaction{
+		Transaction:               &Transaction{TransactionTime: 1725148800123},
+		OriginalSourceAccountName: "Bank",
+	}
+
+	job.AddRowError(3, transaction, errs.ErrAccountNotFound.Message)
+	assert.Equal(t, int32(1), job.FailedCount)
+	assert.Equal(t, 1, len(job.RowErrors))
+	assert.Equal(t, 3, job.RowErrors[0].Index)
+	assert.Equal(t, int64(1725148800), job.RowErrors[0].Time)
+	assert.Equal(t, "Bank", job.RowErrors[0].AccountName)
+	assert.Equal(t, errs.ErrAccountNotFound.Message, job.RowErrors[0].ErrorMessage)
+
+	for i := 0; i < TRANSACTION_IMPORT_JOB_MAX_ROW_ERRORS+10; i++ {
+		job.AddRowError(i, nil, errs.ErrTransactionCategoryNotFound.Message)
+	}
+
+	assert.Equal(t, int32(TRANSACTION_IMPORT_JOB_MAX_ROW_ERRORS+11), job.FailedCount)
+	assert.Equal(t, TRANSACTION_IMPORT_JOB_MAX_ROW_ERRORS, len(job.RowErrors))
+}
+
+func TestTransactionImportJobGetProgress(t *testing.T) {
+	assert.Equal(t, int32(0), (&TransactionImportJob{Status: TRANSACTION_IMPORT_JOB_STATUS_PARSING}).GetProgress())
+	assert.Equal(t, int32(33), (&TransactionImportJob{Status: TRANSACTION_IMPORT_JOB_STATUS_IMPORTING, TotalCount: 3, ProcessedCount: 1}).GetProgress())
+	assert.Equal(t, int32(100), (&TransactionImportJob{Status: TRANSACTION_IMPORT_JOB_STATUS_SUCCEEDED}).GetProgress())
+}
+
+func TestTransactionImportJobToTransactionImportJobInfoResponse_Interrupted(t *testing.T) {
+	job := &TransactionImportJob{
+		JobId:           100,
+		Status:          TRANSACTION_IMPORT_JOB_STATUS_IMPORTING,
+		TotalCount:      10,
+		ProcessedCount:  5,
+		UpdatedUnixTime: 1725148800,
+	}
+
+	response := job.ToTransactionImportJobInfoResponse(1725148800 + TRANSACTION_IMPORT_JOB_MAX_IDLE_SECONDS)
+	assert.Equal(t, TRANSACTION_IMPORT_JOB_STATUS_IMPORTING, response.Status)
+	assert.Equal(t, int32(50), response.Progress)
+	assert.Equal(t, "", response.ErrorMessage)
+	assert.NotNil(t, response.RowErrors)
+
+	response = job.ToTransactionImportJobInfoResponse(1725148800 + TRANSACTION_IMPORT_JOB_MAX_IDLE_SECONDS + 1)
+	assert.Equal(t, TRANSACTION_IMPORT_JOB_STATUS_FAILED, response.Status)
+	assert.Equal(t, errs.ErrTransactionImportJobInterrupted.Message, response.ErrorMessage)
+
+	job.Status = TRANSACTION_IMPORT_JOB_STATUS_SUCCEEDED
+	response = job.ToTransactionImportJobInfoResponse(1725148800 + TRANSACTION_IMPORT_JOB_MAX_IDLE_SECONDS + 1)
+	assert.Equal(t, TRANSACTION_IMPORT_JOB_STATUS_SUCCEEDED, response.Status)
+}
@@ -0,0 +1,226 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// TransactionImportProfileFileType represents the file type of transaction import profile
+type TransactionImportProfileFileType byte
+
+// Transaction import profile file types
+const (
+	TRANSACTION_IMPORT_PROFILE_FILE_TYPE_DSV   TransactionImportProfileFileType = 1
+	TRANSACTION_IMPORT_PROFILE_FILE_TYPE_EXCEL TransactionImportProfileFileType = 2
+)
+
+// TransactionImportProfileAmountSignType represents how transaction import profile determines the transaction type by amount
+type TransactionImportProfileAmountSignType byte
+
+// Transaction import profile amount sign types
+const (
+	TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_POSITIVE_INCOME  TransactionImportProfileAmountSignType = 0
+	TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_POSITIVE_EXPENSE TransactionImportProfileAmountSignType = 1
+	TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_TYPE_COLUMN      TransactionImportProfileAmountSignType = 2
+	TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_SEPARATE_COLUMNS TransactionImportProfileAmountSignType = 3
+)
+
+// TRANSACTION_IMPORT_PROFILE_MAX_SKIP_ROWS represents the maximum rows which can be skipped before the header row
+const TRANSACTION_IMPORT_PROFILE_MAX_SKIP_ROWS = 100
+
+// TransactionImportProfile represents transaction import profile data stored in database
+type TransactionImportProfile struct {
+	ProfileId           int64                                  `xorm:"PK"`
+	Uid                 int64                                  `xorm:"INDEX(IDX_transaction_import_profile_uid_deleted) NOT NULL"`
+	Deleted             bool                                   `xorm:"INDEX(IDX_transaction_import_profile_uid_deleted) NOT NULL"`
+	Name                string                                 `xorm:"VARCHAR(32) NOT NULL"`
+	FileType            TransactionImportProfileFileType       `xorm:"NOT NULL"`
+	Delimiter           string                                 `xorm:"VARCHAR(1) NOT NULL"`
+	SkipRows            int32                                  `xorm:"NOT NULL"`
+	ColumnMapping       *TransactionImportProfileColumnMapping `xorm:"BLOB"`
+	DateTimeFormat      string                                 `xorm:"VARCHAR(32) NOT NULL"`
+	DecimalSeparator    string                                 `xorm:"VARCHAR(1) NOT NULL"`
+	DigitGroupingSymbol string                                 `xorm:"VARCHAR(1) NOT NULL"`
+	AmountSignType      TransactionImportProfileAmountSignType `xorm:"NOT NULL"`
+	IncomeTypeValue     string                                 `xorm:"VARCHAR(32) NOT NULL"`
+	ExpenseTypeValue    string                                 `xorm:"VARCHAR(32) NOT NULL"`
+	TransferTypeValue   string                                 `xorm:"VARCHAR(32) NOT NULL"`
+	DefaultAccountName  string                                 `xorm:"VARCHAR(32) NOT NULL"`
+	TagSeparator        string                                 `xorm:"VARCHAR(1) NOT NULL"`
+	CreatedUnixTime     int64
+	UpdatedUnixTime     int64
+	DeletedUnixTime     int64
+}
+
+// TransactionImportProfileColumnMapping represents the header column names of imported file which are mapped to transaction fields
+type TransactionImportProfileColumnMapping struct {
+	Time            string `json:"time" binding:"max=64"`
+	Type            string `json:"type" binding:"max=64"`
+	Amount          string `json:"amount" binding:"max=64"`
+	IncomeAmount    string `json:"incomeAmount" binding:"max=64"`
+	Account         string `json:"account" binding:"max=64"`
+	AccountCurrency string `json:"accountCurrency" binding:"max=64"`
+	RelatedAccount  string `json:"relatedAccount" binding:"max=64"`
+	Category        string `json:"category" binding:"max=64"`
+	Comment         string `json:"comment" binding:"max=64"`
+	Tags            string `json:"tags" binding:"max=64"`
+}
+
+// TransactionImportProfileGetRequest represents all parameters of transaction import profile getting request
+type TransactionImportProfileGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionImportProfileCreateRequest represents all parameters of transaction import profile creation request
+type TransactionImportProfileCreateRequest struct {
+	Name                string                                 `json:"name" binding:"required,notBlank,max=32"`
+	FileType            TransactionImportProfileFileType       `json:"fileType" binding:"required,min=1,max=2"`
+	Delimiter           string                                 `json:"delimiter" binding:"max=1"`
+	SkipRows            int32                                  `json:"skipRows" binding:"min=0,max=100"`
+	ColumnMapping       *TransactionImportProfileColumnMapping `json:"columnMapping" binding:"required"`
+	DateTimeFormat      string                                 `json:"dateTimeFormat" binding:"required,notBlank,max=32"`
+	DecimalSeparator    string                                 `json:"decimalSeparator" binding:"required,len=1"`
+	DigitGroupingSymbol string                                 `json:"digitGroupingSymbol" binding:"max=1"`
+	AmountSignType      TransactionImportProfileAmountSignType `json:"amountSignType" binding:"min=0,max=3"`
+	IncomeTypeValue     string                                 `json:"incomeTypeValue" binding:"max=32"`
+	ExpenseTypeValue    string                                 `json:"expenseTypeValue" binding:"max=32"`
+	TransferTypeValue   string                                 `json:"transferTypeValue" binding:"max=32"`
+	DefaultAccountName  string                                 `json:"defaultAccountName" binding:"max=32"`
+	TagSeparator        string                                 `json:"tagSeparator" binding:"max=1"`
+}
+
+// TransactionImportProfileModifyRequest represents all parameters of transaction import profile modification request
+type TransactionImportProfileModifyRequest struct {
+	Id                  int64                                  `json:"id,string" binding:"required,min=1"`
+	Name                string                                 `json:"name" binding:"required,notBlank,max=32"`
+	FileType            TransactionImportProfileFileType       `json:"fileType" binding:"required,min=1,max=2"`
+	Delimiter           string                                 `json:"delimiter" binding:"max=1"`
+	SkipRows            int32                                  `json:"skipRows" binding:"min=0,max=100"`
+	ColumnMapping       *TransactionImportProfileColumnMapping `json:"columnMapping" binding:"required"`
+	DateTimeFormat      string                                 `json:"dateTimeFormat" binding:"required,notBlank,max=32"`
+	DecimalSeparator    string                                 `json:"decimalSeparator" binding:"required,len=1"`
+	DigitGroupingSymbol string                                 `json:"digitGroupingSymbol" binding:"max=1"`
+	AmountSignType      TransactionImportProfileAmountSignType `json:"amountSignType" binding:"min=0,max=3"`
+	IncomeTypeValue     string                                 `json:"incomeTypeValue" binding:"max=32"`
+	ExpenseTypeValue    string                                 `json:"expenseTypeValue" binding:"max=32"`
+	TransferTypeValue   string                                 `json:"transferTypeValue" binding:"max=32"`
+	DefaultAccountName  string                                 `json:"defaultAccountName" binding:"max=32"`
+	TagSeparator        string                                 `json:"tagSeparator" binding:"max=1"`
+}
+
+// TransactionImportProfileDeleteRequest represents all parameters of transaction import profile deleting request
+type TransactionImportProfileDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionImportProfileInfoResponse represents a view-object of transaction import profile
+type TransactionImportProfileInfoResponse struct {
+	Id                  int64                                  `json:"id,string"`
+	Name                string                                 `json:"name"`
+	FileType            TransactionImportProfileFileType       `json:"fileType"`
+	Delimiter           string                                 `json:"delimiter"`
+	SkipRows            int32                                  `json:"skipRows"`
+	ColumnMapping       *TransactionImportProfileColumnMapping `json:"columnMapping"`
+	DateTimeFormat      string                                 `json:"dateTimeFormat"`
+	DecimalSeparator    string                                 `json:"decimalSeparator"`
+	DigitGroupingSymbol string                                 `json:"digitGroupingSymbol"`
+	AmountSignType      TransactionImportProfileAmountSignType `json:"amountSignType"`
+	IncomeTypeValue     string                                 `json:"incomeTypeValue"`
+	ExpenseTypeValue    string                                 `json:"expenseTypeValue"`
+	TransferTypeValue   string                                 `json:"transferTypeValue"`
+	DefaultAccountName  string                                 `json:"defaultAccountName"`
+	TagSeparator        string                                 `json:"tagSeparator"`
+}
+
+// FromDB fills the fields from the data stored in database
+func (m *TransactionImportProfileColumnMapping) FromDB(data []byte) error {
+	return json.Unmarshal(data, m)
+}
+
+// ToDB returns the actual stored data in database
+func (m *TransactionImportProfileColumnMapping) ToDB() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// IsValidColumnMapping returns whether the column mapping contains all the required columns for the amount sign type
+func (p *TransactionImportProfile) IsValidColumnMapping() bool {
+	mapping := p.ColumnMapping
+
+	if mapping == nil || mapping.Time == "" || mapping.Amount == "" {
+		return false
+	}
+
+	if mapping.Account == "" && p.DefaultAccountName == "" {
+		return false
+	}
+
+	if p.AmountSignType == TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_TYPE_COLUMN && mapping.Type == "" {
+		return false
+	}
+
+	if p.AmountSignType == TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_SEPARATE_COLUMNS && mapping.IncomeAmount == "" {
+		return false
+	}
+
+	if p.TransferTypeValue != "" && (mapping.Type == "" || mapping.RelatedAccount == "") {
+		return false
+	}
+
+	return true
+}
+
+// IsValidNumberFormat returns whether the decimal separator and the digit grouping symbol of transaction import profile are valid
+func (p *TransactionImportProfile) IsValidNumberFormat() bool {
+	if p.DecimalSeparator != "." && p.DecimalSeparator != "," {
+		return false
+	}
+
+	if p.DigitGroupingSymbol == p.DecimalSeparator || strings.ContainsAny(p.DigitGroupingSymbol, "0123456789+-") {
+		return false
+	}
+
+	return true
+}
+
+// ToTransactionImportProfileInfoResponse returns a view-object according to database model
+func (p *TransactionImportProfile) ToTransactionImportProfileInfoResponse() *TransactionImportProfileInfoResponse {
+	return &TransactionImportProfileInfoResponse{
+		Id:                  p.ProfileId,
+		Name:                p.Name,
+		FileType:            p.FileType,
+		Delimiter:           p.Delimiter,
+		SkipRows:            p.SkipRows,
+		ColumnMapping:       p.ColumnMapping,
+		DateTimeFormat:      p.DateTimeFormat,
+		DecimalSeparator:    p.DecimalSeparator,
+		DigitGroupingSymbol: p.DigitGroupingSymbol,
+		AmountSignType:      p.AmountSignType,
+		IncomeTypeValue:     p.IncomeTypeValue,
+		ExpenseTypeValue:    p.ExpenseTypeValue,
+		TransferTypeValue:   p.TransferTypeValue,
+		DefaultAccountName:  p.DefaultAccountName,
+		TagSeparator:        p.TagSeparator,
+	}
+}
+
+// TransactionImportProfileInfoResponseSlice represents the slice data structure of TransactionImportProfileInfoResponse
+type TransactionImportProfileInfoResponseSlice []*TransactionImportProfileInfoResponse
+
+// Len returns the count of items
+func (s TransactionImportProfileInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionImportProfileInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionImportProfileInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Name != s[j].Name {
+		return s[i].Name < s[j].Name
+	}
+
+	return s[i].Id < s[j].Id
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionImportProfileIsValidColumnMapping(t *testing.T) {
+	assert.True(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Time: "Date", Amount: "Amount", Account: "Account"}}).IsValidColumnMapping())
+	assert.True(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Time: "Date", Amount: "Amount"}, DefaultAccountName: "Bank"}).IsValidColumnMapping())
+	assert.False(t, (&TransactionImportProfile{}).IsValidColumnMapping())
+	assert.False(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Amount: "Amount", Account: "Account"}}).IsValidColumnMapping())
+	assert.False(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Time: "Date", Amount: "Amount"}}).IsValidColumnMapping())
+
+	assert.True(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Time: "Date", Type: "Type", Amount: "Amount", Account: "Account"}, AmountSignType: TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_TYPE_COLUMN}).IsValidColumnMapping())
+	assert.False(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Time: "Date", Amount: "Amount", Account: "Account"}, AmountSignType: TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_TYPE_COLUMN}).IsValidColumnMapping())
+
+	assert.True(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Time: "Date", Amount: "Debit", IncomeAmount: "Credit", Account: "Account"}, AmountSignType: TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_SEPARATE_COLUMNS}).IsValidColumnMapping())
+	assert.False(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Time: "Date", Amount: "Debit", Account: "Account"}, AmountSignType: TRANSACTION_IMPORT_PROFILE_AMOUNT_SIGN_TYPE_SEPARATE_COLUMNS}).IsValidColumnMapping())
+
+	assert.True(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Time: "Date", Type: "Type", Amount: "Amount", Account: "Account", RelatedAccount: "To"}, TransferTypeValue: "Transfer"}).IsValidColumnMapping())
+	assert.False(t, (&TransactionImportProfile{ColumnMapping: &TransactionImportProfileColumnMapping{Time: "Date", Type: "Type", Amount: "Amount", Account: "Account"}, TransferTypeValue: "Transfer"}).IsValidColumnMapping())
+}
+
+func TestTransactionImportProfileIsValidNumberFormat(t *testing.T) {
+	assert.True(t, (&TransactionImportProfile{DecimalSeparator: "."}).IsValidNumberFormat())
+	assert.True(t, (&TransactionImportProfile{DecimalSeparator: ".", DigitGroupingSymbol: ","}).IsValidNumberFormat())
+	assert.True(t, (&TransactionImportProfile{DecimalSeparator: ",", DigitGroupingSymbol: "."}).IsValidNumberFormat())
+	assert.True(t, (&TransactionImportProfile{DecimalSeparator: ",", DigitGroupingSymbol: " "}).IsValidNumberFormat())
+	assert.False(t, (&TransactionImportProfile{DecimalSeparator: ""}).IsValidNumberFormat())
+	assert.False(t, (&TransactionImportProfile{DecimalSeparator: "-"}).IsValidNumberFormat())
+	assert.False(t, (&TransactionImportProfile{DecimalSeparator: ",", DigitGroupingSymbol: ","}).IsValidNumberFormat())
+	assert.False(t, (&TransactionImportProfile{DecimalSeparator: ".", DigitGroupingSymbol: "0"}).IsValidNumberFormat())
+	assert.False(t, (&TransactionImportProfile{DecimalSeparator: ".", DigitGroupingSymbol: "-"}).IsValidNumberFormat())
+}
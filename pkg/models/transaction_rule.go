@@ -0,0 +1,309 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionRuleKeywordMatchType represents how transaction rule matches the keyword
+type TransactionRuleKeywordMatchType byte
+
+// Transaction rule keyword match types
+const (
+	TRANSACTION_RULE_KEYWORD_MATCH_TYPE_CONTAINS TransactionRuleKeywordMatchType = 0
+	TRANSACTION_RULE_KEYWORD_MATCH_TYPE_REGEX    TransactionRuleKeywordMatchType = 1
+)
+
+// TransactionRule represents transaction rule data stored in database
+type TransactionRule struct {
+	RuleId                  int64                           `xorm:"PK"`
+	Uid                     int64                           `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Deleted                 bool                            `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Name                    string                          `xorm:"VARCHAR(32) NOT NULL"`
+	MatchType               TransactionType                 `xorm:"NOT NULL"`
+	MatchAccountId          int64                           `xorm:"NOT NULL"`
+	MatchKeywordType        TransactionRuleKeywordMatchType `xorm:"NOT NULL"`
+	MatchKeyword            string                          `xorm:"VARCHAR(255) NOT NULL"`
+	MatchAmountRange        bool                            `xorm:"NOT NULL"`
+	MatchMinAmount          int64                           `xorm:"NOT NULL"`
+	MatchMaxAmount          int64                           `xorm:"NOT NULL"`
+	SetCategoryId           int64                           `xorm:"NOT NULL"`
+	AddTagIds               string                          `xorm:"VARCHAR(255) NOT NULL"`
+	SetComment              string                          `xorm:"VARCHAR(255) NOT NULL"`
+	SetDestinationAccountId int64                           `xorm:"NOT NULL"`
+	DisplayOrder            int32                           `xorm:"INDEX(IDX_transaction_rule_uid_deleted_order) NOT NULL"`
+	Hidden                  bool                            `xorm:"NOT NULL"`
+	CreatedUnixTime         int64
+	UpdatedUnixTime         int64
+	DeletedUnixTime         int64
+
+	matchKeywordRegex         *regexp.Regexp
+	matchKeywordRegexCompiled bool
+}
+
+// TransactionRuleGetRequest represents all parameters of transaction rule getting request
+type TransactionRuleGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleCreateRequest represents all parameters of transaction rule creation request
+type TransactionRuleCreateRequest struct {
+	Name                    string                          `json:"name" binding:"required,notBlank,max=32"`
+	MatchType               TransactionType                 `json:"matchType" binding:"min=0,max=4"`
+	MatchAccountId          int64                           `json:"matchAccountId,string" binding:"min=0"`
+	MatchKeywordType        TransactionRuleKeywordMatchType `json:"matchKeywordType" binding:"min=0,max=1"`
+	MatchKeyword            string                          `json:"matchKeyword" binding:"max=255"`
+	MatchAmountRange        bool                            `json:"matchAmountRange"`
+	MatchMinAmount          int64                           `json:"matchMinAmount" binding:"min=-99999999999,max=99999999999"`
+	MatchMaxAmount          int64                           `json:"matchMaxAmount" binding:"min=-99999999999,max=99999999999"`
+	SetCategoryId           int64                           `json:"setCategoryId,string" binding:"min=0"`
+	AddTagIds               []string                        `json:"addTagIds"`
+	SetComment              string                          `json:"setComment" binding:"max=255"`
+	SetDestinationAccountId int64                           `json:"setDestinationAccountId,string" binding:"min=0"`
+	ClientSessionId         string                          `json:"clientSessionId"`
+}
+
+// TransactionRuleModifyRequest represents all parameters of transaction rule modification request
+type TransactionRuleModifyRequest struct {
+	Id                      int64                           `json:"id,string" binding:"required,min=1"`
+	Name                    string                          `json:"name" binding:"required,notBlank,max=32"`
+	MatchType               TransactionType                 `json:"matchType" binding:"min=0,max=4"`
+	MatchAccountId          int64                           `json:"matchAccountId,string" binding:"min=0"`
+	MatchKeywordType        TransactionRuleKeywordMatchType `json:"matchKeywordType" binding:"min=0,max=1"`
+	MatchKeyword            string                          `json:"matchKeyword" binding:"max=255"`
+	MatchAmountRange        bool                            `json:"matchAmountRange"`
+	MatchMinAmount          int64                           `json:"matchMinAmount" binding:"min=-99999999999,max=99999999999"`
+	MatchMaxAmount          int64                           `json:"matchMaxAmount" binding:"min=-99999999999,max=99999999999"`
+	SetCategoryId           int64                           `json:"setCategoryId,string" binding:"min=0"`
+	AddTagIds               []string                        `json:"addTagIds"`
+	SetComment              string                          `json:"setComment" binding:"max=255"`
+	SetDestinationAccountId int64                           `json:"setDestinationAccountId,string" binding:"min=0"`
+}
+
+// TransactionRuleHideRequest represents all parameters of transaction rule hiding request
+type TransactionRuleHideRequest struct {
+	Id     int64 `json:"id,string" binding:"required,min=1"`
+	Hidden bool  `json:"hidden"`
+}
+
+// TransactionRuleMoveRequest represents all parameters of transaction rule moving request
+type TransactionRuleMoveRequest struct {
+	NewDisplayOrders []*TransactionRuleNewDisplayOrderRequest `json:"newDisplayOrders" binding:"required,min=1"`
+}
+
+// TransactionRuleNewDisplayOrderRequest represents a data pair of id and display order
+type TransactionRuleNewDisplayOrderRequest struct {
+	Id           int64 `json:"id,string" binding:"required,min=1"`
+	DisplayOrder int32 `json:"displayOrder"`
+}
+
+// TransactionRuleDeleteRequest represents all parameters of transaction rule deleting request
+type TransactionRuleDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// TransactionRuleDryRunRequest represents all parameters of transaction rule dry run request
+type TransactionRuleDryRunRequest struct {
+	Transactions []*TransactionCreateRequest `json:"transactions" binding:"required,min=1"`
+}
+
+// TransactionRuleInfoResponse represents a view-object of transaction rule
+type TransactionRuleInfoResponse struct {
+	Id                      int64                           `json:"id,string"`
+	Name                    string                          `json:"name"`
+	MatchType               TransactionType                 `json:"matchType"`
+	MatchAccountId          int64                           `json:"matchAccountId,string"`
+	MatchKeywordType        TransactionRuleKeywordMatchType `json:"matchKeywordType"`
+	MatchKeyword            string                          `json:"matchKeyword"`
+	MatchAmountRange        bool                            `json:"matchAmountRange"`
+	MatchMinAmount          int64                           `json:"matchMinAmount"`
+	MatchMaxAmount          int64                           `json:"matchMaxAmount"`
+	SetCategoryId           int64                           `json:"setCategoryId,string"`
+	AddTagIds               []string                        `json:"addTagIds"`
+	SetComment              string                          `json:"setComment"`
+	SetDestinationAccountId int64                           `json:"setDestinationAccountId,string"`
+	DisplayOrder            int32                           `json:"displayOrder"`
+	Hidden                  bool                            `json:"hidden"`
+}
+
+// TransactionRuleDryRunResultResponse represents a view-object of transaction rule dry run result of one transaction
+type TransactionRuleDryRunResultResponse struct {
+	Index                int      `json:"index"`
+	Matched              bool     `json:"matched"`
+	RuleId               int64    `json:"ruleId,string,omitempty"`
+	RuleName             string   `json:"ruleName,omitempty"`
+	CategoryId           int64    `json:"categoryId,string"`
+	DestinationAccountId int64    `json:"destinationAccountId,string,omitempty"`
+	TagIds               []string `json:"tagIds"`
+	Comment              string   `json:"comment"`
+}
+
+// IsValid returns whether the keyword match type is valid
+func (t TransactionRuleKeywordMatchType) IsValid() bool {
+	return t == TRANSACTION_RULE_KEYWORD_MATCH_TYPE_CONTAINS || t == TRANSACTION_RULE_KEYWORD_MATCH_TYPE_REGEX
+}
+
+// GetAddTagIds returns all tag ids which would be added to the matched transaction
+func (r *TransactionRule) GetAddTagIds() []int64 {
+	tagIds := make([]string, 0)
+
+	if r.AddTagIds != "" {
+		tagIds = strings.Split(r.AddTagIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(tagIds)
+
+	return result
+}
+
+// IsKeywordValid returns whether the keyword of the rule can be used for matching
+func (r *TransactionRule) IsKeywordValid() bool {
+	if r.MatchKeywordType != TRANSACTION_RULE_KEYWORD_MATCH_TYPE_REGEX || r.MatchKeyword == "" {
+		return true
+	}
+
+	return r.getMatchKeywordRegex() != nil
+}
+
+// HasAction returns whether the rule would change anything of the matched transaction
+func (r *TransactionRule) HasAction() bool {
+	return r.SetCategoryId > 0 || r.AddTagIds != "" || r.SetComment != "" || r.SetDestinationAccountId > 0
+}
+
+// IsMatch returns whether the transaction creation request matches the rule
+func (r *TransactionRule) IsMatch(transactionCreateReq *TransactionCreateRequest) bool {
+	if r.MatchType != 0 && r.MatchType != transactionCreateReq.Type {
+		return false
+	}
+
+	if r.MatchAccountId != 0 && r.MatchAccountId != transactionCreateReq.SourceAccountId {
+		return false
+	}
+
+	if r.MatchAmountRange && (transactionCreateReq.SourceAmount < r.MatchMinAmount || transactionCreateReq.SourceAmount > r.MatchMaxAmount) {
+		return false
+	}
+
+	if r.MatchKeyword == "" {
+		return true
+	}
+
+	if r.MatchKeywordType == TRANSACTION_RULE_KEYWORD_MATCH_TYPE_REGEX {
+		keywordRegex := r.getMatchKeywordRegex()
+
+		return keywordRegex != nil && keywordRegex.MatchString(transactionCreateReq.Comment)
+	}
+
+	return strings.Contains(strings.ToLower(transactionCreateReq.Comment), strings.ToLower(r.MatchKeyword))
+}
+
+// ApplyTo changes the transaction creation request according to the actions of the rule
+func (r *TransactionRule) ApplyTo(transactionCreateReq *TransactionCreateRequest) {
+	if r.SetCategoryId > 0 && transactionCreateReq.Type != TRANSACTION_TYPE_MODIFY_BALANCE {
+		transactionCreateReq.CategoryId = r.SetCategoryId
+	}
+
+	if r.AddTagIds != "" {
+		addTagIds := utils.Int64ArrayToStringArray(r.GetAddTagIds())
+		existedTagIds := make(map[string]bool, len(transactionCreateReq.TagIds))
+
+		for i := 0; i < len(transactionCreateReq.TagIds); i++ {
+			existedTagIds[transactionCreateReq.TagIds[i]] = true
+		}
+
+		for i := 0; i < len(addTagIds); i++ {
+			if !existedTagIds[addTagIds[i]] {
+				transactionCreateReq.TagIds = append(transactionCreateReq.TagIds, addTagIds[i])
+			}
+		}
+	}
+
+	if r.SetComment != "" {
+		transactionCreateReq.Comment = r.SetComment
+	}
+
+	if r.SetDestinationAccountId > 0 && transactionCreateReq.Type == TRANSACTION_TYPE_TRANSFER {
+		transactionCreateReq.DestinationAccountId = r.SetDestinationAccountId
+	}
+}
+
+// getMatchKeywordRegex returns the compiled regular expression of the keyword, the keyword is only compiled once and nil is returned if the keyword is invalid
+func (r *TransactionRule) getMatchKeywordRegex() *regexp.Regexp {
+	if !r.matchKeywordRegexCompiled {
+		keywordRegex, err := regexp.Compile(r.MatchKeyword)
+
+		if err == nil {
+			r.matchKeywordRegex = keywordRegex
+		}
+
+		r.matchKeywordRegexCompiled = true
+	}
+
+	return r.matchKeywordRegex
+}
+
+// ToTransactionRuleInfoResponse returns a view-object according to database model
+func (r *TransactionRule) ToTransactionRuleInfoResponse() *TransactionRuleInfoResponse {
+	return &TransactionRuleInfoResponse{
+		Id:                      r.RuleId,
+		Name:                    r.Name,
+		MatchType:               r.MatchType,
+		MatchAccountId:          r.MatchAccountId,
+		MatchKeywordType:        r.MatchKeywordType,
+		MatchKeyword:            r.MatchKeyword,
+		MatchAmountRange:        r.MatchAmountRange,
+		MatchMinAmount:          r.MatchMinAmount,
+		MatchMaxAmount:          r.MatchMaxAmount,
+		SetCategoryId:           r.SetCategoryId,
+		AddTagIds:               utils.Int64ArrayToStringArray(r.GetAddTagIds()),
+		SetComment:              r.SetComment,
+		SetDestinationAccountId: r.SetDestinationAccountId,
+		DisplayOrder:            r.DisplayOrder,
+		Hidden:                  r.Hidden,
+	}
+}
+
+// TransactionRuleSlice represents the slice data structure of TransactionRule
+type TransactionRuleSlice []*TransactionRule
+
+// GetMatchedRule returns the first visible rule which matches the transaction creation request
+func (s TransactionRuleSlice) GetMatchedRule(transactionCreateReq *TransactionCreateRequest) *TransactionRule {
+	for i := 0; i < len(s); i++ {
+		if !s[i].Hidden && s[i].IsMatch(transactionCreateReq) {
+			return s[i]
+		}
+	}
+
+	return nil
+}
+
+// ApplyTo changes the transaction creation request according to the first matched rule, and returns the matched rule
+func (s TransactionRuleSlice) ApplyTo(transactionCreateReq *TransactionCreateRequest) *TransactionRule {
+	rule := s.GetMatchedRule(transactionCreateReq)
+
+	if rule != nil {
+		rule.ApplyTo(transactionCreateReq)
+	}
+
+	return rule
+}
+
+// TransactionRuleInfoResponseSlice represents the slice data structure of TransactionRuleInfoResponse
+type TransactionRuleInfoResponseSlice []*TransactionRuleInfoResponse
+
+// Len returns the count of items
+func (s TransactionRuleInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionRuleInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionRuleInfoResponseSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
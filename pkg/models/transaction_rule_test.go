@@ -0,0 +1,145 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionRuleIsMatch_Keyword(t *testing.T) {
+	rule := &TransactionRule{
+		MatchKeywordType: TRANSACTION_RULE_KEYWORD_MATCH_TYPE_CONTAINS,
+		MatchKeyword:     "coffee",
+	}
+
+	assert.True(t, rule.IsMatch(&TransactionCreateRequest{Comment: "Morning Coffee at Store"}))
+	assert.False(t, rule.IsMatch(&TransactionCreateRequest{Comment: "Tea"}))
+
+	rule = &TransactionRule{
+		MatchKeywordType: TRANSACTION_RULE_KEYWORD_MATCH_TYPE_REGEX,
+		MatchKeyword:     "^(Uber|Lyft)\\s",
+	}
+
+	assert.True(t, rule.IsMatch(&TransactionCreateRequest{Comment: "Uber trip"}))
+	assert.True(t, rule.IsMatch(&TransactionCreateRequest{Comment: "Lyft ride"}))
+	assert.False(t, rule.IsMatch(&TransactionCreateRequest{Comment: "My Uber trip"}))
+}
+
+func TestTransactionRuleIsMatch_RegexCompiledOnce(t *testing.T) {
+	rule := &TransactionRule{
+		MatchKeywordType: TRANSACTION_RULE_KEYWORD_MATCH_TYPE_REGEX,
+		MatchKeyword:     "^(Uber|Lyft)\\s",
+	}
+
+	assert.True(t, rule.IsMatch(&TransactionCreateRequest{Comment: "Uber trip"}))
+
+	keywordRegex := rule.matchKeywordRegex
+	assert.NotNil(t, keywordRegex)
+
+	assert.True(t, rule.IsMatch(&TransactionCreateRequest{Comment: "Lyft ride"}))
+	assert.Same(t, keywordRegex, rule.matchKeywordRegex)
+}
+
+func TestTransactionRuleIsMatch_InvalidRegex(t *testing.T) {
+	rule := &TransactionRule{
+		MatchKeywordType: TRANSACTION_RULE_KEYWORD_MATCH_TYPE_REGEX,
+		MatchKeyword:     "(",
+	}
+
+	assert.False(t, rule.IsKeywordValid())
+	assert.False(t, rule.IsMatch(&TransactionCreateRequest{Comment: "("}))
+}
+
+func TestTransactionRuleIsMatch_TypeAccountAndAmount(t *testing.T) {
+	rule := &TransactionRule{
+		MatchType:        TRANSACTION_TYPE_EXPENSE,
+		MatchAccountId:   123,
+		MatchAmountRange: true,
+		MatchMinAmount:   1000,
+		MatchMaxAmount:   5000,
+	}
+
+	assert.True(t, rule.IsMatch(&TransactionCreateRequest{Type: TRANSACTION_TYPE_EXPENSE, SourceAccountId: 123, SourceAmount: 1000}))
+	assert.True(t, rule.IsMatch(&TransactionCreateRequest{Type: TRANSACTION_TYPE_EXPENSE, SourceAccountId: 123, SourceAmount: 5000}))
+	assert.False(t, rule.IsMatch(&TransactionCreateRequest{Type: TRANSACTION_TYPE_EXPENSE, SourceAccountId: 123, SourceAmount: 5001}))
+	assert.False(t, rule.IsMatch(&TransactionCreateRequest{Type: TRANSACTION_TYPE_INCOME, SourceAccountId: 123, SourceAmount: 2000}))
+	assert.False(t, rule.IsMatch(&TransactionCreateRequest{Type: TRANSACTION_TYPE_EXPENSE, SourceAccountId: 456, SourceAmount: 2000}))
+}
+
+func TestTransactionRuleApplyTo(t *testing.T) {
+	rule := &TransactionRule{
+		SetCategoryId:           10,
+		AddTagIds:               "1,2",
+		SetComment:              "Groceries",
+		SetDestinationAccountId: 20,
+	}
+
+	transactionCreateReq := &TransactionCreateRequest{
+		Type:       TRANSACTION_TYPE_EXPENSE,
+		CategoryId: 5,
+		TagIds:     []string{"2", "3"},
+		Comment:    "SUPERMARKET 001",
+	}
+
+	rule.ApplyTo(transactionCreateReq)
+
+	assert.Equal(t, int64(10), transactionCreateReq.CategoryId)
+	assert.Equal(t, []string{"2", "3", "1"}, transactionCreateReq.TagIds)
+	assert.Equal(t, "Groceries", transactionCreateReq.Comment)
+	assert.Equal(t, int64(0), transactionCreateReq.DestinationAccountId)
+
+	transactionCreateReq = &TransactionCreateRequest{
+		Type: TRANSACTION_TYPE_TRANSFER,
+	}
+
+	rule.ApplyTo(transactionCreateReq)
+
+	assert.Equal(t, int64(20), transactionCreateReq.DestinationAccountId)
+}
+
+func TestTransactionRuleSliceApplyTo(t *testing.T) {
+	rules := TransactionRuleSlice{
+		&TransactionRule{RuleId: 1, MatchKeyword: "coffee", SetComment: "Hidden Rule", Hidden: true},
+		&TransactionRule{RuleId: 2, MatchKeyword: "coffee", SetComment: "Coffee"},
+		&TransactionRule{RuleId: 3, MatchKeyword: "coffee", SetComment: "Other Rule"},
+	}
+
+	transactionCreateReq := &TransactionCreateRequest{
+		Comment: "coffee shop",
+	}
+
+	matchedRule := rules.ApplyTo(transactionCreateReq)
+	assert.Equal(t, int64(2), matchedRule.RuleId)
+	assert.Equal(t, "Coffee", transactionCreateReq.Comment)
+
+	transactionCreateReq = &TransactionCreateRequest{
+		Comment: "tea shop",
+	}
+
+	matchedRule = rules.ApplyTo(transactionCreateReq)
+	assert.Nil(t, matchedRule)
+	assert.Equal(t, "tea shop", transactionCreateReq.Comment)
+}
+
+func TestTransactionRuleInfoResponseSliceLess(t *testing.T) {
+	var ruleRespSlice TransactionRuleInfoResponseSlice
+	ruleRespSlice = append(ruleRespSlice, &TransactionRuleInfoResponse{
+		Id:           1,
+		DisplayOrder: 3,
+	})
+	ruleRespSlice = append(ruleRespSlice, &TransactionRuleInfoResponse{
+		Id:           2,
+		DisplayOrder: 1,
+	})
+	ruleRespSlice = append(ruleRespSlice, &TransactionRuleInfoResponse{
+		Id:           3,
+		DisplayOrder: 2,
+	})
+
+	sort.Sort(ruleRespSlice)
+
+	assert.Equal(t, int64(2), ruleRespSlice[0].Id)
+	assert.Equal(t, int64(3), ruleRespSlice[1].Id)
+	assert.Equal(t, int64(1), ruleRespSlice[2].Id)
+}
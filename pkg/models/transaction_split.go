@@ -0,0 +1,125 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// TransactionSplit represents a split line of an income or expense transaction stored in database
+type TransactionSplit struct {
+	SplitId         int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) INDEX(IDX_transaction_split_uid_deleted_time) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) INDEX(IDX_transaction_split_uid_deleted_time) NOT NULL"`
+	TransactionId   int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_transaction_id) NOT NULL"`
+	TransactionTime int64  `xorm:"INDEX(IDX_transaction_split_uid_deleted_time) NOT NULL"`
+	CategoryId      int64  `xorm:"NOT NULL"`
+	Amount          int64  `xorm:"NOT NULL"`
+	TagIds          string `xorm:"VARCHAR(255) NOT NULL"`
+	Comment         string `xorm:"VARCHAR(255) NOT NULL"`
+	DisplayOrder    int32  `xorm:"NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// TransactionSplitRequest represents all parameters of a transaction split line in transaction creation or modification request
+type TransactionSplitRequest struct {
+	CategoryId int64    `json:"categoryId,string" binding:"required,min=1"`
+	Amount     int64    `json:"amount" binding:"min=-99999999999,max=99999999999"`
+	TagIds     []string `json:"tagIds"`
+	Comment    string   `json:"comment" binding:"max=255"`
+}
+
+// TransactionSplitInfoResponse represents a view-object of transaction split line
+type TransactionSplitInfoResponse struct {
+	CategoryId int64    `json:"categoryId,string"`
+	Amount     int64    `json:"amount"`
+	TagIds     []string `json:"tagIds"`
+	Comment    string   `json:"comment"`
+}
+
+// GetTagIds returns all tag ids of the transaction split line
+func (s *TransactionSplit) GetTagIds() []int64 {
+	tagIds := make([]string, 0)
+
+	if s.TagIds != "" {
+		tagIds = strings.Split(s.TagIds, ",")
+	}
+
+	result, _ := utils.StringArrayToInt64Array(tagIds)
+
+	return result
+}
+
+// ToTransactionSplitInfoResponse returns a view-object according to database model
+func (s *TransactionSplit) ToTransactionSplitInfoResponse() *TransactionSplitInfoResponse {
+	return &TransactionSplitInfoResponse{
+		CategoryId: s.CategoryId,
+		Amount:     s.Amount,
+		TagIds:     utils.Int64ArrayToStringArray(s.GetTagIds()),
+		Comment:    s.Comment,
+	}
+}
+
+// TransactionSplitSlice represents the slice data structure of TransactionSplit
+type TransactionSplitSlice []*TransactionSplit
+
+// Len returns the count of items
+func (s TransactionSplitSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s TransactionSplitSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s TransactionSplitSlice) Less(i, j int) bool {
+	return s[i].DisplayOrder < s[j].DisplayOrder
+}
+
+// GetTotalAmount returns the total amount of all split lines
+func (s TransactionSplitSlice) GetTotalAmount() int64 {
+	totalAmount := int64(0)
+
+	for i := 0; i < len(s); i++ {
+		totalAmount += s[i].Amount
+	}
+
+	return totalAmount
+}
+
+// ToTransactionSplitInfoResponses returns the view-objects of all split lines
+func (s TransactionSplitSlice) ToTransactionSplitInfoResponses() []*TransactionSplitInfoResponse {
+	if len(s) < 1 {
+		return nil
+	}
+
+	splitResps := make([]*TransactionSplitInfoResponse, len(s))
+
+	for i := 0; i < len(s); i++ {
+		splitResps[i] = s[i].ToTransactionSplitInfoResponse()
+	}
+
+	return splitResps
+}
+
+// IsEquals returns whether the split lines are the same as the other split lines
+func (s TransactionSplitSlice) IsEquals(other TransactionSplitSlice) bool {
+	if len(s) != len(other) {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i].CategoryId != other[i].CategoryId ||
+			s[i].Amount != other[i].Amount ||
+			s[i].Comment != other[i].Comment ||
+			!utils.Int64SliceEquals(s[i].GetTagIds(), other[i].GetTagIds()) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionSplitGetTagIds(t *testing.T) {
+	split := &TransactionSplit{
+		TagIds: "1,23,456",
+	}
+
+	assert.Equal(t, []int64{1, 23, 456}, split.GetTagIds())
+
+	split.TagIds = ""
+	assert.Equal(t, 0, len(split.GetTagIds()))
+}
+
+func TestTransactionSplitSliceGetTotalAmount(t *testing.T) {
+	splits := TransactionSplitSlice{
+		&TransactionSplit{Amount: 1000},
+		&TransactionSplit{Amount: 2345},
+		&TransactionSplit{Amount: -45},
+	}
+
+	assert.Equal(t, int64(3300), splits.GetTotalAmount())
+}
+
+func TestTransactionSplitSliceToTransactionSplitInfoResponses(t *testing.T) {
+	var emptySplits TransactionSplitSlice
+	assert.Nil(t, emptySplits.ToTransactionSplitInfoResponses())
+
+	splits := TransactionSplitSlice{
+		&TransactionSplit{CategoryId: 1, Amount: 1000, TagIds: "2,3", Comment: "foo"},
+		&TransactionSplit{CategoryId: 4, Amount: 2000},
+	}
+
+	splitResps := splits.ToTransactionSplitInfoResponses()
+	assert.Equal(t, 2, len(splitResps))
+	assert.Equal(t, int64(1), splitResps[0].CategoryId)
+	assert.Equal(t, int64(1000), splitResps[0].Amount)
+	assert.Equal(t, []string{"2", "3"}, splitResps[0].TagIds)
+	assert.Equal(t, "foo", splitResps[0].Comment)
+	assert.Equal(t, int64(4), splitResps[1].CategoryId)
+	assert.Equal(t, 0, len(splitResps[1].TagIds))
+}
+
+func TestTransactionSplitSliceIsEquals(t *testing.T) {
+	splits := TransactionSplitSlice{
+		&TransactionSplit{SplitId: 1, CategoryId: 1, Amount: 1000, TagIds: "2,3"},
+		&TransactionSplit{SplitId: 2, CategoryId: 4, Amount: 2000, Comment: "bar"},
+	}
+	otherSplits := TransactionSplitSlice{
+		&TransactionSplit{SplitId: 3, CategoryId: 1, Amount: 1000, TagIds: "2,3"},
+		&TransactionSplit{SplitId: 4, CategoryId: 4, Amount: 2000, Comment: "bar"},
+	}
+
+	assert.True(t, splits.IsEquals(otherSplits))
+
+	otherSplits[1].Comment = "baz"
+	assert.False(t, splits.IsEquals(otherSplits))
+
+	otherSplits[1].Comment = "bar"
+	otherSplits[0].TagIds = "2"
+	assert.False(t, splits.IsEquals(otherSplits))
+
+	assert.False(t, splits.IsEquals(otherSplits[:1]))
+	assert.True(t, TransactionSplitSlice(nil).IsEquals(TransactionSplitSlice{}))
+}
+
+func TestTransactionSplitSliceLess(t *testing.T) {
+	splits := TransactionSplitSlice{
+		&TransactionSplit{SplitId: 1, DisplayOrder: 3},
+		&TransactionSplit{SplitId: 2, DisplayOrder: 1},
+		&TransactionSplit{SplitId: 3, DisplayOrder: 2},
+	}
+
+	sort.Sort(splits)
+
+	assert.Equal(t, int64(2), splits[0].SplitId)
+	assert.Equal(t, int64(3), splits[1].SplitId)
+	assert.Equal(t, int64(1), splits[2].SplitId)
+}
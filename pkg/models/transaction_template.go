@@ -2,6 +2,7 @@ package models
 
 import (
 	"strings"
+	"time"
 
 	"github.com/mayswind/ezbookkeeping/pkg/utils"
 )
@@ -23,14 +24,28 @@ const (
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED TransactionScheduleFrequencyType = 0
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY   TransactionScheduleFrequencyType = 1
 	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY  TransactionScheduleFrequencyType = 2
+	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY    TransactionScheduleFrequencyType = 3
+	TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY   TransactionScheduleFrequencyType = 4
 )
 
+// TRANSACTION_SCHEDULE_FREQUENCY_LAST_DAY_OF_MONTH represents the last day of month in monthly schedule frequency values
+const TRANSACTION_SCHEDULE_FREQUENCY_LAST_DAY_OF_MONTH = -1
+
+// TRANSACTION_SCHEDULE_MAX_INTERVAL represents the maximum interval of scheduled transaction
+const TRANSACTION_SCHEDULE_MAX_INTERVAL = 366
+
+// TRANSACTION_SCHEDULE_NO_NEXT_TIME represents the scheduled template would not create any transaction in future
+const TRANSACTION_SCHEDULE_NO_NEXT_TIME = -1
+
+// maxScheduledNextTimeSearchDays represents the maximum days to search for the next scheduled transaction time
+const maxScheduledNextTimeSearchDays = 732
+
 // TransactionTemplate represents transaction template stored in database
 type TransactionTemplate struct {
 	TemplateId                 int64                            `xorm:"PK"`
 	Uid                        int64                            `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) NOT NULL"`
-	Deleted                    bool                             `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_at) NOT NULL"`
-	TemplateType               TransactionTemplateType          `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_at) NOT NULL"`
+	Deleted                    bool                             `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_at) INDEX(IDX_transaction_template_deleted_type_scheduled_next_time) NOT NULL"`
+	TemplateType               TransactionTemplateType          `xorm:"INDEX(IDX_transaction_template_uid_deleted_template_type_order) INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_at) INDEX(IDX_transaction_template_deleted_type_scheduled_next_time) NOT NULL"`
 	Name                       string                           `xorm:"VARCHAR(32) NOT NULL"`
 	Type                       TransactionType                  `xorm:"NOT NULL"`
 	CategoryId                 int64                            `xorm:"NOT NULL"`
@@ -39,6 +54,13 @@ type TransactionTemplate struct {
 	ScheduledFrequency         string                           `xorm:"VARCHAR(100)"`
 	ScheduledAt                int16                            `xorm:"INDEX(IDX_transaction_template_deleted_type_freqtype_scheduled_at)"`
 	ScheduledTimezoneUtcOffset int16
+	ScheduledInterval          int16
+	ScheduledStartTime         int64
+	ScheduledEndTime           int64
+	ScheduledMaxCount          int32
+	ScheduledCreatedCount      int32
+	ScheduledLastCreatedTime   int64  `xorm:"NOT NULL DEFAULT 0"`
+	ScheduledNextTime          int64  `xorm:"INDEX(IDX_transaction_template_deleted_type_scheduled_next_time) NOT NULL DEFAULT 0"`
 	TagIds                     string `xorm:"VARCHAR(255) NOT NULL"`
 	Amount                     int64  `xorm:"NOT NULL"`
 	RelatedAccountId           int64  `xorm:"NOT NULL"`
@@ -78,6 +100,10 @@ type TransactionTemplateCreateRequest struct {
 	ScheduledFrequencyType     *TransactionScheduleFrequencyType `json:"scheduledFrequencyType" binding:"omitempty"`
 	ScheduledFrequency         *string                           `json:"scheduledFrequency" binding:"omitempty"`
 	ScheduledTimezoneUtcOffset *int16                            `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
+	ScheduledInterval          int16                             `json:"scheduledInterval" binding:"min=0,max=366"`
+	ScheduledStartTime         int64                             `json:"scheduledStartTime" binding:"min=0"`
+	ScheduledEndTime           int64                             `json:"scheduledEndTime" binding:"min=0"`
+	ScheduledMaxCount          int32                             `json:"scheduledMaxCount" binding:"min=0"`
 	ClientSessionId            string                            `json:"clientSessionId"`
 }
 
@@ -103,6 +129,10 @@ type TransactionTemplateModifyRequest struct {
 	ScheduledFrequencyType     *TransactionScheduleFrequencyType `json:"scheduledFrequencyType" binding:"omitempty"`
 	ScheduledFrequency         *string                           `json:"scheduledFrequency" binding:"omitempty"`
 	ScheduledTimezoneUtcOffset *int16                            `json:"utcOffset" binding:"omitempty,min=-720,max=840"`
+	ScheduledInterval          int16                             `json:"scheduledInterval" binding:"min=0,max=366"`
+	ScheduledStartTime         int64                             `json:"scheduledStartTime" binding:"min=0"`
+	ScheduledEndTime           int64                             `json:"scheduledEndTime" binding:"min=0"`
+	ScheduledMaxCount          int32                             `json:"scheduledMaxCount" binding:"min=0"`
 }
 
 // TransactionTemplateHideRequest represents all parameters of transaction template hiding request
@@ -134,6 +164,11 @@ type TransactionTemplateInfoResponse struct {
 	ScheduledFrequencyType *TransactionScheduleFrequencyType `json:"scheduledFrequencyType,omitempty"`
 	ScheduledFrequency     *string                           `json:"scheduledFrequency,omitempty"`
 	ScheduledAt            *int16                            `json:"scheduledAt,omitempty"`
+	ScheduledInterval      *int16                            `json:"scheduledInterval,omitempty"`
+	ScheduledStartTime     *int64                            `json:"scheduledStartTime,omitempty"`
+	ScheduledEndTime       *int64                            `json:"scheduledEndTime,omitempty"`
+	ScheduledMaxCount      *int32                            `json:"scheduledMaxCount,omitempty"`
+	ScheduledCreatedCount  *int32                            `json:"scheduledCreatedCount,omitempty"`
 	DisplayOrder           int32                             `json:"displayOrder"`
 	Hidden                 bool                              `json:"hidden"`
 }
@@ -151,6 +186,233 @@ func (t *TransactionTemplate) GetTagIds() []int64 {
 	return result
 }
 
+// IsValidFrequency returns whether the schedule frequency values are valid for the schedule frequency type
+func (f TransactionScheduleFrequencyType) IsValidFrequency(frequency string) bool {
+	if f == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED || f == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY {
+		return frequency == ""
+	}
+
+	if f != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY && f != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY && f != TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY {
+		return false
+	}
+
+	if frequency == "" {
+		return false
+	}
+
+	values, err := utils.StringArrayToInt64Array(strings.Split(frequency, ","))
+
+	if err != nil {
+		return false
+	}
+
+	for i := 0; i < len(values); i++ {
+		value := values[i]
+
+		if f == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY && (value < 0 || value > 6) {
+			return false
+		} else if f == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY && value != TRANSACTION_SCHEDULE_FREQUENCY_LAST_DAY_OF_MONTH && (value < 1 || value > 31) {
+			return false
+		} else if f == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY && (value/100 < 1 || value/100 > 12 || value%100 < 1 || value%100 > 31) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetScheduledInterval returns the count of periods between two scheduled transactions
+func (t *TransactionTemplate) GetScheduledInterval() int {
+	if t.ScheduledInterval < 1 {
+		return 1
+	}
+
+	return int(t.ScheduledInterval)
+}
+
+// IsScheduledTimeInRange returns whether the specified unix time is between the start time and the end time of the schedule
+func (t *TransactionTemplate) IsScheduledTimeInRange(unixTime int64) bool {
+	if t.ScheduledStartTime > 0 && unixTime < t.ScheduledStartTime {
+		return false
+	}
+
+	if t.ScheduledEndTime > 0 && unixTime > t.ScheduledEndTime {
+		return false
+	}
+
+	return true
+}
+
+// IsScheduledCountReached returns whether the scheduled template has created the maximum count of transactions
+func (t *TransactionTemplate) IsScheduledCountReached() bool {
+	return t.ScheduledMaxCount > 0 && t.ScheduledCreatedCount >= t.ScheduledMaxCount
+}
+
+// GetScheduledOccurrenceTimes returns the unix times of all scheduled transactions which are after the start time and before the end time
+func (t *TransactionTemplate) GetScheduledOccurrenceTimes(startUnixTime int64, endUnixTime int64) []int64 {
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED || startUnixTime >= endUnixTime {
+		return nil
+	}
+
+	var occurrenceUnixTimes []int64
+	templateTimeZone := time.FixedZone("Template Timezone", int(t.ScheduledTimezoneUtcOffset)*60)
+	startTime := time.Unix(startUnixTime, 0).In(time.UTC)
+	startDayFirstTime := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, time.UTC)
+
+	for occurrenceTime := startDayFirstTime.Add(time.Duration(t.ScheduledAt) * time.Minute); occurrenceTime.Unix() < endUnixTime; occurrenceTime = occurrenceTime.AddDate(0, 0, 1) {
+		occurrenceUnixTime := occurrenceTime.Unix()
+
+		if occurrenceUnixTime <= startUnixTime || !t.IsScheduledTimeInRange(occurrenceUnixTime) {
+			continue
+		}
+
+		if t.IsScheduledAtDate(occurrenceTime.In(templateTimeZone)) {
+			occurrenceUnixTimes = append(occurrenceUnixTimes, occurrenceUnixTime)
+		}
+	}
+
+	return occurrenceUnixTimes
+}
+
+// GetScheduledNextTime returns the unix time of the first scheduled transaction after the specified time,
+// returns the time which should be checked again if there is no scheduled transaction in the search range,
+// or returns TRANSACTION_SCHEDULE_NO_NEXT_TIME if the template would not create any transaction in future
+func (t *TransactionTemplate) GetScheduledNextTime(afterUnixTime int64) int64 {
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED || !t.ScheduledFrequencyType.IsValidFrequency(t.ScheduledFrequency) || t.IsScheduledCountReached() {
+		return TRANSACTION_SCHEDULE_NO_NEXT_TIME
+	}
+
+	if t.ScheduledEndTime > 0 && afterUnixTime >= t.ScheduledEndTime {
+		return TRANSACTION_SCHEDULE_NO_NEXT_TIME
+	}
+
+	templateTimeZone := time.FixedZone("Template Timezone", int(t.ScheduledTimezoneUtcOffset)*60)
+	afterTime := time.Unix(afterUnixTime, 0).In(time.UTC)
+	occurrenceTime := time.Date(afterTime.Year(), afterTime.Month(), afterTime.Day(), 0, 0, 0, 0, time.UTC).Add(time.Duration(t.ScheduledAt) * time.Minute)
+
+	for i := 0; i < maxScheduledNextTimeSearchDays; i, occurrenceTime = i+1, occurrenceTime.AddDate(0, 0, 1) {
+		occurrenceUnixTime := occurrenceTime.Unix()
+
+		if occurrenceUnixTime <= afterUnixTime {
+			continue
+		}
+
+		if t.ScheduledEndTime > 0 && occurrenceUnixTime > t.ScheduledEndTime {
+			return TRANSACTION_SCHEDULE_NO_NEXT_TIME
+		}
+
+		if t.IsScheduledTimeInRange(occurrenceUnixTime) && t.IsScheduledAtDate(occurrenceTime.In(templateTimeZone)) {
+			return occurrenceUnixTime
+		}
+	}
+
+	return occurrenceTime.Unix()
+}
+
+// IsScheduledAtDate returns whether the scheduled template should create transaction on the date of specified time (which should be in the template timezone)
+func (t *TransactionTemplate) IsScheduledAtDate(transactionTime time.Time) bool {
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED || !t.ScheduledFrequencyType.IsValidFrequency(t.ScheduledFrequency) {
+		return false
+	}
+
+	frequencyValueSet := make(map[int64]bool)
+
+	if t.ScheduledFrequency != "" {
+		frequencyValues, _ := utils.StringArrayToInt64Array(strings.Split(t.ScheduledFrequency, ","))
+		frequencyValueSet = utils.ToSet(frequencyValues)
+	}
+
+	if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY && !frequencyValueSet[int64(transactionTime.Weekday())] {
+		return false
+	} else if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY && !frequencyValueSet[int64(transactionTime.Day())] {
+		isLastDayOfMonth := transactionTime.AddDate(0, 0, 1).Month() != transactionTime.Month()
+
+		if !isLastDayOfMonth || !frequencyValueSet[TRANSACTION_SCHEDULE_FREQUENCY_LAST_DAY_OF_MONTH] {
+			return false
+		}
+	} else if t.ScheduledFrequencyType == TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY && !frequencyValueSet[int64(transactionTime.Month())*100+int64(transactionTime.Day())] {
+		return false
+	}
+
+	interval := t.GetScheduledInterval()
+
+	if interval <= 1 {
+		return true
+	}
+
+	anchorUnixTime := t.ScheduledStartTime
+
+	if anchorUnixTime <= 0 {
+		anchorUnixTime = t.CreatedUnixTime
+	}
+
+	anchorTime := time.Unix(anchorUnixTime, 0).In(transactionTime.Location())
+	anchorDate := time.Date(anchorTime.Year(), anchorTime.Month(), anchorTime.Day(), 0, 0, 0, 0, time.UTC)
+	transactionDate := time.Date(transactionTime.Year(), transactionTime.Month(), transactionTime.Day(), 0, 0, 0, 0, time.UTC)
+	periods := 0
+
+	switch t.ScheduledFrequencyType {
+	case TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY:
+		periods = int(transactionDate.Sub(anchorDate).Hours() / 24)
+	case TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY:
+		anchorWeekFirstDate := anchorDate.AddDate(0, 0, -int(anchorDate.Weekday()))
+		transactionWeekFirstDate := transactionDate.AddDate(0, 0, -int(transactionDate.Weekday()))
+		periods = int(transactionWeekFirstDate.Sub(anchorWeekFirstDate).Hours() / 24 / 7)
+	case TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY:
+		periods = (transactionDate.Year()*12 + int(transactionDate.Month())) - (anchorDate.Year()*12 + int(anchorDate.Month()))
+	case TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY:
+		periods = transactionDate.Year() - anchorDate.Year()
+	}
+
+	return periods >= 0 && periods%interval == 0
+}
+
+// GetAccountBalanceChanges returns the balance changes of the accounts when the scheduled transaction is created
+func (t *TransactionTemplate) GetAccountBalanceChanges() map[int64]int64 {
+	changes := make(map[int64]int64, 2)
+
+	if t.Type == TRANSACTION_TYPE_EXPENSE {
+		changes[t.AccountId] -= t.Amount
+	} else if t.Type == TRANSACTION_TYPE_INCOME {
+		changes[t.AccountId] += t.Amount
+	} else if t.Type == TRANSACTION_TYPE_TRANSFER {
+		changes[t.AccountId] -= t.Amount
+		changes[t.RelatedAccountId] += t.RelatedAccountAmount
+	}
+
+	return changes
+}
+
+// ToTransactionForecastTransactionResponse returns a view-object of the upcoming scheduled transaction at specified time
+func (t *TransactionTemplate) ToTransactionForecastTransactionResponse(unixTime int64) *TransactionForecastTransactionResponse {
+	tagIds := make([]string, 0)
+
+	if t.TagIds != "" {
+		tagIds = strings.Split(t.TagIds, ",")
+	}
+
+	response := &TransactionForecastTransactionResponse{
+		TemplateId:      t.TemplateId,
+		TemplateName:    t.Name,
+		Type:            t.Type,
+		CategoryId:      t.CategoryId,
+		Time:            unixTime,
+		UtcOffset:       t.ScheduledTimezoneUtcOffset,
+		SourceAccountId: t.AccountId,
+		SourceAmount:    t.Amount,
+		HideAmount:      t.HideAmount,
+		TagIds:          tagIds,
+		Comment:         t.Comment,
+	}
+
+	if t.Type == TRANSACTION_TYPE_TRANSFER {
+		response.DestinationAccountId = t.RelatedAccountId
+		response.DestinationAmount = t.RelatedAccountAmount
+	}
+
+	return response
+}
+
 // ToTransactionTemplateInfoResponse returns a view-object according to database model
 func (t *TransactionTemplate) ToTransactionTemplateInfoResponse(serverUtcOffset int16) *TransactionTemplateInfoResponse {
 	utcOffset := serverUtcOffset
@@ -171,6 +433,11 @@ func (t *TransactionTemplate) ToTransactionTemplateInfoResponse(serverUtcOffset
 		response.ScheduledFrequencyType = &t.ScheduledFrequencyType
 		response.ScheduledFrequency = &t.ScheduledFrequency
 		response.ScheduledAt = &t.ScheduledAt
+		response.ScheduledInterval = &t.ScheduledInterval
+		response.ScheduledStartTime = &t.ScheduledStartTime
+		response.ScheduledEndTime = &t.ScheduledEndTime
+		response.ScheduledMaxCount = &t.ScheduledMaxCount
+		response.ScheduledCreatedCount = &t.ScheduledCreatedCount
 	}
 
 	return response
@@ -3,6 +3,7 @@ package models
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -16,6 +17,193 @@ func TestTransactionTemplateGetTagIds(t *testing.T) {
 	assert.EqualValues(t, expectedValue, template.GetTagIds())
 }
 
+func TestTransactionScheduleFrequencyTypeIsValidFrequency(t *testing.T) {
+	assert.True(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED.IsValidFrequency(""))
+	assert.False(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED.IsValidFrequency("1"))
+	assert.True(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY.IsValidFrequency(""))
+	assert.False(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY.IsValidFrequency("1"))
+	assert.True(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY.IsValidFrequency("0,6"))
+	assert.False(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY.IsValidFrequency("7"))
+	assert.False(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY.IsValidFrequency(""))
+	assert.True(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY.IsValidFrequency("-1,1,31"))
+	assert.False(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY.IsValidFrequency("0"))
+	assert.False(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY.IsValidFrequency("32"))
+	assert.True(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY.IsValidFrequency("101,1231"))
+	assert.False(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY.IsValidFrequency("1301"))
+	assert.False(t, TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY.IsValidFrequency("100"))
+	assert.False(t, TransactionScheduleFrequencyType(99).IsValidFrequency("1"))
+}
+
+func TestTransactionTemplateIsScheduledAtDate_Daily(t *testing.T) {
+	timezone := time.UTC
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY,
+		ScheduledInterval:      3,
+		ScheduledStartTime:     time.Date(2024, 9, 1, 0, 0, 0, 0, timezone).Unix(),
+	}
+
+	assert.True(t, template.IsScheduledAtDate(time.Date(2024, 9, 1, 8, 0, 0, 0, timezone)))
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 9, 2, 8, 0, 0, 0, timezone)))
+	assert.True(t, template.IsScheduledAtDate(time.Date(2024, 9, 4, 8, 0, 0, 0, timezone)))
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 8, 29, 8, 0, 0, 0, timezone)))
+}
+
+func TestTransactionTemplateIsScheduledAtDate_EveryTwoWeeks(t *testing.T) {
+	timezone := time.FixedZone("Test Timezone", 8*60*60)
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY,
+		ScheduledFrequency:     "5",
+		ScheduledInterval:      2,
+		ScheduledStartTime:     time.Date(2024, 9, 2, 0, 0, 0, 0, timezone).Unix(), // Monday
+	}
+
+	assert.True(t, template.IsScheduledAtDate(time.Date(2024, 9, 6, 0, 0, 0, 0, timezone)))   // Friday
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 9, 13, 0, 0, 0, 0, timezone))) // Friday of next week
+	assert.True(t, template.IsScheduledAtDate(time.Date(2024, 9, 20, 0, 0, 0, 0, timezone)))
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 9, 19, 0, 0, 0, 0, timezone))) // Thursday
+}
+
+func TestTransactionTemplateIsScheduledAtDate_Monthly(t *testing.T) {
+	timezone := time.UTC
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:     "15",
+		ScheduledInterval:      3,
+		CreatedUnixTime:        time.Date(2024, 1, 10, 0, 0, 0, 0, timezone).Unix(),
+	}
+
+	assert.True(t, template.IsScheduledAtDate(time.Date(2024, 1, 15, 0, 0, 0, 0, timezone)))
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 2, 15, 0, 0, 0, 0, timezone)))
+	assert.True(t, template.IsScheduledAtDate(time.Date(2024, 4, 15, 0, 0, 0, 0, timezone)))
+	assert.True(t, template.IsScheduledAtDate(time.Date(2025, 1, 15, 0, 0, 0, 0, timezone)))
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 4, 16, 0, 0, 0, 0, timezone)))
+}
+
+func TestTransactionTemplateIsScheduledAtDate_LastDayOfMonth(t *testing.T) {
+	timezone := time.UTC
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY,
+		ScheduledFrequency:     "-1",
+	}
+
+	assert.True(t, template.IsScheduledAtDate(time.Date(2024, 2, 29, 0, 0, 0, 0, timezone)))
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 2, 28, 0, 0, 0, 0, timezone)))
+	assert.True(t, template.IsScheduledAtDate(time.Date(2023, 2, 28, 0, 0, 0, 0, timezone)))
+	assert.True(t, template.IsScheduledAtDate(time.Date(2024, 4, 30, 0, 0, 0, 0, timezone)))
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 5, 30, 0, 0, 0, 0, timezone)))
+}
+
+func TestTransactionTemplateIsScheduledAtDate_Yearly(t *testing.T) {
+	timezone := time.UTC
+	template := &TransactionTemplate{
+		ScheduledFrequencyType: TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY,
+		ScheduledFrequency:     "315",
+	}
+
+	assert.True(t, template.IsScheduledAtDate(time.Date(2024, 3, 15, 0, 0, 0, 0, timezone)))
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 3, 16, 0, 0, 0, 0, timezone)))
+	assert.False(t, template.IsScheduledAtDate(time.Date(2024, 4, 15, 0, 0, 0, 0, timezone)))
+}
+
+func TestTransactionTemplateGetScheduledOccurrenceTimes(t *testing.T) {
+	timezone := time.FixedZone("Test Timezone", 8*60*60)
+	template := &TransactionTemplate{
+		ScheduledFrequencyType:     TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY,
+		ScheduledFrequency:         "1,3",
+		ScheduledAt:                60, // 09:00 in UTC+8
+		ScheduledTimezoneUtcOffset: 480,
+	}
+
+	startUnixTime := time.Date(2024, 9, 2, 9, 0, 0, 0, timezone).Unix() // Monday
+	endUnixTime := time.Date(2024, 9, 16, 9, 0, 0, 0, timezone).Unix()  // Monday after two weeks
+
+	actualTimes := template.GetScheduledOccurrenceTimes(startUnixTime, endUnixTime)
+	assert.Equal(t, []int64{
+		time.Date(2024, 9, 4, 9, 0, 0, 0, timezone).Unix(),
+		time.Date(2024, 9, 9, 9, 0, 0, 0, timezone).Unix(),
+		time.Date(2024, 9, 11, 9, 0, 0, 0, timezone).Unix(),
+	}, actualTimes)
+
+	actualTimes = template.GetScheduledOccurrenceTimes(startUnixTime-1, endUnixTime+1)
+	assert.Equal(t, 5, len(actualTimes))
+
+	template.ScheduledEndTime = time.Date(2024, 9, 10, 0, 0, 0, 0, timezone).Unix()
+	actualTimes = template.GetScheduledOccurrenceTimes(startUnixTime, endUnixTime)
+	assert.Equal(t, 2, len(actualTimes))
+
+	assert.Nil(t, template.GetScheduledOccurrenceTimes(endUnixTime, startUnixTime))
+}
+
+func TestTransactionTemplateGetScheduledNextTime(t *testing.T) {
+	timezone := time.FixedZone("Test Timezone", 8*60*60)
+	template := &TransactionTemplate{
+		ScheduledFrequencyType:     TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY,
+		ScheduledFrequency:         "1,3",
+		ScheduledAt:                60, // 09:00 in UTC+8
+		ScheduledTimezoneUtcOffset: 480,
+	}
+
+	mondayUnixTime := time.Date(2024, 9, 2, 9, 0, 0, 0, timezone).Unix()
+	assert.Equal(t, time.Date(2024, 9, 4, 9, 0, 0, 0, timezone).Unix(), template.GetScheduledNextTime(mondayUnixTime))
+	assert.Equal(t, mondayUnixTime, template.GetScheduledNextTime(mondayUnixTime-1))
+
+	template.ScheduledEndTime = time.Date(2024, 9, 4, 0, 0, 0, 0, timezone).Unix()
+	assert.Equal(t, int64(TRANSACTION_SCHEDULE_NO_NEXT_TIME), template.GetScheduledNextTime(mondayUnixTime))
+
+	template.ScheduledEndTime = 0
+	template.ScheduledMaxCount = 1
+	template.ScheduledCreatedCount = 1
+	assert.Equal(t, int64(TRANSACTION_SCHEDULE_NO_NEXT_TIME), template.GetScheduledNextTime(mondayUnixTime))
+
+	template.ScheduledFrequencyType = TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED
+	assert.Equal(t, int64(TRANSACTION_SCHEDULE_NO_NEXT_TIME), template.GetScheduledNextTime(mondayUnixTime))
+}
+
+func TestTransactionTemplateGetScheduledNextTime_OutOfSearchRange(t *testing.T) {
+	timezone := time.FixedZone("Test Timezone", 8*60*60)
+	template := &TransactionTemplate{
+		ScheduledFrequencyType:     TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY,
+		ScheduledFrequency:         "101",
+		ScheduledAt:                60, // 09:00 in UTC+8
+		ScheduledTimezoneUtcOffset: 480,
+		ScheduledInterval:          10,
+		ScheduledStartTime:         time.Date(2024, 1, 1, 0, 0, 0, 0, timezone).Unix(),
+	}
+
+	// the next one is in 2034, so the time which should be checked again would be returned
+	nextUnixTime := template.GetScheduledNextTime(time.Date(2024, 1, 1, 9, 0, 0, 0, timezone).Unix())
+	assert.True(t, nextUnixTime > time.Date(2025, 12, 31, 0, 0, 0, 0, timezone).Unix())
+	assert.True(t, nextUnixTime < time.Date(2034, 1, 1, 9, 0, 0, 0, timezone).Unix())
+	assert.Equal(t, 0, len(template.GetScheduledOccurrenceTimes(time.Date(2024, 1, 1, 9, 0, 0, 0, timezone).Unix(), nextUnixTime+1)))
+}
+
+func TestTransactionTemplateIsScheduledTimeInRange(t *testing.T) {
+	template := &TransactionTemplate{}
+	assert.True(t, template.IsScheduledTimeInRange(1000))
+
+	template.ScheduledStartTime = 2000
+	assert.False(t, template.IsScheduledTimeInRange(1000))
+	assert.True(t, template.IsScheduledTimeInRange(2000))
+
+	template.ScheduledEndTime = 3000
+	assert.True(t, template.IsScheduledTimeInRange(3000))
+	assert.False(t, template.IsScheduledTimeInRange(3001))
+}
+
+func TestTransactionTemplateIsScheduledCountReached(t *testing.T) {
+	template := &TransactionTemplate{
+		ScheduledCreatedCount: 100,
+	}
+	assert.False(t, template.IsScheduledCountReached())
+
+	template.ScheduledMaxCount = 12
+	template.ScheduledCreatedCount = 11
+	assert.False(t, template.IsScheduledCountReached())
+
+	template.ScheduledCreatedCount = 12
+	assert.True(t, template.IsScheduledCountReached())
+}
+
 func TestTransactionTemplateInfoResponseSliceLess(t *testing.T) {
 	var transactionTemplateRespSlice TransactionTemplateInfoResponseSlice
 	transactionTemplateRespSlice = append(transactionTemplateRespSlice, &TransactionTemplateInfoResponse{
@@ -0,0 +1,314 @@
+package models
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE represents the data source name of user custom exchange rates
+const USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE = "UserCustom"
+
+// UserCustomExchangeRateDateFormat represents the date format of valid from date of user custom exchange rate
+const UserCustomExchangeRateDateFormat = "2006-01-02"
+
+// UserCustomExchangeRate represents user custom exchange rate data stored in database
+type UserCustomExchangeRate struct {
+	RateId          int64  `xorm:"PK"`
+	Uid             int64  `xorm:"INDEX(IDX_user_custom_exchange_rate_uid_deleted_valid_from_date) NOT NULL"`
+	Deleted         bool   `xorm:"INDEX(IDX_user_custom_exchange_rate_uid_deleted_valid_from_date) NOT NULL"`
+	Currency        string `xorm:"VARCHAR(3) NOT NULL"`
+	BaseCurrency    string `xorm:"VARCHAR(3) NOT NULL"`
+	Rate            string `xorm:"VARCHAR(32) NOT NULL"`
+	ValidFromDate   string `xorm:"INDEX(IDX_user_custom_exchange_rate_uid_deleted_valid_from_date) VARCHAR(10) NOT NULL"`
+	CreatedUnixTime int64
+	UpdatedUnixTime int64
+	DeletedUnixTime int64
+}
+
+// UserCustomExchangeRateGetRequest represents all parameters of user custom exchange rate getting request
+type UserCustomExchangeRateGetRequest struct {
+	Id int64 `form:"id,string" binding:"required,min=1"`
+}
+
+// UserCustomExchangeRateCreateRequest represents all parameters of user custom exchange rate creation request
+type UserCustomExchangeRateCreateRequest struct {
+	Currency      string `json:"currency" binding:"required,len=3,validCurrency"`
+	BaseCurrency  string `json:"baseCurrency" binding:"required,len=3,validCurrency"`
+	Rate          string `json:"rate" binding:"required,max=32"`
+	ValidFromDate string `json:"validFromDate" binding:"required,len=10"`
+}
+
+// UserCustomExchangeRateModifyRequest represents all parameters of user custom exchange rate modification request
+type UserCustomExchangeRateModifyRequest struct {
+	Id            int64  `json:"id,string" binding:"required,min=1"`
+	Currency      string `json:"currency" binding:"required,len=3,validCurrency"`
+	BaseCurrency  string `json:"baseCurrency" binding:"required,len=3,validCurrency"`
+	Rate          string `json:"rate" binding:"required,max=32"`
+	ValidFromDate string `json:"validFromDate" binding:"required,len=10"`
+}
+
+// UserCustomExchangeRateDeleteRequest represents all parameters of user custom exchange rate deleting request
+type UserCustomExchangeRateDeleteRequest struct {
+	Id int64 `json:"id,string" binding:"required,min=1"`
+}
+
+// UserCustomExchangeRateInfoResponse represents a view-object of user custom exchange rate
+type UserCustomExchangeRateInfoResponse struct {
+	Id            int64  `json:"id,string"`
+	Currency      string `json:"currency"`
+	BaseCurrency  string `json:"baseCurrency"`
+	Rate          string `json:"rate"`
+	ValidFromDate string `json:"validFromDate"`
+}
+
+// IsValidRate returns whether the rate of user custom exchange rate is a positive number
+func (r *UserCustomExchangeRate) IsValidRate() bool {
+	rate, err := utils.StringToFloat64(r.Rate)
+	return err == nil && rate > 0
+}
+
+// IsValidValidFromDate returns whether the valid from date of user custom exchange rate is a valid date
+func (r *UserCustomExchangeRate) IsValidValidFromDate() bool {
+	_, err := time.Parse(UserCustomExchangeRateDateFormat, r.ValidFromDate)
+	return err == nil
+}
+
+// ToUserCustomExchangeRateInfoResponse returns a view-object according to database model
+func (r *UserCustomExchangeRate) ToUserCustomExchangeRateInfoResponse() *UserCustomExchangeRateInfoResponse {
+	return &UserCustomExchangeRateInfoResponse{
+		Id:            r.RateId,
+		Currency:      r.Currency,
+		BaseCurrency:  r.BaseCurrency,
+		Rate:          r.Rate,
+		ValidFromDate: r.ValidFromDate,
+	}
+}
+
+// GetEffectiveUserCustomExchangeRates returns the latest user custom exchange rate of each currency which is valid at the specified date
+func GetEffectiveUserCustomExchangeRates(customExchangeRates []*UserCustomExchangeRate, date string) []*UserCustomExchangeRate {
+	effectiveExchangeRates := make([]*UserCustomExchangeRate, 0, len(customExchangeRates))
+	effectiveExchangeRatesMap := make(map[string]int, len(customExchangeRates))
+
+	for i := 0; i < len(customExchangeRates); i++ {
+		customExchangeRate := customExchangeRates[i]
+
+		if customExchangeRate.ValidFromDate > date {
+			continue
+		}
+
+		index, exists := effectiveExchangeRatesMap[customExchangeRate.Currency]
+
+		if !exists {
+			effectiveExchangeRatesMap[customExchangeRate.Currency] = len(effectiveExchangeRates)
+			effectiveExchangeRates = append(effectiveExchangeRates, customExchangeRate)
+			continue
+		}
+
+		existedExchangeRate := effectiveExchangeRates[index]
+
+		if customExchangeRate.ValidFromDate > existedExchangeRate.ValidFromDate ||
+			(customExchangeRate.ValidFromDate == existedExchangeRate.ValidFromDate && customExchangeRate.UpdatedUnixTime > existedExchangeRate.UpdatedUnixTime) {
+			effectiveExchangeRates[index] = customExchangeRate
+		}
+	}
+
+	return effectiveExchangeRates
+}
+
+// MergeUserCustomExchangeRates returns a new latest exchange rate response which the specified user custom exchange rates are merged on top of
+func (r *LatestExchangeRateResponse) MergeUserCustomExchangeRates(customExchangeRates []*UserCustomExchangeRate) *LatestExchangeRateResponse {
+	baseCurrency := r.BaseCurrency
+
+	if baseCurrency == "" && len(customExchangeRates) > 0 {
+		baseCurrency = customExchangeRates[0].BaseCurrency
+	}
+
+	exchangeRatesMap := make(map[string]*LatestExchangeRate, len(r.ExchangeRates)+len(customExchangeRates))
+	ratesMap := make(map[string]float64, len(r.ExchangeRates)+len(customExchangeRates))
+
+	for i := 0; i < len(r.ExchangeRates); i++ {
+		exchangeRate := r.ExchangeRates[i]
+		rate, err := utils.StringToFloat64(exchangeRate.Rate)
+
+		if err != nil || rate <= 0 {
+			continue
+		}
+
+		exchangeRatesMap[exchangeRate.Currency] = exchangeRate
+		ratesMap[exchangeRate.Currency] = rate
+	}
+
+	if _, exists := ratesMap[baseCurrency]; !exists && baseCurrency != "" {
+		exchangeRatesMap[baseCurrency] = &LatestExchangeRate{
+			Currency: baseCurrency,
+			Rate:     "1",
+		}
+		ratesMap[baseCurrency] = 1
+	}
+
+	pendingExchangeRates := make([]*UserCustomExchangeRate, 0, len(customExchangeRates))
+
+	for i := 0; i < len(customExchangeRates); i++ {
+		if customExchangeRates[i].IsValidRate() && customExchangeRates[i].Currency != customExchangeRates[i].BaseCurrency {
+			pendingExchangeRates = append(pendingExchangeRates, customExchangeRates[i])
+		}
+	}
+
+	ignorePendingBaseCurrency := false
+
+	for len(pendingExchangeRates) > 0 {
+		pendingCurrencies := make(map[string]bool, len(pendingExchangeRates))
+		remainingExchangeRates := make([]*UserCustomExchangeRate, 0, len(pendingExchangeRates))
+
+		for i := 0; i < len(pendingExchangeRates); i++ {
+			pendingCurrencies[pendingExchangeRates[i].Currency] = true
+		}
+
+		for i := 0; i < len(pendingExchangeRates); i++ {
+			customExchangeRate := pendingExchangeRates[i]
+			customRate, _ := utils.StringToFloat64(customExchangeRate.Rate)
+
+			// the custom exchange rate of base currency is applied reversely to the currency which it is against
+			if customExchangeRate.Currency == baseCurrency {
+				ratesMap[customExchangeRate.BaseCurrency] = 1 / customRate
+				exchangeRatesMap[customExchangeRate.BaseCurrency] = &LatestExchangeRate{
+					Currency:   customExchangeRate.BaseCurrency,
+					Rate:       utils.Float64ToString(1 / customRate),
+					DataSource: USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE,
+				}
+				continue
+			}
+
+			baseRate, exists := ratesMap[customExchangeRate.BaseCurrency]
+
+			// the custom exchange rate would be applied after the custom exchange rate of the currency which it is against
+			if !exists || (pendingCurrencies[customExchangeRate.BaseCurrency] && !ignorePendingBaseCurrency) {
+				remainingExchangeRates = append(remainingExchangeRates, customExchangeRate)
+				continue
+			}
+
+			ratesMap[customExchangeRate.Currency] = baseRate * customRate
+			exchangeRatesMap[customExchangeRate.Currency] = &LatestExchangeRate{
+				Currency:   customExchangeRate.Currency,
+				Rate:       utils.Float64ToString(baseRate * customRate),
+				DataSource: USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE,
+			}
+		}
+
+		if len(remainingExchangeRates) == len(pendingExchangeRates) {
+			if ignorePendingBaseCurrency {
+				break
+			}
+
+			ignorePendingBaseCurrency = true
+		}
+
+		pendingExchangeRates = remainingExchangeRates
+	}
+
+	finalExchangeRates := make(LatestExchangeRateSlice, 0, len(exchangeRatesMap))
+
+	for _, exchangeRate := range exchangeRatesMap {
+		finalExchangeRates = append(finalExchangeRates, exchangeRate)
+	}
+
+	sort.Sort(finalExchangeRates)
+
+	return &LatestExchangeRateResponse{
+		DataSource:    r.DataSource,
+		ReferenceUrl:  r.ReferenceUrl,
+		UpdateTime:    r.UpdateTime,
+		BaseCurrency:  baseCurrency,
+		ExchangeRates: finalExchangeRates,
+		CacheAge:      r.CacheAge,
+		IsStale:       r.IsStale,
+	}
+}
+
+// GetExchangeRate returns the exchange rate from one currency to another currency and the data source of this exchange rate
+func (r *LatestExchangeRateResponse) GetExchangeRate(fromCurrency string, toCurrency string) (float64, string, bool) {
+	var fromExchangeRate, toExchangeRate *LatestExchangeRate
+
+	for i := 0; i < len(r.ExchangeRates); i++ {
+		if r.ExchangeRates[i].Currency == fromCurrency {
+			fromExchangeRate = r.ExchangeRates[i]
+		}
+
+		if r.ExchangeRates[i].Currency == toCurrency {
+			toExchangeRate = r.ExchangeRates[i]
+		}
+	}
+
+	if fromExchangeRate == nil || toExchangeRate == nil {
+		return 0, "", false
+	}
+
+	fromRate, err := utils.StringToFloat64(fromExchangeRate.Rate)
+
+	if err != nil || fromRate <= 0 {
+		return 0, "", false
+	}
+
+	toRate, err := utils.StringToFloat64(toExchangeRate.Rate)
+
+	if err != nil || toRate <= 0 {
+		return 0, "", false
+	}
+
+	dataSource := r.getExchangeRateDataSource(fromExchangeRate, toExchangeRate)
+
+	return toRate / fromRate, dataSource, true
+}
+
+// getExchangeRateDataSource returns the data sources of all the specified exchange rates, the exchange rate of base currency is always 1 so its data source is ignored
+func (r *LatestExchangeRateResponse) getExchangeRateDataSource(exchangeRates ...*LatestExchangeRate) string {
+	dataSources := make([]string, 0, len(exchangeRates))
+
+	for i := 0; i < len(exchangeRates); i++ {
+		if exchangeRates[i].Currency == r.BaseCurrency {
+			continue
+		}
+
+		dataSource := exchangeRates[i].DataSource
+
+		if dataSource == "" {
+			dataSource = r.DataSource
+		}
+
+		if dataSource != "" && !slices.Contains(dataSources, dataSource) {
+			dataSources = append(dataSources, dataSource)
+		}
+	}
+
+	if len(dataSources) < 1 {
+		return r.DataSource
+	}
+
+	return strings.Join(dataSources, ", ")
+}
+
+// UserCustomExchangeRateInfoResponseSlice represents the slice data structure of UserCustomExchangeRateInfoResponse
+type UserCustomExchangeRateInfoResponseSlice []*UserCustomExchangeRateInfoResponse
+
+// Len returns the count of items
+func (s UserCustomExchangeRateInfoResponseSlice) Len() int {
+	return len(s)
+}
+
+// Swap swaps two items
+func (s UserCustomExchangeRateInfoResponseSlice) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+// Less reports whether the first item is less than the second one
+func (s UserCustomExchangeRateInfoResponseSlice) Less(i, j int) bool {
+	if s[i].Currency != s[j].Currency {
+		return strings.Compare(s[i].Currency, s[j].Currency) < 0
+	}
+
+	return strings.Compare(s[i].ValidFromDate, s[j].ValidFromDate) > 0
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserCustomExchangeRateIsValidRate(t *testing.T) {
+	assert.True(t, (&UserCustomExchangeRate{Rate: "1.5"}).IsValidRate())
+	assert.False(t, (&UserCustomExchangeRate{Rate: "0"}).IsValidRate())
+	assert.False(t, (&UserCustomExchangeRate{Rate: "-1"}).IsValidRate())
+	assert.False(t, (&UserCustomExchangeRate{Rate: "abc"}).IsValidRate())
+}
+
+func TestUserCustomExchangeRateIsValidValidFromDate(t *testing.T) {
+	assert.True(t, (&UserCustomExchangeRate{ValidFromDate: "2024-02-29"}).IsValidValidFromDate())
+	assert.False(t, (&UserCustomExchangeRate{ValidFromDate: "2023-02-29"}).IsValidValidFromDate())
+	assert.False(t, (&UserCustomExchangeRate{ValidFromDate: "2024/01/01"}).IsValidValidFromDate())
+}
+
+func TestGetEffectiveUserCustomExchangeRates(t *testing.T) {
+	customExchangeRates := []*UserCustomExchangeRate{
+		{RateId: 1, Currency: "XAF", ValidFromDate: "2024-01-01"},
+		{RateId: 2, Currency: "XAF", ValidFromDate: "2024-03-01"},
+		{RateId: 3, Currency: "XAF", ValidFromDate: "2024-06-01"},
+		{RateId: 4, Currency: "CUP", ValidFromDate: "2024-02-01", UpdatedUnixTime: 1},
+		{RateId: 5, Currency: "CUP", ValidFromDate: "2024-02-01", UpdatedUnixTime: 2},
+	}
+
+	effectiveExchangeRates := GetEffectiveUserCustomExchangeRates(customExchangeRates, "2024-04-01")
+	assert.Equal(t, 2, len(effectiveExchangeRates))
+	assert.Equal(t, int64(2), effectiveExchangeRates[0].RateId)
+	assert.Equal(t, int64(5), effectiveExchangeRates[1].RateId)
+
+	effectiveExchangeRates = GetEffectiveUserCustomExchangeRates(customExchangeRates, "2024-01-15")
+	assert.Equal(t, 1, len(effectiveExchangeRates))
+	assert.Equal(t, int64(1), effectiveExchangeRates[0].RateId)
+
+	effectiveExchangeRates = GetEffectiveUserCustomExchangeRates(customExchangeRates, "2023-12-31")
+	assert.Equal(t, 0, len(effectiveExchangeRates))
+}
+
+func TestLatestExchangeRateResponseMergeUserCustomExchangeRates(t *testing.T) {
+	exchangeRateResponse := &LatestExchangeRateResponse{
+		DataSource:   "Test",
+		BaseCurrency: "USD",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "0.5", DataSource: "Test"},
+			{Currency: "GBP", Rate: "0.25", DataSource: "Test"},
+			{Currency: "USD", Rate: "1", DataSource: "Test"},
+		},
+	}
+
+	mergedResponse := exchangeRateResponse.MergeUserCustomExchangeRates([]*UserCustomExchangeRate{
+		{Currency: "XAF", BaseCurrency: "EUR", Rate: "600"},
+		{Currency: "GBP", BaseCurrency: "USD", Rate: "0.75"},
+		{Currency: "CUP", BaseCurrency: "XAF", Rate: "0.1"},
+	})
+
+	assert.Equal(t, "USD", mergedResponse.BaseCurrency)
+	assert.Equal(t, 5, len(mergedResponse.ExchangeRates))
+
+	assert.Equal(t, "CUP", mergedResponse.ExchangeRates[0].Currency)
+	assert.Equal(t, "30", mergedResponse.ExchangeRates[0].Rate)
+	assert.Equal(t, USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE, mergedResponse.ExchangeRates[0].DataSource)
+
+	assert.Equal(t, "EUR", mergedResponse.ExchangeRates[1].Currency)
+	assert.Equal(t, "0.5", mergedResponse.ExchangeRates[1].Rate)
+	assert.Equal(t, "Test", mergedResponse.ExchangeRates[1].DataSource)
+
+	assert.Equal(t, "GBP", mergedResponse.ExchangeRates[2].Currency)
+	assert.Equal(t, "0.75", mergedResponse.ExchangeRates[2].Rate)
+	assert.Equal(t, USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE, mergedResponse.ExchangeRates[2].DataSource)
+
+	assert.Equal(t, "USD", mergedResponse.ExchangeRates[3].Currency)
+	assert.Equal(t, "1", mergedResponse.ExchangeRates[3].Rate)
+
+	assert.Equal(t, "XAF", mergedResponse.ExchangeRates[4].Currency)
+	assert.Equal(t, "300", mergedResponse.ExchangeRates[4].Rate)
+	assert.Equal(t, USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE, mergedResponse.ExchangeRates[4].DataSource)
+
+	assert.Equal(t, "0.25", exchangeRateResponse.ExchangeRates[1].Rate)
+}
+
+func TestLatestExchangeRateResponseMergeUserCustomExchangeRates_BaseCurrency(t *testing.T) {
+	exchangeRateResponse := &LatestExchangeRateResponse{
+		BaseCurrency: "USD",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "0.5"},
+			{Currency: "USD", Rate: "1"},
+		},
+	}
+
+	mergedResponse := exchangeRateResponse.MergeUserCustomExchangeRates([]*UserCustomExchangeRate{
+		{Currency: "USD", BaseCurrency: "EUR", Rate: "4"},
+	})
+
+	assert.Equal(t, 2, len(mergedResponse.ExchangeRates))
+	assert.Equal(t, "EUR", mergedResponse.ExchangeRates[0].Currency)
+	assert.Equal(t, "0.25", mergedResponse.ExchangeRates[0].Rate)
+	assert.Equal(t, USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE, mergedResponse.ExchangeRates[0].DataSource)
+}
+
+func TestLatestExchangeRateResponseMergeUserCustomExchangeRates_NoDataSourceExchangeRates(t *testing.T) {
+	exchangeRateResponse := &LatestExchangeRateResponse{}
+
+	mergedResponse := exchangeRateResponse.MergeUserCustomExchangeRates([]*UserCustomExchangeRate{
+		{Currency: "XAF", BaseCurrency: "EUR", Rate: "600"},
+		{Currency: "CUP", BaseCurrency: "USD", Rate: "24"},
+	})
+
+	assert.Equal(t, "EUR", mergedResponse.BaseCurrency)
+	assert.Equal(t, 2, len(mergedResponse.ExchangeRates))
+	assert.Equal(t, "EUR", mergedResponse.ExchangeRates[0].Currency)
+	assert.Equal(t, "1", mergedResponse.ExchangeRates[0].Rate)
+	assert.Equal(t, "XAF", mergedResponse.ExchangeRates[1].Currency)
+	assert.Equal(t, "600", mergedResponse.ExchangeRates[1].Rate)
+}
+
+func TestLatestExchangeRateResponseGetExchangeRate(t *testing.T) {
+	exchangeRateResponse := &LatestExchangeRateResponse{
+		DataSource:   "Test",
+		BaseCurrency: "USD",
+		ExchangeRates: LatestExchangeRateSlice{
+			{Currency: "EUR", Rate: "0.5", DataSource: "Test"},
+			{Currency: "USD", Rate: "1", DataSource: "Test"},
+			{Currency: "XAF", Rate: "300", DataSource: USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE},
+		},
+	}
+
+	rate, dataSource, ok := exchangeRateResponse.GetExchangeRate("USD", "EUR")
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, rate)
+	assert.Equal(t, "Test", dataSource)
+
+	rate, dataSource, ok = exchangeRateResponse.GetExchangeRate("EUR", "XAF")
+	assert.True(t, ok)
+	assert.Equal(t, float64(600), rate)
+	assert.Equal(t, "Test, "+USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE, dataSource)
+
+	rate, dataSource, ok = exchangeRateResponse.GetExchangeRate("XAF", "EUR")
+	assert.True(t, ok)
+	assert.Equal(t, 0.5/300, rate)
+	assert.Equal(t, USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE+", Test", dataSource)
+
+	rate, dataSource, ok = exchangeRateResponse.GetExchangeRate("XAF", "USD")
+	assert.True(t, ok)
+	assert.Equal(t, USER_CUSTOM_EXCHANGE_RATE_DATA_SOURCE, dataSource)
+
+	_, _, ok = exchangeRateResponse.GetExchangeRate("USD", "CUP")
+	assert.False(t, ok)
+}
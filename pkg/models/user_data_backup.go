@@ -0,0 +1,394 @@
+package models
+
+import (
+	"strings"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+// UserDataBackupCurrentVersion represents the current version of user data backup archive
+const UserDataBackupCurrentVersion = 2
+
+// UserDataBackupRawModelVersion represents the version of user data backup archive which stores the raw data models in data file
+const UserDataBackupRawModelVersion = 1
+
+// UserDataBackupDataFileName represents the file name of user data in user data backup archive
+const UserDataBackupDataFileName = "data.json"
+
+// UserDataBackupPictureDirectory represents the directory of transaction pictures in user data backup archive
+const UserDataBackupPictureDirectory = "pictures/"
+
+// UserDataBackup represents all data of a user in user data backup, the data file of version 1 archive is the json of this structure
+type UserDataBackup struct {
+	Version             int32                          `json:"version"`
+	ExportedUnixTime    int64                          `json:"exportedUnixTime"`
+	Preferences         *UserDataBackupUserPreferences `json:"preferences"`
+	Accounts            []*Account                     `json:"accounts"`
+	Categories          []*TransactionCategory         `json:"categories"`
+	Tags                []*TransactionTag              `json:"tags"`
+	Transactions        []*Transaction                 `json:"transactions"`
+	TagIndexes          []*TransactionTagIndex         `json:"tagIndexes"`
+	Splits              []*TransactionSplit            `json:"splits"`
+	Pictures            []*TransactionPictureInfo      `json:"pictures"`
+	Templates           []*TransactionTemplate         `json:"templates"`
+	Rules               []*TransactionRule             `json:"rules"`
+	Budgets             []*Budget                      `json:"budgets"`
+	CustomExchangeRates []*UserCustomExchangeRate      `json:"customExchangeRates"`
+	ImportProfiles      []*TransactionImportProfile    `json:"importProfiles"`
+	ImportBatches       []*TransactionImportBatch      `json:"importBatches"`
+}
+
+// UserDataBackupUserPreferences represents the user preferences in user data backup archive
+type UserDataBackupUserPreferences struct {
+	DefaultAccountId     int64                    `json:"defaultAccountId,string"`
+	TransactionEditScope TransactionEditScope     `json:"transactionEditScope"`
+	Language             string                   `json:"language"`
+	DefaultCurrency      string                   `json:"defaultCurrency"`
+	FirstDayOfWeek       core.WeekDay             `json:"firstDayOfWeek"`
+	LongDateFormat       core.LongDateFormat      `json:"longDateFormat"`
+	ShortDateFormat      core.ShortDateFormat     `json:"shortDateFormat"`
+	LongTimeFormat       core.LongTimeFormat      `json:"longTimeFormat"`
+	ShortTimeFormat      core.ShortTimeFormat     `json:"shortTimeFormat"`
+	DecimalSeparator     core.DecimalSeparator    `json:"decimalSeparator"`
+	DigitGroupingSymbol  core.DigitGroupingSymbol `json:"digitGroupingSymbol"`
+	DigitGrouping        core.DigitGroupingType   `json:"digitGrouping"`
+	CurrencyDisplayType  core.CurrencyDisplayType `json:"currencyDisplayType"`
+	ExpenseAmountColor   AmountColorType          `json:"expenseAmountColor"`
+	IncomeAmountColor    AmountColorType          `json:"incomeAmountColor"`
+}
+
+// UserDataBackupIdMappings represents the mappings from original ids in user data backup archive to new ids
+type UserDataBackupIdMappings struct {
+	Accounts            map[int64]int64
+	Categories          map[int64]int64
+	Tags                map[int64]int64
+	Transactions        map[int64]int64
+	TagIndexes          map[int64]int64
+	Splits              map[int64]int64
+	Pictures            map[int64]int64
+	Templates           map[int64]int64
+	Rules               map[int64]int64
+	Budgets             map[int64]int64
+	CustomExchangeRates map[int64]int64
+	ImportProfiles      map[int64]int64
+	ImportBatches       map[int64]int64
+}
+
+// UserDataBackupRestoreResponse represents a view-object of user data backup restore result
+type UserDataBackupRestoreResponse struct {
+	AccountCount            int `json:"accountCount"`
+	CategoryCount           int `json:"categoryCount"`
+	TagCount                int `json:"tagCount"`
+	TransactionCount        int `json:"transactionCount"`
+	PictureCount            int `json:"pictureCount"`
+	TemplateCount           int `json:"templateCount"`
+	RuleCount               int `json:"ruleCount"`
+	BudgetCount             int `json:"budgetCount"`
+	CustomExchangeRateCount int `json:"customExchangeRateCount"`
+	ImportProfileCount      int `json:"importProfileCount"`
+	ImportBatchCount        int `json:"importBatchCount"`
+}
+
+// NewUserDataBackupUserPreferences returns the user preferences in user data backup archive according to user model
+func NewUserDataBackupUserPreferences(user *User) *UserDataBackupUserPreferences {
+	return &UserDataBackupUserPreferences{
+		DefaultAccountId:     user.DefaultAccountId,
+		TransactionEditScope: user.TransactionEditScope,
+		Language:             user.Language,
+		DefaultCurrency:      user.DefaultCurrency,
+		FirstDayOfWeek:       user.FirstDayOfWeek,
+		LongDateFormat:       user.LongDateFormat,
+		ShortDateFormat:      user.ShortDateFormat,
+		LongTimeFormat:       user.LongTimeFormat,
+		ShortTimeFormat:      user.ShortTimeFormat,
+		DecimalSeparator:     user.DecimalSeparator,
+		DigitGroupingSymbol:  user.DigitGroupingSymbol,
+		DigitGrouping:        user.DigitGrouping,
+		CurrencyDisplayType:  user.CurrencyDisplayType,
+		ExpenseAmountColor:   user.ExpenseAmountColor,
+		IncomeAmountColor:    user.IncomeAmountColor,
+	}
+}
+
+// ApplyToUser sets the user preferences in user data backup archive to the user model
+func (p *UserDataBackupUserPreferences) ApplyToUser(user *User) {
+	user.DefaultAccountId = p.DefaultAccountId
+	user.TransactionEditScope = p.TransactionEditScope
+	user.Language = p.Language
+	user.DefaultCurrency = p.DefaultCurrency
+	user.FirstDayOfWeek = p.FirstDayOfWeek
+	user.LongDateFormat = p.LongDateFormat
+	user.ShortDateFormat = p.ShortDateFormat
+	user.LongTimeFormat = p.LongTimeFormat
+	user.ShortTimeFormat = p.ShortTimeFormat
+	user.DecimalSeparator = p.DecimalSeparator
+	user.DigitGroupingSymbol = p.DigitGroupingSymbol
+	user.DigitGrouping = p.DigitGrouping
+	user.CurrencyDisplayType = p.CurrencyDisplayType
+	user.ExpenseAmountColor = p.ExpenseAmountColor
+	user.IncomeAmountColor = p.IncomeAmountColor
+}
+
+// ApplyIdMappings replaces all ids and references in user data backup with the new ids and sets the owner to the specified user
+func (b *UserDataBackup) ApplyIdMappings(uid int64, mappings *UserDataBackupIdMappings) error {
+	var err error
+
+	if b.Preferences != nil {
+		if b.Preferences.DefaultAccountId, err = getMappedUserDataBackupId(mappings.Accounts, b.Preferences.DefaultAccountId, false); err != nil {
+			b.Preferences.DefaultAccountId = 0
+		}
+	}
+
+	for i := 0; i < len(b.Accounts); i++ {
+		account := b.Accounts[i]
+		account.Uid = uid
+
+		if account.AccountId, err = getMappedUserDataBackupId(mappings.Accounts, account.AccountId, true); err != nil {
+			return err
+		}
+
+		if account.ParentAccountId, err = getMappedUserDataBackupId(mappings.Accounts, account.ParentAccountId, false); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(b.Categories); i++ {
+		category := b.Categories[i]
+		category.Uid = uid
+
+		if category.CategoryId, err = getMappedUserDataBackupId(mappings.Categories, category.CategoryId, true); err != nil {
+			return err
+		}
+
+		if category.ParentCategoryId, err = getMappedUserDataBackupId(mappings.Categories, category.ParentCategoryId, false); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(b.Tags); i++ {
+		tag := b.Tags[i]
+		tag.Uid = uid
+
+		if tag.TagId, err = getMappedUserDataBackupId(mappings.Tags, tag.TagId, true); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(b.Transactions); i++ {
+		transaction := b.Transactions[i]
+		transaction.Uid = uid
+
+		if transaction.TransactionId, err = getMappedUserDataBackupId(mappings.Transactions, transaction.TransactionId, true); err != nil {
+			return err
+		}
+
+		if transaction.RelatedId, err = getMappedUserDataBackupId(mappings.Transactions, transaction.RelatedId, false); err != nil {
+			return err
+		}
+
+		if transaction.AccountId, err = getMappedUserDataBackupId(mappings.Accounts, transaction.AccountId, true); err != nil {
+			return err
+		}
+
+		if transaction.RelatedAccountId, err = getMappedUserDataBackupId(mappings.Accounts, transaction.RelatedAccountId, false); err != nil {
+			return err
+		}
+
+		if transaction.CategoryId, err = getMappedUserDataBackupId(mappings.Categories, transaction.CategoryId, false); err != nil {
+			return err
+		}
+
+		if transaction.ImportBatchId, err = getMappedUserDataBackupId(mappings.ImportBatches, transaction.ImportBatchId, false); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(b.TagIndexes); i++ {
+		tagIndex := b.TagIndexes[i]
+		tagIndex.Uid = uid
+
+		if tagIndex.TagIndexId, err = getMappedUserDataBackupId(mappings.TagIndexes, tagIndex.TagIndexId, true); err != nil {
+			return err
+		}
+
+		if tagIndex.TagId, err = getMappedUserDataBackupId(mappings.Tags, tagIndex.TagId, true); err != nil {
+			return err
+		}
+
+		if tagIndex.TransactionId, err = getMappedUserDataBackupId(mappings.Transactions, tagIndex.TransactionId, true); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(b.Splits); i++ {
+		split := b.Splits[i]
+		split.Uid = uid
+
+		if split.SplitId, err = getMappedUserDataBackupId(mappings.Splits, split.SplitId, true); err != nil {
+			return err
+		}
+
+		if split.TransactionId, err = getMappedUserDataBackupId(mappings.Transactions, split.TransactionId, true); err != nil {
+			return err
+		}
+
+		if split.CategoryId, err = getMappedUserDataBackupId(mappings.Categories, split.CategoryId, true); err != nil {
+			return err
+		}
+
+		split.TagIds = getMappedUserDataBackupIds(mappings.Tags, split.GetTagIds())
+	}
+
+	for i := 0; i < len(b.Pictures); i++ {
+		picture := b.Pictures[i]
+		picture.Uid = uid
+
+		if picture.PictureId, err = getMappedUserDataBackupId(mappings.Pictures, picture.PictureId, true); err != nil {
+			return err
+		}
+
+		if picture.TransactionId, err = getMappedUserDataBackupId(mappings.Transactions, picture.TransactionId, false); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(b.Templates); i++ {
+		template := b.Templates[i]
+		template.Uid = uid
+
+		if template.TemplateId, err = getMappedUserDataBackupId(mappings.Templates, template.TemplateId, true); err != nil {
+			return err
+		}
+
+		if template.AccountId, err = getMappedUserDataBackupId(mappings.Accounts, template.AccountId, false); err != nil {
+			return err
+		}
+
+		if template.RelatedAccountId, err = getMappedUserDataBackupId(mappings.Accounts, template.RelatedAccountId, false); err != nil {
+			return err
+		}
+
+		if template.CategoryId, err = getMappedUserDataBackupId(mappings.Categories, template.CategoryId, false); err != nil {
+			return err
+		}
+
+		template.TagIds = getMappedUserDataBackupIds(mappings.Tags, template.GetTagIds())
+	}
+
+	for i := 0; i < len(b.Rules); i++ {
+		rule := b.Rules[i]
+		rule.Uid = uid
+
+		if rule.RuleId, err = getMappedUserDataBackupId(mappings.Rules, rule.RuleId, true); err != nil {
+			return err
+		}
+
+		if rule.MatchAccountId, err = getMappedUserDataBackupId(mappings.Accounts, rule.MatchAccountId, false); err != nil {
+			return err
+		}
+
+		if rule.SetCategoryId, err = getMappedUserDataBackupId(mappings.Categories, rule.SetCategoryId, false); err != nil {
+			return err
+		}
+
+		if rule.SetDestinationAccountId, err = getMappedUserDataBackupId(mappings.Accounts, rule.SetDestinationAccountId, false); err != nil {
+			return err
+		}
+
+		rule.AddTagIds = getMappedUserDataBackupIds(mappings.Tags, rule.GetAddTagIds())
+	}
+
+	for i := 0; i < len(b.Budgets); i++ {
+		budget := b.Budgets[i]
+		budget.Uid = uid
+
+		if budget.BudgetId, err = getMappedUserDataBackupId(mappings.Budgets, budget.BudgetId, true); err != nil {
+			return err
+		}
+
+		if budget.CategoryId, err = getMappedUserDataBackupId(mappings.Categories, budget.CategoryId, false); err != nil {
+			return err
+		}
+
+		if budget.AccountId, err = getMappedUserDataBackupId(mappings.Accounts, budget.AccountId, false); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(b.CustomExchangeRates); i++ {
+		customExchangeRate := b.CustomExchangeRates[i]
+		customExchangeRate.Uid = uid
+
+		if customExchangeRate.RateId, err = getMappedUserDataBackupId(mappings.CustomExchangeRates, customExchangeRate.RateId, true); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(b.ImportProfiles); i++ {
+		importProfile := b.ImportProfiles[i]
+		importProfile.Uid = uid
+
+		if importProfile.ProfileId, err = getMappedUserDataBackupId(mappings.ImportProfiles, importProfile.ProfileId, true); err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(b.ImportBatches); i++ {
+		importBatch := b.ImportBatches[i]
+		importBatch.Uid = uid
+
+		if importBatch.BatchId, err = getMappedUserDataBackupId(mappings.ImportBatches, importBatch.BatchId, true); err != nil {
+			return err
+		}
+
+		importBatch.CreatedAccountIds = getMappedUserDataBackupIds(mappings.Accounts, importBatch.GetCreatedAccountIds())
+		importBatch.CreatedCategoryIds = getMappedUserDataBackupIds(mappings.Categories, importBatch.GetCreatedCategoryIds())
+	}
+
+	return nil
+}
+
+// ToUserDataBackupRestoreResponse returns a view-object according to user data backup
+func (b *UserDataBackup) ToUserDataBackupRestoreResponse() *UserDataBackupRestoreResponse {
+	return &UserDataBackupRestoreResponse{
+		AccountCount:            len(b.Accounts),
+		CategoryCount:           len(b.Categories),
+		TagCount:                len(b.Tags),
+		TransactionCount:        len(b.Transactions),
+		PictureCount:            len(b.Pictures),
+		TemplateCount:           len(b.Templates),
+		RuleCount:               len(b.Rules),
+		BudgetCount:             len(b.Budgets),
+		CustomExchangeRateCount: len(b.CustomExchangeRates),
+		ImportProfileCount:      len(b.ImportProfiles),
+		ImportBatchCount:        len(b.ImportBatches),
+	}
+}
+
+func getMappedUserDataBackupId(mapping map[int64]int64, originalId int64, required bool) (int64, error) {
+	if originalId == 0 && !required {
+		return 0, nil
+	}
+
+	newId, exists := mapping[originalId]
+
+	if !exists {
+		return 0, errs.ErrUserDataBackupReferenceInvalid
+	}
+
+	return newId, nil
+}
+
+func getMappedUserDataBackupIds(mapping map[int64]int64, originalIds []int64) string {
+	newIds := make([]int64, 0, len(originalIds))
+
+	for i := 0; i < len(originalIds); i++ {
+		if newId, exists := mapping[originalIds[i]]; exists {
+			newIds = append(newIds, newId)
+		}
+	}
+
+	return strings.Join(utils.Int64ArrayToStringArray(newIds), ",")
+}
@@ -0,0 +1,824 @@
+package models
+
+import "github.com/mayswind/ezbookkeeping/pkg/errs"
+
+// UserDataBackupArchiveData represents the data file (version 2 and later) in user data backup archive
+type UserDataBackupArchiveData struct {
+	Version             int32                                 `json:"version"`
+	ExportedUnixTime    int64                                 `json:"exportedUnixTime"`
+	Preferences         *UserDataBackupUserPreferences        `json:"preferences"`
+	Accounts            []*UserDataBackupArchiveAccount       `json:"accounts"`
+	Categories          []*UserDataBackupArchiveCategory      `json:"categories"`
+	Tags                []*UserDataBackupArchiveTag           `json:"tags"`
+	Transactions        []*UserDataBackupArchiveTransaction   `json:"transactions"`
+	TagIndexes          []*UserDataBackupArchiveTagIndex      `json:"tagIndexes"`
+	Splits              []*UserDataBackupArchiveSplit         `json:"splits"`
+	Pictures            []*UserDataBackupArchivePicture       `json:"pictures"`
+	Templates           []*UserDataBackupArchiveTemplate      `json:"templates"`
+	Rules               []*UserDataBackupArchiveRule          `json:"rules"`
+	Budgets             []*UserDataBackupArchiveBudget        `json:"budgets"`
+	CustomExchangeRates []*UserDataBackupArchiveExchangeRate  `json:"customExchangeRates"`
+	ImportProfiles      []*UserDataBackupArchiveImportProfile `json:"importProfiles"`
+	ImportBatches       []*UserDataBackupArchiveImportBatch   `json:"importBatches"`
+}
+
+// UserDataBackupArchiveAccount represents an account in user data backup archive
+type UserDataBackupArchiveAccount struct {
+	AccountId       int64           `json:"accountId"`
+	Category        AccountCategory `json:"category"`
+	Type            AccountType     `json:"type"`
+	ParentAccountId int64           `json:"parentAccountId"`
+	Name            string          `json:"name"`
+	DisplayOrder    int32           `json:"displayOrder"`
+	Icon            int64           `json:"icon"`
+	Color           string          `json:"color"`
+	Currency        string          `json:"currency"`
+	Balance         int64           `json:"balance"`
+	Comment         string          `json:"comment"`
+	Extend          *AccountExtend  `json:"extend,omitempty"`
+	Hidden          bool            `json:"hidden"`
+	CreatedUnixTime int64           `json:"createdUnixTime"`
+	UpdatedUnixTime int64           `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveCategory represents a transaction category in user data backup archive
+type UserDataBackupArchiveCategory struct {
+	CategoryId       int64                   `json:"categoryId"`
+	Type             TransactionCategoryType `json:"type"`
+	ParentCategoryId int64                   `json:"parentCategoryId"`
+	Name             string                  `json:"name"`
+	DisplayOrder     int32                   `json:"displayOrder"`
+	Icon             int64                   `json:"icon"`
+	Color            string                  `json:"color"`
+	Hidden           bool                    `json:"hidden"`
+	Comment          string                  `json:"comment"`
+	CreatedUnixTime  int64                   `json:"createdUnixTime"`
+	UpdatedUnixTime  int64                   `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveTag represents a transaction tag in user data backup archive
+type UserDataBackupArchiveTag struct {
+	TagId           int64  `json:"tagId"`
+	Name            string `json:"name"`
+	DisplayOrder    int32  `json:"displayOrder"`
+	Hidden          bool   `json:"hidden"`
+	CreatedUnixTime int64  `json:"createdUnixTime"`
+	UpdatedUnixTime int64  `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveTransaction represents a transaction in user data backup archive
+type UserDataBackupArchiveTransaction struct {
+	TransactionId        int64             `json:"transactionId"`
+	Type                 TransactionDbType `json:"type"`
+	CategoryId           int64             `json:"categoryId"`
+	AccountId            int64             `json:"accountId"`
+	TransactionTime      int64             `json:"transactionTime"`
+	TimezoneUtcOffset    int16             `json:"timezoneUtcOffset"`
+	Amount               int64             `json:"amount"`
+	RelatedId            int64             `json:"relatedId"`
+	RelatedAccountId     int64             `json:"relatedAccountId"`
+	RelatedAccountAmount int64             `json:"relatedAccountAmount"`
+	HideAmount           bool              `json:"hideAmount"`
+	Comment              string            `json:"comment"`
+	ExternalId           string            `json:"externalId"`
+	ImportBatchId        int64             `json:"importBatchId"`
+	GeoLongitude         float64           `json:"geoLongitude"`
+	GeoLatitude          float64           `json:"geoLatitude"`
+	CreatedIp            string            `json:"createdIp"`
+	ScheduledCreated     bool              `json:"scheduledCreated"`
+	CreatedUnixTime      int64             `json:"createdUnixTime"`
+	UpdatedUnixTime      int64             `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveTagIndex represents a transaction tag index in user data backup archive
+type UserDataBackupArchiveTagIndex struct {
+	TagIndexId      int64 `json:"tagIndexId"`
+	TransactionTime int64 `json:"transactionTime"`
+	TagId           int64 `json:"tagId"`
+	TransactionId   int64 `json:"transactionId"`
+	CreatedUnixTime int64 `json:"createdUnixTime"`
+	UpdatedUnixTime int64 `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveSplit represents a transaction split in user data backup archive
+type UserDataBackupArchiveSplit struct {
+	SplitId         int64  `json:"splitId"`
+	TransactionId   int64  `json:"transactionId"`
+	TransactionTime int64  `json:"transactionTime"`
+	CategoryId      int64  `json:"categoryId"`
+	Amount          int64  `json:"amount"`
+	TagIds          string `json:"tagIds"`
+	Comment         string `json:"comment"`
+	DisplayOrder    int32  `json:"displayOrder"`
+	CreatedUnixTime int64  `json:"createdUnixTime"`
+	UpdatedUnixTime int64  `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchivePicture represents a transaction picture info in user data backup archive
+type UserDataBackupArchivePicture struct {
+	PictureId        int64  `json:"pictureId"`
+	TransactionId    int64  `json:"transactionId"`
+	PictureExtension string `json:"pictureExtension"`
+	CreatedIp        string `json:"createdIp"`
+	CreatedUnixTime  int64  `json:"createdUnixTime"`
+	UpdatedUnixTime  int64  `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveTemplate represents a transaction template in user data backup archive
+type UserDataBackupArchiveTemplate struct {
+	TemplateId                 int64                            `json:"templateId"`
+	TemplateType               TransactionTemplateType          `json:"templateType"`
+	Name                       string                           `json:"name"`
+	Type                       TransactionType                  `json:"type"`
+	CategoryId                 int64                            `json:"categoryId"`
+	AccountId                  int64                            `json:"accountId"`
+	ScheduledFrequencyType     TransactionScheduleFrequencyType `json:"scheduledFrequencyType"`
+	ScheduledFrequency         string                           `json:"scheduledFrequency"`
+	ScheduledAt                int16                            `json:"scheduledAt"`
+	ScheduledTimezoneUtcOffset int16                            `json:"scheduledTimezoneUtcOffset"`
+	ScheduledInterval          int16                            `json:"scheduledInterval"`
+	ScheduledStartTime         int64                            `json:"scheduledStartTime"`
+	ScheduledEndTime           int64                            `json:"scheduledEndTime"`
+	ScheduledMaxCount          int32                            `json:"scheduledMaxCount"`
+	ScheduledCreatedCount      int32                            `json:"scheduledCreatedCount"`
+	ScheduledLastCreatedTime   int64                            `json:"scheduledLastCreatedTime"`
+	ScheduledNextTime          int64                            `json:"scheduledNextTime"`
+	TagIds                     string                           `json:"tagIds"`
+	Amount                     int64                            `json:"amount"`
+	RelatedAccountId           int64                            `json:"relatedAccountId"`
+	RelatedAccountAmount       int64                            `json:"relatedAccountAmount"`
+	HideAmount                 bool                             `json:"hideAmount"`
+	Comment                    string                           `json:"comment"`
+	DisplayOrder               int32                            `json:"displayOrder"`
+	Hidden                     bool                             `json:"hidden"`
+	CreatedUnixTime            int64                            `json:"createdUnixTime"`
+	UpdatedUnixTime            int64                            `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveRule represents a transaction rule in user data backup archive
+type UserDataBackupArchiveRule struct {
+	RuleId                  int64                           `json:"ruleId"`
+	Name                    string                          `json:"name"`
+	MatchType               TransactionType                 `json:"matchType"`
+	MatchAccountId          int64                           `json:"matchAccountId"`
+	MatchKeywordType        TransactionRuleKeywordMatchType `json:"matchKeywordType"`
+	MatchKeyword            string                          `json:"matchKeyword"`
+	MatchAmountRange        bool                            `json:"matchAmountRange"`
+	MatchMinAmount          int64                           `json:"matchMinAmount"`
+	MatchMaxAmount          int64                           `json:"matchMaxAmount"`
+	SetCategoryId           int64                           `json:"setCategoryId"`
+	AddTagIds               string                          `json:"addTagIds"`
+	SetComment              string                          `json:"setComment"`
+	SetDestinationAccountId int64                           `json:"setDestinationAccountId"`
+	DisplayOrder            int32                           `json:"displayOrder"`
+	Hidden                  bool                            `json:"hidden"`
+	CreatedUnixTime         int64                           `json:"createdUnixTime"`
+	UpdatedUnixTime         int64                           `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveBudget represents a budget in user data backup archive
+type UserDataBackupArchiveBudget struct {
+	BudgetId        int64              `json:"budgetId"`
+	CategoryId      int64              `json:"categoryId"`
+	AccountId       int64              `json:"accountId"`
+	PeriodType      BudgetPeriodType   `json:"periodType"`
+	Amount          int64              `json:"amount"`
+	RolloverType    BudgetRolloverType `json:"rolloverType"`
+	Comment         string             `json:"comment"`
+	DisplayOrder    int32              `json:"displayOrder"`
+	CreatedUnixTime int64              `json:"createdUnixTime"`
+	UpdatedUnixTime int64              `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveExchangeRate represents a user custom exchange rate in user data backup archive
+type UserDataBackupArchiveExchangeRate struct {
+	RateId          int64  `json:"rateId"`
+	Currency        string `json:"currency"`
+	BaseCurrency    string `json:"baseCurrency"`
+	Rate            string `json:"rate"`
+	ValidFromDate   string `json:"validFromDate"`
+	CreatedUnixTime int64  `json:"createdUnixTime"`
+	UpdatedUnixTime int64  `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveImportProfile represents a transaction import profile in user data backup archive
+type UserDataBackupArchiveImportProfile struct {
+	ProfileId           int64                                  `json:"profileId"`
+	Name                string                                 `json:"name"`
+	FileType            TransactionImportProfileFileType       `json:"fileType"`
+	Delimiter           string                                 `json:"delimiter"`
+	SkipRows            int32                                  `json:"skipRows"`
+	ColumnMapping       *TransactionImportProfileColumnMapping `json:"columnMapping,omitempty"`
+	DateTimeFormat      string                                 `json:"dateTimeFormat"`
+	DecimalSeparator    string                                 `json:"decimalSeparator"`
+	DigitGroupingSymbol string                                 `json:"digitGroupingSymbol"`
+	AmountSignType      TransactionImportProfileAmountSignType `json:"amountSignType"`
+	IncomeTypeValue     string                                 `json:"incomeTypeValue"`
+	ExpenseTypeValue    string                                 `json:"expenseTypeValue"`
+	TransferTypeValue   string                                 `json:"transferTypeValue"`
+	DefaultAccountName  string                                 `json:"defaultAccountName"`
+	TagSeparator        string                                 `json:"tagSeparator"`
+	CreatedUnixTime     int64                                  `json:"createdUnixTime"`
+	UpdatedUnixTime     int64                                  `json:"updatedUnixTime"`
+}
+
+// UserDataBackupArchiveImportBatch represents a transaction import batch in user data backup archive
+type UserDataBackupArchiveImportBatch struct {
+	BatchId            int64  `json:"batchId"`
+	FileType           string `json:"fileType"`
+	FileName           string `json:"fileName"`
+	TransactionCount   int32  `json:"transactionCount"`
+	CreatedAccountIds  string `json:"createdAccountIds"`
+	CreatedCategoryIds string `json:"createdCategoryIds"`
+	CreatedUnixTime    int64  `json:"createdUnixTime"`
+	UpdatedUnixTime    int64  `json:"updatedUnixTime"`
+}
+
+// NewUserDataBackupArchiveData returns the data file in user data backup archive according to user data backup
+func NewUserDataBackupArchiveData(b *UserDataBackup) *UserDataBackupArchiveData {
+	data := &UserDataBackupArchiveData{
+		Version:             b.Version,
+		ExportedUnixTime:    b.ExportedUnixTime,
+		Preferences:         b.Preferences,
+		Accounts:            make([]*UserDataBackupArchiveAccount, len(b.Accounts)),
+		Categories:          make([]*UserDataBackupArchiveCategory, len(b.Categories)),
+		Tags:                make([]*UserDataBackupArchiveTag, len(b.Tags)),
+		Transactions:        make([]*UserDataBackupArchiveTransaction, len(b.Transactions)),
+		TagIndexes:          make([]*UserDataBackupArchiveTagIndex, len(b.TagIndexes)),
+		Splits:              make([]*UserDataBackupArchiveSplit, len(b.Splits)),
+		Pictures:            make([]*UserDataBackupArchivePicture, len(b.Pictures)),
+		Templates:           make([]*UserDataBackupArchiveTemplate, len(b.Templates)),
+		Rules:               make([]*UserDataBackupArchiveRule, len(b.Rules)),
+		Budgets:             make([]*UserDataBackupArchiveBudget, len(b.Budgets)),
+		CustomExchangeRates: make([]*UserDataBackupArchiveExchangeRate, len(b.CustomExchangeRates)),
+		ImportProfiles:      make([]*UserDataBackupArchiveImportProfile, len(b.ImportProfiles)),
+		ImportBatches:       make([]*UserDataBackupArchiveImportBatch, len(b.ImportBatches)),
+	}
+
+	for i := 0; i < len(b.Accounts); i++ {
+		account := b.Accounts[i]
+		data.Accounts[i] = &UserDataBackupArchiveAccount{
+			AccountId:       account.AccountId,
+			Category:        account.Category,
+			Type:            account.Type,
+			ParentAccountId: account.ParentAccountId,
+			Name:            account.Name,
+			DisplayOrder:    account.DisplayOrder,
+			Icon:            account.Icon,
+			Color:           account.Color,
+			Currency:        account.Currency,
+			Balance:         account.Balance,
+			Comment:         account.Comment,
+			Extend:          account.Extend,
+			Hidden:          account.Hidden,
+			CreatedUnixTime: account.CreatedUnixTime,
+			UpdatedUnixTime: account.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.Categories); i++ {
+		category := b.Categories[i]
+		data.Categories[i] = &UserDataBackupArchiveCategory{
+			CategoryId:       category.CategoryId,
+			Type:             category.Type,
+			ParentCategoryId: category.ParentCategoryId,
+			Name:             category.Name,
+			DisplayOrder:     category.DisplayOrder,
+			Icon:             category.Icon,
+			Color:            category.Color,
+			Hidden:           category.Hidden,
+			Comment:          category.Comment,
+			CreatedUnixTime:  category.CreatedUnixTime,
+			UpdatedUnixTime:  category.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.Tags); i++ {
+		tag := b.Tags[i]
+		data.Tags[i] = &UserDataBackupArchiveTag{
+			TagId:           tag.TagId,
+			Name:            tag.Name,
+			DisplayOrder:    tag.DisplayOrder,
+			Hidden:          tag.Hidden,
+			CreatedUnixTime: tag.CreatedUnixTime,
+			UpdatedUnixTime: tag.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.Transactions); i++ {
+		transaction := b.Transactions[i]
+		data.Transactions[i] = &UserDataBackupArchiveTransaction{
+			TransactionId:        transaction.TransactionId,
+			Type:                 transaction.Type,
+			CategoryId:           transaction.CategoryId,
+			AccountId:            transaction.AccountId,
+			TransactionTime:      transaction.TransactionTime,
+			TimezoneUtcOffset:    transaction.TimezoneUtcOffset,
+			Amount:               transaction.Amount,
+			RelatedId:            transaction.RelatedId,
+			RelatedAccountId:     transaction.RelatedAccountId,
+			RelatedAccountAmount: transaction.RelatedAccountAmount,
+			HideAmount:           transaction.HideAmount,
+			Comment:              transaction.Comment,
+			ExternalId:           transaction.ExternalId,
+			ImportBatchId:        transaction.ImportBatchId,
+			GeoLongitude:         transaction.GeoLongitude,
+			GeoLatitude:          transaction.GeoLatitude,
+			CreatedIp:            transaction.CreatedIp,
+			ScheduledCreated:     transaction.ScheduledCreated,
+			CreatedUnixTime:      transaction.CreatedUnixTime,
+			UpdatedUnixTime:      transaction.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.TagIndexes); i++ {
+		tagIndex := b.TagIndexes[i]
+		data.TagIndexes[i] = &UserDataBackupArchiveTagIndex{
+			TagIndexId:      tagIndex.TagIndexId,
+			TransactionTime: tagIndex.TransactionTime,
+			TagId:           tagIndex.TagId,
+			TransactionId:   tagIndex.TransactionId,
+			CreatedUnixTime: tagIndex.CreatedUnixTime,
+			UpdatedUnixTime: tagIndex.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.Splits); i++ {
+		split := b.Splits[i]
+		data.Splits[i] = &UserDataBackupArchiveSplit{
+			SplitId:         split.SplitId,
+			TransactionId:   split.TransactionId,
+			TransactionTime: split.TransactionTime,
+			CategoryId:      split.CategoryId,
+			Amount:          split.Amount,
+			TagIds:          split.TagIds,
+			Comment:         split.Comment,
+			DisplayOrder:    split.DisplayOrder,
+			CreatedUnixTime: split.CreatedUnixTime,
+			UpdatedUnixTime: split.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.Pictures); i++ {
+		picture := b.Pictures[i]
+		data.Pictures[i] = &UserDataBackupArchivePicture{
+			PictureId:        picture.PictureId,
+			TransactionId:    picture.TransactionId,
+			PictureExtension: picture.PictureExtension,
+			CreatedIp:        picture.CreatedIp,
+			CreatedUnixTime:  picture.CreatedUnixTime,
+			UpdatedUnixTime:  picture.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.Templates); i++ {
+		template := b.Templates[i]
+		data.Templates[i] = &UserDataBackupArchiveTemplate{
+			TemplateId:                 template.TemplateId,
+			TemplateType:               template.TemplateType,
+			Name:                       template.Name,
+			Type:                       template.Type,
+			CategoryId:                 template.CategoryId,
+			AccountId:                  template.AccountId,
+			ScheduledFrequencyType:     template.ScheduledFrequencyType,
+			ScheduledFrequency:         template.ScheduledFrequency,
+			ScheduledAt:                template.ScheduledAt,
+			ScheduledTimezoneUtcOffset: template.ScheduledTimezoneUtcOffset,
+			ScheduledInterval:          template.ScheduledInterval,
+			ScheduledStartTime:         template.ScheduledStartTime,
+			ScheduledEndTime:           template.ScheduledEndTime,
+			ScheduledMaxCount:          template.ScheduledMaxCount,
+			ScheduledCreatedCount:      template.ScheduledCreatedCount,
+			ScheduledLastCreatedTime:   template.ScheduledLastCreatedTime,
+			ScheduledNextTime:          template.ScheduledNextTime,
+			TagIds:                     template.TagIds,
+			Amount:                     template.Amount,
+			RelatedAccountId:           template.RelatedAccountId,
+			RelatedAccountAmount:       template.RelatedAccountAmount,
+			HideAmount:                 template.HideAmount,
+			Comment:                    template.Comment,
+			DisplayOrder:               template.DisplayOrder,
+			Hidden:                     template.Hidden,
+			CreatedUnixTime:            template.CreatedUnixTime,
+			UpdatedUnixTime:            template.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.Rules); i++ {
+		rule := b.Rules[i]
+		data.Rules[i] = &UserDataBackupArchiveRule{
+			RuleId:                  rule.RuleId,
+			Name:                    rule.Name,
+			MatchType:               rule.MatchType,
+			MatchAccountId:          rule.MatchAccountId,
+			MatchKeywordType:        rule.MatchKeywordType,
+			MatchKeyword:            rule.MatchKeyword,
+			MatchAmountRange:        rule.MatchAmountRange,
+			MatchMinAmount:          rule.MatchMinAmount,
+			MatchMaxAmount:          rule.MatchMaxAmount,
+			SetCategoryId:           rule.SetCategoryId,
+			AddTagIds:               rule.AddTagIds,
+			SetComment:              rule.SetComment,
+			SetDestinationAccountId: rule.SetDestinationAccountId,
+			DisplayOrder:            rule.DisplayOrder,
+			Hidden:                  rule.Hidden,
+			CreatedUnixTime:         rule.CreatedUnixTime,
+			UpdatedUnixTime:         rule.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.Budgets); i++ {
+		budget := b.Budgets[i]
+		data.Budgets[i] = &UserDataBackupArchiveBudget{
+			BudgetId:        budget.BudgetId,
+			CategoryId:      budget.CategoryId,
+			AccountId:       budget.AccountId,
+			PeriodType:      budget.PeriodType,
+			Amount:          budget.Amount,
+			RolloverType:    budget.RolloverType,
+			Comment:         budget.Comment,
+			DisplayOrder:    budget.DisplayOrder,
+			CreatedUnixTime: budget.CreatedUnixTime,
+			UpdatedUnixTime: budget.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.CustomExchangeRates); i++ {
+		customExchangeRate := b.CustomExchangeRates[i]
+		data.CustomExchangeRates[i] = &UserDataBackupArchiveExchangeRate{
+			RateId:          customExchangeRate.RateId,
+			Currency:        customExchangeRate.Currency,
+			BaseCurrency:    customExchangeRate.BaseCurrency,
+			Rate:            customExchangeRate.Rate,
+			ValidFromDate:   customExchangeRate.ValidFromDate,
+			CreatedUnixTime: customExchangeRate.CreatedUnixTime,
+			UpdatedUnixTime: customExchangeRate.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.ImportProfiles); i++ {
+		importProfile := b.ImportProfiles[i]
+		data.ImportProfiles[i] = &UserDataBackupArchiveImportProfile{
+			ProfileId:           importProfile.ProfileId,
+			Name:                importProfile.Name,
+			FileType:            importProfile.FileType,
+			Delimiter:           importProfile.Delimiter,
+			SkipRows:            importProfile.SkipRows,
+			ColumnMapping:       importProfile.ColumnMapping,
+			DateTimeFormat:      importProfile.DateTimeFormat,
+			DecimalSeparator:    importProfile.DecimalSeparator,
+			DigitGroupingSymbol: importProfile.DigitGroupingSymbol,
+			AmountSignType:      importProfile.AmountSignType,
+			IncomeTypeValue:     importProfile.IncomeTypeValue,
+			ExpenseTypeValue:    importProfile.ExpenseTypeValue,
+			TransferTypeValue:   importProfile.TransferTypeValue,
+			DefaultAccountName:  importProfile.DefaultAccountName,
+			TagSeparator:        importProfile.TagSeparator,
+			CreatedUnixTime:     importProfile.CreatedUnixTime,
+			UpdatedUnixTime:     importProfile.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(b.ImportBatches); i++ {
+		importBatch := b.ImportBatches[i]
+		data.ImportBatches[i] = &UserDataBackupArchiveImportBatch{
+			BatchId:            importBatch.BatchId,
+			FileType:           importBatch.FileType,
+			FileName:           importBatch.FileName,
+			TransactionCount:   importBatch.TransactionCount,
+			CreatedAccountIds:  importBatch.CreatedAccountIds,
+			CreatedCategoryIds: importBatch.CreatedCategoryIds,
+			CreatedUnixTime:    importBatch.CreatedUnixTime,
+			UpdatedUnixTime:    importBatch.UpdatedUnixTime,
+		}
+	}
+
+	return data
+}
+
+// ToUserDataBackup returns the user data backup according to the data file in user data backup archive
+func (d *UserDataBackupArchiveData) ToUserDataBackup() (*UserDataBackup, error) {
+	b := &UserDataBackup{
+		Version:             d.Version,
+		ExportedUnixTime:    d.ExportedUnixTime,
+		Preferences:         d.Preferences,
+		Accounts:            make([]*Account, len(d.Accounts)),
+		Categories:          make([]*TransactionCategory, len(d.Categories)),
+		Tags:                make([]*TransactionTag, len(d.Tags)),
+		Transactions:        make([]*Transaction, len(d.Transactions)),
+		TagIndexes:          make([]*TransactionTagIndex, len(d.TagIndexes)),
+		Splits:              make([]*TransactionSplit, len(d.Splits)),
+		Pictures:            make([]*TransactionPictureInfo, len(d.Pictures)),
+		Templates:           make([]*TransactionTemplate, len(d.Templates)),
+		Rules:               make([]*TransactionRule, len(d.Rules)),
+		Budgets:             make([]*Budget, len(d.Budgets)),
+		CustomExchangeRates: make([]*UserCustomExchangeRate, len(d.CustomExchangeRates)),
+		ImportProfiles:      make([]*TransactionImportProfile, len(d.ImportProfiles)),
+		ImportBatches:       make([]*TransactionImportBatch, len(d.ImportBatches)),
+	}
+
+	for i := 0; i < len(d.Accounts); i++ {
+		account := d.Accounts[i]
+
+		if account == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.Accounts[i] = &Account{
+			AccountId:       account.AccountId,
+			Category:        account.Category,
+			Type:            account.Type,
+			ParentAccountId: account.ParentAccountId,
+			Name:            account.Name,
+			DisplayOrder:    account.DisplayOrder,
+			Icon:            account.Icon,
+			Color:           account.Color,
+			Currency:        account.Currency,
+			Balance:         account.Balance,
+			Comment:         account.Comment,
+			Extend:          account.Extend,
+			Hidden:          account.Hidden,
+			CreatedUnixTime: account.CreatedUnixTime,
+			UpdatedUnixTime: account.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.Categories); i++ {
+		category := d.Categories[i]
+
+		if category == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.Categories[i] = &TransactionCategory{
+			CategoryId:       category.CategoryId,
+			Type:             category.Type,
+			ParentCategoryId: category.ParentCategoryId,
+			Name:             category.Name,
+			DisplayOrder:     category.DisplayOrder,
+			Icon:             category.Icon,
+			Color:            category.Color,
+			Hidden:           category.Hidden,
+			Comment:          category.Comment,
+			CreatedUnixTime:  category.CreatedUnixTime,
+			UpdatedUnixTime:  category.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.Tags); i++ {
+		tag := d.Tags[i]
+
+		if tag == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.Tags[i] = &TransactionTag{
+			TagId:           tag.TagId,
+			Name:            tag.Name,
+			DisplayOrder:    tag.DisplayOrder,
+			Hidden:          tag.Hidden,
+			CreatedUnixTime: tag.CreatedUnixTime,
+			UpdatedUnixTime: tag.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.Transactions); i++ {
+		transaction := d.Transactions[i]
+
+		if transaction == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.Transactions[i] = &Transaction{
+			TransactionId:        transaction.TransactionId,
+			Type:                 transaction.Type,
+			CategoryId:           transaction.CategoryId,
+			AccountId:            transaction.AccountId,
+			TransactionTime:      transaction.TransactionTime,
+			TimezoneUtcOffset:    transaction.TimezoneUtcOffset,
+			Amount:               transaction.Amount,
+			RelatedId:            transaction.RelatedId,
+			RelatedAccountId:     transaction.RelatedAccountId,
+			RelatedAccountAmount: transaction.RelatedAccountAmount,
+			HideAmount:           transaction.HideAmount,
+			Comment:              transaction.Comment,
+			ExternalId:           transaction.ExternalId,
+			ImportBatchId:        transaction.ImportBatchId,
+			GeoLongitude:         transaction.GeoLongitude,
+			GeoLatitude:          transaction.GeoLatitude,
+			CreatedIp:            transaction.CreatedIp,
+			ScheduledCreated:     transaction.ScheduledCreated,
+			CreatedUnixTime:      transaction.CreatedUnixTime,
+			UpdatedUnixTime:      transaction.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.TagIndexes); i++ {
+		tagIndex := d.TagIndexes[i]
+
+		if tagIndex == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.TagIndexes[i] = &TransactionTagIndex{
+			TagIndexId:      tagIndex.TagIndexId,
+			TransactionTime: tagIndex.TransactionTime,
+			TagId:           tagIndex.TagId,
+			TransactionId:   tagIndex.TransactionId,
+			CreatedUnixTime: tagIndex.CreatedUnixTime,
+			UpdatedUnixTime: tagIndex.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.Splits); i++ {
+		split := d.Splits[i]
+
+		if split == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.Splits[i] = &TransactionSplit{
+			SplitId:         split.SplitId,
+			TransactionId:   split.TransactionId,
+			TransactionTime: split.TransactionTime,
+			CategoryId:      split.CategoryId,
+			Amount:          split.Amount,
+			TagIds:          split.TagIds,
+			Comment:         split.Comment,
+			DisplayOrder:    split.DisplayOrder,
+			CreatedUnixTime: split.CreatedUnixTime,
+			UpdatedUnixTime: split.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.Pictures); i++ {
+		picture := d.Pictures[i]
+
+		if picture == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.Pictures[i] = &TransactionPictureInfo{
+			PictureId:        picture.PictureId,
+			TransactionId:    picture.TransactionId,
+			PictureExtension: picture.PictureExtension,
+			CreatedIp:        picture.CreatedIp,
+			CreatedUnixTime:  picture.CreatedUnixTime,
+			UpdatedUnixTime:  picture.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.Templates); i++ {
+		template := d.Templates[i]
+
+		if template == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.Templates[i] = &TransactionTemplate{
+			TemplateId:                 template.TemplateId,
+			TemplateType:               template.TemplateType,
+			Name:                       template.Name,
+			Type:                       template.Type,
+			CategoryId:                 template.CategoryId,
+			AccountId:                  template.AccountId,
+			ScheduledFrequencyType:     template.ScheduledFrequencyType,
+			ScheduledFrequency:         template.ScheduledFrequency,
+			ScheduledAt:                template.ScheduledAt,
+			ScheduledTimezoneUtcOffset: template.ScheduledTimezoneUtcOffset,
+			ScheduledInterval:          template.ScheduledInterval,
+			ScheduledStartTime:         template.ScheduledStartTime,
+			ScheduledEndTime:           template.ScheduledEndTime,
+			ScheduledMaxCount:          template.ScheduledMaxCount,
+			ScheduledCreatedCount:      template.ScheduledCreatedCount,
+			ScheduledLastCreatedTime:   template.ScheduledLastCreatedTime,
+			ScheduledNextTime:          template.ScheduledNextTime,
+			TagIds:                     template.TagIds,
+			Amount:                     template.Amount,
+			RelatedAccountId:           template.RelatedAccountId,
+			RelatedAccountAmount:       template.RelatedAccountAmount,
+			HideAmount:                 template.HideAmount,
+			Comment:                    template.Comment,
+			DisplayOrder:               template.DisplayOrder,
+			Hidden:                     template.Hidden,
+			CreatedUnixTime:            template.CreatedUnixTime,
+			UpdatedUnixTime:            template.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.Rules); i++ {
+		rule := d.Rules[i]
+
+		if rule == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.Rules[i] = &TransactionRule{
+			RuleId:                  rule.RuleId,
+			Name:                    rule.Name,
+			MatchType:               rule.MatchType,
+			MatchAccountId:          rule.MatchAccountId,
+			MatchKeywordType:        rule.MatchKeywordType,
+			MatchKeyword:            rule.MatchKeyword,
+			MatchAmountRange:        rule.MatchAmountRange,
+			MatchMinAmount:          rule.MatchMinAmount,
+			MatchMaxAmount:          rule.MatchMaxAmount,
+			SetCategoryId:           rule.SetCategoryId,
+			AddTagIds:               rule.AddTagIds,
+			SetComment:              rule.SetComment,
+			SetDestinationAccountId: rule.SetDestinationAccountId,
+			DisplayOrder:            rule.DisplayOrder,
+			Hidden:                  rule.Hidden,
+			CreatedUnixTime:         rule.CreatedUnixTime,
+			UpdatedUnixTime:         rule.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.Budgets); i++ {
+		budget := d.Budgets[i]
+
+		if budget == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.Budgets[i] = &Budget{
+			BudgetId:        budget.BudgetId,
+			CategoryId:      budget.CategoryId,
+			AccountId:       budget.AccountId,
+			PeriodType:      budget.PeriodType,
+			Amount:          budget.Amount,
+			RolloverType:    budget.RolloverType,
+			Comment:         budget.Comment,
+			DisplayOrder:    budget.DisplayOrder,
+			CreatedUnixTime: budget.CreatedUnixTime,
+			UpdatedUnixTime: budget.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.CustomExchangeRates); i++ {
+		customExchangeRate := d.CustomExchangeRates[i]
+
+		if customExchangeRate == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.CustomExchangeRates[i] = &UserCustomExchangeRate{
+			RateId:          customExchangeRate.RateId,
+			Currency:        customExchangeRate.Currency,
+			BaseCurrency:    customExchangeRate.BaseCurrency,
+			Rate:            customExchangeRate.Rate,
+			ValidFromDate:   customExchangeRate.ValidFromDate,
+			CreatedUnixTime: customExchangeRate.CreatedUnixTime,
+			UpdatedUnixTime: customExchangeRate.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.ImportProfiles); i++ {
+		importProfile := d.ImportProfiles[i]
+
+		if importProfile == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.ImportProfiles[i] = &TransactionImportProfile{
+			ProfileId:           importProfile.ProfileId,
+			Name:                importProfile.Name,
+			FileType:            importProfile.FileType,
+			Delimiter:           importProfile.Delimiter,
+			SkipRows:            importProfile.SkipRows,
+			ColumnMapping:       importProfile.ColumnMapping,
+			DateTimeFormat:      importProfile.DateTimeFormat,
+			DecimalSeparator:    importProfile.DecimalSeparator,
+			DigitGroupingSymbol: importProfile.DigitGroupingSymbol,
+			AmountSignType:      importProfile.AmountSignType,
+			IncomeTypeValue:     importProfile.IncomeTypeValue,
+			ExpenseTypeValue:    importProfile.ExpenseTypeValue,
+			TransferTypeValue:   importProfile.TransferTypeValue,
+			DefaultAccountName:  importProfile.DefaultAccountName,
+			TagSeparator:        importProfile.TagSeparator,
+			CreatedUnixTime:     importProfile.CreatedUnixTime,
+			UpdatedUnixTime:     importProfile.UpdatedUnixTime,
+		}
+	}
+
+	for i := 0; i < len(d.ImportBatches); i++ {
+		importBatch := d.ImportBatches[i]
+
+		if importBatch == nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		b.ImportBatches[i] = &TransactionImportBatch{
+			BatchId:            importBatch.BatchId,
+			FileType:           importBatch.FileType,
+			FileName:           importBatch.FileName,
+			TransactionCount:   importBatch.TransactionCount,
+			CreatedAccountIds:  importBatch.CreatedAccountIds,
+			CreatedCategoryIds: importBatch.CreatedCategoryIds,
+			CreatedUnixTime:    importBatch.CreatedUnixTime,
+			UpdatedUnixTime:    importBatch.UpdatedUnixTime,
+		}
+	}
+
+	return b, nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestUserDataBackupArchiveData_MarshalAndUnmarshal(t *testing.T) {
+	backup := &UserDataBackup{
+		Version:  UserDataBackupCurrentVersion,
+		Accounts: []*Account{{Uid: 1, AccountId: 10, Name: "Cash", Currency: "USD", Balance: 123}},
+		Transactions: []*Transaction{
+			{Uid: 1, TransactionId: 40, Type: TRANSACTION_DB_TYPE_EXPENSE, AccountId: 10, Amount: 100, Comment: "lunch"},
+		},
+		Pictures: []*TransactionPictureInfo{{Uid: 1, PictureId: 70, TransactionId: 40, PictureExtension: "jpg"}},
+	}
+
+	data, err := json.Marshal(NewUserDataBackupArchiveData(backup))
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "\"accountId\":10")
+	assert.Contains(t, string(data), "\"pictureExtension\":\"jpg\"")
+	assert.NotContains(t, string(data), "\"Uid\"")
+
+	archiveData := &UserDataBackupArchiveData{}
+	err = json.Unmarshal(data, archiveData)
+	assert.Nil(t, err)
+
+	restoredBackup, err := archiveData.ToUserDataBackup()
+	assert.Nil(t, err)
+	assert.Equal(t, int32(UserDataBackupCurrentVersion), restoredBackup.Version)
+
+	assert.Equal(t, 1, len(restoredBackup.Accounts))
+	assert.Equal(t, int64(0), restoredBackup.Accounts[0].Uid)
+	assert.Equal(t, int64(10), restoredBackup.Accounts[0].AccountId)
+	assert.Equal(t, "Cash", restoredBackup.Accounts[0].Name)
+	assert.Equal(t, int64(123), restoredBackup.Accounts[0].Balance)
+
+	assert.Equal(t, 1, len(restoredBackup.Transactions))
+	assert.Equal(t, TRANSACTION_DB_TYPE_EXPENSE, restoredBackup.Transactions[0].Type)
+	assert.Equal(t, int64(100), restoredBackup.Transactions[0].Amount)
+	assert.Equal(t, "lunch", restoredBackup.Transactions[0].Comment)
+
+	assert.Equal(t, 1, len(restoredBackup.Pictures))
+	assert.Equal(t, int64(70), restoredBackup.Pictures[0].PictureId)
+	assert.Equal(t, "jpg", restoredBackup.Pictures[0].PictureExtension)
+}
+
+func TestUserDataBackupArchiveDataToUserDataBackup_NullItem(t *testing.T) {
+	archiveData := &UserDataBackupArchiveData{}
+	err := json.Unmarshal([]byte("{\"version\":2,\"accounts\":[null]}"), archiveData)
+	assert.Nil(t, err)
+
+	_, err = archiveData.ToUserDataBackup()
+	assert.EqualError(t, err, errs.ErrUserDataBackupFileInvalid.Message)
+}
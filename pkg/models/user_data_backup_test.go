@@ -0,0 +1,180 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+)
+
+func TestUserDataBackupApplyIdMappings(t *testing.T) {
+	backup := &UserDataBackup{
+		Preferences: &UserDataBackupUserPreferences{DefaultAccountId: 11},
+		Accounts: []*Account{
+			{Uid: 1, AccountId: 10, ParentAccountId: LevelOneAccountParentId},
+			{Uid: 1, AccountId: 11, ParentAccountId: 10},
+			{Uid: 1, AccountId: 12, ParentAccountId: LevelOneAccountParentId},
+		},
+		Categories: []*TransactionCategory{
+			{Uid: 1, CategoryId: 20, ParentCategoryId: LevelOneTransactionParentId},
+			{Uid: 1, CategoryId: 21, ParentCategoryId: 20},
+		},
+		Tags: []*TransactionTag{
+			{Uid: 1, TagId: 30},
+			{Uid: 1, TagId: 31},
+		},
+		Transactions: []*Transaction{
+			{Uid: 1, TransactionId: 40, AccountId: 11, CategoryId: 21, ImportBatchId: 130},
+			{Uid: 1, TransactionId: 41, RelatedId: 42, AccountId: 11, RelatedAccountId: 12},
+			{Uid: 1, TransactionId: 42, RelatedId: 41, AccountId: 12, RelatedAccountId: 11},
+		},
+		TagIndexes: []*TransactionTagIndex{
+			{Uid: 1, TagIndexId: 50, TagId: 31, TransactionId: 40},
+		},
+		Splits: []*TransactionSplit{
+			{Uid: 1, SplitId: 60, TransactionId: 40, CategoryId: 21, TagIds: "30,31,39"},
+		},
+		Pictures: []*TransactionPictureInfo{
+			{Uid: 1, PictureId: 70, TransactionId: 40},
+		},
+		Templates: []*TransactionTemplate{
+			{Uid: 1, TemplateId: 80, AccountId: 11, CategoryId: 21, TagIds: "31"},
+		},
+		Rules: []*TransactionRule{
+			{Uid: 1, RuleId: 90, SetCategoryId: 21, AddTagIds: "30", SetDestinationAccountId: 12},
+		},
+		Budgets: []*Budget{
+			{Uid: 1, BudgetId: 100, CategoryId: 20},
+		},
+		CustomExchangeRates: []*UserCustomExchangeRate{
+			{Uid: 1, RateId: 110},
+		},
+		ImportProfiles: []*TransactionImportProfile{
+			{Uid: 1, ProfileId: 120},
+		},
+		ImportBatches: []*TransactionImportBatch{
+			{Uid: 1, BatchId: 130, CreatedAccountIds: "12,19", CreatedCategoryIds: "21"},
+		},
+	}
+
+	mappings := &UserDataBackupIdMappings{
+		Accounts:            map[int64]int64{10: 1010, 11: 1011, 12: 1012},
+		Categories:          map[int64]int64{20: 1020, 21: 1021},
+		Tags:                map[int64]int64{30: 1030, 31: 1031},
+		Transactions:        map[int64]int64{40: 1040, 41: 1041, 42: 1042},
+		TagIndexes:          map[int64]int64{50: 1050},
+		Splits:              map[int64]int64{60: 1060},
+		Pictures:            map[int64]int64{70: 1070},
+		Templates:           map[int64]int64{80: 1080},
+		Rules:               map[int64]int64{90: 1090},
+		Budgets:             map[int64]int64{100: 1100},
+		CustomExchangeRates: map[int64]int64{110: 1110},
+		ImportProfiles:      map[int64]int64{120: 1120},
+		ImportBatches:       map[int64]int64{130: 1130},
+	}
+
+	err := backup.ApplyIdMappings(2, mappings)
+	assert.Nil(t, err)
+
+	assert.Equal(t, int64(1011), backup.Preferences.DefaultAccountId)
+
+	assert.Equal(t, int64(2), backup.Accounts[0].Uid)
+	assert.Equal(t, int64(1010), backup.Accounts[0].AccountId)
+	assert.Equal(t, int64(LevelOneAccountParentId), backup.Accounts[0].ParentAccountId)
+	assert.Equal(t, int64(1011), backup.Accounts[1].AccountId)
+	assert.Equal(t, int64(1010), backup.Accounts[1].ParentAccountId)
+
+	assert.Equal(t, int64(1020), backup.Categories[0].CategoryId)
+	assert.Equal(t, int64(LevelOneTransactionParentId), backup.Categories[0].ParentCategoryId)
+	assert.Equal(t, int64(1020), backup.Categories[1].ParentCategoryId)
+
+	assert.Equal(t, int64(2), backup.Transactions[0].Uid)
+	assert.Equal(t, int64(1040), backup.Transactions[0].TransactionId)
+	assert.Equal(t, int64(0), backup.Transactions[0].RelatedId)
+	assert.Equal(t, int64(1011), backup.Transactions[0].AccountId)
+	assert.Equal(t, int64(1021), backup.Transactions[0].CategoryId)
+	assert.Equal(t, int64(1130), backup.Transactions[0].ImportBatchId)
+	assert.Equal(t, int64(1042), backup.Transactions[1].RelatedId)
+	assert.Equal(t, int64(1012), backup.Transactions[1].RelatedAccountId)
+	assert.Equal(t, int64(1041), backup.Transactions[2].RelatedId)
+
+	assert.Equal(t, int64(1050), backup.TagIndexes[0].TagIndexId)
+	assert.Equal(t, int64(1031), backup.TagIndexes[0].TagId)
+	assert.Equal(t, int64(1040), backup.TagIndexes[0].TransactionId)
+
+	assert.Equal(t, int64(1060), backup.Splits[0].SplitId)
+	assert.Equal(t, int64(1040), backup.Splits[0].TransactionId)
+	assert.Equal(t, "1030,1031", backup.Splits[0].TagIds)
+
+	assert.Equal(t, int64(1070), backup.Pictures[0].PictureId)
+	assert.Equal(t, int64(1040), backup.Pictures[0].TransactionId)
+
+	assert.Equal(t, int64(1080), backup.Templates[0].TemplateId)
+	assert.Equal(t, int64(1011), backup.Templates[0].AccountId)
+	assert.Equal(t, int64(0), backup.Templates[0].RelatedAccountId)
+	assert.Equal(t, "1031", backup.Templates[0].TagIds)
+
+	assert.Equal(t, int64(1090), backup.Rules[0].RuleId)
+	assert.Equal(t, int64(0), backup.Rules[0].MatchAccountId)
+	assert.Equal(t, int64(1021), backup.Rules[0].SetCategoryId)
+	assert.Equal(t, int64(1012), backup.Rules[0].SetDestinationAccountId)
+	assert.Equal(t, "1030", backup.Rules[0].AddTagIds)
+
+	assert.Equal(t, int64(1100), backup.Budgets[0].BudgetId)
+	assert.Equal(t, int64(1020), backup.Budgets[0].CategoryId)
+
+	assert.Equal(t, int64(2), backup.CustomExchangeRates[0].Uid)
+	assert.Equal(t, int64(1110), backup.CustomExchangeRates[0].RateId)
+
+	assert.Equal(t, int64(2), backup.ImportProfiles[0].Uid)
+	assert.Equal(t, int64(1120), backup.ImportProfiles[0].ProfileId)
+
+	assert.Equal(t, int64(2), backup.ImportBatches[0].Uid)
+	assert.Equal(t, int64(1130), backup.ImportBatches[0].BatchId)
+	assert.Equal(t, "1012", backup.ImportBatches[0].CreatedAccountIds)
+	assert.Equal(t, "1021", backup.ImportBatches[0].CreatedCategoryIds)
+}
+
+func TestUserDataBackupApplyIdMappings_InvalidReference(t *testing.T) {
+	backup := &UserDataBackup{
+		Transactions: []*Transaction{
+			{Uid: 1, TransactionId: 40, AccountId: 19},
+		},
+	}
+
+	mappings := &UserDataBackupIdMappings{
+		Accounts:     map[int64]int64{10: 1010},
+		Transactions: map[int64]int64{40: 1040},
+	}
+
+	err := backup.ApplyIdMappings(2, mappings)
+	assert.EqualError(t, err, errs.ErrUserDataBackupReferenceInvalid.Message)
+}
+
+func TestUserDataBackupApplyIdMappings_InvalidDefaultAccount(t *testing.T) {
+	backup := &UserDataBackup{
+		Preferences: &UserDataBackupUserPreferences{DefaultAccountId: 19},
+	}
+
+	err := backup.ApplyIdMappings(2, &UserDataBackupIdMappings{})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), backup.Preferences.DefaultAccountId)
+}
+
+func TestUserDataBackupUserPreferencesApplyToUser(t *testing.T) {
+	user := &User{
+		DefaultAccountId: 10,
+		Language:         "en",
+		DefaultCurrency:  "USD",
+	}
+
+	preferences := NewUserDataBackupUserPreferences(user)
+
+	restoredUser := &User{}
+	preferences.ApplyToUser(restoredUser)
+
+	assert.Equal(t, int64(10), restoredUser.DefaultAccountId)
+	assert.Equal(t, "en", restoredUser.Language)
+	assert.Equal(t, "USD", restoredUser.DefaultCurrency)
+}
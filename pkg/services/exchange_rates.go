@@ -0,0 +1,258 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+)
+
+const exchangeRateDateFormat = "2006-01-02"
+
+// ExchangeRateService represents exchange rate service
+type ExchangeRateService struct {
+	ServiceUsingDB
+}
+
+// ExchangeRateConverter converts the amounts of accounts to the target currency by the saved exchange rates of the date when the amounts occur
+type ExchangeRateConverter struct {
+	c                   core.Context
+	exchangeRates       *ExchangeRateService
+	targetCurrency      string
+	accountCurrencies   map[int64]string
+	customExchangeRates []*models.UserCustomExchangeRate
+	exchangeRatesByDate map[string]*models.LatestExchangeRateResponse
+}
+
+// Initialize an exchange rate service singleton instance
+var (
+	ExchangeRates = &ExchangeRateService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetExchangeRatesByDate returns the exchange rates of the latest date which is not later than the specified date
+func (s *ExchangeRateService) GetExchangeRatesByDate(c core.Context, date string) (*models.LatestExchangeRateResponse, string, error) {
+	latestExchangeRate := &models.ExchangeRate{}
+	has, err := s.UserDB().NewSession(c).Where("date<=?", date).OrderBy("date desc, update_time desc").Limit(1).Get(latestExchangeRate)
+
+	if err != nil {
+		return nil, "", err
+	} else if !has {
+		return nil, "", errs.ErrExchangeRateNotFound
+	}
+
+	// the exchange rates from different data sources may have different base currencies and cannot be converted to each other, so only the exchange rates from the same data source as the latest one are returned
+	var exchangeRates []*models.ExchangeRate
+	err = s.UserDB().NewSession(c).Where("date=? AND data_source=? AND base_currency=?", latestExchangeRate.Date, latestExchangeRate.DataSource, latestExchangeRate.BaseCurrency).Find(&exchangeRates)
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	latestExchangeRates := make(models.LatestExchangeRateSlice, len(exchangeRates))
+
+	for i := 0; i < len(exchangeRates); i++ {
+		latestExchangeRates[i] = &models.LatestExchangeRate{
+			Currency:   exchangeRates[i].Currency,
+			Rate:       exchangeRates[i].Rate,
+			DataSource: exchangeRates[i].RateDataSource,
+		}
+	}
+
+	exchangeRateResponse := &models.LatestExchangeRateResponse{
+		DataSource:    latestExchangeRate.DataSource,
+		UpdateTime:    latestExchangeRate.UpdateTime,
+		BaseCurrency:  latestExchangeRate.BaseCurrency,
+		ExchangeRates: latestExchangeRates,
+	}
+
+	return exchangeRateResponse, latestExchangeRate.Date, nil
+}
+
+// GetExchangeRate returns the exchange rate from one currency to another currency at the specified time, the specified user custom exchange rates are merged on top of the saved exchange rates
+func (s *ExchangeRateService) GetExchangeRate(c core.Context, fromCurrency string, toCurrency string, unixTime int64, customExchangeRates []*models.UserCustomExchangeRate) (*models.HistoricalExchangeRateResponse, error) {
+	date := time.Unix(unixTime, 0).In(time.UTC).Format(exchangeRateDateFormat)
+
+	if fromCurrency == toCurrency {
+		return &models.HistoricalExchangeRateResponse{
+			Date:         date,
+			FromCurrency: fromCurrency,
+			ToCurrency:   toCurrency,
+			Rate:         "1",
+		}, nil
+	}
+
+	exchangeRateResponse, rateDate, err := s.getExchangeRatesWithCustomExchangeRates(c, date, customExchangeRates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rate, dataSource, ok := exchangeRateResponse.GetExchangeRate(fromCurrency, toCurrency)
+
+	if !ok {
+		return nil, errs.ErrExchangeRateNotFound
+	}
+
+	return &models.HistoricalExchangeRateResponse{
+		DataSource:   dataSource,
+		Date:         rateDate,
+		FromCurrency: fromCurrency,
+		ToCurrency:   toCurrency,
+		Rate:         utils.Float64ToString(rate),
+	}, nil
+}
+
+// NewExchangeRateConverter returns a new exchange rate converter which converts the amounts of the specified accounts to the target currency, the user custom exchange rates which are valid at the date of amount are merged on top of the saved exchange rates
+func (s *ExchangeRateService) NewExchangeRateConverter(c core.Context, targetCurrency string, accounts []*models.Account, customExchangeRates []*models.UserCustomExchangeRate) *ExchangeRateConverter {
+	accountCurrencies := make(map[int64]string, len(accounts))
+
+	for i := 0; i < len(accounts); i++ {
+		accountCurrencies[accounts[i].AccountId] = accounts[i].Currency
+	}
+
+	return &ExchangeRateConverter{
+		c:                   c,
+		exchangeRates:       s,
+		targetCurrency:      targetCurrency,
+		accountCurrencies:   accountCurrencies,
+		customExchangeRates: customExchangeRates,
+		exchangeRatesByDate: make(map[string]*models.LatestExchangeRateResponse),
+	}
+}
+
+// ConvertAccountAmount returns the amount in target currency of the specified account amount at the specified time, and returns false if there is no available exchange rate
+func (e *ExchangeRateConverter) ConvertAccountAmount(accountId int64, amount int64, unixTime int64) (int64, bool, error) {
+	currency, exists := e.accountCurrencies[accountId]
+
+	if !exists {
+		return 0, false, nil
+	} else if currency == e.targetCurrency {
+		return amount, true, nil
+	}
+
+	date := time.Unix(unixTime, 0).In(time.UTC).Format(exchangeRateDateFormat)
+	exchangeRateResponse, exists := e.exchangeRatesByDate[date]
+
+	if !exists {
+		var err error
+		exchangeRateResponse, _, err = e.exchangeRates.getExchangeRatesWithCustomExchangeRates(e.c, date, models.GetEffectiveUserCustomExchangeRates(e.customExchangeRates, date))
+
+		if err != nil {
+			return 0, false, err
+		}
+
+		e.exchangeRatesByDate[date] = exchangeRateResponse
+	}
+
+	rate, _, ok := exchangeRateResponse.GetExchangeRate(currency, e.targetCurrency)
+
+	if !ok {
+		return 0, false, nil
+	}
+
+	return int64(math.Floor(float64(amount) * rate)), true, nil
+}
+
+func (s *ExchangeRateService) getExchangeRatesWithCustomExchangeRates(c core.Context, date string, customExchangeRates []*models.UserCustomExchangeRate) (*models.LatestExchangeRateResponse, string, error) {
+	exchangeRateResponse, rateDate, err := s.GetExchangeRatesByDate(c, date)
+
+	if err != nil && err != errs.ErrExchangeRateNotFound {
+		return nil, "", err
+	}
+
+	if exchangeRateResponse == nil {
+		exchangeRateResponse = &models.LatestExchangeRateResponse{}
+		rateDate = date
+	}
+
+	if len(customExchangeRates) > 0 {
+		exchangeRateResponse = exchangeRateResponse.MergeUserCustomExchangeRates(customExchangeRates)
+	}
+
+	return exchangeRateResponse, rateDate, nil
+}
+
+// SaveExchangeRates saves the exchange rates of the update date to database
+func (s *ExchangeRateService) SaveExchangeRates(c core.Context, exchangeRateResponse *models.LatestExchangeRateResponse) error {
+	if exchangeRateResponse == nil || len(exchangeRateResponse.ExchangeRates) < 1 {
+		return errs.ErrExchangeRateNotFound
+	}
+
+	now := time.Now().Unix()
+	date := time.Unix(exchangeRateResponse.UpdateTime, 0).In(time.UTC).Format(exchangeRateDateFormat)
+	exchangeRates := make([]*models.ExchangeRate, len(exchangeRateResponse.ExchangeRates))
+
+	for i := 0; i < len(exchangeRateResponse.ExchangeRates); i++ {
+		rateDataSource := exchangeRateResponse.ExchangeRates[i].DataSource
+
+		if rateDataSource == "" {
+			rateDataSource = exchangeRateResponse.DataSource
+		}
+
+		exchangeRates[i] = &models.ExchangeRate{
+			Date:            date,
+			DataSource:      exchangeRateResponse.DataSource,
+			Currency:        exchangeRateResponse.ExchangeRates[i].Currency,
+			BaseCurrency:    exchangeRateResponse.BaseCurrency,
+			Rate:            exchangeRateResponse.ExchangeRates[i].Rate,
+			RateDataSource:  rateDataSource,
+			UpdateTime:      exchangeRateResponse.UpdateTime,
+			CreatedUnixTime: now,
+			UpdatedUnixTime: now,
+		}
+	}
+
+	insertedCount := 0
+	updatedCount := 0
+
+	err := s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(exchangeRates); i++ {
+			exchangeRate := exchangeRates[i]
+			exists, err := sess.Where("date=? AND data_source=? AND currency=?", exchangeRate.Date, exchangeRate.DataSource, exchangeRate.Currency).Exist(&models.ExchangeRate{})
+
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				_, err = sess.Cols("base_currency", "rate", "rate_data_source", "update_time", "updated_unix_time").Where("date=? AND data_source=? AND currency=?", exchangeRate.Date, exchangeRate.DataSource, exchangeRate.Currency).Update(exchangeRate)
+
+				if err != nil {
+					return err
+				}
+
+				updatedCount++
+				continue
+			}
+
+			_, err = sess.Insert(exchangeRate)
+
+			if err != nil {
+				return err
+			}
+
+			insertedCount++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	log.Infof(c, "[exchange_rates.SaveExchangeRates] %d exchange rates of %s have been saved, %d inserted and %d updated", len(exchangeRates), date, insertedCount, updatedCount)
+
+	return nil
+}
@@ -453,16 +453,25 @@ func (s *TransactionCategoryService) GetCategoryNameMapByList(categories []*mode
 	expenseCategoryMap = make(map[string]*models.TransactionCategory)
 	incomeCategoryMap = make(map[string]*models.TransactionCategory)
 	transferCategoryMap = make(map[string]*models.TransactionCategory)
+	categoryMap := s.GetCategoryMapByList(categories)
 
 	for i := 0; i < len(categories); i++ {
 		category := categories[i]
+		categoryNames := []string{category.Name}
+
+		// secondary categories can also be found by the name which contains the name of primary category
+		if parentCategory, exists := categoryMap[category.ParentCategoryId]; exists && category.ParentCategoryId != models.LevelOneTransactionParentId {
+			categoryNames = append(categoryNames, models.GetTransactionCategoryHierarchyName(parentCategory.Name, category.Name))
+		}
 
-		if category.Type == models.CATEGORY_TYPE_INCOME {
-			incomeCategoryMap[category.Name] = category
-		} else if category.Type == models.CATEGORY_TYPE_EXPENSE {
-			expenseCategoryMap[category.Name] = category
-		} else if category.Type == models.CATEGORY_TYPE_TRANSFER {
-			transferCategoryMap[category.Name] = category
+		for j := 0; j < len(categoryNames); j++ {
+			if category.Type == models.CATEGORY_TYPE_INCOME {
+				incomeCategoryMap[categoryNames[j]] = category
+			} else if category.Type == models.CATEGORY_TYPE_EXPENSE {
+				expenseCategoryMap[categoryNames[j]] = category
+			} else if category.Type == models.CATEGORY_TYPE_TRANSFER {
+				transferCategoryMap[categoryNames[j]] = category
+			}
 		}
 	}
 
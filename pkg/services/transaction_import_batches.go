@@ -0,0 +1,516 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const revertImportBatchTransactionIdsPageSize = 500
+
+// TransactionImportBatchService represents transaction import batch service
+type TransactionImportBatchService struct {
+	ServiceUsingDB
+}
+
+// Initialize a transaction import batch service singleton instance
+var (
+	TransactionImportBatches = &TransactionImportBatchService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetAllBatchesByUid returns all transaction import batch models of user
+func (s *TransactionImportBatchService) GetAllBatchesByUid(c core.Context, uid int64) ([]*models.TransactionImportBatch, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var batches []*models.TransactionImportBatch
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("created_unix_time desc").Find(&batches)
+
+	return batches, err
+}
+
+// GetBatchByBatchId returns a transaction import batch model according to batch id
+func (s *TransactionImportBatchService) GetBatchByBatchId(c core.Context, uid int64, batchId int64) (*models.TransactionImportBatch, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if batchId <= 0 {
+		return nil, errs.ErrTransactionImportBatchIdInvalid
+	}
+
+	batch := &models.TransactionImportBatch{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(batchId).Where("uid=? AND deleted=?", uid, false).Get(batch)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionImportBatchNotFound
+	}
+
+	return batch, nil
+}
+
+// RevertBatch deletes all transactions of the specified import batch, restores the balances of related accounts,
+// and deletes the accounts and categories which are created for this import batch and are not used anymore
+func (s *TransactionImportBatchService) RevertBatch(c core.Context, user *models.User, batchId int64, utcOffset int16) (*models.TransactionImportBatchRevertResponse, error) {
+	if user.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	uid := user.Uid
+
+	if batchId <= 0 {
+		return nil, errs.ErrTransactionImportBatchIdInvalid
+	}
+
+	now := time.Now().Unix()
+	result := &models.TransactionImportBatchRevertResponse{}
+
+	err := s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		batch := &models.TransactionImportBatch{}
+		has, err := sess.ID(batchId).Where("uid=? AND deleted=?", uid, false).Get(batch)
+
+		if err != nil {
+			return err
+		} else if !has {
+			return errs.ErrTransactionImportBatchNotFound
+		}
+
+		var transactions []*models.Transaction
+		err = sess.Cols("transaction_id", "uid", "deleted", "type", "account_id", "related_account_id", "transaction_time", "amount", "related_account_amount").Where("uid=? AND deleted=? AND import_batch_id=?", uid, false, batchId).Find(&transactions)
+
+		if err != nil {
+			return err
+		}
+
+		transactionIds := make([]int64, len(transactions))
+		accountBalanceChanges := make(map[int64]int64)
+
+		for i := 0; i < len(transactions); i++ {
+			transaction := transactions[i]
+			transactionIds[i] = transaction.TransactionId
+
+			if !user.CanEditTransactionByTransactionTime(transaction.TransactionTime, utcOffset) {
+				return errs.ErrCannotDeleteTransactionWithThisTransactionTime
+			}
+
+			if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
+				accountBalanceChanges[transaction.AccountId] -= transaction.RelatedAccountAmount
+			} else if transaction.Type == models.TRANSACTION_DB_TYPE_INCOME {
+				accountBalanceChanges[transaction.AccountId] -= transaction.Amount
+			} else if transaction.Type == models.TRANSACTION_DB_TYPE_EXPENSE {
+				accountBalanceChanges[transaction.AccountId] += transaction.Amount
+			} else if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT {
+				accountBalanceChanges[transaction.AccountId] += transaction.Amount
+				accountBalanceChanges[transaction.RelatedAccountId] -= transaction.RelatedAccountAmount
+			}
+		}
+
+		// Update transaction rows and all related rows to deleted
+		if len(transactionIds) > 0 {
+			err = s.deleteImportBatchTransactions(sess, uid, batchId, transactionIds, now)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		// Update account table
+		accountIds := make([]int64, 0, len(accountBalanceChanges))
+
+		for accountId, balanceChange := range accountBalanceChanges {
+			if balanceChange != 0 {
+				accountIds = append(accountIds, accountId)
+			}
+		}
+
+		sort.Slice(accountIds, func(i, j int) bool {
+			return accountIds[i] < accountIds[j]
+		})
+
+		for i := 0; i < len(accountIds); i++ {
+			accountId := accountIds[i]
+			account := &models.Account{
+				UpdatedUnixTime: now,
+			}
+
+			updatedRows, err := sess.ID(accountId).SetExpr("balance", fmt.Sprintf("balance+(%d)", accountBalanceChanges[accountId])).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(account)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrDatabaseOperationFailed
+			}
+		}
+
+		// Delete the created accounts and categories which are not used anymore
+		deletedAccountCount, err := s.deleteUnusedCreatedAccounts(sess, uid, batch.GetCreatedAccountIds(), now)
+
+		if err != nil {
+			return err
+		}
+
+		deletedCategoryCount, err := s.deleteUnusedCreatedCategories(sess, uid, batch.GetCreatedCategoryIds(), now)
+
+		if err != nil {
+			return err
+		}
+
+		// Update import batch row to deleted
+		batchUpdateModel := &models.TransactionImportBatch{
+			Deleted:         true,
+			UpdatedUnixTime: now,
+			DeletedUnixTime: now,
+		}
+
+		deletedRows, err := sess.ID(batchId).Cols("deleted", "updated_unix_time", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(batchUpdateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionImportBatchNotFound
+		}
+
+		result.TransactionCount = len(transactionIds)
+		result.DeletedAccountCount = deletedAccountCount
+		result.DeletedCategoryCount = deletedCategoryCount
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof(c, "[transaction_import_batches.RevertBatch] import batch \"id:%d\" of user \"uid:%d\" has been reverted, %d transactions, %d accounts and %d categories have been deleted", batchId, uid, result.TransactionCount, result.DeletedAccountCount, result.DeletedCategoryCount)
+
+	return result, nil
+}
+
+// GetUnusedAccountAndCategoryIds returns the ids of the specified accounts and categories (or their parent ones) which are not used by any transaction, they are regarded as created for the import batch
+func (s *TransactionImportBatchService) GetUnusedAccountAndCategoryIds(c core.Context, uid int64, accountIds []int64, categoryIds []int64) ([]int64, []int64, error) {
+	if uid <= 0 {
+		return nil, nil, errs.ErrUserIdInvalid
+	}
+
+	unusedAccountIds, err := s.getUnusedAccountIds(c, uid, accountIds)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unusedCategoryIds, err := s.getUnusedCategoryIds(c, uid, categoryIds)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return unusedAccountIds, unusedCategoryIds, nil
+}
+
+// DeleteAllBatches deletes all existed transaction import batches from database
+func (s *TransactionImportBatchService) DeleteAllBatches(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionImportBatch{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *TransactionImportBatchService) deleteImportBatchTransactions(sess *xorm.Session, uid int64, batchId int64, transactionIds []int64, now int64) error {
+	updateModel := &models.Transaction{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	tagIndexUpdateModel := &models.TransactionTagIndex{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	pictureUpdateModel := &models.TransactionPictureInfo{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	deletedRows, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND import_batch_id=?", uid, false, batchId).Update(updateModel)
+
+	if err != nil {
+		return err
+	} else if deletedRows < int64(len(transactionIds)) {
+		return errs.ErrDatabaseOperationFailed
+	}
+
+	for i := 0; i < len(transactionIds); i += revertImportBatchTransactionIdsPageSize {
+		end := i + revertImportBatchTransactionIdsPageSize
+
+		if end > len(transactionIds) {
+			end = len(transactionIds)
+		}
+
+		pageTransactionIds := transactionIds[i:end]
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", pageTransactionIds).Update(tagIndexUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", pageTransactionIds).Update(pictureUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("transaction_id", pageTransactionIds).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionImportBatchService) deleteUnusedCreatedAccounts(sess *xorm.Session, uid int64, accountIds []int64, now int64) (int, error) {
+	updateModel := &models.Account{
+		Deleted:         true,
+		UpdatedUnixTime: now,
+		DeletedUnixTime: now,
+	}
+
+	deletedCount := 0
+
+	for i := 0; i < len(accountIds); i++ {
+		accountId := accountIds[i]
+
+		var accountAndSubAccounts []*models.Account
+		err := sess.Where("uid=? AND deleted=? AND (account_id=? OR parent_account_id=?)", uid, false, accountId, accountId).Find(&accountAndSubAccounts)
+
+		if err != nil {
+			return 0, err
+		} else if len(accountAndSubAccounts) < 1 {
+			continue
+		}
+
+		accountAndSubAccountIds := make([]int64, len(accountAndSubAccounts))
+		hasBalance := false
+
+		for j := 0; j < len(accountAndSubAccounts); j++ {
+			accountAndSubAccountIds[j] = accountAndSubAccounts[j].AccountId
+
+			if accountAndSubAccounts[j].Balance != 0 {
+				hasBalance = true
+			}
+		}
+
+		if hasBalance {
+			continue
+		}
+
+		exists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return 0, err
+		} else if exists {
+			continue
+		}
+
+		deletedRows, err := sess.Cols("deleted", "updated_unix_time", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Update(updateModel)
+
+		if err != nil {
+			return 0, err
+		}
+
+		deletedCount += int(deletedRows)
+	}
+
+	return deletedCount, nil
+}
+
+func (s *TransactionImportBatchService) deleteUnusedCreatedCategories(sess *xorm.Session, uid int64, categoryIds []int64, now int64) (int, error) {
+	updateModel := &models.TransactionCategory{
+		Deleted:         true,
+		UpdatedUnixTime: now,
+		DeletedUnixTime: now,
+	}
+
+	deletedCount := 0
+
+	for i := 0; i < len(categoryIds); i++ {
+		categoryId := categoryIds[i]
+
+		var categoryAndSubCategories []*models.TransactionCategory
+		err := sess.Where("uid=? AND deleted=? AND (category_id=? OR parent_category_id=?)", uid, false, categoryId, categoryId).Find(&categoryAndSubCategories)
+
+		if err != nil {
+			return 0, err
+		} else if len(categoryAndSubCategories) < 1 {
+			continue
+		}
+
+		categoryAndSubCategoryIds := make([]int64, len(categoryAndSubCategories))
+
+		for j := 0; j < len(categoryAndSubCategories); j++ {
+			categoryAndSubCategoryIds[j] = categoryAndSubCategories[j].CategoryId
+		}
+
+		exists, err := sess.Cols("uid", "deleted", "category_id").Where("uid=? AND deleted=?", uid, false).In("category_id", categoryAndSubCategoryIds).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return 0, err
+		} else if exists {
+			continue
+		}
+
+		deletedRows, err := sess.Cols("deleted", "updated_unix_time", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).In("category_id", categoryAndSubCategoryIds).Update(updateModel)
+
+		if err != nil {
+			return 0, err
+		}
+
+		deletedCount += int(deletedRows)
+	}
+
+	return deletedCount, nil
+}
+
+func (s *TransactionImportBatchService) getUnusedAccountIds(c core.Context, uid int64, accountIds []int64) ([]int64, error) {
+	unusedAccountIds := make([]int64, 0, len(accountIds))
+	checkedAccountIds := make(map[int64]bool, len(accountIds))
+
+	for i := 0; i < len(accountIds) && len(unusedAccountIds) < models.TRANSACTION_IMPORT_BATCH_MAX_CREATED_ITEMS; i++ {
+		account := &models.Account{}
+		has, err := s.UserDataDB(uid).NewSession(c).ID(accountIds[i]).Where("uid=? AND deleted=?", uid, false).Get(account)
+
+		if err != nil {
+			return nil, err
+		} else if !has {
+			continue
+		}
+
+		accountId := account.AccountId
+
+		if account.ParentAccountId != models.LevelOneAccountParentId {
+			accountId = account.ParentAccountId
+		}
+
+		if checkedAccountIds[accountId] {
+			continue
+		}
+
+		checkedAccountIds[accountId] = true
+
+		var accountAndSubAccounts []*models.Account
+		err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND (account_id=? OR parent_account_id=?)", uid, false, accountId, accountId).Find(&accountAndSubAccounts)
+
+		if err != nil {
+			return nil, err
+		}
+
+		accountAndSubAccountIds := make([]int64, len(accountAndSubAccounts))
+
+		for j := 0; j < len(accountAndSubAccounts); j++ {
+			accountAndSubAccountIds[j] = accountAndSubAccounts[j].AccountId
+		}
+
+		exists, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=?", uid, false).In("account_id", accountAndSubAccountIds).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return nil, err
+		} else if exists {
+			continue
+		}
+
+		unusedAccountIds = append(unusedAccountIds, accountId)
+	}
+
+	return unusedAccountIds, nil
+}
+
+func (s *TransactionImportBatchService) getUnusedCategoryIds(c core.Context, uid int64, categoryIds []int64) ([]int64, error) {
+	unusedCategoryIds := make([]int64, 0, len(categoryIds))
+	checkedCategoryIds := make(map[int64]bool, len(categoryIds))
+
+	for i := 0; i < len(categoryIds) && len(unusedCategoryIds) < models.TRANSACTION_IMPORT_BATCH_MAX_CREATED_ITEMS; i++ {
+		category := &models.TransactionCategory{}
+		has, err := s.UserDataDB(uid).NewSession(c).ID(categoryIds[i]).Where("uid=? AND deleted=?", uid, false).Get(category)
+
+		if err != nil {
+			return nil, err
+		} else if !has {
+			continue
+		}
+
+		categoryId := category.CategoryId
+
+		if category.ParentCategoryId != models.LevelOneTransactionParentId {
+			categoryId = category.ParentCategoryId
+		}
+
+		if checkedCategoryIds[categoryId] {
+			continue
+		}
+
+		checkedCategoryIds[categoryId] = true
+
+		var categoryAndSubCategories []*models.TransactionCategory
+		err = s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND (category_id=? OR parent_category_id=?)", uid, false, categoryId, categoryId).Find(&categoryAndSubCategories)
+
+		if err != nil {
+			return nil, err
+		}
+
+		categoryAndSubCategoryIds := make([]int64, len(categoryAndSubCategories))
+
+		for j := 0; j < len(categoryAndSubCategories); j++ {
+			categoryAndSubCategoryIds[j] = categoryAndSubCategories[j].CategoryId
+		}
+
+		exists, err := s.UserDataDB(uid).NewSession(c).Cols("uid", "deleted", "category_id").Where("uid=? AND deleted=?", uid, false).In("category_id", categoryAndSubCategoryIds).Limit(1).Exist(&models.Transaction{})
+
+		if err != nil {
+			return nil, err
+		} else if exists {
+			continue
+		}
+
+		unusedCategoryIds = append(unusedCategoryIds, categoryId)
+	}
+
+	return unusedCategoryIds, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionImportJobService represents transaction import job service
+type TransactionImportJobService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction import job service singleton instance
+var (
+	TransactionImportJobs = &TransactionImportJobService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllJobsByUid returns all transaction import job models of user
+func (s *TransactionImportJobService) GetAllJobsByUid(c core.Context, uid int64) ([]*models.TransactionImportJob, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var jobs []*models.TransactionImportJob
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("created_unix_time desc").Find(&jobs)
+
+	return jobs, err
+}
+
+// GetJobByJobId returns a transaction import job model according to job id
+func (s *TransactionImportJobService) GetJobByJobId(c core.Context, uid int64, jobId int64) (*models.TransactionImportJob, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if jobId <= 0 {
+		return nil, errs.ErrTransactionImportJobIdInvalid
+	}
+
+	job := &models.TransactionImportJob{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(jobId).Where("uid=? AND deleted=?", uid, false).Get(job)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionImportJobNotFound
+	}
+
+	return job, nil
+}
+
+// CreateJob saves a new transaction import job model to database, only one unfinished job is allowed for each user
+func (s *TransactionImportJobService) CreateJob(c core.Context, job *models.TransactionImportJob) error {
+	if job.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	job.JobId = s.GenerateUuid(uuid.UUID_TYPE_IMPORT_BATCH)
+
+	if job.JobId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	now := time.Now().Unix()
+
+	job.Deleted = false
+	job.Status = models.TRANSACTION_IMPORT_JOB_STATUS_PENDING
+	job.CreatedUnixTime = now
+	job.UpdatedUnixTime = now
+
+	return s.UserDataDB(job.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		var unfinishedJobs []*models.TransactionImportJob
+		err := sess.Where("uid=? AND deleted=? AND status IN (?,?,?) AND updated_unix_time>=?", job.Uid, false, models.TRANSACTION_IMPORT_JOB_STATUS_PENDING, models.TRANSACTION_IMPORT_JOB_STATUS_PARSING, models.TRANSACTION_IMPORT_JOB_STATUS_IMPORTING, now-models.TRANSACTION_IMPORT_JOB_MAX_IDLE_SECONDS).Limit(1).Find(&unfinishedJobs)
+
+		if err != nil {
+			return err
+		} else if len(unfinishedJobs) > 0 {
+			return errs.ErrTransactionImportJobAlreadyRunning
+		}
+
+		_, err = sess.Insert(job)
+		return err
+	})
+}
+
+// UpdateJobProgress saves the status, counts and errors of an existed transaction import job to database
+func (s *TransactionImportJobService) UpdateJobProgress(c core.Context, job *models.TransactionImportJob) error {
+	if job.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+	job.UpdatedUnixTime = now
+
+	if job.Status.IsFinished() {
+		job.FinishedUnixTime = now
+	}
+
+	return s.UserDataDB(job.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(job.JobId).Cols("status", "total_count", "processed_count", "imported_count", "skipped_duplicate_count", "failed_count", "import_batch_id", "error_message", "row_errors", "updated_unix_time", "finished_unix_time").Where("uid=? AND deleted=?", job.Uid, false).Update(job)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionImportJobNotFound
+		}
+
+		return nil
+	})
+}
+
+// FailAllUnfinishedJobs marks all unfinished transaction import jobs as failed, the import file of these jobs is only held in memory, so they cannot be resumed after the server is restarted
+func (s *TransactionImportJobService) FailAllUnfinishedJobs(c core.Context) (int64, error) {
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionImportJob{
+		Status:           models.TRANSACTION_IMPORT_JOB_STATUS_FAILED,
+		ErrorMessage:     errs.ErrTransactionImportJobInterrupted.Message,
+		UpdatedUnixTime:  now,
+		FinishedUnixTime: now,
+	}
+
+	totalUpdatedRows := int64(0)
+
+	for i := 0; i < s.UserDataDBCount(); i++ {
+		err := s.UserDataDBByIndex(i).DoTransaction(c, func(sess *xorm.Session) error {
+			updatedRows, err := sess.Cols("status", "error_message", "updated_unix_time", "finished_unix_time").Where("deleted=?", false).In("status", models.TRANSACTION_IMPORT_JOB_STATUS_PENDING, models.TRANSACTION_IMPORT_JOB_STATUS_PARSING, models.TRANSACTION_IMPORT_JOB_STATUS_IMPORTING).Update(updateModel)
+			totalUpdatedRows += updatedRows
+			return err
+		})
+
+		if err != nil {
+			return totalUpdatedRows, err
+		}
+	}
+
+	return totalUpdatedRows, nil
+}
+
+// DeleteAllJobs deletes all existed transaction import jobs from database
+func (s *TransactionImportJobService) DeleteAllJobs(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionImportJob{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
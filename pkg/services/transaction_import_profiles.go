@@ -0,0 +1,158 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// TransactionImportProfileService represents transaction import profile service
+type TransactionImportProfileService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a transaction import profile service singleton instance
+var (
+	TransactionImportProfiles = &TransactionImportProfileService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllProfilesByUid returns all transaction import profile models of user
+func (s *TransactionImportProfileService) GetAllProfilesByUid(c core.Context, uid int64) ([]*models.TransactionImportProfile, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var profiles []*models.TransactionImportProfile
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("name asc").Find(&profiles)
+
+	return profiles, err
+}
+
+// GetProfileByProfileId returns a transaction import profile model according to profile id
+func (s *TransactionImportProfileService) GetProfileByProfileId(c core.Context, uid int64, profileId int64) (*models.TransactionImportProfile, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if profileId <= 0 {
+		return nil, errs.ErrTransactionImportProfileIdInvalid
+	}
+
+	profile := &models.TransactionImportProfile{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(profileId).Where("uid=? AND deleted=?", uid, false).Get(profile)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrTransactionImportProfileNotFound
+	}
+
+	return profile, nil
+}
+
+// CreateProfile saves a new transaction import profile model to database
+func (s *TransactionImportProfileService) CreateProfile(c core.Context, profile *models.TransactionImportProfile) error {
+	if profile.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	profile.ProfileId = s.GenerateUuid(uuid.UUID_TYPE_IMPORT_PROFILE)
+
+	if profile.ProfileId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	profile.Deleted = false
+	profile.CreatedUnixTime = time.Now().Unix()
+	profile.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(profile.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(profile)
+		return err
+	})
+}
+
+// ModifyProfile saves an existed transaction import profile model to database
+func (s *TransactionImportProfileService) ModifyProfile(c core.Context, profile *models.TransactionImportProfile) error {
+	if profile.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	profile.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(profile.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(profile.ProfileId).Cols("name", "file_type", "delimiter", "skip_rows", "column_mapping", "date_time_format", "decimal_separator", "digit_grouping_symbol", "amount_sign_type", "income_type_value", "expense_type_value", "transfer_type_value", "default_account_name", "tag_separator", "updated_unix_time").Where("uid=? AND deleted=?", profile.Uid, false).Update(profile)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrTransactionImportProfileNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteProfile deletes an existed transaction import profile from database
+func (s *TransactionImportProfileService) DeleteProfile(c core.Context, uid int64, profileId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionImportProfile{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(profileId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrTransactionImportProfileNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllProfiles deletes all existed transaction import profiles from database
+func (s *TransactionImportProfileService) DeleteAllProfiles(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.TransactionImportProfile{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
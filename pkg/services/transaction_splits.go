@@ -0,0 +1,116 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+)
+
+const pageCountForLoadAllTransactionSplits = 1000
+
+// TransactionSplitService represents transaction split service
+type TransactionSplitService struct {
+	ServiceUsingDB
+}
+
+// Initialize a transaction split service singleton instance
+var (
+	TransactionSplits = &TransactionSplitService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+	}
+)
+
+// GetSplitsByTransactionId returns all split lines of given transaction
+func (s *TransactionSplitService) GetSplitsByTransactionId(c core.Context, uid int64, transactionId int64) (models.TransactionSplitSlice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if transactionId <= 0 {
+		return nil, errs.ErrTransactionIdInvalid
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND transaction_id=?", uid, false, transactionId).OrderBy("display_order asc").Find(&splits)
+
+	return splits, err
+}
+
+// GetSplitsByTransactionIds returns all split lines map grouped by transaction id for given transactions
+func (s *TransactionSplitService) GetSplitsByTransactionIds(c core.Context, uid int64, transactionIds []int64) (map[int64]models.TransactionSplitSlice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if len(transactionIds) < 1 {
+		return make(map[int64]models.TransactionSplitSlice), nil
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).In("transaction_id", transactionIds).Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetGroupedTransactionSplits(splits), nil
+}
+
+// GetAllSplitsMapOfAllTransactions returns all split lines map grouped by transaction id
+func (s *TransactionSplitService) GetAllSplitsMapOfAllTransactions(c core.Context, uid int64) (map[int64]models.TransactionSplitSlice, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var allSplits []*models.TransactionSplit
+	maxSplitId := int64(0)
+
+	for maxSplitId >= 0 {
+		var splits []*models.TransactionSplit
+		sess := s.UserDataDB(uid).NewSession(c)
+
+		if maxSplitId > 0 {
+			sess = sess.Where("uid=? AND deleted=? AND split_id<=?", uid, false, maxSplitId)
+		} else {
+			sess = sess.Where("uid=? AND deleted=?", uid, false)
+		}
+
+		err := sess.Limit(pageCountForLoadAllTransactionSplits, 0).OrderBy("split_id desc").Find(&splits)
+
+		if err != nil {
+			return nil, err
+		}
+
+		allSplits = append(allSplits, splits...)
+
+		if len(splits) < pageCountForLoadAllTransactionSplits {
+			maxSplitId = -1
+			break
+		}
+
+		maxSplitId = splits[len(splits)-1].SplitId - 1
+	}
+
+	return s.GetGroupedTransactionSplits(allSplits), nil
+}
+
+// GetGroupedTransactionSplits returns split lines map grouped by transaction id and sorted by display order
+func (s *TransactionSplitService) GetGroupedTransactionSplits(splits []*models.TransactionSplit) map[int64]models.TransactionSplitSlice {
+	allTransactionSplits := make(map[int64]models.TransactionSplitSlice)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		allTransactionSplits[split.TransactionId] = append(allTransactionSplits[split.TransactionId], split)
+	}
+
+	for _, transactionSplits := range allTransactionSplits {
+		sort.Sort(transactionSplits)
+	}
+
+	return allTransactionSplits
+}
@@ -121,6 +121,12 @@ func (s *TransactionTemplateService) CreateTemplate(c core.Context, template *mo
 	template.CreatedUnixTime = time.Now().Unix()
 	template.UpdatedUnixTime = time.Now().Unix()
 
+	// scheduled transactions before the template is created do not need to be created
+	if template.TemplateType == models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
+		template.ScheduledLastCreatedTime = template.CreatedUnixTime
+		template.ScheduledNextTime = template.GetScheduledNextTime(template.ScheduledLastCreatedTime)
+	}
+
 	return s.UserDataDB(template.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		_, err := sess.Insert(template)
 		return err
@@ -136,7 +142,7 @@ func (s *TransactionTemplateService) ModifyTemplate(c core.Context, template *mo
 	template.UpdatedUnixTime = time.Now().Unix()
 
 	return s.UserDataDB(template.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		updatedRows, err := sess.ID(template.TemplateId).Cols("name", "type", "category_id", "account_id", "scheduled_frequency_type", "scheduled_frequency", "scheduled_at", "scheduled_timezone_utc_offset", "tag_ids", "amount", "related_account_id", "related_account_amount", "hide_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", template.Uid, false).Update(template)
+		updatedRows, err := sess.ID(template.TemplateId).Cols("name", "type", "category_id", "account_id", "scheduled_frequency_type", "scheduled_frequency", "scheduled_at", "scheduled_timezone_utc_offset", "scheduled_interval", "scheduled_start_time", "scheduled_end_time", "scheduled_max_count", "tag_ids", "amount", "related_account_id", "related_account_amount", "hide_amount", "comment", "updated_unix_time").Where("uid=? AND deleted=?", template.Uid, false).Update(template)
 
 		if err != nil {
 			return err
@@ -144,6 +150,34 @@ func (s *TransactionTemplateService) ModifyTemplate(c core.Context, template *mo
 			return errs.ErrTransactionTemplateNotFound
 		}
 
+		// the last created time only moves forward, so the scheduled transactions which have been created would not be created again
+		if template.ScheduledLastCreatedTime > 0 {
+			_, err = sess.ID(template.TemplateId).Cols("scheduled_last_created_time").Where("uid=? AND deleted=? AND scheduled_last_created_time<?", template.Uid, false, template.ScheduledLastCreatedTime).Update(template)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		currentTemplate := &models.TransactionTemplate{}
+		has, err := sess.ID(template.TemplateId).Where("uid=? AND deleted=?", template.Uid, false).Get(currentTemplate)
+
+		if err != nil {
+			return err
+		} else if !has || currentTemplate.TemplateType != models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE {
+			return nil
+		}
+
+		// the schedule may be changed, so the next time should be calculated again since the last created one
+		lastCreatedUnixTime := currentTemplate.ScheduledLastCreatedTime
+
+		if lastCreatedUnixTime <= 0 {
+			lastCreatedUnixTime = template.UpdatedUnixTime
+		}
+
+		template.ScheduledNextTime = currentTemplate.GetScheduledNextTime(lastCreatedUnixTime)
+		_, err = sess.ID(template.TemplateId).Cols("scheduled_next_time").Where("uid=? AND deleted=?", template.Uid, false).Update(template)
+
 		return err
 	})
 }
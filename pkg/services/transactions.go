@@ -169,6 +169,59 @@ func (s *TransactionService) GetTransactionByTransactionId(c core.Context, uid i
 	return transaction, nil
 }
 
+// MarkLikelyDuplicateImportTransactions marks the imported transactions which are likely the same as the existed transactions of user and returns the count of likely duplicate transactions
+func (s *TransactionService) MarkLikelyDuplicateImportTransactions(c core.Context, uid int64, importTransactions models.ImportedTransactionSlice) (int, error) {
+	if uid <= 0 {
+		return 0, errs.ErrUserIdInvalid
+	}
+
+	accountIdsMap := make(map[int64]bool)
+	minTransactionUnixTime := int64(0)
+	maxTransactionUnixTime := int64(0)
+
+	for i := 0; i < len(importTransactions); i++ {
+		importTransaction := importTransactions[i]
+
+		if importTransaction.AccountId <= 0 {
+			continue
+		}
+
+		transactionUnixTime := utils.GetUnixTimeFromTransactionTime(importTransaction.TransactionTime)
+
+		if len(accountIdsMap) < 1 || transactionUnixTime < minTransactionUnixTime {
+			minTransactionUnixTime = transactionUnixTime
+		}
+
+		if len(accountIdsMap) < 1 || transactionUnixTime > maxTransactionUnixTime {
+			maxTransactionUnixTime = transactionUnixTime
+		}
+
+		accountIdsMap[importTransaction.AccountId] = true
+	}
+
+	if len(accountIdsMap) < 1 {
+		return 0, nil
+	}
+
+	accountIds := make([]int64, 0, len(accountIdsMap))
+
+	for accountId := range accountIdsMap {
+		accountIds = append(accountIds, accountId)
+	}
+
+	minTransactionTime := utils.GetMinTransactionTimeFromUnixTime(minTransactionUnixTime - models.IMPORT_TRANSACTION_DUPLICATE_TIME_WINDOW)
+	maxTransactionTime := utils.GetMaxTransactionTimeFromUnixTime(maxTransactionUnixTime + models.IMPORT_TRANSACTION_DUPLICATE_TIME_WINDOW)
+
+	var existedTransactions []*models.Transaction
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND type<>? AND transaction_time>=? AND transaction_time<=?", uid, false, models.TRANSACTION_DB_TYPE_TRANSFER_IN, minTransactionTime, maxTransactionTime).In("account_id", accountIds).OrderBy("transaction_time asc").Find(&existedTransactions)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return importTransactions.MarkLikelyDuplicates(existedTransactions), nil
+}
+
 // GetAllTransactionCount returns total count of transactions
 func (s *TransactionService) GetAllTransactionCount(c core.Context, uid int64) (int64, error) {
 	return s.GetTransactionCount(c, uid, 0, 0, 0, nil, nil, nil, false, models.TRANSACTION_TAG_FILTER_HAS_ANY, "", "")
@@ -188,63 +241,17 @@ func (s *TransactionService) GetTransactionCount(c core.Context, uid int64, maxT
 }
 
 // CreateTransaction saves a new transaction to database
-func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64) error {
+func (s *TransactionService) CreateTransaction(c core.Context, transaction *models.Transaction, tagIds []int64, pictureIds []int64, splits []*models.TransactionSplit) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
 
-	// Check whether account id is valid
-	err := s.isAccountIdValid(transaction)
-
-	if err != nil {
-		return err
-	}
-
 	now := time.Now().Unix()
-
-	needTransactionUuidCount := 1
-
-	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-		needTransactionUuidCount = 2
-	}
-
-	transactionUuids := s.GenerateUuids(uuid.UUID_TYPE_TRANSACTION, uint16(needTransactionUuidCount))
-
-	if len(transactionUuids) < needTransactionUuidCount {
-		return errs.ErrSystemIsBusy
-	}
-
 	tagIds = utils.ToUniqueInt64Slice(tagIds)
-	needTagIndexUuidCount := uint16(len(tagIds))
-	tagIndexUuids := s.GenerateUuids(uuid.UUID_TYPE_TAG_INDEX, needTagIndexUuidCount)
-
-	if len(tagIndexUuids) < int(needTagIndexUuidCount) {
-		return errs.ErrSystemIsBusy
-	}
-
-	transaction.TransactionId = transactionUuids[0]
-
-	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
-		transaction.RelatedId = transactionUuids[1]
-	}
-
-	transaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
-
-	transaction.CreatedUnixTime = now
-	transaction.UpdatedUnixTime = now
-
-	transactionTagIndexes := make([]*models.TransactionTagIndex, len(tagIds))
+	transactionTagIndexes, err := s.prepareNewTransaction(transaction, tagIds, splits, now)
 
-	for i := 0; i < len(tagIds); i++ {
-		transactionTagIndexes[i] = &models.TransactionTagIndex{
-			TagIndexId:      tagIndexUuids[i],
-			Uid:             transaction.Uid,
-			Deleted:         false,
-			TagId:           tagIds[i],
-			TransactionId:   transaction.TransactionId,
-			CreatedUnixTime: now,
-			UpdatedUnixTime: now,
-		}
+	if err != nil {
+		return err
 	}
 
 	pictureUpdateModel := &models.TransactionPictureInfo{
@@ -253,12 +260,12 @@ func (s *TransactionService) CreateTransaction(c core.Context, transaction *mode
 	}
 
 	return s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
-		return s.doCreateTransaction(sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel)
+		return s.doCreateTransaction(sess, transaction, transactionTagIndexes, tagIds, pictureIds, pictureUpdateModel, splits)
 	})
 }
 
-// BatchCreateTransactions saves new transactions to database
-func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64) error {
+// BatchCreateTransactions saves new transactions and the import batch they belong to (if any) to database, the transactions will be appended to the import batch if it has already been saved
+func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64, transactions []*models.Transaction, allTagIds map[int][]int64, importBatch *models.TransactionImportBatch) error {
 	now := time.Now().Unix()
 	needTransactionUuidCount := uint16(0)
 	needTagIndexUuidCount := uint16(0)
@@ -328,6 +335,32 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 		return errs.ErrSystemIsBusy
 	}
 
+	appendToImportBatch := importBatch != nil && importBatch.BatchId > 0
+
+	if importBatch != nil {
+		if appendToImportBatch {
+			if importBatch.Uid != uid {
+				return errs.ErrUserIdInvalid
+			}
+		} else {
+			importBatch.BatchId = s.GenerateUuid(uuid.UUID_TYPE_IMPORT_BATCH)
+
+			if importBatch.BatchId < 1 {
+				return errs.ErrSystemIsBusy
+			}
+
+			importBatch.Uid = uid
+			importBatch.Deleted = false
+			importBatch.CreatedUnixTime = now
+		}
+
+		importBatch.UpdatedUnixTime = now
+
+		for i := 0; i < len(transactions); i++ {
+			transactions[i].ImportBatchId = importBatch.BatchId
+		}
+	}
+
 	allTransactionTagIndexes := make(map[int64][]*models.TransactionTagIndex)
 	allTransactionTagIds := make(map[int64][]int64)
 
@@ -356,11 +389,28 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 	}
 
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		if appendToImportBatch {
+			updatedRows, err := sess.ID(importBatch.BatchId).Incr("transaction_count", len(transactions)).Cols("updated_unix_time").Where("uid=? AND deleted=?", uid, false).Update(importBatch)
+
+			if err != nil {
+				return err
+			} else if updatedRows < 1 {
+				return errs.ErrTransactionImportBatchNotFound
+			}
+		} else if importBatch != nil {
+			importBatch.TransactionCount = int32(len(transactions))
+			_, err := sess.Insert(importBatch)
+
+			if err != nil {
+				return err
+			}
+		}
+
 		for i := 0; i < len(transactions); i++ {
 			transaction := transactions[i]
 			transactionTagIndexes := allTransactionTagIndexes[transaction.TransactionId]
 			transactionTagIds := allTransactionTagIds[transaction.TransactionId]
-			err := s.doCreateTransaction(sess, transaction, transactionTagIndexes, transactionTagIds, nil, nil)
+			err := s.doCreateTransaction(sess, transaction, transactionTagIndexes, transactionTagIds, nil, nil, nil)
 
 			if err != nil {
 				transactionUnixTime := utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime)
@@ -374,7 +424,7 @@ func (s *TransactionService) BatchCreateTransactions(c core.Context, uid int64,
 	})
 }
 
-// CreateScheduledTransactions saves all scheduled transactions that should be created now
+// CreateScheduledTransactions saves all scheduled transactions that should be created now, and all missed scheduled transactions since the last created one
 func (s *TransactionService) CreateScheduledTransactions(c core.Context, currentUnixTime int64, interval time.Duration) error {
 	var allTemplates []*models.TransactionTemplate
 	intervalMinute := int(interval / time.Minute)
@@ -382,19 +432,12 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 	currentMinute := (currentTime.Minute() / intervalMinute) * intervalMinute
 
 	startTime := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), currentTime.Hour(), currentMinute, 0, 0, time.Local)
-	startTimeInUTC := startTime.In(time.UTC)
-
-	minutesElapsedOfDayInUtc := startTimeInUTC.Hour()*60 + startTimeInUTC.Minute()
-	secondsElapsedOfDayInUtc := minutesElapsedOfDayInUtc * 60
-	todayFirstTimeInUTC := startTimeInUTC.Add(time.Duration(-secondsElapsedOfDayInUtc) * time.Second)
-	todayFirstUnixTimeInUTC := todayFirstTimeInUTC.Unix()
-
-	minScheduledAt := minutesElapsedOfDayInUtc
-	maxScheduledAt := minScheduledAt + intervalMinute
+	startUnixTime := startTime.Unix()
+	endUnixTime := startUnixTime + int64(intervalMinute)*60
 
 	for i := 0; i < s.UserDataDBCount(); i++ {
 		var templates []*models.TransactionTemplate
-		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND template_type=? AND (scheduled_frequency_type=? OR scheduled_frequency_type=?) AND scheduled_at>=? AND scheduled_at<?", false, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, minScheduledAt, maxScheduledAt).Find(&templates)
+		err := s.UserDataDBByIndex(i).NewSession(c).Where("deleted=? AND template_type=? AND scheduled_next_time>=? AND scheduled_next_time<? AND scheduled_frequency_type IN (?,?,?,?)", false, models.TRANSACTION_TEMPLATE_TYPE_SCHEDULE, 0, endUnixTime, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DAILY, models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_YEARLY).Find(&templates)
 
 		if err != nil {
 			return err
@@ -407,9 +450,10 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 		return nil
 	}
 
-	log.Infof(c, "[transactions.CreateScheduledTransactions] should process %d scheduled transaction templates now (scheduled at from %d to %d)", len(allTemplates), minScheduledAt, maxScheduledAt)
+	log.Infof(c, "[transactions.CreateScheduledTransactions] should process %d scheduled transaction templates now (next scheduled before %d)", len(allTemplates), endUnixTime)
 
 	successCount := 0
+	backfilledCount := 0
 	skipCount := 0
 	failedCount := 0
 
@@ -419,37 +463,37 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 		if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_DISABLED {
 			skipCount++
 			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" disabled scheduled transaction frequency", template.TemplateId)
+			s.updateScheduledTemplateNextTime(c, template, models.TRANSACTION_SCHEDULE_NO_NEXT_TIME)
 			continue
 		}
 
-		if (template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY &&
-			template.ScheduledFrequencyType != models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY) ||
-			template.ScheduledFrequency == "" {
+		if !template.ScheduledFrequencyType.IsValidFrequency(template.ScheduledFrequency) {
 			skipCount++
 			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid scheduled transaction frequency", template.TemplateId)
+			s.updateScheduledTemplateNextTime(c, template, models.TRANSACTION_SCHEDULE_NO_NEXT_TIME)
 			continue
 		}
 
-		frequencyValues, err := utils.StringArrayToInt64Array(strings.Split(template.ScheduledFrequency, ","))
-
-		if err != nil {
+		if template.IsScheduledCountReached() {
 			skipCount++
-			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid scheduled transaction frequency, because %s", template.TemplateId, err.Error())
+			log.Debugf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has created %d transactions and reached the maximum count", template.TemplateId, template.ScheduledCreatedCount)
+			s.updateScheduledTemplateNextTime(c, template, models.TRANSACTION_SCHEDULE_NO_NEXT_TIME)
 			continue
 		}
 
-		frequencyValueSet := utils.ToSet(frequencyValues)
-		templateTimeZone := time.FixedZone("Template Timezone", int(template.ScheduledTimezoneUtcOffset)*60)
-		transactionUnixTime := todayFirstUnixTimeInUTC + int64(template.ScheduledAt)*60
-		transactionTime := time.Unix(transactionUnixTime, 0).In(templateTimeZone)
+		lastCreatedUnixTime := template.ScheduledLastCreatedTime
 
-		if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_WEEKLY && !frequencyValueSet[int64(transactionTime.Weekday())] {
-			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %s", template.TemplateId, startTimeInUTC.Weekday())
-			continue
-		} else if template.ScheduledFrequencyType == models.TRANSACTION_SCHEDULE_FREQUENCY_TYPE_MONTHLY && !frequencyValueSet[int64(transactionTime.Day())] {
+		// templates which have never tracked the last created time only create the transactions of current period
+		if lastCreatedUnixTime <= 0 {
+			lastCreatedUnixTime = startUnixTime - 1
+		}
+
+		occurrenceUnixTimes := template.GetScheduledOccurrenceTimes(lastCreatedUnixTime, endUnixTime)
+
+		if len(occurrenceUnixTimes) < 1 {
 			skipCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction, today is %d of month", template.TemplateId, startTimeInUTC.Day())
+			log.Debugf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" does not need to create transaction now", template.TemplateId)
+			s.updateScheduledTemplateNextTime(c, template, template.GetScheduledNextTime(max(lastCreatedUnixTime, endUnixTime-1)))
 			continue
 		}
 
@@ -464,47 +508,90 @@ func (s *TransactionService) CreateScheduledTransactions(c core.Context, current
 		} else {
 			skipCount++
 			log.Warnf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has invalid transaction type", template.TemplateId)
+			s.updateScheduledTemplateNextTime(c, template, models.TRANSACTION_SCHEDULE_NO_NEXT_TIME)
 			continue
 		}
 
-		transaction := &models.Transaction{
-			Uid:               template.Uid,
-			Type:              transactionDbType,
-			CategoryId:        template.CategoryId,
-			TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(transactionTime.Unix()),
-			TimezoneUtcOffset: template.ScheduledTimezoneUtcOffset,
-			AccountId:         template.AccountId,
-			Amount:            template.Amount,
-			HideAmount:        template.HideAmount,
-			Comment:           template.Comment,
-			CreatedIp:         "127.0.0.1",
-			ScheduledCreated:  true,
-		}
+		templateBackfilledCount := 0
+		templateCompleted := true
+
+		for j := 0; j < len(occurrenceUnixTimes) && !template.IsScheduledCountReached(); j++ {
+			occurrenceUnixTime := occurrenceUnixTimes[j]
+
+			transaction := &models.Transaction{
+				Uid:               template.Uid,
+				Type:              transactionDbType,
+				CategoryId:        template.CategoryId,
+				TransactionTime:   utils.GetMinTransactionTimeFromUnixTime(occurrenceUnixTime),
+				TimezoneUtcOffset: template.ScheduledTimezoneUtcOffset,
+				AccountId:         template.AccountId,
+				Amount:            template.Amount,
+				HideAmount:        template.HideAmount,
+				Comment:           template.Comment,
+				CreatedIp:         "127.0.0.1",
+				ScheduledCreated:  true,
+			}
 
-		if template.Type == models.TRANSACTION_TYPE_TRANSFER {
-			transaction.RelatedAccountId = template.RelatedAccountId
-			transaction.RelatedAccountAmount = template.RelatedAccountAmount
-		}
+			if template.Type == models.TRANSACTION_TYPE_TRANSFER {
+				transaction.RelatedAccountId = template.RelatedAccountId
+				transaction.RelatedAccountAmount = template.RelatedAccountAmount
+			}
+
+			err := s.createScheduledTransaction(c, template, transaction, template.GetTagIds(), occurrenceUnixTime)
+
+			if err == errs.ErrScheduledTransactionAlreadyCreated {
+				log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has already created the transaction at %d", template.TemplateId, occurrenceUnixTime)
+				templateCompleted = false
+				break
+			} else if err != nil && (!errs.IsCustomError(err) || err == errs.ErrSystemIsBusy) {
+				// the template keeps the current next time, so the transaction would be created again in next run
+				failedCount++
+				templateCompleted = false
+				log.Errorf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" failed to create new trasaction at %d and would retry later, because %s", template.TemplateId, occurrenceUnixTime, err.Error())
+				break
+			} else if err != nil {
+				// the error would occur again if retrying (e.g. the account has been deleted), so skip this occurrence and move to the next one
+				failedCount++
+				log.Errorf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" failed to create new trasaction at %d and skip it, because %s", template.TemplateId, occurrenceUnixTime, err.Error())
+
+				if err = s.skipScheduledTransaction(c, template, occurrenceUnixTime); err != nil {
+					templateCompleted = false
+					log.Errorf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" failed to skip the trasaction at %d, because %s", template.TemplateId, occurrenceUnixTime, err.Error())
+					break
+				}
 
-		tagIds := template.GetTagIds()
-		err = s.CreateTransaction(c, transaction, tagIds, nil)
+				template.ScheduledLastCreatedTime = occurrenceUnixTime
+				continue
+			}
 
-		if err == nil {
 			successCount++
-			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has created a new trasaction \"id:%d\"", template.TemplateId, transaction.TransactionId)
-		} else {
-			failedCount++
-			log.Errorf(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" failed to create new trasaction", template.TemplateId)
+			template.ScheduledCreatedCount++
+			template.ScheduledLastCreatedTime = occurrenceUnixTime
+
+			if occurrenceUnixTime < startUnixTime {
+				backfilledCount++
+				templateBackfilledCount++
+			}
+
+			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has created a new trasaction \"id:%d\" at %d", template.TemplateId, transaction.TransactionId, occurrenceUnixTime)
+		}
+
+		if templateBackfilledCount > 0 {
+			log.Infof(c, "[transactions.CreateScheduledTransactions] transaction template \"id:%d\" has backfilled %d missed transactions since %d", template.TemplateId, templateBackfilledCount, lastCreatedUnixTime)
+		}
+
+		if templateCompleted {
+			s.updateScheduledTemplateNextTime(c, template, template.GetScheduledNextTime(max(template.ScheduledLastCreatedTime, endUnixTime-1)))
 		}
 	}
 
-	log.Infof(c, "[transactions.CreateScheduledTransactions] %d transactions has been created successfully, %d templates does not need to create transactions and %d transactions failed to create", successCount, skipCount, failedCount)
+	log.Infof(c, "[transactions.CreateScheduledTransactions] %d transactions has been created successfully (including %d missed transactions), %d templates does not need to create transactions and %d transactions failed to create", successCount, backfilledCount, skipCount, failedCount)
 
 	return nil
 }
 
 // ModifyTransaction saves an existed transaction to database
-func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64) error {
+func (s *TransactionService) ModifyTransaction(c core.Context, transaction *models.Transaction, currentTagIdsCount int, addTagIds []int64, removeTagIds []int64, addPictureIds []int64, removePictureIds []int64, splits []*models.TransactionSplit, updateSplits bool) error {
 	if transaction.Uid <= 0 {
 		return errs.ErrUserIdInvalid
 	}
@@ -540,6 +627,14 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 		}
 	}
 
+	if updateSplits {
+		err := s.initTransactionSplits(transaction, splits, now)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	err := s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
@@ -695,6 +790,15 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			return err
 		}
 
+		// Get and verify splits
+		if updateSplits {
+			err = s.isSplitsValid(sess, transaction, splits)
+
+			if err != nil {
+				return err
+			}
+		}
+
 		// Not allow to add transaction before balance modification transaction
 		if transaction.Type != models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			otherTransactionExists := false
@@ -806,6 +910,41 @@ func (s *TransactionService) ModifyTransaction(c core.Context, transaction *mode
 			}
 		}
 
+		// Update transaction splits
+		if updateSplits {
+			splitUpdateModel := &models.TransactionSplit{
+				Deleted:         true,
+				DeletedUnixTime: now,
+			}
+
+			_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitUpdateModel)
+
+			if err != nil {
+				return err
+			}
+
+			for i := 0; i < len(splits); i++ {
+				split := splits[i]
+				split.TransactionTime = transaction.TransactionTime
+
+				_, err := sess.Insert(split)
+
+				if err != nil {
+					return err
+				}
+			}
+		} else if modifyTransactionTime {
+			splitUpdateModel := &models.TransactionSplit{
+				TransactionTime: transaction.TransactionTime,
+			}
+
+			_, err := sess.Cols("transaction_time").Where("uid=? AND deleted=? AND transaction_id=?", transaction.Uid, false, transaction.TransactionId).Update(splitUpdateModel)
+
+			if err != nil {
+				return err
+			}
+		}
+
 		// Update account table
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			if transaction.AccountId != oldTransaction.AccountId {
@@ -983,6 +1122,11 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 		DeletedUnixTime: now,
 	}
 
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
 		// Get and verify current transaction
 		oldTransaction := &models.Transaction{}
@@ -1042,6 +1186,13 @@ func (s *TransactionService) DeleteTransaction(c core.Context, uid int64, transa
 			return err
 		}
 
+		// Update transaction splits
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=? AND transaction_id=?", uid, false, oldTransaction.TransactionId).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update account table
 		if oldTransaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 			sourceAccount.UpdatedUnixTime = time.Now().Unix()
@@ -1119,6 +1270,11 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64) er
 		DeletedUnixTime: now,
 	}
 
+	splitUpdateModel := &models.TransactionSplit{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
 	accountUpdateModel := &models.Account{
 		Balance:         0,
 		Deleted:         true,
@@ -1147,6 +1303,13 @@ func (s *TransactionService) DeleteAllTransactions(c core.Context, uid int64) er
 			return err
 		}
 
+		// Update all transaction splits to deleted
+		_, err = sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(splitUpdateModel)
+
+		if err != nil {
+			return err
+		}
+
 		// Update all account table to deleted
 		_, err = sess.Cols("balance", "deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(accountUpdateModel)
 
@@ -1187,6 +1350,7 @@ func (s *TransactionService) GetRelatedTransferTransaction(originalTransaction *
 		RelatedAccountId:     originalTransaction.AccountId,
 		RelatedAccountAmount: originalTransaction.Amount,
 		Comment:              originalTransaction.Comment,
+		ImportBatchId:        originalTransaction.ImportBatchId,
 		GeoLongitude:         originalTransaction.GeoLongitude,
 		GeoLatitude:          originalTransaction.GeoLatitude,
 		CreatedIp:            originalTransaction.CreatedIp,
@@ -1288,7 +1452,7 @@ func (s *TransactionService) GetAccountsTotalIncomeAndExpense(c core.Context, ui
 }
 
 // GetAccountsAndCategoriesTotalIncomeAndExpense returns the every accounts and categories total income and expense amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, utcOffset int16, useTransactionTimezone bool) ([]*models.Transaction, error) {
+func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c core.Context, uid int64, startUnixTime int64, endUnixTime int64, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, utcOffset int16, useTransactionTimezone bool, exchangeRateConverter *ExchangeRateConverter) ([]*models.TransactionTotalAmount, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -1336,7 +1500,7 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c cor
 			finalConditionParams = append(finalConditionParams, maxTransactionTime)
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("category_id, account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, category_id, account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
@@ -1355,7 +1519,13 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c cor
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
-	transactionTotalAmountsMap := make(map[string]*models.Transaction)
+	allTransactionSplits, err := s.getTransactionSplitsMapByTransactionTime(c, uid, startTransactionTime, endTransactionTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transactionTotalAmountsMap := make(map[string]*models.TransactionTotalAmount)
 
 	for i := 0; i < len(allTransactions); i++ {
 		transaction := allTransactions[i]
@@ -1371,23 +1541,33 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c cor
 			continue
 		}
 
-		groupKey := fmt.Sprintf("%d_%d", transaction.CategoryId, transaction.AccountId)
-		totalAmounts, exists := transactionTotalAmountsMap[groupKey]
+		categoryAmounts := s.getCategoryAmountsOfTransaction(transaction, allTransactionSplits)
 
-		if !exists {
-			totalAmounts = &models.Transaction{
-				CategoryId: transaction.CategoryId,
-				AccountId:  transaction.AccountId,
-				Amount:     0,
+		for j := 0; j < len(categoryAmounts); j++ {
+			categoryAmount := categoryAmounts[j]
+			groupKey := fmt.Sprintf("%d_%d", categoryAmount.CategoryId, transaction.AccountId)
+			totalAmounts, exists := transactionTotalAmountsMap[groupKey]
+
+			if !exists {
+				totalAmounts = &models.TransactionTotalAmount{
+					CategoryId:                 categoryAmount.CategoryId,
+					AccountId:                  transaction.AccountId,
+					Amount:                     0,
+					HasAmountInDefaultCurrency: exchangeRateConverter != nil,
+				}
+
+				transactionTotalAmountsMap[groupKey] = totalAmounts
 			}
 
-			transactionTotalAmountsMap[groupKey] = totalAmounts
-		}
+			err = s.addTransactionTotalAmount(totalAmounts, transaction, categoryAmount.Amount, exchangeRateConverter)
 
-		totalAmounts.Amount += transaction.Amount
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	transactionTotalAmounts := make([]*models.Transaction, 0, len(transactionTotalAmountsMap))
+	transactionTotalAmounts := make([]*models.TransactionTotalAmount, 0, len(transactionTotalAmountsMap))
 
 	for _, totalAmounts := range transactionTotalAmountsMap {
 		transactionTotalAmounts = append(transactionTotalAmounts, totalAmounts)
@@ -1397,7 +1577,7 @@ func (s *TransactionService) GetAccountsAndCategoriesTotalIncomeAndExpense(c cor
 }
 
 // GetAccountsAndCategoriesMonthlyIncomeAndExpense returns the every accounts monthly income and expense amount by specific date range
-func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, utcOffset int16, useTransactionTimezone bool) (map[int32][]*models.Transaction, error) {
+func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c core.Context, uid int64, startYear int32, startMonth int32, endYear int32, endMonth int32, tagIds []int64, noTags bool, tagFilterType models.TransactionTagFilterType, utcOffset int16, useTransactionTimezone bool, exchangeRateConverter *ExchangeRateConverter) (map[int32][]*models.TransactionTotalAmount, error) {
 	if uid <= 0 {
 		return nil, errs.ErrUserIdInvalid
 	}
@@ -1450,7 +1630,7 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c c
 			finalConditionParams = append(finalConditionParams, maxTransactionTime)
 		}
 
-		sess := s.UserDataDB(uid).NewSession(c).Select("category_id, account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
+		sess := s.UserDataDB(uid).NewSession(c).Select("transaction_id, category_id, account_id, transaction_time, timezone_utc_offset, amount").Where(finalCondition, finalConditionParams...)
 		sess = s.appendFilterTagIdsConditionToQuery(sess, uid, maxTransactionTime, minTransactionTime, tagIds, noTags, tagFilterType)
 
 		err := sess.Limit(pageCountForLoadTransactionAmounts, 0).OrderBy("transaction_time desc").Find(&transactions)
@@ -1469,10 +1649,16 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c c
 		maxTransactionTime = transactions[len(transactions)-1].TransactionTime - 1
 	}
 
+	allTransactionSplits, err := s.getTransactionSplitsMapByTransactionTime(c, uid, startTransactionTime, endTransactionTime)
+
+	if err != nil {
+		return nil, err
+	}
+
 	startYearMonth := startYear*100 + startMonth
 	endYearMonth := endYear*100 + endMonth
-	transactionsMonthlyAmountsMap := make(map[string]*models.Transaction)
-	transactionsMonthlyAmounts := make(map[int32][]*models.Transaction)
+	transactionsMonthlyAmountsMap := make(map[string]*models.TransactionTotalAmount)
+	transactionsMonthlyAmounts := make(map[int32][]*models.TransactionTotalAmount)
 
 	for i := 0; i < len(allTransactions); i++ {
 		transaction := allTransactions[i]
@@ -1488,18 +1674,28 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c c
 			continue
 		}
 
-		groupKey := fmt.Sprintf("%d_%d_%d", yearMonth, transaction.CategoryId, transaction.AccountId)
-		transactionAmounts, exists := transactionsMonthlyAmountsMap[groupKey]
+		categoryAmounts := s.getCategoryAmountsOfTransaction(transaction, allTransactionSplits)
 
-		if !exists {
-			transactionAmounts = &models.Transaction{
-				CategoryId: transaction.CategoryId,
-				AccountId:  transaction.AccountId,
+		for j := 0; j < len(categoryAmounts); j++ {
+			categoryAmount := categoryAmounts[j]
+			groupKey := fmt.Sprintf("%d_%d_%d", yearMonth, categoryAmount.CategoryId, transaction.AccountId)
+			transactionAmounts, exists := transactionsMonthlyAmountsMap[groupKey]
+
+			if !exists {
+				transactionAmounts = &models.TransactionTotalAmount{
+					CategoryId:                 categoryAmount.CategoryId,
+					AccountId:                  transaction.AccountId,
+					HasAmountInDefaultCurrency: exchangeRateConverter != nil,
+				}
+				transactionsMonthlyAmountsMap[groupKey] = transactionAmounts
 			}
-			transactionsMonthlyAmountsMap[groupKey] = transactionAmounts
-		}
 
-		transactionAmounts.Amount += transaction.Amount
+			err = s.addTransactionTotalAmount(transactionAmounts, transaction, categoryAmount.Amount, exchangeRateConverter)
+
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	for groupKey, transaction := range transactionsMonthlyAmountsMap {
@@ -1508,7 +1704,7 @@ func (s *TransactionService) GetAccountsAndCategoriesMonthlyIncomeAndExpense(c c
 		monthlyAmounts, exists := transactionsMonthlyAmounts[yearMonth]
 
 		if !exists {
-			monthlyAmounts = make([]*models.Transaction, 0, 0)
+			monthlyAmounts = make([]*models.TransactionTotalAmount, 0, 0)
 		}
 
 		monthlyAmounts = append(monthlyAmounts, transaction)
@@ -1541,7 +1737,127 @@ func (s *TransactionService) GetTransactionIds(transactions []*models.Transactio
 	return transactionIds
 }
 
-func (s *TransactionService) doCreateTransaction(sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo) error {
+func (s *TransactionService) createScheduledTransaction(c core.Context, template *models.TransactionTemplate, transaction *models.Transaction, tagIds []int64, occurrenceUnixTime int64) error {
+	now := time.Now().Unix()
+	tagIds = utils.ToUniqueInt64Slice(tagIds)
+	transactionTagIndexes, err := s.prepareNewTransaction(transaction, tagIds, nil, now)
+
+	if err != nil {
+		return err
+	}
+
+	pictureUpdateModel := &models.TransactionPictureInfo{
+		TransactionId:   transaction.TransactionId,
+		UpdatedUnixTime: now,
+	}
+
+	templateUpdateModel := &models.TransactionTemplate{
+		ScheduledLastCreatedTime: occurrenceUnixTime,
+	}
+
+	return s.UserDataDB(transaction.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		// only the first one who moves forward the last created time can create the transaction, so each occurrence would be created exactly once
+		updatedRows, err := sess.ID(template.TemplateId).Cols("scheduled_last_created_time").Incr("scheduled_created_count").Where("uid=? AND deleted=? AND scheduled_last_created_time<?", template.Uid, false, occurrenceUnixTime).Update(templateUpdateModel)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrScheduledTransactionAlreadyCreated
+		}
+
+		return s.doCreateTransaction(sess, transaction, transactionTagIndexes, tagIds, nil, pictureUpdateModel, nil)
+	})
+}
+
+func (s *TransactionService) skipScheduledTransaction(c core.Context, template *models.TransactionTemplate, occurrenceUnixTime int64) error {
+	templateUpdateModel := &models.TransactionTemplate{
+		ScheduledLastCreatedTime: occurrenceUnixTime,
+	}
+
+	return s.UserDataDB(template.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.ID(template.TemplateId).Cols("scheduled_last_created_time").Where("uid=? AND deleted=? AND scheduled_last_created_time<?", template.Uid, false, occurrenceUnixTime).Update(templateUpdateModel)
+		return err
+	})
+}
+
+func (s *TransactionService) updateScheduledTemplateNextTime(c core.Context, template *models.TransactionTemplate, nextUnixTime int64) {
+	templateUpdateModel := &models.TransactionTemplate{
+		ScheduledNextTime: nextUnixTime,
+	}
+
+	err := s.UserDataDB(template.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		// the template may be modified by user or processed by others meanwhile, then the next time has been updated by them
+		_, err := sess.ID(template.TemplateId).Cols("scheduled_next_time").Where("uid=? AND deleted=? AND scheduled_last_created_time=?", template.Uid, false, template.ScheduledLastCreatedTime).Update(templateUpdateModel)
+		return err
+	})
+
+	if err != nil {
+		log.Errorf(c, "[transactions.updateScheduledTemplateNextTime] failed to update next scheduled time of transaction template \"id:%d\", because %s", template.TemplateId, err.Error())
+	}
+}
+
+func (s *TransactionService) prepareNewTransaction(transaction *models.Transaction, tagIds []int64, splits []*models.TransactionSplit, now int64) ([]*models.TransactionTagIndex, error) {
+	// Check whether account id is valid
+	err := s.isAccountIdValid(transaction)
+
+	if err != nil {
+		return nil, err
+	}
+
+	needTransactionUuidCount := 1
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		needTransactionUuidCount = 2
+	}
+
+	transactionUuids := s.GenerateUuids(uuid.UUID_TYPE_TRANSACTION, uint16(needTransactionUuidCount))
+
+	if len(transactionUuids) < needTransactionUuidCount {
+		return nil, errs.ErrSystemIsBusy
+	}
+
+	needTagIndexUuidCount := uint16(len(tagIds))
+	tagIndexUuids := s.GenerateUuids(uuid.UUID_TYPE_TAG_INDEX, needTagIndexUuidCount)
+
+	if len(tagIndexUuids) < int(needTagIndexUuidCount) {
+		return nil, errs.ErrSystemIsBusy
+	}
+
+	transaction.TransactionId = transactionUuids[0]
+
+	if transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_OUT || transaction.Type == models.TRANSACTION_DB_TYPE_TRANSFER_IN {
+		transaction.RelatedId = transactionUuids[1]
+	}
+
+	err = s.initTransactionSplits(transaction, splits, now)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.TransactionTime = utils.GetMinTransactionTimeFromUnixTime(utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+
+	transaction.CreatedUnixTime = now
+	transaction.UpdatedUnixTime = now
+
+	transactionTagIndexes := make([]*models.TransactionTagIndex, len(tagIds))
+
+	for i := 0; i < len(tagIds); i++ {
+		transactionTagIndexes[i] = &models.TransactionTagIndex{
+			TagIndexId:      tagIndexUuids[i],
+			Uid:             transaction.Uid,
+			Deleted:         false,
+			TagId:           tagIds[i],
+			TransactionId:   transaction.TransactionId,
+			CreatedUnixTime: now,
+			UpdatedUnixTime: now,
+		}
+	}
+
+	return transactionTagIndexes, nil
+}
+
+func (s *TransactionService) doCreateTransaction(sess *xorm.Session, transaction *models.Transaction, transactionTagIndexes []*models.TransactionTagIndex, tagIds []int64, pictureIds []int64, pictureUpdateModel *models.TransactionPictureInfo, splits []*models.TransactionSplit) error {
 	// Get and verify source and destination account
 	sourceAccount, destinationAccount, err := s.getAccountModels(sess, transaction)
 
@@ -1588,6 +1904,13 @@ func (s *TransactionService) doCreateTransaction(sess *xorm.Session, transaction
 		return err
 	}
 
+	// Get and verify splits
+	err = s.isSplitsValid(sess, transaction, splits)
+
+	if err != nil {
+		return err
+	}
+
 	// Verify balance modification transaction and calculate real amount
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		otherTransactionExists, err := sess.Cols("uid", "deleted", "account_id").Where("uid=? AND deleted=? AND account_id=?", transaction.Uid, false, sourceAccount.AccountId).Limit(1).Exist(&models.Transaction{})
@@ -1691,6 +2014,20 @@ func (s *TransactionService) doCreateTransaction(sess *xorm.Session, transaction
 		}
 	}
 
+	// Insert transaction splits
+	if len(splits) > 0 {
+		for i := 0; i < len(splits); i++ {
+			split := splits[i]
+			split.TransactionTime = transaction.TransactionTime
+
+			_, err := sess.Insert(split)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	// Update account table
 	if transaction.Type == models.TRANSACTION_DB_TYPE_MODIFY_BALANCE {
 		sourceAccount.UpdatedUnixTime = time.Now().Unix()
@@ -2205,3 +2542,148 @@ func (s *TransactionService) isPicturesValid(sess *xorm.Session, transaction *mo
 
 	return nil
 }
+
+func (s *TransactionService) isSplitsValid(sess *xorm.Session, transaction *models.Transaction, splits []*models.TransactionSplit) error {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	if transaction.Type != models.TRANSACTION_DB_TYPE_INCOME && transaction.Type != models.TRANSACTION_DB_TYPE_EXPENSE {
+		return errs.ErrTransactionSplitsNotAllowed
+	}
+
+	if models.TransactionSplitSlice(splits).GetTotalAmount() != transaction.Amount {
+		return errs.ErrTransactionSplitsAmountNotEqual
+	}
+
+	var allSplitTagIds []int64
+
+	for i := 0; i < len(splits); i++ {
+		splitTransaction := &models.Transaction{
+			Uid:        transaction.Uid,
+			Type:       transaction.Type,
+			CategoryId: splits[i].CategoryId,
+		}
+
+		err := s.isCategoryValid(sess, splitTransaction)
+
+		if err != nil {
+			return err
+		}
+
+		allSplitTagIds = append(allSplitTagIds, splits[i].GetTagIds()...)
+	}
+
+	allSplitTagIds = utils.ToUniqueInt64Slice(allSplitTagIds)
+
+	if len(allSplitTagIds) > 0 {
+		var tags []*models.TransactionTag
+		err := sess.Where("uid=? AND deleted=?", transaction.Uid, false).In("tag_id", allSplitTagIds).Find(&tags)
+
+		if err != nil {
+			return err
+		}
+
+		for i := 0; i < len(tags); i++ {
+			if tags[i].Hidden {
+				return errs.ErrCannotUseHiddenTransactionTag
+			}
+		}
+
+		if len(tags) < len(allSplitTagIds) {
+			return errs.ErrTransactionTagNotFound
+		}
+	}
+
+	return nil
+}
+
+func (s *TransactionService) initTransactionSplits(transaction *models.Transaction, splits []*models.TransactionSplit, now int64) error {
+	if len(splits) < 1 {
+		return nil
+	}
+
+	splitUuids := s.GenerateUuids(uuid.UUID_TYPE_SPLIT, uint16(len(splits)))
+
+	if len(splitUuids) < len(splits) {
+		return errs.ErrSystemIsBusy
+	}
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		split.SplitId = splitUuids[i]
+		split.Uid = transaction.Uid
+		split.Deleted = false
+		split.TransactionId = transaction.TransactionId
+		split.DisplayOrder = int32(i + 1)
+		split.CreatedUnixTime = now
+		split.UpdatedUnixTime = now
+	}
+
+	return nil
+}
+
+func (s *TransactionService) getTransactionSplitsMapByTransactionTime(c core.Context, uid int64, minTransactionTime int64, maxTransactionTime int64) (map[int64][]*models.TransactionSplit, error) {
+	condition := "uid=? AND deleted=?"
+	conditionParams := make([]any, 0, 4)
+	conditionParams = append(conditionParams, uid)
+	conditionParams = append(conditionParams, false)
+
+	if minTransactionTime > 0 {
+		condition = condition + " AND transaction_time>=?"
+		conditionParams = append(conditionParams, minTransactionTime)
+	}
+
+	if maxTransactionTime > 0 {
+		condition = condition + " AND transaction_time<=?"
+		conditionParams = append(conditionParams, maxTransactionTime)
+	}
+
+	var splits []*models.TransactionSplit
+	err := s.UserDataDB(uid).NewSession(c).Select("transaction_id, category_id, amount").Where(condition, conditionParams...).Find(&splits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	allTransactionSplits := make(map[int64][]*models.TransactionSplit)
+
+	for i := 0; i < len(splits); i++ {
+		split := splits[i]
+		allTransactionSplits[split.TransactionId] = append(allTransactionSplits[split.TransactionId], split)
+	}
+
+	return allTransactionSplits, nil
+}
+
+func (s *TransactionService) getCategoryAmountsOfTransaction(transaction *models.Transaction, allTransactionSplits map[int64][]*models.TransactionSplit) []*models.TransactionSplit {
+	if splits, exists := allTransactionSplits[transaction.TransactionId]; exists && len(splits) > 0 {
+		return splits
+	}
+
+	return []*models.TransactionSplit{
+		{
+			CategoryId: transaction.CategoryId,
+			Amount:     transaction.Amount,
+		},
+	}
+}
+
+func (s *TransactionService) addTransactionTotalAmount(totalAmount *models.TransactionTotalAmount, transaction *models.Transaction, amount int64, exchangeRateConverter *ExchangeRateConverter) error {
+	totalAmount.Amount += amount
+
+	if exchangeRateConverter == nil || !totalAmount.HasAmountInDefaultCurrency {
+		return nil
+	}
+
+	amountInDefaultCurrency, ok, err := exchangeRateConverter.ConvertAccountAmount(transaction.AccountId, amount, utils.GetUnixTimeFromTransactionTime(transaction.TransactionTime))
+
+	if err != nil {
+		return err
+	}
+
+	totalAmount.AmountInDefaultCurrency += amountInDefaultCurrency
+	totalAmount.HasAmountInDefaultCurrency = ok
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+// UserCustomExchangeRateService represents user custom exchange rate service
+type UserCustomExchangeRateService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+}
+
+// Initialize a user custom exchange rate service singleton instance
+var (
+	UserCustomExchangeRates = &UserCustomExchangeRateService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+	}
+)
+
+// GetAllCustomExchangeRatesByUid returns all user custom exchange rate models of user
+func (s *UserCustomExchangeRateService) GetAllCustomExchangeRatesByUid(c core.Context, uid int64) ([]*models.UserCustomExchangeRate, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var customExchangeRates []*models.UserCustomExchangeRate
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).OrderBy("valid_from_date desc").Find(&customExchangeRates)
+
+	return customExchangeRates, err
+}
+
+// GetEffectiveCustomExchangeRatesByUid returns the latest user custom exchange rate model of each currency which is valid at the specified date
+func (s *UserCustomExchangeRateService) GetEffectiveCustomExchangeRatesByUid(c core.Context, uid int64, date string) ([]*models.UserCustomExchangeRate, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	var customExchangeRates []*models.UserCustomExchangeRate
+	err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=? AND valid_from_date<=?", uid, false, date).OrderBy("valid_from_date desc").Find(&customExchangeRates)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return models.GetEffectiveUserCustomExchangeRates(customExchangeRates, date), nil
+}
+
+// GetCustomExchangeRateByRateId returns a user custom exchange rate model according to rate id
+func (s *UserCustomExchangeRateService) GetCustomExchangeRateByRateId(c core.Context, uid int64, rateId int64) (*models.UserCustomExchangeRate, error) {
+	if uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	if rateId <= 0 {
+		return nil, errs.ErrUserCustomExchangeRateIdInvalid
+	}
+
+	customExchangeRate := &models.UserCustomExchangeRate{}
+	has, err := s.UserDataDB(uid).NewSession(c).ID(rateId).Where("uid=? AND deleted=?", uid, false).Get(customExchangeRate)
+
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errs.ErrUserCustomExchangeRateNotFound
+	}
+
+	return customExchangeRate, nil
+}
+
+// CreateCustomExchangeRate saves a new user custom exchange rate model to database
+func (s *UserCustomExchangeRateService) CreateCustomExchangeRate(c core.Context, customExchangeRate *models.UserCustomExchangeRate) error {
+	if customExchangeRate.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	customExchangeRate.RateId = s.GenerateUuid(uuid.UUID_TYPE_CUSTOM_EXCHANGE_RATE)
+
+	if customExchangeRate.RateId < 1 {
+		return errs.ErrSystemIsBusy
+	}
+
+	customExchangeRate.Deleted = false
+	customExchangeRate.CreatedUnixTime = time.Now().Unix()
+	customExchangeRate.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(customExchangeRate.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Insert(customExchangeRate)
+		return err
+	})
+}
+
+// ModifyCustomExchangeRate saves an existed user custom exchange rate model to database
+func (s *UserCustomExchangeRateService) ModifyCustomExchangeRate(c core.Context, customExchangeRate *models.UserCustomExchangeRate) error {
+	if customExchangeRate.Uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	customExchangeRate.UpdatedUnixTime = time.Now().Unix()
+
+	return s.UserDataDB(customExchangeRate.Uid).DoTransaction(c, func(sess *xorm.Session) error {
+		updatedRows, err := sess.ID(customExchangeRate.RateId).Cols("currency", "base_currency", "rate", "valid_from_date", "updated_unix_time").Where("uid=? AND deleted=?", customExchangeRate.Uid, false).Update(customExchangeRate)
+
+		if err != nil {
+			return err
+		} else if updatedRows < 1 {
+			return errs.ErrUserCustomExchangeRateNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteCustomExchangeRate deletes an existed user custom exchange rate from database
+func (s *UserCustomExchangeRateService) DeleteCustomExchangeRate(c core.Context, uid int64, rateId int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.UserCustomExchangeRate{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		deletedRows, err := sess.ID(rateId).Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		} else if deletedRows < 1 {
+			return errs.ErrUserCustomExchangeRateNotFound
+		}
+
+		return err
+	})
+}
+
+// DeleteAllCustomExchangeRates deletes all existed user custom exchange rates from database
+func (s *UserCustomExchangeRateService) DeleteAllCustomExchangeRates(c core.Context, uid int64) error {
+	if uid <= 0 {
+		return errs.ErrUserIdInvalid
+	}
+
+	now := time.Now().Unix()
+
+	updateModel := &models.UserCustomExchangeRate{
+		Deleted:         true,
+		DeletedUnixTime: now,
+	}
+
+	return s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		_, err := sess.Cols("deleted", "deleted_unix_time").Where("uid=? AND deleted=?", uid, false).Update(updateModel)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,728 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"xorm.io/xorm"
+
+	"github.com/mayswind/ezbookkeeping/pkg/core"
+	"github.com/mayswind/ezbookkeeping/pkg/datastore"
+	"github.com/mayswind/ezbookkeeping/pkg/errs"
+	"github.com/mayswind/ezbookkeeping/pkg/log"
+	"github.com/mayswind/ezbookkeeping/pkg/models"
+	"github.com/mayswind/ezbookkeeping/pkg/settings"
+	"github.com/mayswind/ezbookkeeping/pkg/storage"
+	"github.com/mayswind/ezbookkeeping/pkg/utils"
+	"github.com/mayswind/ezbookkeeping/pkg/uuid"
+)
+
+const maxUuidCountPerGeneration = 65535
+
+// UserDataBackupService represents user data backup service
+type UserDataBackupService struct {
+	ServiceUsingDB
+	ServiceUsingUuid
+	ServiceUsingStorage
+	ServiceUsingConfig
+}
+
+// Initialize a user data backup service singleton instance
+var (
+	UserDataBackups = &UserDataBackupService{
+		ServiceUsingDB: ServiceUsingDB{
+			container: datastore.Container,
+		},
+		ServiceUsingUuid: ServiceUsingUuid{
+			container: uuid.Container,
+		},
+		ServiceUsingStorage: ServiceUsingStorage{
+			container: storage.Container,
+		},
+		ServiceUsingConfig: ServiceUsingConfig{
+			container: settings.Container,
+		},
+	}
+)
+
+// userDataBackupVersionInfo represents the version info in the data file of user data backup archive
+type userDataBackupVersionInfo struct {
+	Version int32 `json:"version"`
+}
+
+// userDataBackupPictureObject represents a transaction picture read from user data backup archive
+type userDataBackupPictureObject struct {
+	*bytes.Reader
+}
+
+// Close does nothing because the picture data is in memory
+func (o *userDataBackupPictureObject) Close() error {
+	return nil
+}
+
+// GetUserDataBackup returns all data of specified user
+func (s *UserDataBackupService) GetUserDataBackup(c core.Context, user *models.User) (*models.UserDataBackup, error) {
+	if user.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	uid := user.Uid
+	backup := &models.UserDataBackup{
+		Version:          models.UserDataBackupCurrentVersion,
+		ExportedUnixTime: time.Now().Unix(),
+		Preferences:      models.NewUserDataBackupUserPreferences(user),
+	}
+
+	sess := s.UserDataDB(uid).NewSession(c)
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("parent_account_id asc, display_order asc").Find(&backup.Accounts); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("type asc, parent_category_id asc, display_order asc").Find(&backup.Categories); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.Tags); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("transaction_time asc").Find(&backup.Transactions); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).Find(&backup.TagIndexes); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).Find(&backup.Splits); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=? AND transaction_id<>?", uid, false, models.TransactionPictureNewPictureTransactionId).Find(&backup.Pictures); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("template_type asc, display_order asc").Find(&backup.Templates); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.Rules); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("display_order asc").Find(&backup.Budgets); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("valid_from_date asc").Find(&backup.CustomExchangeRates); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("name asc").Find(&backup.ImportProfiles); err != nil {
+		return nil, err
+	}
+
+	if err := sess.Where("uid=? AND deleted=?", uid, false).OrderBy("created_unix_time asc").Find(&backup.ImportBatches); err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// ExportUserDataBackupArchive returns the user data backup archive (a zip file contains all data in json and all transaction pictures) of specified user
+func (s *UserDataBackupService) ExportUserDataBackupArchive(c core.Context, user *models.User) ([]byte, error) {
+	backup, err := s.GetUserDataBackup(c, user)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(buffer)
+	exportedPictures := make([]*models.TransactionPictureInfo, 0, len(backup.Pictures))
+
+	for i := 0; i < len(backup.Pictures); i++ {
+		pictureInfo := backup.Pictures[i]
+		exists, err := s.ExistsTransactionPicture(pictureInfo.Uid, pictureInfo.PictureId, pictureInfo.PictureExtension)
+
+		if err != nil {
+			return nil, err
+		} else if !exists {
+			log.Warnf(c, "[user_data_backups.ExportUserDataBackupArchive] transaction picture \"id:%d\" does not exist in storage for user \"uid:%d\", skip it", pictureInfo.PictureId, user.Uid)
+			continue
+		}
+
+		pictureData, err := s.readTransactionPictureData(pictureInfo)
+
+		if err != nil {
+			return nil, err
+		}
+
+		pictureWriter, err := zipWriter.Create(getUserDataBackupPictureFileName(pictureInfo.PictureId, pictureInfo.PictureExtension))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = pictureWriter.Write(pictureData); err != nil {
+			return nil, err
+		}
+
+		exportedPictures = append(exportedPictures, pictureInfo)
+	}
+
+	backup.Pictures = exportedPictures
+	data, err := json.Marshal(models.NewUserDataBackupArchiveData(backup))
+
+	if err != nil {
+		return nil, err
+	}
+
+	dataWriter, err := zipWriter.Create(models.UserDataBackupDataFileName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = dataWriter.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err = zipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// RestoreUserDataBackupArchive restores all data in user data backup archive to specified user who does not have any data
+func (s *UserDataBackupService) RestoreUserDataBackupArchive(c core.Context, user *models.User, archiveData []byte) (*models.UserDataBackup, error) {
+	if user.Uid <= 0 {
+		return nil, errs.ErrUserIdInvalid
+	}
+
+	uid := user.Uid
+	backup, pictureFiles, err := s.parseUserDataBackupArchive(archiveData)
+
+	if err != nil {
+		return nil, err
+	}
+
+	empty, err := s.isUserDataEmpty(c, uid)
+
+	if err != nil {
+		return nil, err
+	} else if !empty {
+		return nil, errs.ErrUserDataRestoreTargetUserNotEmpty
+	}
+
+	restoredPictures := make([]*models.TransactionPictureInfo, 0, len(backup.Pictures))
+
+	for i := 0; i < len(backup.Pictures); i++ {
+		pictureInfo := backup.Pictures[i]
+
+		if _, exists := pictureFiles[getUserDataBackupPictureFileName(pictureInfo.PictureId, pictureInfo.PictureExtension)]; !exists {
+			log.Warnf(c, "[user_data_backups.RestoreUserDataBackupArchive] transaction picture \"id:%d\" does not exist in backup archive for user \"uid:%d\", skip it", pictureInfo.PictureId, uid)
+			continue
+		}
+
+		restoredPictures = append(restoredPictures, pictureInfo)
+	}
+
+	backup.Pictures = restoredPictures
+	originalPictureFileNames := make([]string, len(backup.Pictures))
+
+	for i := 0; i < len(backup.Pictures); i++ {
+		originalPictureFileNames[i] = getUserDataBackupPictureFileName(backup.Pictures[i].PictureId, backup.Pictures[i].PictureExtension)
+	}
+
+	mappings, err := s.generateIdMappings(backup)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = backup.ApplyIdMappings(uid, mappings); err != nil {
+		return nil, err
+	}
+
+	savedPictureCount := 0
+
+	for i := 0; i < len(backup.Pictures); i++ {
+		pictureInfo := backup.Pictures[i]
+		pictureObject := &userDataBackupPictureObject{
+			Reader: bytes.NewReader(pictureFiles[originalPictureFileNames[i]]),
+		}
+
+		if err = s.SaveTransactionPicture(uid, pictureInfo.PictureId, pictureObject, pictureInfo.PictureExtension); err != nil {
+			log.Errorf(c, "[user_data_backups.RestoreUserDataBackupArchive] failed to save transaction picture \"id:%d\" for user \"uid:%d\", because %s", pictureInfo.PictureId, uid, err.Error())
+			s.deleteRestoredTransactionPictures(c, backup.Pictures[:savedPictureCount])
+			return nil, err
+		}
+
+		savedPictureCount++
+	}
+
+	err = s.UserDataDB(uid).DoTransaction(c, func(sess *xorm.Session) error {
+		for i := 0; i < len(backup.Accounts); i++ {
+			if _, err := sess.Insert(backup.Accounts[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Categories); i++ {
+			if _, err := sess.Insert(backup.Categories[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Tags); i++ {
+			if _, err := sess.Insert(backup.Tags[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Transactions); i++ {
+			if _, err := sess.Insert(backup.Transactions[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.TagIndexes); i++ {
+			if _, err := sess.Insert(backup.TagIndexes[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Splits); i++ {
+			if _, err := sess.Insert(backup.Splits[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Pictures); i++ {
+			if _, err := sess.Insert(backup.Pictures[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Templates); i++ {
+			if _, err := sess.Insert(backup.Templates[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Rules); i++ {
+			if _, err := sess.Insert(backup.Rules[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.Budgets); i++ {
+			if _, err := sess.Insert(backup.Budgets[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.CustomExchangeRates); i++ {
+			if _, err := sess.Insert(backup.CustomExchangeRates[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.ImportProfiles); i++ {
+			if _, err := sess.Insert(backup.ImportProfiles[i]); err != nil {
+				return err
+			}
+		}
+
+		for i := 0; i < len(backup.ImportBatches); i++ {
+			if _, err := sess.Insert(backup.ImportBatches[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		s.deleteRestoredTransactionPictures(c, backup.Pictures)
+		return nil, err
+	}
+
+	if backup.Preferences != nil {
+		updateModel := &models.User{}
+		backup.Preferences.ApplyToUser(updateModel)
+		updateModel.UpdatedUnixTime = time.Now().Unix()
+
+		err = s.UserDB().DoTransaction(c, func(sess *xorm.Session) error {
+			_, err := sess.ID(uid).Cols("default_account_id", "transaction_edit_scope", "language", "default_currency", "first_day_of_week", "long_date_format", "short_date_format", "long_time_format", "short_time_format", "decimal_separator", "digit_grouping_symbol", "digit_grouping", "currency_display_type", "expense_amount_color", "income_amount_color", "updated_unix_time").Where("deleted=?", false).Update(updateModel)
+			return err
+		})
+
+		if err != nil {
+			log.Errorf(c, "[user_data_backups.RestoreUserDataBackupArchive] failed to restore preferences for user \"uid:%d\", because %s", uid, err.Error())
+			return nil, err
+		}
+	}
+
+	return backup, nil
+}
+
+func (s *UserDataBackupService) deleteRestoredTransactionPictures(c core.Context, pictureInfos []*models.TransactionPictureInfo) {
+	for i := 0; i < len(pictureInfos); i++ {
+		pictureInfo := pictureInfos[i]
+
+		if err := s.DeleteTransactionPicture(pictureInfo.Uid, pictureInfo.PictureId, pictureInfo.PictureExtension); err != nil {
+			log.Warnf(c, "[user_data_backups.deleteRestoredTransactionPictures] failed to delete restored transaction picture \"id:%d\" for user \"uid:%d\", because %s", pictureInfo.PictureId, pictureInfo.Uid, err.Error())
+		}
+	}
+}
+
+func (s *UserDataBackupService) readTransactionPictureData(pictureInfo *models.TransactionPictureInfo) ([]byte, error) {
+	pictureFile, err := s.ReadTransactionPicture(pictureInfo.Uid, pictureInfo.PictureId, pictureInfo.PictureExtension)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer pictureFile.Close()
+
+	return io.ReadAll(pictureFile)
+}
+
+func (s *UserDataBackupService) parseUserDataBackupArchive(archiveData []byte) (*models.UserDataBackup, map[string][]byte, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(archiveData), int64(len(archiveData)))
+
+	if err != nil {
+		return nil, nil, errs.ErrUserDataBackupFileInvalid
+	}
+
+	var dataFile *zip.File
+	pictureZipFiles := make(map[string]*zip.File)
+
+	for i := 0; i < len(zipReader.File); i++ {
+		file := zipReader.File[i]
+
+		if strings.Contains(file.Name, "..") || strings.Count(file.Name, "/") > 1 {
+			return nil, nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		if file.Name == models.UserDataBackupDataFileName {
+			dataFile = file
+		} else if strings.HasPrefix(file.Name, models.UserDataBackupPictureDirectory) && len(file.Name) > len(models.UserDataBackupPictureDirectory) {
+			pictureZipFiles[file.Name] = file
+		}
+	}
+
+	if dataFile == nil {
+		return nil, nil, errs.ErrUserDataBackupFileInvalid
+	}
+
+	remainingSize := int64(s.CurrentConfig().MaxUserDataBackupRestoreSize)
+	fileData, err := s.readZipFileData(dataFile, remainingSize)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remainingSize -= int64(len(fileData))
+	backup, err := s.parseUserDataBackupDataFile(fileData)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pictureFiles := make(map[string][]byte, len(backup.Pictures))
+
+	for i := 0; i < len(backup.Pictures); i++ {
+		pictureInfo := backup.Pictures[i]
+
+		if utils.GetImageContentType(pictureInfo.PictureExtension) == "" {
+			return nil, nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		pictureFileName := getUserDataBackupPictureFileName(pictureInfo.PictureId, pictureInfo.PictureExtension)
+		pictureZipFile, exists := pictureZipFiles[pictureFileName]
+
+		if !exists {
+			continue
+		}
+
+		if _, read := pictureFiles[pictureFileName]; read {
+			continue
+		}
+
+		pictureData, err := s.readZipFileData(pictureZipFile, remainingSize)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		remainingSize -= int64(len(pictureData))
+		pictureFiles[pictureFileName] = pictureData
+	}
+
+	return backup, pictureFiles, nil
+}
+
+func (s *UserDataBackupService) parseUserDataBackupDataFile(fileData []byte) (*models.UserDataBackup, error) {
+	versionInfo := &userDataBackupVersionInfo{}
+
+	if err := json.Unmarshal(fileData, versionInfo); err != nil {
+		return nil, errs.ErrUserDataBackupFileInvalid
+	}
+
+	if versionInfo.Version < models.UserDataBackupRawModelVersion || versionInfo.Version > models.UserDataBackupCurrentVersion {
+		return nil, errs.ErrUserDataBackupVersionNotSupported
+	}
+
+	if versionInfo.Version == models.UserDataBackupRawModelVersion {
+		backup := &models.UserDataBackup{}
+
+		if err := json.Unmarshal(fileData, backup); err != nil {
+			return nil, errs.ErrUserDataBackupFileInvalid
+		}
+
+		return backup, nil
+	}
+
+	archiveData := &models.UserDataBackupArchiveData{}
+
+	if err := json.Unmarshal(fileData, archiveData); err != nil {
+		return nil, errs.ErrUserDataBackupFileInvalid
+	}
+
+	return archiveData.ToUserDataBackup()
+}
+
+func (s *UserDataBackupService) readZipFileData(file *zip.File, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 || file.UncompressedSize64 > uint64(maxSize) {
+		return nil, errs.ErrUserDataBackupFileTooLarge
+	}
+
+	fileReader, err := file.Open()
+
+	if err != nil {
+		return nil, errs.ErrUserDataBackupFileInvalid
+	}
+
+	defer fileReader.Close()
+
+	// the declared uncompressed size in zip header cannot be trusted, so read at most one byte more than the limit to detect oversized data
+	data, err := io.ReadAll(io.LimitReader(fileReader, maxSize+1))
+
+	if err != nil {
+		return nil, errs.ErrUserDataBackupFileInvalid
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, errs.ErrUserDataBackupFileTooLarge
+	}
+
+	return data, nil
+}
+
+func (s *UserDataBackupService) isUserDataEmpty(c core.Context, uid int64) (bool, error) {
+	tables := []any{
+		&models.Account{},
+		&models.TransactionCategory{},
+		&models.TransactionTag{},
+		&models.Transaction{},
+		&models.TransactionTemplate{},
+		&models.TransactionRule{},
+		&models.Budget{},
+		&models.UserCustomExchangeRate{},
+		&models.TransactionImportProfile{},
+		&models.TransactionImportBatch{},
+	}
+
+	for i := 0; i < len(tables); i++ {
+		count, err := s.UserDataDB(uid).NewSession(c).Where("uid=? AND deleted=?", uid, false).Count(tables[i])
+
+		if err != nil {
+			return false, err
+		} else if count > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (s *UserDataBackupService) generateIdMappings(backup *models.UserDataBackup) (*models.UserDataBackupIdMappings, error) {
+	var err error
+	mappings := &models.UserDataBackupIdMappings{}
+
+	accountIds := make([]int64, len(backup.Accounts))
+
+	for i := 0; i < len(backup.Accounts); i++ {
+		accountIds[i] = backup.Accounts[i].AccountId
+	}
+
+	if mappings.Accounts, err = s.generateIdMapping(uuid.UUID_TYPE_ACCOUNT, accountIds); err != nil {
+		return nil, err
+	}
+
+	categoryIds := make([]int64, len(backup.Categories))
+
+	for i := 0; i < len(backup.Categories); i++ {
+		categoryIds[i] = backup.Categories[i].CategoryId
+	}
+
+	if mappings.Categories, err = s.generateIdMapping(uuid.UUID_TYPE_CATEGORY, categoryIds); err != nil {
+		return nil, err
+	}
+
+	tagIds := make([]int64, len(backup.Tags))
+
+	for i := 0; i < len(backup.Tags); i++ {
+		tagIds[i] = backup.Tags[i].TagId
+	}
+
+	if mappings.Tags, err = s.generateIdMapping(uuid.UUID_TYPE_TAG, tagIds); err != nil {
+		return nil, err
+	}
+
+	transactionIds := make([]int64, len(backup.Transactions))
+
+	for i := 0; i < len(backup.Transactions); i++ {
+		transactionIds[i] = backup.Transactions[i].TransactionId
+	}
+
+	if mappings.Transactions, err = s.generateIdMapping(uuid.UUID_TYPE_TRANSACTION, transactionIds); err != nil {
+		return nil, err
+	}
+
+	tagIndexIds := make([]int64, len(backup.TagIndexes))
+
+	for i := 0; i < len(backup.TagIndexes); i++ {
+		tagIndexIds[i] = backup.TagIndexes[i].TagIndexId
+	}
+
+	if mappings.TagIndexes, err = s.generateIdMapping(uuid.UUID_TYPE_TAG_INDEX, tagIndexIds); err != nil {
+		return nil, err
+	}
+
+	splitIds := make([]int64, len(backup.Splits))
+
+	for i := 0; i < len(backup.Splits); i++ {
+		splitIds[i] = backup.Splits[i].SplitId
+	}
+
+	if mappings.Splits, err = s.generateIdMapping(uuid.UUID_TYPE_SPLIT, splitIds); err != nil {
+		return nil, err
+	}
+
+	pictureIds := make([]int64, len(backup.Pictures))
+
+	for i := 0; i < len(backup.Pictures); i++ {
+		pictureIds[i] = backup.Pictures[i].PictureId
+	}
+
+	if mappings.Pictures, err = s.generateIdMapping(uuid.UUID_TYPE_PICTURE, pictureIds); err != nil {
+		return nil, err
+	}
+
+	templateIds := make([]int64, len(backup.Templates))
+
+	for i := 0; i < len(backup.Templates); i++ {
+		templateIds[i] = backup.Templates[i].TemplateId
+	}
+
+	if mappings.Templates, err = s.generateIdMapping(uuid.UUID_TYPE_TEMPLATE, templateIds); err != nil {
+		return nil, err
+	}
+
+	ruleIds := make([]int64, len(backup.Rules))
+
+	for i := 0; i < len(backup.Rules); i++ {
+		ruleIds[i] = backup.Rules[i].RuleId
+	}
+
+	if mappings.Rules, err = s.generateIdMapping(uuid.UUID_TYPE_RULE, ruleIds); err != nil {
+		return nil, err
+	}
+
+	budgetIds := make([]int64, len(backup.Budgets))
+
+	for i := 0; i < len(backup.Budgets); i++ {
+		budgetIds[i] = backup.Budgets[i].BudgetId
+	}
+
+	if mappings.Budgets, err = s.generateIdMapping(uuid.UUID_TYPE_BUDGET, budgetIds); err != nil {
+		return nil, err
+	}
+
+	rateIds := make([]int64, len(backup.CustomExchangeRates))
+
+	for i := 0; i < len(backup.CustomExchangeRates); i++ {
+		rateIds[i] = backup.CustomExchangeRates[i].RateId
+	}
+
+	if mappings.CustomExchangeRates, err = s.generateIdMapping(uuid.UUID_TYPE_CUSTOM_EXCHANGE_RATE, rateIds); err != nil {
+		return nil, err
+	}
+
+	profileIds := make([]int64, len(backup.ImportProfiles))
+
+	for i := 0; i < len(backup.ImportProfiles); i++ {
+		profileIds[i] = backup.ImportProfiles[i].ProfileId
+	}
+
+	if mappings.ImportProfiles, err = s.generateIdMapping(uuid.UUID_TYPE_IMPORT_PROFILE, profileIds); err != nil {
+		return nil, err
+	}
+
+	batchIds := make([]int64, len(backup.ImportBatches))
+
+	for i := 0; i < len(backup.ImportBatches); i++ {
+		batchIds[i] = backup.ImportBatches[i].BatchId
+	}
+
+	if mappings.ImportBatches, err = s.generateIdMapping(uuid.UUID_TYPE_IMPORT_BATCH, batchIds); err != nil {
+		return nil, err
+	}
+
+	return mappings, nil
+}
+
+func (s *UserDataBackupService) generateIdMapping(uuidType uuid.UuidType, originalIds []int64) (map[int64]int64, error) {
+	mapping := make(map[int64]int64, len(originalIds))
+
+	for i := 0; i < len(originalIds); i += maxUuidCountPerGeneration {
+		count := len(originalIds) - i
+
+		if count > maxUuidCountPerGeneration {
+			count = maxUuidCountPerGeneration
+		}
+
+		newIds := s.GenerateUuids(uuidType, uint16(count))
+
+		if len(newIds) < count {
+			return nil, errs.ErrSystemIsBusy
+		}
+
+		for j := 0; j < count; j++ {
+			originalId := originalIds[i+j]
+
+			if _, exists := mapping[originalId]; exists || originalId <= 0 {
+				return nil, errs.ErrUserDataBackupFileInvalid
+			}
+
+			mapping[originalId] = newIds[j]
+		}
+	}
+
+	return mapping, nil
+}
+
+func getUserDataBackupPictureFileName(pictureId int64, pictureExtension string) string {
+	return fmt.Sprintf("%s%d.%s", models.UserDataBackupPictureDirectory, pictureId, pictureExtension)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -148,9 +149,13 @@ const (
 	defaultTransactionPictureFileMaxSize uint32 = 10485760 // 10MB
 	defaultUserAvatarFileMaxSize         uint32 = 1048576  // 1MB
 
-	defaultImportFileMaxSize uint32 = 10485760 // 10MB
+	defaultImportFileMaxSize    uint32 = 10485760  // 10MB
+	defaultImportJobFileMaxSize uint32 = 104857600 // 100MB
+
+	defaultUserDataBackupRestoreMaxSize uint32 = 1073741824 // 1GB
 
 	defaultExchangeRatesDataRequestTimeout uint32 = 10000 // 10 seconds
+	defaultExchangeRatesCacheExpiration    uint32 = 3600  // 60 minutes
 )
 
 // DatabaseConfig represents the database setting config
@@ -272,6 +277,7 @@ type Config struct {
 	// Cron
 	EnableRemoveExpiredTokens        bool
 	EnableCreateScheduledTransaction bool
+	EnableUpdateExchangeRates        bool
 
 	// Secret
 	SecretKeyNoSet                        bool
@@ -302,9 +308,12 @@ type Config struct {
 	DefaultFeatureRestrictions       core.UserFeatureRestrictions
 
 	// Data
-	EnableDataExport  bool
-	EnableDataImport  bool
-	MaxImportFileSize uint32
+	EnableDataExport     bool
+	EnableDataImport     bool
+	MaxImportFileSize    uint32
+	MaxImportJobFileSize uint32
+
+	MaxUserDataBackupRestoreSize uint32
 
 	// Tip
 	LoginPageTips TipConfig
@@ -334,10 +343,12 @@ type Config struct {
 
 	// Exchange Rates
 	ExchangeRatesDataSource                       string
+	ExchangeRatesDataSources                      []string
 	ExchangeRatesRequestTimeout                   uint32
 	ExchangeRatesRequestTimeoutExceedDefaultValue bool
 	ExchangeRatesProxy                            string
 	ExchangeRatesSkipTLSVerify                    bool
+	ExchangeRatesCacheExpiration                  uint32
 }
 
 // LoadConfiguration loads setting config from given config file path
@@ -721,6 +732,7 @@ func loadDuplicateCheckerConfiguration(config *Config, configFile *ini.File, sec
 func loadCronConfiguration(config *Config, configFile *ini.File, sectionName string) error {
 	config.EnableRemoveExpiredTokens = getConfigItemBoolValue(configFile, sectionName, "enable_remove_expired_tokens", false)
 	config.EnableCreateScheduledTransaction = getConfigItemBoolValue(configFile, sectionName, "enable_create_scheduled_transaction", false)
+	config.EnableUpdateExchangeRates = getConfigItemBoolValue(configFile, sectionName, "enable_update_exchange_rates", false)
 
 	return nil
 }
@@ -803,6 +815,8 @@ func loadDataConfiguration(config *Config, configFile *ini.File, sectionName str
 	config.EnableDataExport = getConfigItemBoolValue(configFile, sectionName, "enable_export", false)
 	config.EnableDataImport = getConfigItemBoolValue(configFile, sectionName, "enable_import", false)
 	config.MaxImportFileSize = getConfigItemUint32Value(configFile, sectionName, "max_import_file_size", defaultImportFileMaxSize)
+	config.MaxImportJobFileSize = getConfigItemUint32Value(configFile, sectionName, "max_import_job_file_size", defaultImportJobFileMaxSize)
+	config.MaxUserDataBackupRestoreSize = getConfigItemUint32Value(configFile, sectionName, "max_user_data_backup_restore_size", defaultUserDataBackupRestoreMaxSize)
 
 	return nil
 }
@@ -886,9 +900,46 @@ func loadMapConfiguration(config *Config, configFile *ini.File, sectionName stri
 	return nil
 }
 func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectionName string) error {
-	dataSource := getConfigItemStringValue(configFile, sectionName, "data_source")
+	dataSources := strings.Split(getConfigItemStringValue(configFile, sectionName, "data_source"), ",")
+	config.ExchangeRatesDataSources = make([]string, 0, len(dataSources))
+
+	for i := 0; i < len(dataSources); i++ {
+		dataSource := strings.TrimSpace(dataSources[i])
+
+		if dataSource == "" && len(dataSources) > 1 {
+			continue
+		}
+
+		if !isValidExchangeRatesDataSource(dataSource) {
+			return errs.ErrInvalidExchangeRatesDataSource
+		}
+
+		if !slices.Contains(config.ExchangeRatesDataSources, dataSource) {
+			config.ExchangeRatesDataSources = append(config.ExchangeRatesDataSources, dataSource)
+		}
+	}
+
+	if len(config.ExchangeRatesDataSources) < 1 {
+		return errs.ErrInvalidExchangeRatesDataSource
+	}
+
+	config.ExchangeRatesDataSource = config.ExchangeRatesDataSources[0]
+
+	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
+	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
+
+	if config.ExchangeRatesRequestTimeout > defaultExchangeRatesDataRequestTimeout {
+		config.ExchangeRatesRequestTimeoutExceedDefaultValue = true
+	}
+
+	config.ExchangeRatesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
+	config.ExchangeRatesCacheExpiration = getConfigItemUint32Value(configFile, sectionName, "cache_expiration", defaultExchangeRatesCacheExpiration)
+
+	return nil
+}
 
-	if dataSource == ReserveBankOfAustraliaDataSource ||
+func isValidExchangeRatesDataSource(dataSource string) bool {
+	return dataSource == ReserveBankOfAustraliaDataSource ||
 		dataSource == BankOfCanadaDataSource ||
 		dataSource == CzechNationalBankDataSource ||
 		dataSource == DanmarksNationalbankDataSource ||
@@ -903,22 +954,7 @@ func loadExchangeRatesConfiguration(config *Config, configFile *ini.File, sectio
 		dataSource == BankOfRussiaDataSource ||
 		dataSource == SwissNationalBankDataSource ||
 		dataSource == CentralBankOfUzbekistanDataSource ||
-		dataSource == InternationalMonetaryFundDataSource {
-		config.ExchangeRatesDataSource = dataSource
-	} else {
-		return errs.ErrInvalidExchangeRatesDataSource
-	}
-
-	config.ExchangeRatesProxy = getConfigItemStringValue(configFile, sectionName, "proxy", "system")
-	config.ExchangeRatesRequestTimeout = getConfigItemUint32Value(configFile, sectionName, "request_timeout", defaultExchangeRatesDataRequestTimeout)
-
-	if config.ExchangeRatesRequestTimeout > defaultExchangeRatesDataRequestTimeout {
-		config.ExchangeRatesRequestTimeoutExceedDefaultValue = true
-	}
-
-	config.ExchangeRatesSkipTLSVerify = getConfigItemBoolValue(configFile, sectionName, "skip_tls_verify", false)
-
-	return nil
+		dataSource == InternationalMonetaryFundDataSource
 }
 
 func getWorkingPath() (string, error) {
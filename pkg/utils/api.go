@@ -28,6 +28,18 @@ func PrintDataSuccessResult(c *core.WebContext, contentType string, fileName str
 	c.Data(http.StatusOK, contentType, result)
 }
 
+// PrintDataStreamSuccessResult writes success response in custom content type to current http context by the data writer function
+func PrintDataStreamSuccessResult(c *core.WebContext, contentType string, fileName string, writer core.DataStreamWriterFunc) error {
+	if fileName != "" {
+		c.Header("Content-Disposition", "attachment;filename="+fileName)
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Status(http.StatusOK)
+
+	return writer(c.Writer)
+}
+
 // PrintJsonErrorResult writes error response in json format to current http context
 func PrintJsonErrorResult(c *core.WebContext, err *errs.Error) {
 	c.SetResponseError(err)
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+const textEncodingDetectionMaxSampleSize = 64 * 1024
+
+var utf8ByteOrderMark = []byte{0xEF, 0xBB, 0xBF}
+var utf16LittleEndianByteOrderMark = []byte{0xFF, 0xFE}
+var utf16BigEndianByteOrderMark = []byte{0xFE, 0xFF}
+
+// GetTextEncodingByName returns the text encoding according to the specified name (e.g. utf-8, utf-16le, windows-1252, iso-8859-15, gb18030), or returns nil if the name is not supported
+func GetTextEncodingByName(name string) encoding.Encoding {
+	name = strings.TrimSpace(name)
+
+	if name == "" {
+		return nil
+	}
+
+	enc, err := htmlindex.Get(name)
+
+	if err != nil {
+		return nil
+	}
+
+	return enc
+}
+
+// DetectTextEncoding returns the text encoding of the specified data, which is detected by the byte order mark at first and then by heuristics
+func DetectTextEncoding(data []byte) encoding.Encoding {
+	if bytes.HasPrefix(data, utf8ByteOrderMark) {
+		return unicode.UTF8BOM
+	} else if bytes.HasPrefix(data, utf16LittleEndianByteOrderMark) {
+		return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+	} else if bytes.HasPrefix(data, utf16BigEndianByteOrderMark) {
+		return unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+	}
+
+	sample := data
+
+	if len(sample) > textEncodingDetectionMaxSampleSize {
+		sample = sample[:textEncodingDetectionMaxSampleSize]
+	}
+
+	if enc := detectUtf16TextEncodingWithoutByteOrderMark(sample); enc != nil {
+		return enc
+	}
+
+	if isValidUtf8Sample(sample, len(sample) < len(data)) {
+		return unicode.UTF8
+	}
+
+	singleHighByteCount := 0
+	doubleHighByteCount := 0
+
+	for i := 0; i < len(sample); i++ {
+		if sample[i] < 0x80 {
+			continue
+		}
+
+		// the chinese characters in gb18030 are mostly composed of two high bytes, but the accented latin characters in single byte encodings are mostly followed by ascii characters
+		if i+1 < len(sample) && sample[i] >= 0x81 && sample[i] <= 0xFE && sample[i+1] >= 0x80 {
+			doubleHighByteCount++
+			i++
+		} else {
+			singleHighByteCount++
+		}
+	}
+
+	if doubleHighByteCount > singleHighByteCount {
+		return simplifiedchinese.GB18030
+	}
+
+	return charmap.Windows1252
+}
+
+// DecodeTextData returns the utf-8 text data without byte order mark which is decoded from the specified data by the specified text encoding, the text encoding is detected automatically if it is nil
+func DecodeTextData(data []byte, enc encoding.Encoding) ([]byte, error) {
+	if enc == nil {
+		enc = DetectTextEncoding(data)
+	}
+
+	// remove the byte order mark if it exists, whether the text encoding is specified or detected
+	decoder := unicode.BOMOverride(enc.NewDecoder())
+	result, _, err := transform.Bytes(decoder, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimPrefix(result, utf8ByteOrderMark), nil
+}
+
+func detectUtf16TextEncodingWithoutByteOrderMark(sample []byte) encoding.Encoding {
+	if len(sample) < 2 {
+		return nil
+	}
+
+	evenZeroByteCount := 0
+	oddZeroByteCount := 0
+
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			evenZeroByteCount++
+		}
+
+		if sample[i+1] == 0 {
+			oddZeroByteCount++
+		}
+	}
+
+	charCount := len(sample) / 2
+
+	// the high byte of ascii characters in utf-16 is zero, and plain text files rarely contain any zero byte
+	if oddZeroByteCount*10 >= charCount*3 && evenZeroByteCount*10 < charCount {
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	} else if evenZeroByteCount*10 >= charCount*3 && oddZeroByteCount*10 < charCount {
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	}
+
+	return nil
+}
+
+func isValidUtf8Sample(sample []byte, truncated bool) bool {
+	if utf8.Valid(sample) {
+		return true
+	}
+
+	if !truncated {
+		return false
+	}
+
+	// the last character may be truncated when the sample is not the whole data
+	for i := 1; i < utf8.UTFMax && i < len(sample); i++ {
+		if utf8.Valid(sample[:len(sample)-i]) {
+			return true
+		}
+	}
+
+	return false
+}
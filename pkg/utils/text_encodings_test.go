@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestGetTextEncodingByName(t *testing.T) {
+	assert.Equal(t, unicode.UTF8, GetTextEncodingByName("utf-8"))
+	assert.Equal(t, unicode.UTF8, GetTextEncodingByName("UTF8"))
+	assert.Equal(t, charmap.Windows1252, GetTextEncodingByName("windows-1252"))
+	assert.Equal(t, charmap.ISO8859_15, GetTextEncodingByName("iso-8859-15"))
+	assert.Equal(t, simplifiedchinese.GB18030, GetTextEncodingByName("gb18030"))
+	assert.NotNil(t, GetTextEncodingByName("utf-16le"))
+	assert.NotNil(t, GetTextEncodingByName("utf-16be"))
+}
+
+func TestGetTextEncodingByName_InvalidName(t *testing.T) {
+	assert.Nil(t, GetTextEncodingByName(""))
+	assert.Nil(t, GetTextEncodingByName("foo"))
+}
+
+func TestDetectTextEncoding_ByteOrderMark(t *testing.T) {
+	assert.Equal(t, unicode.UTF8BOM, DetectTextEncoding([]byte{0xEF, 0xBB, 0xBF, 'a', 'b'}))
+	assert.Equal(t, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM), DetectTextEncoding([]byte{0xFF, 0xFE, 'a', 0x00}))
+	assert.Equal(t, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM), DetectTextEncoding([]byte{0xFE, 0xFF, 0x00, 'a'}))
+}
+
+func TestDetectTextEncoding_Utf16WithoutByteOrderMark(t *testing.T) {
+	assert.Equal(t, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), DetectTextEncoding([]byte{'a', 0x00, ',', 0x00, 'b', 0x00, '\n', 0x00}))
+	assert.Equal(t, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), DetectTextEncoding([]byte{0x00, 'a', 0x00, ',', 0x00, 'b', 0x00, '\n'}))
+}
+
+func TestDetectTextEncoding_Heuristics(t *testing.T) {
+	assert.Equal(t, unicode.UTF8, DetectTextEncoding([]byte("Time,Description\n2024-09-01,Café")))
+
+	windows1252Data, _ := charmap.Windows1252.NewEncoder().Bytes([]byte("Time,Description\n2024-09-01,Café Crème"))
+	assert.Equal(t, charmap.Windows1252, DetectTextEncoding(windows1252Data))
+
+	gb18030Data, _ := simplifiedchinese.GB18030.NewEncoder().Bytes([]byte("交易时间,金额\n2024-09-01,100"))
+	assert.Equal(t, simplifiedchinese.GB18030, DetectTextEncoding(gb18030Data))
+}
+
+func TestDecodeTextData(t *testing.T) {
+	actualValue, err := DecodeTextData([]byte{0xEF, 0xBB, 0xBF, 'a', ',', 'b'}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "a,b", string(actualValue))
+
+	actualValue, err = DecodeTextData([]byte{0xFF, 0xFE, 'a', 0x00, ',', 0x00, 0xE9, 0x00}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "a,é", string(actualValue))
+
+	actualValue, err = DecodeTextData([]byte{'C', 'a', 'f', 0xE9}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "Café", string(actualValue))
+
+	actualValue, err = DecodeTextData([]byte{'1', '0', ' ', 0xA4}, charmap.ISO8859_15)
+	assert.Nil(t, err)
+	assert.Equal(t, "10 €", string(actualValue))
+
+	actualValue, err = DecodeTextData([]byte{0xEF, 0xBB, 0xBF, 'a', ',', 'b'}, charmap.Windows1252)
+	assert.Nil(t, err)
+	assert.Equal(t, "a,b", string(actualValue))
+}
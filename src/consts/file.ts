@@ -21,7 +21,7 @@ export const SUPPORTED_IMPORT_FILE_TYPES: ImportFileType[] = [
     {
         type: 'ezbookkeeping',
         name: 'ezbookkeeping Data Export File',
-        extensions: '.csv,.tsv',
+        extensions: '.csv,.tsv,.xlsx',
         subTypes: [
             {
                 type: 'ezbookkeeping_csv',
@@ -32,6 +32,11 @@ export const SUPPORTED_IMPORT_FILE_TYPES: ImportFileType[] = [
                 type: 'ezbookkeeping_tsv',
                 name: 'TSV (Tab-separated values) File',
                 extensions: '.tsv',
+            },
+            {
+                type: 'ezbookkeeping_xlsx',
+                name: 'Microsoft Excel (XLSX) File',
+                extensions: '.xlsx',
             }
         ],
         document: {
@@ -49,6 +54,26 @@ export const SUPPORTED_IMPORT_FILE_TYPES: ImportFileType[] = [
         name: 'Quicken Financial Exchange (QFX) File',
         extensions: '.qfx'
     },
+    {
+        type: 'camt',
+        name: 'ISO 20022 Bank Statement (CAMT) File',
+        extensions: '.xml',
+        subTypes: [
+            {
+                type: 'camt053',
+                name: 'Bank to Customer Statement (camt.053)',
+            },
+            {
+                type: 'camt052',
+                name: 'Bank to Customer Account Report (camt.052)',
+            }
+        ]
+    },
+    {
+        type: 'mt940',
+        name: 'SWIFT MT940 Statement File',
+        extensions: '.sta,.mt940,.txt'
+    },
     {
         type: 'qif',
         name: 'Quicken Interchange Format (QIF) File',
@@ -82,6 +107,16 @@ export const SUPPORTED_IMPORT_FILE_TYPES: ImportFileType[] = [
             anchor: 'how-to-get-gnucash-xml-database-file'
         }
     },
+    {
+        type: 'beancount',
+        name: 'Beancount Data File',
+        extensions: '.beancount,.bean'
+    },
+    {
+        type: 'ledger',
+        name: 'Ledger / hledger Journal File',
+        extensions: '.ledger,.journal,.hledger,.dat'
+    },
     {
         type: 'firefly_iii_csv',
         name: 'Firefly III Data Export File',
@@ -91,6 +126,30 @@ export const SUPPORTED_IMPORT_FILE_TYPES: ImportFileType[] = [
             anchor: 'how-to-get-firefly-iii-data-export-file'
         }
     },
+    {
+        type: 'ynab_csv',
+        name: 'YNAB Register Export File',
+        extensions: '.csv',
+        subTypes: [
+            {
+                type: 'ynab_csv_mdy',
+                name: 'Month-day-year format',
+            },
+            {
+                type: 'ynab_csv_dmy',
+                name: 'Day-month-year format',
+            },
+            {
+                type: 'ynab_csv_ymd',
+                name: 'Year-month-day format',
+            }
+        ]
+    },
+    {
+        type: 'actual_budget_csv',
+        name: 'Actual Budget Transaction Export File',
+        extensions: '.csv'
+    },
     {
         type: 'feidee_mymoney_csv',
         name: 'Feidee MyMoney (App) Data Export File',
@@ -137,3 +196,23 @@ export const SUPPORTED_IMPORT_FILE_TYPES: ImportFileType[] = [
         }
     }
 ];
+
+export interface ImportFileEncoding {
+    readonly encoding: string;
+    readonly name: string;
+}
+
+export const SUPPORTED_IMPORT_FILE_ENCODINGS: ImportFileEncoding[] = [
+    { encoding: '', name: 'Auto Detect' },
+    { encoding: 'utf-8', name: 'UTF-8' },
+    { encoding: 'utf-16le', name: 'UTF-16LE' },
+    { encoding: 'utf-16be', name: 'UTF-16BE' },
+    { encoding: 'windows-1252', name: 'Windows-1252' },
+    { encoding: 'iso-8859-15', name: 'ISO-8859-15' },
+    { encoding: 'windows-1250', name: 'Windows-1250' },
+    { encoding: 'windows-1251', name: 'Windows-1251' },
+    { encoding: 'gb18030', name: 'GB18030' },
+    { encoding: 'big5', name: 'Big5' },
+    { encoding: 'shift_jis', name: 'Shift_JIS' },
+    { encoding: 'euc-kr', name: 'EUC-KR' }
+];
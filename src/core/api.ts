@@ -8,4 +8,5 @@ export interface ErrorResponse {
     readonly errorCode: number;
     readonly errorMessage: string;
     readonly path: string;
+    readonly context?: Record<string, unknown>;
 }
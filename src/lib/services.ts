@@ -29,6 +29,7 @@ import type {
 } from '@/models/auth_response.ts';
 import type {
     ClearDataRequest,
+    ExportDataRequest,
     DataStatisticsResponse
 } from '@/models/data_management.ts';
 import type {
@@ -38,7 +39,11 @@ import type {
     ForgetPasswordRequest
 } from '@/models/forget_password.ts';
 import type {
-    ImportTransactionResponsePageWrapper
+    ImportTransactionResponsePageWrapper,
+    TransactionImportBatchRevertRequest,
+    TransactionImportBatchInfoResponse,
+    TransactionImportBatchRevertResponse,
+    TransactionImportJobInfoResponse
 } from '@/models/imported_transaction.ts';
 import type {
     TransactionCreateRequest,
@@ -316,11 +321,67 @@ export default {
     getUserDataStatistics: (): ApiResponsePromise<DataStatisticsResponse> => {
         return axios.get<ApiResponse<DataStatisticsResponse>>('v1/data/statistics.json');
     },
-    getExportedUserData: (fileType: string): Promise<AxiosResponse<BlobPart>> => {
+    getExportedUserData: (fileType: string, req?: ExportDataRequest): Promise<AxiosResponse<BlobPart>> => {
+        const queryParams = [];
+
+        if (req?.startTime) {
+            queryParams.push(`startTime=${req.startTime}`);
+        }
+
+        if (req?.endTime) {
+            queryParams.push(`endTime=${req.endTime}`);
+        }
+
+        if (req?.type) {
+            queryParams.push(`type=${req.type}`);
+        }
+
+        if (req?.categoryIds) {
+            queryParams.push(`category_ids=${req.categoryIds}`);
+        }
+
+        if (req?.accountIds) {
+            queryParams.push(`account_ids=${req.accountIds}`);
+        }
+
+        if (req?.tagIds) {
+            queryParams.push(`tag_ids=${req.tagIds}`);
+        }
+
+        if (req?.tagFilterType) {
+            queryParams.push(`tag_filter_type=${req.tagFilterType}`);
+        }
+
+        if (req?.amountFilter) {
+            queryParams.push(`amount_filter=${encodeURIComponent(req.amountFilter)}`);
+        }
+
+        if (req?.keyword) {
+            queryParams.push(`keyword=${encodeURIComponent(req.keyword)}`);
+        }
+
+        if (req?.withAccounts) {
+            queryParams.push('withAccounts=true');
+        }
+
+        if (req?.withCategories) {
+            queryParams.push('withCategories=true');
+        }
+
+        if (req?.withMonthlySummary) {
+            queryParams.push('withMonthlySummary=true');
+        }
+
+        const queryString = queryParams.length ? '?' + queryParams.join('&') : '';
+
         if (fileType === 'csv') {
-            return axios.get<BlobPart>('v1/data/export.csv');
+            return axios.get<BlobPart>('v1/data/export.csv' + queryString);
         } else if (fileType === 'tsv') {
-            return axios.get<BlobPart>('v1/data/export.tsv');
+            return axios.get<BlobPart>('v1/data/export.tsv' + queryString);
+        } else if (fileType === 'xlsx') {
+            return axios.get<BlobPart>('v1/data/export.xlsx' + queryString, {
+                responseType: 'blob'
+            });
         } else {
             return Promise.reject('Parameter Invalid');
         }
@@ -421,9 +482,11 @@ export default {
     deleteTransaction: (req: TransactionDeleteRequest): ApiResponsePromise<boolean> => {
         return axios.post<ApiResponse<boolean>>('v1/transactions/delete.json', req);
     },
-    parseImportTransaction: ({ fileType, importFile }: { fileType: string, importFile: unknown }): ApiResponsePromise<ImportTransactionResponsePageWrapper> => {
+    parseImportTransaction: ({ fileType, profileId, encoding, importFile }: { fileType: string, profileId?: string, encoding?: string, importFile: unknown }): ApiResponsePromise<ImportTransactionResponsePageWrapper> => {
         return axios.postForm<ApiResponse<ImportTransactionResponsePageWrapper>>('v1/transactions/parse_import.json', {
             fileType: fileType,
+            profileId: profileId,
+            encoding: encoding,
             file: importFile
         }, {
             timeout: DEFAULT_UPLOAD_API_TIMEOUT
@@ -434,6 +497,31 @@ export default {
             timeout: DEFAULT_IMPORT_API_TIMEOUT
         } as ApiRequestConfig);
     },
+    addTransactionImportJob: ({ fileType, profileId, encoding, importFile, skipLikelyDuplicates }: { fileType: string, profileId?: string, encoding?: string, importFile: unknown, skipLikelyDuplicates: boolean }): ApiResponsePromise<TransactionImportJobInfoResponse> => {
+        return axios.postForm<ApiResponse<TransactionImportJobInfoResponse>>('v1/transaction/import_jobs/add.json', {
+            fileType: fileType,
+            profileId: profileId,
+            encoding: encoding,
+            skipLikelyDuplicates: skipLikelyDuplicates ? 'true' : 'false',
+            file: importFile
+        }, {
+            timeout: DEFAULT_UPLOAD_API_TIMEOUT
+        } as ApiRequestConfig);
+    },
+    getAllTransactionImportJobs: (): ApiResponsePromise<TransactionImportJobInfoResponse[]> => {
+        return axios.get<ApiResponse<TransactionImportJobInfoResponse[]>>('v1/transaction/import_jobs/list.json');
+    },
+    getTransactionImportJob: ({ id }: { id: string }): ApiResponsePromise<TransactionImportJobInfoResponse> => {
+        return axios.get<ApiResponse<TransactionImportJobInfoResponse>>(`v1/transaction/import_jobs/get.json?id=${id}`);
+    },
+    getAllTransactionImportBatches: (): ApiResponsePromise<TransactionImportBatchInfoResponse[]> => {
+        return axios.get<ApiResponse<TransactionImportBatchInfoResponse[]>>('v1/transaction/import_batches/list.json');
+    },
+    revertTransactionImportBatch: (req: TransactionImportBatchRevertRequest): ApiResponsePromise<TransactionImportBatchRevertResponse> => {
+        return axios.post<ApiResponse<TransactionImportBatchRevertResponse>>('v1/transaction/import_batches/revert.json', req, {
+            timeout: DEFAULT_IMPORT_API_TIMEOUT
+        } as ApiRequestConfig);
+    },
     uploadTransactionPicture: ({ pictureFile, clientSessionId }: { pictureFile: unknown, clientSessionId: string }): ApiResponsePromise<TransactionPictureInfoBasicResponse> => {
         return axios.postForm<ApiResponse<TransactionPictureInfoBasicResponse>>('v1/transaction/pictures/upload.json', {
             picture: pictureFile,
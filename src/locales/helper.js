@@ -1277,7 +1277,8 @@ function getLocalizedError(error) {
 
     if (error.errorCode !== KnownErrorCode.ValidatorError) {
         return {
-            message: `error.${error.errorMessage}`
+            message: `error.${error.errorMessage}`,
+            parameters: getErrorContextParameters(error.context)
         };
     }
 
@@ -1304,6 +1305,26 @@ function getLocalizedError(error) {
     };
 }
 
+function getErrorContextParameters(context) {
+    if (!context) {
+        return undefined;
+    }
+
+    const parameters = [];
+
+    for (const key in context) {
+        if (Object.prototype.hasOwnProperty.call(context, key)) {
+            parameters.push({
+                key: key,
+                localized: false,
+                value: String(context[key])
+            });
+        }
+    }
+
+    return parameters;
+}
+
 function getLocalizedErrorParameters(parameters, i18nFunc) {
     let localizedParameters = {};
 
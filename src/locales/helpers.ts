@@ -259,7 +259,8 @@ export function useI18n() {
 
         if (error.errorCode !== KnownErrorCode.ValidatorError) {
             return {
-                message: `error.${error.errorMessage}`
+                message: `error.${error.errorMessage}`,
+                parameters: getErrorContextParameters(error.context)
             };
         }
 
@@ -286,6 +287,26 @@ export function useI18n() {
         };
     }
 
+    function getErrorContextParameters(context?: Record<string, unknown>): LocalizedErrorParameter[] | undefined {
+        if (!context) {
+            return undefined;
+        }
+
+        const parameters: LocalizedErrorParameter[] = [];
+
+        for (const key in context) {
+            if (Object.prototype.hasOwnProperty.call(context, key)) {
+                parameters.push({
+                    key: key,
+                    localized: false,
+                    value: String(context[key])
+                });
+            }
+        }
+
+        return parameters;
+    }
+
     function getLocalizedErrorParameters(parameters?: LocalizedErrorParameter[]): Record<string, string> {
         const localizedParameters: Record<string, string> = {};
 
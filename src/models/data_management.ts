@@ -2,6 +2,21 @@ export interface ClearDataRequest {
     readonly password: string;
 }
 
+export interface ExportDataRequest {
+    readonly startTime?: number;
+    readonly endTime?: number;
+    readonly type?: number;
+    readonly categoryIds?: string;
+    readonly accountIds?: string;
+    readonly tagIds?: string;
+    readonly tagFilterType?: number;
+    readonly amountFilter?: string;
+    readonly keyword?: string;
+    readonly withAccounts?: boolean;
+    readonly withCategories?: boolean;
+    readonly withMonthlySummary?: boolean;
+}
+
 export interface DataStatisticsResponse {
     readonly totalAccountCount: string;
     readonly totalTransactionCategoryCount: string;
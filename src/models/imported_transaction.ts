@@ -3,6 +3,7 @@ import type { TransactionGeoLocationResponse } from './transaction.ts';
 export interface ImportTransactionResponse {
     readonly type: number;
     readonly categoryId: string;
+    readonly originalParentCategoryName?: string;
     readonly originalCategoryName: string;
     readonly time: number;
     readonly utcOffset: number;
@@ -24,3 +25,50 @@ export interface ImportTransactionResponsePageWrapper {
     readonly items: ImportTransactionResponse[];
     readonly totalCount: number;
 }
+
+export interface TransactionImportBatchRevertRequest {
+    readonly id: string;
+}
+
+export interface TransactionImportBatchInfoResponse {
+    readonly id: string;
+    readonly fileType: string;
+    readonly fileName: string;
+    readonly transactionCount: number;
+    readonly createdAccountCount: number;
+    readonly createdCategoryCount: number;
+    readonly importedTime: number;
+}
+
+export interface TransactionImportBatchRevertResponse {
+    readonly transactionCount: number;
+    readonly deletedAccountCount: number;
+    readonly deletedCategoryCount: number;
+}
+
+export interface TransactionImportJobRowError {
+    readonly index: number;
+    readonly time: number;
+    readonly accountName: string;
+    readonly errorMessage: string;
+}
+
+export interface TransactionImportJobInfoResponse {
+    readonly id: string;
+    readonly status: number;
+    readonly fileType: string;
+    readonly fileName: string;
+    readonly skipLikelyDuplicates: boolean;
+    readonly totalCount: number;
+    readonly processedCount: number;
+    readonly importedCount: number;
+    readonly skippedDuplicateCount: number;
+    readonly failedCount: number;
+    readonly progress: number;
+    readonly importBatchId?: string;
+    readonly errorMessage?: string;
+    readonly rowErrors: TransactionImportJobRowError[];
+    readonly createdTime: number;
+    readonly updatedTime: number;
+    readonly finishedTime?: number;
+}
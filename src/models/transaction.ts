@@ -50,6 +50,8 @@ export interface TransactionDeleteRequest {
 
 export interface TransactionImportRequest {
     readonly transactions: TransactionCreateRequest[];
+    readonly fileType?: string;
+    readonly fileName?: string;
     readonly clientSessionId: string;
 }
 
@@ -202,6 +204,7 @@ export interface TransactionStatisticResponseItem {
     readonly categoryId: string;
     readonly accountId: string;
     readonly totalAmount: number;
+    readonly amountInDefaultCurrency?: number;
 }
 
 export interface TransactionStatisticTrendsItem {
@@ -80,7 +80,10 @@ function assembleAccountAndCategoryInfo(userStore, accountsStore, transactionCat
             item.primaryCategory = item.category;
         }
 
-        if (item.account && item.account.currency !== defaultCurrency) {
+        if (isNumber(dataItem.amountInDefaultCurrency)) {
+            // the amount has been converted by the exchange rates of the transaction dates in server
+            item.amountInDefaultCurrency = dataItem.amountInDefaultCurrency;
+        } else if (item.account && item.account.currency !== defaultCurrency) {
             const amount = exchangeRatesStore.getExchangedAmount(item.amount, item.account.currency, defaultCurrency);
 
             if (isNumber(amount)) {
@@ -1201,9 +1201,9 @@ export const useTransactionsStore = defineStore('transactions', {
                 });
             });
         },
-        parseImportTransaction({ fileType, importFile }) {
+        parseImportTransaction({ fileType, encoding, importFile }) {
             return new Promise((resolve, reject) => {
-                services.parseImportTransaction({ fileType, importFile }).then(response => {
+                services.parseImportTransaction({ fileType, encoding, importFile }).then(response => {
                     const data = response.data;
 
                     if (!data || !data.success || !data.result) {
@@ -1225,7 +1225,7 @@ export const useTransactionsStore = defineStore('transactions', {
                 });
             });
         },
-        importTransactions({ transactions, clientSessionId }) {
+        importTransactions({ transactions, fileType, fileName, clientSessionId }) {
             const submitTransactions = [];
 
             if (transactions) {
@@ -1234,6 +1234,11 @@ export const useTransactionsStore = defineStore('transactions', {
                     const submitTransaction = buildBasicSubmitTransaction(transaction, false);
 
                     submitTransaction.categoryId = transaction.categoryId;
+
+                    if (transaction.externalId) {
+                        submitTransaction.externalId = transaction.externalId;
+                    }
+
                     submitTransactions.push(submitTransaction);
                 }
             }
@@ -1241,6 +1246,8 @@ export const useTransactionsStore = defineStore('transactions', {
             return new Promise((resolve, reject) => {
                 services.importTransactions({
                     transactions: submitTransactions,
+                    fileType: fileType,
+                    fileName: fileName,
                     clientSessionId: clientSessionId
                 }).then(response => {
                     const data = response.data;
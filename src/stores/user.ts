@@ -14,6 +14,7 @@ import {
 } from '@/models/user.ts';
 
 import type {
+    ExportDataRequest,
     DataStatisticsResponse
 } from '@/models/data_management.ts';
 
@@ -260,9 +261,9 @@ export const useUserStore = defineStore('user', () => {
         });
     }
 
-    function getExportedUserData(fileType: string): Promise<Blob> {
+    function getExportedUserData(fileType: string, req?: ExportDataRequest): Promise<Blob> {
         return new Promise((resolve, reject) => {
-            services.getExportedUserData(fileType).then(response => {
+            services.getExportedUserData(fileType, req).then(response => {
                 if (response && response.headers) {
                     if (fileType === 'csv' && response.headers['content-type'] !== 'text/csv') {
                         reject({ message: 'Unable to retrieve exported user data' });
@@ -270,6 +271,9 @@ export const useUserStore = defineStore('user', () => {
                     } else if (fileType === 'tsv' && response.headers['content-type'] !== 'text/tab-separated-values') {
                         reject({ message: 'Unable to retrieve exported user data' });
                         return;
+                    } else if (fileType === 'xlsx' && response.headers['content-type'] !== 'application/vnd.openxmlformats-officedocument.spreadsheetml.sheet') {
+                        reject({ message: 'Unable to retrieve exported user data' });
+                        return;
                     }
                 }
 
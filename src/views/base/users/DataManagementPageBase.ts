@@ -4,7 +4,7 @@ import { useI18n } from '@/locales/helpers.ts';
 
 import { useUserStore } from '@/stores/user.ts';
 
-import type { DataStatisticsResponse, DisplayDataStatistics } from '@/models/data_management.ts';
+import type { ExportDataRequest, DataStatisticsResponse, DisplayDataStatistics } from '@/models/data_management.ts';
 
 export function useDataManagementPageBase() {
     const { tt, appendDigitGroupingSymbol } = useI18n();
@@ -41,12 +41,25 @@ export function useDataManagementPageBase() {
         return tt('dataExport.defaultExportFilename') + '.' + fileExtension;
     }
 
+    function getExportDataRequest(fileType: string): ExportDataRequest | undefined {
+        if (fileType === 'xlsx') {
+            return {
+                withAccounts: true,
+                withCategories: true,
+                withMonthlySummary: true
+            };
+        }
+
+        return undefined;
+    }
+
     return {
         // states
         dataStatistics,
         // computed states
         displayDataStatistics,
         // functions
-        getExportFileName
+        getExportFileName,
+        getExportDataRequest
     }
 }